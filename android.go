@@ -0,0 +1,415 @@
+package gotext
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/feature/plural"
+	"golang.org/x/text/language"
+)
+
+/*
+Android parses Android string resources (res/values/strings.xml) and provides all the Translation functions needed,
+so catalogs can be shared with mobile apps.
+
+Resource names are used as msgids: <string name="hello"> is looked up with Get("hello").
+<plurals> resources are plural entries, looked up with GetN(name, name, n), where the quantity of each item
+is mapped to the msgstr index of its CLDR category in the language given to NewAndroid.
+Android strings have no context, and other resources (like <string-array>) are ignored.
+
+Example:
+
+	import (
+		"fmt"
+		"github.com/leonelquinteros/gotext"
+	)
+
+	func main() {
+		res := gotext.NewAndroid("ru")
+		res.ParseFile("/path/to/app/src/main/res/values-ru/strings.xml")
+
+		fmt.Println(res.Get("app_name"))
+		fmt.Println(res.GetN("songs", "songs", 5, 5))
+	}
+*/
+type Android struct {
+	domain *Domain
+}
+
+// NewAndroid should always be used to instantiate a new Android object.
+// lang is the language of the resources, which can't be told from the file and is needed to map plural quantities.
+func NewAndroid(lang string) *Android {
+	a := &Android{domain: NewDomain()}
+	a.domain.setCLDRLanguage(lang)
+
+	return a
+}
+
+// setCLDRLanguage sets the language of a catalog without Plural-Forms header, using the CLDR plural rules of lang.
+func (do *Domain) setCLDRLanguage(lang string) {
+	do.trMutex.Lock()
+	do.pluralMutex.Lock()
+	defer do.trMutex.Unlock()
+	defer do.pluralMutex.Unlock()
+
+	do.setHeader("Language", lang)
+	do.Language = lang
+	do.tag = language.Make(lang)
+
+	forms := cardinalForms(do.tag)
+	do.nplurals = len(forms)
+	do.setPluralExpression(cldrPlural{tag: do.tag, forms: forms})
+}
+
+func (a *Android) GetDomain() *Domain {
+	return a.domain
+}
+
+func (a *Android) Get(str string, vars ...interface{}) string {
+	return a.domain.Get(str, vars...)
+}
+
+func (a *Android) GetN(str, plural string, n int, vars ...interface{}) string {
+	return a.domain.GetN(str, plural, n, vars...)
+}
+
+func (a *Android) GetC(str, ctx string, vars ...interface{}) string {
+	return a.domain.GetC(str, ctx, vars...)
+}
+
+func (a *Android) GetNC(str, plural string, n int, ctx string, vars ...interface{}) string {
+	return a.domain.GetNC(str, plural, n, ctx, vars...)
+}
+
+func (a *Android) MarshalBinary() ([]byte, error) {
+	return a.domain.MarshalBinary()
+}
+
+func (a *Android) UnmarshalBinary(data []byte) error {
+	return a.domain.UnmarshalBinary(data)
+}
+
+func (a *Android) ParseFile(f string) {
+	data, err := getFileData(f)
+	if err != nil {
+		return
+	}
+
+	a.Parse(data)
+}
+
+// Parse loads the translations specified in the provided byte slice, in the Android string resources format.
+func (a *Android) Parse(buf []byte) {
+	a.ParseWithError(buf)
+}
+
+// ParseWithError works like Parse, but returns the XML syntax error found, if any.
+// The resources read before the error are kept.
+func (a *Android) ParseWithError(buf []byte) error {
+	do := a.domain
+
+	// Lock while parsing
+	do.trMutex.Lock()
+	do.pluralMutex.Lock()
+	defer do.trMutex.Unlock()
+	defer do.pluralMutex.Unlock()
+
+	dec := xml.NewDecoder(bytes.NewReader(buf))
+	forms := cardinalForms(do.tag)
+
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		start, ok := tok.(xml.StartElement)
+		if !ok || start.Name.Local == "resources" {
+			continue
+		}
+
+		name := xmlAttr(start, "name")
+		switch start.Name.Local {
+		case "string":
+			text, err := androidText(dec)
+			if err != nil {
+				return err
+			}
+			tr := NewTranslation()
+			tr.ID = name
+			tr.Set(text)
+			do.translations[name] = tr
+
+		case "plurals":
+			tr := NewTranslation()
+			tr.ID = name
+			tr.PluralID = name
+			if err := parseAndroidPlurals(dec, tr, forms); err != nil {
+				return err
+			}
+			do.translations[name] = tr
+
+		default:
+			if err := dec.Skip(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// parseAndroidPlurals reads the items of a <plurals> element into tr.
+// Items with a quantity the language doesn't use are ignored.
+func parseAndroidPlurals(dec *xml.Decoder, tr *Translation, forms []plural.Form) error {
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+
+		switch t := tok.(type) {
+		case xml.EndElement:
+			return nil
+		case xml.StartElement:
+			if t.Name.Local != "item" {
+				if err := dec.Skip(); err != nil {
+					return err
+				}
+				continue
+			}
+			text, err := androidText(dec)
+			if err != nil {
+				return err
+			}
+			form, ok := cldrCategories[xmlAttr(t, "quantity")]
+			if !ok {
+				continue
+			}
+			for i, f := range forms {
+				if f == form {
+					tr.Trs[i] = text
+				}
+			}
+		}
+	}
+}
+
+// xmlAttr returns the value of the attribute name of an element.
+func xmlAttr(el xml.StartElement, name string) string {
+	for _, attr := range el.Attr {
+		if attr.Name.Local == name {
+			return attr.Value
+		}
+	}
+	return ""
+}
+
+// androidText reads the content of the current element up to its end, and returns it unescaped.
+// Nested markup, like <b> or <xliff:g>, is kept as is.
+func androidText(dec *xml.Decoder) (string, error) {
+	var raw strings.Builder
+	depth := 0
+
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return "", err
+		}
+
+		switch t := tok.(type) {
+		case xml.CharData:
+			raw.Write(t)
+		case xml.StartElement:
+			depth++
+			// Markup quotes and backslashes are escaped, so unescapeAndroid keeps them
+			raw.WriteString("<" + xmlName(t.Name))
+			for _, attr := range t.Attr {
+				value := strings.Replace(escapeXML(attr.Value), `\`, `\\`, -1)
+				raw.WriteString(" " + xmlName(attr.Name) + `=\"` + value + `\"`)
+			}
+			raw.WriteString(">")
+		case xml.EndElement:
+			if depth == 0 {
+				return unescapeAndroid(raw.String()), nil
+			}
+			depth--
+			raw.WriteString("</" + xmlName(t.Name) + ">")
+		}
+	}
+}
+
+// xmlName returns the name of an element or attribute with its namespace prefix.
+// The decoder resolves prefixes to namespace URLs, so only the well known Android ones are restored.
+func xmlName(n xml.Name) string {
+	switch n.Space {
+	case "":
+		return n.Local
+	case "urn:oasis:names:tc:xliff:document:1.2":
+		return "xliff:" + n.Local
+	case "http://schemas.android.com/tools":
+		return "tools:" + n.Local
+	}
+	return n.Space + ":" + n.Local
+}
+
+// unescapeAndroid resolves the escape sequences and quoting of an Android string resource.
+// Whitespace outside double quotes is collapsed, like the Android resource compiler does.
+func unescapeAndroid(s string) string {
+	var b strings.Builder
+	quoted := false
+	space := false
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '\\' && i+1 < len(s):
+			i++
+			switch s[i] {
+			case 'n':
+				b.WriteByte('\n')
+			case 't':
+				b.WriteByte('\t')
+			case 'u':
+				if i+4 < len(s) {
+					if r, err := strconv.ParseUint(s[i+1:i+5], 16, 32); err == nil {
+						b.WriteRune(rune(r))
+						i += 4
+						break
+					}
+				}
+				b.WriteByte('u')
+			default:
+				b.WriteByte(s[i])
+			}
+			space = false
+		case c == '"':
+			quoted = !quoted
+			space = false
+		case !quoted && (c == ' ' || c == '\t' || c == '\n' || c == '\r'):
+			if !space {
+				b.WriteByte(' ')
+			}
+			space = true
+		default:
+			b.WriteByte(c)
+			space = false
+		}
+	}
+
+	out := b.String()
+	if !strings.HasPrefix(s, `"`) {
+		out = strings.TrimLeft(out, " ")
+	}
+	if !strings.HasSuffix(s, `"`) || strings.HasSuffix(s, `\"`) {
+		out = strings.TrimRight(out, " ")
+	}
+	return out
+}
+
+// escapeAndroid escapes a string to be written as an Android string resource.
+// Strings with leading, trailing or repeated spaces are quoted, so they're kept as is.
+func escapeAndroid(s string) string {
+	var b strings.Builder
+	for i, r := range s {
+		switch r {
+		case '\\', '"', '\'':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '@', '?':
+			if i == 0 {
+				b.WriteByte('\\')
+			}
+			b.WriteRune(r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+
+	out := escapeXMLText(b.String())
+	if strings.HasPrefix(s, " ") || strings.HasSuffix(s, " ") || strings.Contains(s, "  ") {
+		out = `"` + out + `"`
+	}
+	return out
+}
+
+// escapeXMLText escapes the characters that can't be written as is in XML text.
+func escapeXMLText(s string) string {
+	return strings.Replace(strings.Replace(strings.Replace(s, "&", "&amp;", -1), "<", "&lt;", -1), ">", "&gt;", -1)
+}
+
+// escapeXML escapes the characters that can't be written as is in XML attribute values.
+func escapeXML(s string) string {
+	var b strings.Builder
+	for len(s) > 0 {
+		r, size := utf8.DecodeRuneInString(s)
+		switch r {
+		case '&':
+			b.WriteString("&amp;")
+		case '<':
+			b.WriteString("&lt;")
+		case '>':
+			b.WriteString("&gt;")
+		case '"':
+			b.WriteString("&quot;")
+		default:
+			b.WriteString(s[:size])
+		}
+		s = s[size:]
+	}
+	return b.String()
+}
+
+// MarshalText serializes the translations in the Android string resources format.
+// See Domain.MarshalAndroid.
+func (a *Android) MarshalText() ([]byte, error) {
+	return a.domain.MarshalAndroid()
+}
+
+/*
+MarshalAndroid serializes the translations of the Domain in the Android string resources format, sorted by msgid.
+Plural entries are written as <plurals> resources, with each msgstr index mapped to its CLDR category
+in the Domain language. Entries in a context, untranslated ones and the header are skipped,
+as Android has no equivalent for them.
+
+msgids are written as resource names, so they must be valid Android resource names for the file to compile.
+*/
+func (do *Domain) MarshalAndroid() ([]byte, error) {
+	do.trMutex.RLock()
+	forms := cardinalForms(do.tag)
+	do.trMutex.RUnlock()
+
+	var buf bytes.Buffer
+	buf.WriteString("<?xml version=\"1.0\" encoding=\"utf-8\"?>\n<resources>\n")
+
+	for _, e := range do.entries() {
+		if e.Context != "" || !e.Translation.IsTranslated() {
+			continue
+		}
+
+		if e.Translation.PluralID == "" {
+			buf.WriteString(`    <string name="` + escapeXML(e.MsgID) + `">` + escapeAndroid(e.Translation.Get()) + "</string>\n")
+			continue
+		}
+
+		buf.WriteString(`    <plurals name="` + escapeXML(e.MsgID) + "\">\n")
+		for i, form := range forms {
+			if str, ok := e.Translation.Trs[i]; ok {
+				buf.WriteString(`        <item quantity="` + cldrCategory(form) + `">` + escapeAndroid(str) + "</item>\n")
+			}
+		}
+		buf.WriteString("    </plurals>\n")
+	}
+
+	buf.WriteString("</resources>\n")
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,165 @@
+package gotext
+
+import (
+	"strings"
+	"testing"
+)
+
+const androidResources = `<?xml version="1.0" encoding="utf-8"?>
+<resources xmlns:xliff="urn:oasis:names:tc:xliff:document:1.2">
+    <string name="app_name">My App</string>
+    <string name="quote">Don\'t say \"hi\"</string>
+    <string name="lines">First\nSecond</string>
+    <string name="spaces">"  two  spaces "</string>
+    <string name="collapsed">
+        Some   wrapped
+        text
+    </string>
+    <string name="at">\@home</string>
+    <string name="unicode">été</string>
+    <string name="entities">Fish &amp; Chips</string>
+    <string name="markup">Hello <b>%s</b></string>
+    <string name="placeholder">Hi <xliff:g id="name">%s</xliff:g></string>
+    <string-array name="planets">
+        <item>Mercury</item>
+    </string-array>
+    <plurals name="songs">
+        <item quantity="one">%d песня</item>
+        <item quantity="few">%d песни</item>
+        <item quantity="many">%d песен</item>
+        <item quantity="other">%d песни</item>
+    </plurals>
+</resources>
+`
+
+func TestAndroid(t *testing.T) {
+	a := NewAndroid("ru")
+	if err := a.ParseWithError([]byte(androidResources)); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, want := range map[string]string{
+		"app_name":    "My App",
+		"quote":       `Don't say "hi"`,
+		"lines":       "First\nSecond",
+		"spaces":      "  two  spaces ",
+		"collapsed":   "Some wrapped text",
+		"at":          "@home",
+		"unicode":     "été",
+		"entities":    "Fish & Chips",
+		"markup":      "Hello <b>%s</b>",
+		"placeholder": `Hi <xliff:g id="name">%s</xliff:g>`,
+		"planets":     "planets",
+	} {
+		if got := a.Get(name); got != want {
+			t.Errorf("Get(%q) = %q, want %q", name, got, want)
+		}
+	}
+
+	for n, want := range map[int]string{1: "1 песня", 3: "3 песни", 5: "5 песен", 21: "21 песня"} {
+		if got := a.GetN("songs", "songs", n, n); got != want {
+			t.Errorf("GetN(songs, %d) = %q, want %q", n, got, want)
+		}
+	}
+
+	if a.GetDomain().GetLanguage() != "ru" {
+		t.Errorf("unexpected language %q", a.GetDomain().GetLanguage())
+	}
+}
+
+func TestAndroidSyntaxError(t *testing.T) {
+	a := NewAndroid("en")
+	err := a.ParseWithError([]byte(`<resources><string name="ok">OK</string><string name="bad">Bad</resources>`))
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if got := a.Get("ok"); got != "OK" {
+		t.Errorf("expected resources before the error to be kept, got %q", got)
+	}
+}
+
+func TestAndroidMarshalText(t *testing.T) {
+	a := NewAndroid("ru")
+	a.Parse([]byte(androidResources))
+
+	out, err := a.MarshalText()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{
+		`<string name="quote">Don\'t say \"hi\"</string>`,
+		`<string name="spaces">"  two  spaces "</string>`,
+		`<string name="at">\@home</string>`,
+		`<string name="entities">Fish &amp; Chips</string>`,
+		`<item quantity="few">%d песни</item>`,
+	} {
+		if !strings.Contains(string(out), want) {
+			t.Errorf("expected output to contain %s, got:\n%s", want, out)
+		}
+	}
+
+	// Round trip
+	b := NewAndroid("ru")
+	b.Parse(out)
+	a.GetDomain().Iterate(func(ctx, msgid string, tr *Translation) bool {
+		other, ok := b.GetDomain().GetTranslation(msgid)
+		if !ok {
+			t.Errorf("%q missing after round trip", msgid)
+			return true
+		}
+		for i, str := range tr.Trs {
+			if other.Trs[i] != str {
+				t.Errorf("%q[%d] = %q after round trip, want %q", msgid, i, other.Trs[i], str)
+			}
+		}
+		return true
+	})
+}
+
+func TestAndroidFromPo(t *testing.T) {
+	po := NewPo()
+	po.Parse([]byte(`
+msgid ""
+msgstr ""
+"Language: en\n"
+"Plural-Forms: nplurals=2; plural=(n != 1);\n"
+
+msgid "title"
+msgstr "Title"
+
+msgid "untranslated"
+msgstr ""
+
+msgctxt "menu"
+msgid "open"
+msgstr "Open"
+
+msgid "file"
+msgid_plural "files"
+msgstr[0] "%d file"
+msgstr[1] "%d files"
+`))
+
+	out, err := po.GetDomain().MarshalAndroid()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `<?xml version="1.0" encoding="utf-8"?>
+<resources>
+    <plurals name="file">
+        <item quantity="one">%d file</item>
+        <item quantity="other">%d files</item>
+    </plurals>
+    <string name="title">Title</string>
+</resources>
+`
+	if string(out) != want {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+}
+
+func TestAndroidTranslator(t *testing.T) {
+	var _ Translator = NewAndroid("en")
+}
@@ -0,0 +1,74 @@
+package gotext
+
+import (
+	"sort"
+)
+
+// addDomainOrder records dom in the registration order of the domains, if it's new. The Locale must be locked.
+func (l *Locale) addDomainOrder(dom string) {
+	for _, d := range l.domainOrder {
+		if d == dom {
+			return
+		}
+	}
+	l.domainOrder = append(l.domainOrder, dom)
+}
+
+// SetDomainPriority sets the domains GetAny searches first, in order. The rest are searched after them,
+// in the order they were added.
+func (l *Locale) SetDomainPriority(doms ...string) {
+	l.Lock()
+	l.domainPriority = append([]string(nil), doms...)
+	l.Unlock()
+}
+
+// searchOrder returns the domains of the Locale in the order GetAny searches them: the ones set by
+// SetDomainPriority, then the rest in registration order, and last the ones set directly in Domains, sorted.
+// The Locale must be locked.
+func (l *Locale) searchOrder() []string {
+	order := make([]string, 0, len(l.Domains))
+	seen := make(map[string]bool, len(l.Domains))
+	for _, doms := range [][]string{l.domainPriority, l.domainOrder} {
+		for _, dom := range doms {
+			if _, ok := l.Domains[dom]; ok && !seen[dom] {
+				seen[dom] = true
+				order = append(order, dom)
+			}
+		}
+	}
+
+	var rest []string
+	for dom := range l.Domains {
+		if !seen[dom] {
+			rest = append(rest, dom)
+		}
+	}
+	sort.Strings(rest)
+	return append(order, rest...)
+}
+
+/*
+GetAny translates str with the first domain that has a translation for it, so callers don't need to know
+which catalog holds a string, like after splitting a large catalog in several ones:
+
+	l.AddDomain("default")
+	l.AddDomain("billing")
+	l.GetAny("Invoice") // from "billing", unless "default" translates it too
+
+Domains are searched in the order they were added, or the one set by SetDomainPriority.
+Only messages without context are looked up, and entries with an empty msgstr or fuzzy matches don't count.
+When no domain translates str, it's returned formatted with vars, without reporting a miss to the catalogs.
+*/
+func (l *Locale) GetAny(str string, vars ...interface{}) string {
+	l.RLock()
+	defer l.RUnlock()
+	vars = l.localVars(vars)
+
+	for _, dom := range l.searchOrder() {
+		if tr := l.Domains[dom]; tr != nil && translated(tr, str, "", false) {
+			return l.output(str, tr.Get(str, vars...))
+		}
+	}
+
+	return l.output(str, Printf(str, vars...))
+}
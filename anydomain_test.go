@@ -0,0 +1,51 @@
+package gotext
+
+import (
+	"testing"
+)
+
+func TestLocaleGetAny(t *testing.T) {
+	l := NewLocale("", "es")
+	for _, c := range []struct{ dom, po string }{
+		{"default", "msgid \"Hello\"\nmsgstr \"Hola\"\n\nmsgid \"Invoice\"\nmsgstr \"\"\n"},
+		{"billing", "msgid \"Invoice\"\nmsgstr \"Factura\"\n\nmsgid \"Hello\"\nmsgstr \"Buenas\"\n"},
+		{"admin", "msgid \"Invoice\"\nmsgstr \"Factura (admin)\"\n\nmsgid \"Delete %s\"\nmsgstr \"Borrar %s\"\n"},
+	} {
+		if err := l.AddDomainBytes(c.dom, []byte(c.po), FormatPO); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	hello, invoice, del, missing := "Hello", "Invoice", "Delete %s", "Missing %d"
+	for _, tc := range []struct {
+		str  string
+		vars []interface{}
+		want string
+	}{
+		{hello, nil, "Hola"},
+		// Empty msgstrs don't count
+		{invoice, nil, "Factura"},
+		{del, []interface{}{"x"}, "Borrar x"},
+		{missing, []interface{}{3}, "Missing 3"},
+	} {
+		if got := l.GetAny(tc.str, tc.vars...); got != tc.want {
+			t.Errorf("%q: got %q, want %q", tc.str, got, tc.want)
+		}
+	}
+
+	l.SetDomainPriority("admin", "billing")
+	if got := l.GetAny(invoice); got != "Factura (admin)" {
+		t.Errorf("expected the priority domain to be searched first, got %q", got)
+	}
+	if got := l.GetAny(hello); got != "Buenas" {
+		t.Errorf("expected billing before default, got %q", got)
+	}
+
+	// Domains set directly are searched last
+	extra := NewPo()
+	extra.Parse([]byte("msgid \"Bye\"\nmsgstr \"Adiós\"\n"))
+	l.Domains["extra"] = extra
+	if got := l.GetAny("Bye"); got != "Adiós" {
+		t.Errorf("unexpected translation %q", got)
+	}
+}
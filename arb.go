@@ -0,0 +1,672 @@
+package gotext
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+/*
+ARB parses Application Resource Bundle (.arb) files, used by Flutter, and provides all the Translation functions needed,
+so Flutter apps and Go backends can share catalogs.
+
+Message keys are used as msgids, and the @@locale attribute sets the catalog language
+(the one given to NewARB is used when it's missing). Messages use the ICU syntax:
+
+	{
+	  "@@locale": "en",
+	  "hello": "Hello {userName}",
+	  "@hello": {"placeholders": {"userName": {"type": "String", "example": "Bob"}}},
+	  "nWombats": "{count, plural, =0{no wombats} =1{one wombat} other{{count} wombats}}"
+	}
+
+Placeholders are passed as positional vars, in the order of the placeholders metadata
+(and then by first appearance for undeclared ones), so the messages above are rendered
+with Get("hello", "Bob") and GetN("nWombats", "nWombats", n, n).
+
+Plural expressions map to plural entries using the CLDR categories of the language.
+Exact matches, like =0, are ignored, but =1 is used as the "one" category when that one is missing.
+select expressions map every case to a context named after its key, with the "other" case as the message without context.
+selectordinal expressions are rendered with their "other" case.
+
+The metadata of each message is kept, and written back by MarshalText.
+*/
+type ARB struct {
+	domain *Domain
+
+	// Attributes (@@ keys) other than @@locale, in file order
+	attrs     map[string]json.RawMessage
+	attrOrder []string
+
+	// Message keys in file order, and what's known about each one
+	order    []string
+	messages map[string]*arbMessage
+}
+
+// arbMessage is the metadata of an ARB message.
+type arbMessage struct {
+	// The @key object, as read
+	meta json.RawMessage
+	// Declared placeholders, in order
+	placeholders []ARBPlaceholder
+	// Variable names, by argument number (starting at 1)
+	vars []string
+
+	// Kind ("plural" or "select") and variable of the first plural or select expression, if any
+	kind     string
+	selector string
+}
+
+// ARBPlaceholder is a placeholder declared in the metadata of an ARB message.
+type ARBPlaceholder struct {
+	Name        string
+	Type        string
+	Format      string
+	Example     string
+	Description string
+}
+
+// NewARB should always be used to instantiate a new ARB object.
+// lang is the language of the resources when the file doesn't have a @@locale attribute.
+func NewARB(lang string) *ARB {
+	a := &ARB{
+		domain:   NewDomain(),
+		attrs:    make(map[string]json.RawMessage),
+		messages: make(map[string]*arbMessage),
+	}
+	a.domain.setCLDRLanguage(lang)
+
+	return a
+}
+
+func (a *ARB) GetDomain() *Domain {
+	return a.domain
+}
+
+func (a *ARB) Get(str string, vars ...interface{}) string {
+	return printfIndexed(a.domain.Get(str), vars...)
+}
+
+func (a *ARB) GetN(str, plural string, n int, vars ...interface{}) string {
+	return printfIndexed(a.domain.GetN(str, plural, n), vars...)
+}
+
+func (a *ARB) GetC(str, ctx string, vars ...interface{}) string {
+	return printfIndexed(a.domain.GetC(str, ctx), vars...)
+}
+
+func (a *ARB) GetNC(str, plural string, n int, ctx string, vars ...interface{}) string {
+	return printfIndexed(a.domain.GetNC(str, plural, n, ctx), vars...)
+}
+
+func (a *ARB) MarshalBinary() ([]byte, error) {
+	return a.domain.MarshalBinary()
+}
+
+func (a *ARB) UnmarshalBinary(data []byte) error {
+	return a.domain.UnmarshalBinary(data)
+}
+
+func (a *ARB) ParseFile(f string) {
+	data, err := getFileData(f)
+	if err != nil {
+		return
+	}
+
+	a.Parse(data)
+}
+
+// Parse loads the translations specified in the provided byte slice, in the ARB format.
+func (a *ARB) Parse(buf []byte) {
+	a.ParseWithError(buf)
+}
+
+// ParseWithError works like Parse, but returns an error when the file isn't valid JSON, without loading anything,
+// or when some messages have an invalid ICU syntax, which are skipped.
+func (a *ARB) ParseWithError(buf []byte) error {
+	keys, values, err := jsonObject(buf)
+	if err != nil {
+		return fmt.Errorf("gotext: invalid ARB file: %s", err)
+	}
+
+	if raw, ok := values["@@locale"]; ok {
+		var lang string
+		if json.Unmarshal(raw, &lang) == nil && lang != "" {
+			a.domain.setCLDRLanguage(lang)
+		}
+	}
+
+	do := a.domain
+	do.trMutex.Lock()
+	do.pluralMutex.Lock()
+	defer do.trMutex.Unlock()
+	defer do.pluralMutex.Unlock()
+
+	var errs []string
+	for _, key := range keys {
+		raw := values[key]
+		switch {
+		case key == "@@locale":
+		case strings.HasPrefix(key, "@@"):
+			if _, ok := a.attrs[key]; !ok {
+				a.attrOrder = append(a.attrOrder, key)
+			}
+			a.attrs[key] = raw
+		case strings.HasPrefix(key, "@"):
+		default:
+			var str string
+			if err := json.Unmarshal(raw, &str); err != nil {
+				errs = append(errs, fmt.Sprintf("%s: message isn't a string", key))
+				continue
+			}
+
+			msg, err := newARBMessage(values["@"+key])
+			if err != nil {
+				errs = append(errs, fmt.Sprintf("@%s: %s", key, err))
+				continue
+			}
+
+			p := &icuParser{s: str}
+			nodes, err := p.parseMessage("", false)
+			if err != nil {
+				errs = append(errs, fmt.Sprintf("%s: %s", key, err))
+				continue
+			}
+
+			vars := make(map[string]int)
+			for i, ph := range msg.placeholders {
+				vars[ph.Name] = i + 1
+			}
+			for _, n := range nodes {
+				if n.kind == ftlSelect {
+					msg.kind, msg.selector = n.text, n.selector.text
+					break
+				}
+			}
+			do.addFtlMessage(key, nodes, vars)
+			msg.vars = make([]string, len(vars))
+			for name, i := range vars {
+				msg.vars[i-1] = name
+			}
+
+			if _, ok := a.messages[key]; !ok {
+				a.order = append(a.order, key)
+			}
+			a.messages[key] = msg
+		}
+	}
+
+	if len(errs) > 0 {
+		return errors.New("gotext: " + strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// newARBMessage reads the metadata of an ARB message, which may be missing.
+func newARBMessage(meta json.RawMessage) (*arbMessage, error) {
+	msg := &arbMessage{meta: meta}
+	if meta == nil {
+		return msg, nil
+	}
+
+	_, values, err := jsonObject(meta)
+	if err != nil {
+		return nil, err
+	}
+	if values["placeholders"] == nil {
+		return msg, nil
+	}
+
+	names, placeholders, err := jsonObject(values["placeholders"])
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range names {
+		var ph struct {
+			Type        string          `json:"type"`
+			Format      string          `json:"format"`
+			Example     json.RawMessage `json:"example"`
+			Description string          `json:"description"`
+		}
+		if err := json.Unmarshal(placeholders[name], &ph); err != nil {
+			return nil, err
+		}
+
+		// Examples of numbers and dates aren't always strings
+		example := string(ph.Example)
+		json.Unmarshal(ph.Example, &example)
+
+		msg.placeholders = append(msg.placeholders, ARBPlaceholder{
+			Name:        name,
+			Type:        ph.Type,
+			Format:      ph.Format,
+			Example:     example,
+			Description: ph.Description,
+		})
+	}
+	return msg, nil
+}
+
+// jsonObject decodes a JSON object, returning its keys in order and their raw values.
+func jsonObject(data []byte) ([]string, map[string]json.RawMessage, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if tok, err := dec.Token(); err != nil {
+		return nil, nil, err
+	} else if tok != json.Delim('{') {
+		return nil, nil, errors.New("expected an object")
+	}
+
+	var keys []string
+	values := make(map[string]json.RawMessage)
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, nil, err
+		}
+		key := tok.(string)
+
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return nil, nil, err
+		}
+		if _, ok := values[key]; !ok {
+			keys = append(keys, key)
+		}
+		values[key] = raw
+	}
+	if _, err := dec.Token(); err != nil {
+		return nil, nil, err
+	}
+
+	return keys, values, nil
+}
+
+// Placeholders returns the placeholders declared in the metadata of a message, in order.
+// They're the vars expected by the Get functions for it.
+func (a *ARB) Placeholders(id string) []ARBPlaceholder {
+	a.domain.trMutex.RLock()
+	defer a.domain.trMutex.RUnlock()
+
+	if msg, ok := a.messages[id]; ok {
+		return append([]ARBPlaceholder(nil), msg.placeholders...)
+	}
+	return nil
+}
+
+// Description returns the description of a message, from its metadata.
+func (a *ARB) Description(id string) string {
+	a.domain.trMutex.RLock()
+	defer a.domain.trMutex.RUnlock()
+
+	msg, ok := a.messages[id]
+	if !ok || msg.meta == nil {
+		return ""
+	}
+	var meta struct {
+		Description string `json:"description"`
+	}
+	json.Unmarshal(msg.meta, &meta)
+	return meta.Description
+}
+
+/*
+MarshalText serializes the translations in the ARB format, with the metadata and attributes read from the parsed files.
+Messages are written in the order they were read, followed by the ones added to the Domain afterwards, sorted.
+
+fmt placeholders are written as ICU ones, named after the declared placeholders of the message
+(or arg1, arg2... when unknown), and plural entries as plural expressions over their first argument.
+Entries in a context are only written as the cases of the select expressions read from ARB files.
+*/
+func (a *ARB) MarshalText() ([]byte, error) {
+	entries := a.domain.entries()
+
+	a.domain.trMutex.RLock()
+	defer a.domain.trMutex.RUnlock()
+
+	var buf bytes.Buffer
+	buf.WriteString("{")
+	write := func(key string, value []byte) {
+		if buf.Len() > 1 {
+			buf.WriteString(",")
+		}
+		buf.Write(arbJSON(key))
+		buf.WriteString(":")
+		buf.Write(value)
+	}
+
+	write("@@locale", arbJSON(a.domain.Language))
+	for _, key := range a.attrOrder {
+		write(key, a.attrs[key])
+	}
+
+	// Messages read first, in order
+	byID := make(map[string]*Translation)
+	var added []string
+	for _, e := range entries {
+		if e.Context != "" {
+			continue
+		}
+		byID[e.MsgID] = e.Translation
+		if _, ok := a.messages[e.MsgID]; !ok {
+			added = append(added, e.MsgID)
+		}
+	}
+	keys := append(append([]string(nil), a.order...), added...)
+
+	forms := cardinalForms(a.domain.tag)
+	for _, key := range keys {
+		tr, ok := byID[key]
+		if !ok {
+			continue
+		}
+		msg := a.messages[key]
+		if msg == nil {
+			msg = &arbMessage{}
+		}
+
+		names := func(n int) string {
+			if n <= len(msg.vars) {
+				return msg.vars[n-1]
+			}
+			return "arg" + strconv.Itoa(n)
+		}
+
+		var str string
+		switch {
+		case tr.PluralID != "":
+			selector := msg.selector
+			if selector == "" {
+				selector = names(1)
+			}
+			str = "{" + selector + ", plural,"
+			for i, form := range forms {
+				if s, ok := tr.Trs[i]; ok {
+					str += " " + cldrCategory(form) + "{" + icuString(s, names) + "}"
+				}
+			}
+			if cldrCategory(forms[len(forms)-1]) != "other" {
+				str += " other{" + icuString(tr.GetN(len(forms)-1), names) + "}"
+			}
+			str += "}"
+
+		case msg.kind == "select":
+			cases := make([]string, 0, len(a.domain.contexts))
+			for ctx, trs := range a.domain.contexts {
+				if _, ok := trs[key]; ok && ctx != "other" {
+					cases = append(cases, ctx)
+				}
+			}
+			sort.Strings(cases)
+			str = "{" + msg.selector + ", select,"
+			for _, ctx := range cases {
+				str += " " + ctx + "{" + icuString(a.domain.contexts[ctx][key].Get(), names) + "}"
+			}
+			str += " other{" + icuString(tr.Get(), names) + "}}"
+
+		default:
+			str = icuString(tr.Get(), names)
+		}
+
+		write(key, arbJSON(str))
+		switch {
+		case msg.meta != nil:
+			write("@"+key, msg.meta)
+		case strings.Contains(str, "{"):
+			write("@"+key, arbPlaceholdersMeta(str))
+		}
+	}
+	buf.WriteString("}")
+
+	var out bytes.Buffer
+	if err := json.Indent(&out, buf.Bytes(), "", "  "); err != nil {
+		return nil, err
+	}
+	out.WriteString("\n")
+	return out.Bytes(), nil
+}
+
+// arbJSON encodes a string as JSON, without escaping HTML characters.
+func arbJSON(s string) []byte {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.Encode(s)
+	return bytes.TrimRight(buf.Bytes(), "\n")
+}
+
+// arbPlaceholdersMeta returns the metadata declaring the placeholders of an ICU message.
+func arbPlaceholdersMeta(str string) json.RawMessage {
+	p := &icuParser{s: str}
+	nodes, _ := p.parseMessage("", false)
+	vars := make(map[string]int)
+	ftlCollectVars(nodes, vars)
+
+	names := make([]string, len(vars))
+	for name, i := range vars {
+		names[i-1] = name
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(`{"placeholders":{`)
+	for i, name := range names {
+		if i > 0 {
+			buf.WriteString(",")
+		}
+		buf.Write(arbJSON(name))
+		buf.WriteString(":{}")
+	}
+	buf.WriteString("}}")
+	return buf.Bytes()
+}
+
+// icuString converts the fmt placeholders of a translation to ICU ones, named by the names func from their argument number.
+func icuString(s string, names func(n int) string) string {
+	var b strings.Builder
+	next := 1
+	last := 0
+	for _, loc := range verbRe.FindAllStringIndex(s, -1) {
+		b.WriteString(s[last:loc[0]])
+		last = loc[1]
+
+		v := s[loc[0]:loc[1]]
+		switch {
+		case v == "%%":
+			b.WriteString("%")
+			continue
+		case strings.HasPrefix(v, "%("):
+			b.WriteString("{" + v[2:strings.Index(v, ")")] + "}")
+			continue
+		}
+
+		if i := strings.LastIndex(v, "["); i != -1 {
+			if n, err := strconv.Atoi(v[i+1 : strings.Index(v[i:], "]")+i]); err == nil {
+				next = n
+			}
+		}
+		b.WriteString("{" + names(next) + "}")
+		next++
+	}
+	b.WriteString(s[last:])
+	return b.String()
+}
+
+// icuParser parses ICU MessageFormat messages into Fluent patterns, which are mapped to gettext entries the same way.
+type icuParser struct {
+	s   string
+	pos int
+}
+
+// parseMessage parses a message up to the end of the input, or up to the closing brace of a case when nested is set.
+// '#' is replaced by pluralVar inside plural cases.
+func (p *icuParser) parseMessage(pluralVar string, nested bool) ([]ftlNode, error) {
+	var nodes []ftlNode
+	var text strings.Builder
+	flush := func() {
+		if text.Len() > 0 {
+			nodes = append(nodes, ftlNode{kind: ftlText, text: text.String()})
+			text.Reset()
+		}
+	}
+
+	for p.pos < len(p.s) {
+		c := p.s[p.pos]
+		switch {
+		case c == '{':
+			flush()
+			p.pos++
+			arg, err := p.parseArgument(pluralVar)
+			if err != nil {
+				return nil, err
+			}
+			nodes = append(nodes, arg...)
+		case c == '}':
+			if !nested {
+				return nil, errors.New("unexpected '}'")
+			}
+			flush()
+			return nodes, nil
+		case c == '#' && pluralVar != "":
+			flush()
+			nodes = append(nodes, ftlNode{kind: ftlVariable, text: pluralVar})
+			p.pos++
+		default:
+			text.WriteByte(c)
+			p.pos++
+		}
+	}
+
+	if nested {
+		return nil, errors.New("unterminated case")
+	}
+	flush()
+	return nodes, nil
+}
+
+// parseArgument parses an argument, after its opening brace, up to its closing brace.
+// Plural and select expressions are returned as select nodes, with their kind as text.
+func (p *icuParser) parseArgument(pluralVar string) ([]ftlNode, error) {
+	name := strings.TrimSpace(p.until(",}"))
+	if name == "" || p.pos >= len(p.s) {
+		return nil, errors.New("invalid argument")
+	}
+	arg := ftlNode{kind: ftlVariable, text: name}
+	if p.s[p.pos] == '}' {
+		p.pos++
+		return []ftlNode{arg}, nil
+	}
+
+	p.pos++
+	kind := strings.TrimSpace(p.until(",}"))
+	if p.pos >= len(p.s) {
+		return nil, errors.New("unterminated argument")
+	}
+	if p.s[p.pos] == '}' {
+		p.pos++
+		return []ftlNode{arg}, nil
+	}
+	p.pos++
+
+	switch kind {
+	case "plural", "selectordinal":
+		pluralVar = name
+	case "select":
+	default:
+		// Formatted argument, like {d, date, yMd}
+		depth := 1
+		for ; p.pos < len(p.s) && depth > 0; p.pos++ {
+			switch p.s[p.pos] {
+			case '{':
+				depth++
+			case '}':
+				depth--
+			}
+		}
+		if depth > 0 {
+			return nil, errors.New("unterminated argument")
+		}
+		return []ftlNode{arg}, nil
+	}
+
+	variants, err := p.parseCases(pluralVar)
+	if err != nil {
+		return nil, err
+	}
+	if kind == "selectordinal" {
+		return ftlPickVariant(variants, "other").value, nil
+	}
+
+	if kind == "plural" {
+		hasOne := false
+		for _, v := range variants {
+			hasOne = hasOne || v.key == "one"
+		}
+		for i, v := range variants {
+			switch {
+			case v.key == "=1" && !hasOne:
+				variants[i].key = "one"
+			case strings.HasPrefix(v.key, "="):
+				variants[i].key = v.key[1:]
+			}
+		}
+	}
+
+	return []ftlNode{{kind: ftlSelect, text: kind, selector: &arg, variants: variants}}, nil
+}
+
+// parseCases parses the cases of a plural or select expression, and its closing brace.
+func (p *icuParser) parseCases(pluralVar string) ([]ftlVariant, error) {
+	var variants []ftlVariant
+	other := false
+
+	for {
+		for p.pos < len(p.s) && strings.IndexByte(" \t\r\n", p.s[p.pos]) != -1 {
+			p.pos++
+		}
+		if p.pos >= len(p.s) {
+			return nil, errors.New("unterminated expression")
+		}
+		if p.s[p.pos] == '}' {
+			p.pos++
+			break
+		}
+
+		key := strings.TrimSpace(p.until("{}"))
+		if p.pos >= len(p.s) || p.s[p.pos] != '{' || key == "" {
+			return nil, errors.New("invalid case")
+		}
+		// Plural offsets aren't supported
+		if strings.HasPrefix(key, "offset:") {
+			if fields := strings.Fields(key); len(fields) > 1 {
+				key = fields[len(fields)-1]
+			}
+		}
+		p.pos++
+
+		value, err := p.parseMessage(pluralVar, true)
+		if err != nil {
+			return nil, err
+		}
+		p.pos++
+
+		other = other || key == "other"
+		variants = append(variants, ftlVariant{key: key, def: key == "other", value: value})
+	}
+
+	if !other {
+		return nil, errors.New("missing other case")
+	}
+	return variants, nil
+}
+
+// until reads up to the first of the given characters, or the end of the input.
+func (p *icuParser) until(chars string) string {
+	start := p.pos
+	for p.pos < len(p.s) && strings.IndexByte(chars, p.s[p.pos]) == -1 {
+		p.pos++
+	}
+	return p.s[start:p.pos]
+}
@@ -0,0 +1,187 @@
+package gotext
+
+import (
+	"strings"
+	"testing"
+)
+
+const arbFile = `{
+  "@@locale": "en",
+  "@@last_modified": "2020-10-25T12:00:00Z",
+  "title": "My <App>",
+  "@title": {
+    "description": "The app title"
+  },
+  "hello": "Hello {userName}, welcome to {place}",
+  "@hello": {
+    "placeholders": {
+      "place": {"type": "String", "example": "Paris"},
+      "userName": {"type": "String", "example": "Bob"}
+    }
+  },
+  "nWombats": "{count, plural, =0{no wombats} =1{one wombat} other{{count} wombats}}",
+  "@nWombats": {
+    "placeholders": {
+      "count": {"type": "num", "format": "compact", "example": 42}
+    }
+  },
+  "items": "You have {n, plural, one{# item} other{# items}} in {list}",
+  "pronoun": "{gender, select, male{he} female{she} other{they}}",
+  "rank": "{n, selectordinal, one{#st} two{#nd} few{#rd} other{#th}}",
+  "date": "Today is {day, date, yMMMd}",
+  "discount": "100% off"
+}`
+
+func TestARB(t *testing.T) {
+	a := NewARB("de")
+	if err := a.ParseWithError([]byte(arbFile)); err != nil {
+		t.Fatal(err)
+	}
+
+	if lang := a.GetDomain().GetLanguage(); lang != "en" {
+		t.Errorf("unexpected language %q", lang)
+	}
+
+	for _, c := range []struct {
+		id   string
+		vars []interface{}
+		want string
+	}{
+		{"title", nil, "My <App>"},
+		{"hello", []interface{}{"Paris", "Bob"}, "Hello Bob, welcome to Paris"},
+		{"pronoun", nil, "they"},
+		{"rank", []interface{}{4}, "4th"},
+		{"date", []interface{}{"Oct 25, 2020"}, "Today is Oct 25, 2020"},
+		{"discount", nil, "100% off"},
+	} {
+		if got := a.Get(c.id, c.vars...); got != c.want {
+			t.Errorf("Get(%q) = %q, want %q", c.id, got, c.want)
+		}
+	}
+
+	for n, want := range map[int]string{1: "one wombat", 5: "5 wombats"} {
+		if got := a.GetN("nWombats", "nWombats", n, n); got != want {
+			t.Errorf("GetN(nWombats, %d) = %q, want %q", n, got, want)
+		}
+	}
+	if got := a.GetN("items", "items", 1, 1, "cart"); got != "You have 1 item in cart" {
+		t.Errorf("unexpected plural %q", got)
+	}
+	if got := a.GetC("pronoun", "female"); got != "she" {
+		t.Errorf("unexpected select case %q", got)
+	}
+
+	phs := a.Placeholders("hello")
+	if len(phs) != 2 || phs[0].Name != "place" || phs[0].Example != "Paris" || phs[1].Name != "userName" {
+		t.Errorf("unexpected placeholders %+v", phs)
+	}
+	if phs := a.Placeholders("nWombats"); len(phs) != 1 || phs[0].Format != "compact" || phs[0].Example != "42" {
+		t.Errorf("unexpected placeholders %+v", phs)
+	}
+	if d := a.Description("title"); d != "The app title" {
+		t.Errorf("unexpected description %q", d)
+	}
+}
+
+func TestARBErrors(t *testing.T) {
+	a := NewARB("en")
+	if err := a.ParseWithError([]byte(`{"a": "A"`)); err == nil {
+		t.Error("expected an error for invalid JSON")
+	}
+
+	err := a.ParseWithError([]byte(`{"ok": "OK", "bad": "{n, plural, one{x}}", "num": 1}`))
+	if err == nil || !strings.Contains(err.Error(), "bad: missing other case") || !strings.Contains(err.Error(), "num:") {
+		t.Errorf("unexpected error %v", err)
+	}
+	if got := a.Get("ok"); got != "OK" {
+		t.Errorf("valid messages should be loaded, got %q", got)
+	}
+}
+
+func TestARBMarshalText(t *testing.T) {
+	a := NewARB("en")
+	a.Parse([]byte(arbFile))
+
+	out, err := a.MarshalText()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{
+		`"@@locale": "en",
+  "@@last_modified": "2020-10-25T12:00:00Z",
+  "title": "My <App>",`,
+		`"hello": "Hello {userName}, welcome to {place}",`,
+		`"nWombats": "{count, plural, one{one wombat} other{{count} wombats}}",`,
+		`"pronoun": "{gender, select, female{she} male{he} other{they}}",`,
+		`"format": "compact"`,
+	} {
+		if !strings.Contains(string(out), want) {
+			t.Errorf("expected output to contain %s, got:\n%s", want, out)
+		}
+	}
+
+	// Round trip
+	b := NewARB("en")
+	if err := b.ParseWithError(out); err != nil {
+		t.Fatal(err)
+	}
+	if got := b.Get("hello", "Paris", "Bob"); got != "Hello Bob, welcome to Paris" {
+		t.Errorf("unexpected translation after round trip %q", got)
+	}
+	if got := b.GetN("items", "items", 2, 2, "cart"); got != "You have 2 items in cart" {
+		t.Errorf("unexpected translation after round trip %q", got)
+	}
+}
+
+func TestARBFromPo(t *testing.T) {
+	po := NewPo()
+	po.Parse([]byte(`
+msgid ""
+msgstr ""
+"Language: es\n"
+"Plural-Forms: nplurals=2; plural=(n != 1);\n"
+
+msgid "Hello %s"
+msgstr "Hola %s"
+
+msgid "%d file"
+msgid_plural "%d files"
+msgstr[0] "%d archivo"
+msgstr[1] "%d archivos"
+`))
+
+	a := NewARB("es")
+	po.GetDomain().Iterate(func(ctx, msgid string, tr *Translation) bool {
+		a.GetDomain().translations[msgid] = tr
+		return true
+	})
+
+	out, err := a.MarshalText()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{
+  "@@locale": "es",
+  "%d file": "{arg1, plural, one{{arg1} archivo} other{{arg1} archivos}}",
+  "@%d file": {
+    "placeholders": {
+      "arg1": {}
+    }
+  },
+  "Hello %s": "Hola {arg1}",
+  "@Hello %s": {
+    "placeholders": {
+      "arg1": {}
+    }
+  }
+}
+`
+	if string(out) != want {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+}
+
+func TestARBTranslator(t *testing.T) {
+	var _ Translator = NewARB("en")
+}
@@ -0,0 +1,192 @@
+package gotext
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+/*
+NewLocaleFromArchive creates a Locale that loads its domains from a .zip, .tar, .tar.gz or .tgz bundle
+instead of a directory tree.
+
+The archive follows the same layout as a locales directory (see NewLocale and SetPathResolver),
+optionally nested under a top level directory:
+
+	locales.zip
+	locales.zip/de_DE/LC_MESSAGES/default.mo
+	locales.zip/de_DE/LC_MESSAGES/extras.ftl
+	locales.zip/fr/default.po.gz
+
+Every file of the archive is read into memory, and the archive is closed before returning.
+AddDomain then chooses among them like it does in a directory.
+
+	l, err := gotext.NewLocaleFromArchive("/path/to/locales.zip", "de_DE")
+	if err != nil {
+		return err
+	}
+	l.AddDomain("default")
+*/
+func NewLocaleFromArchive(archive, lang string) (*Locale, error) {
+	l := NewLocale(archive, lang)
+	files := make(map[string][]byte)
+
+	var err error
+	switch name := strings.ToLower(archive); {
+	case strings.HasSuffix(name, ".zip"):
+		err = readZip(archive, files)
+	case strings.HasSuffix(name, ".tar.gz"), strings.HasSuffix(name, ".tgz"):
+		err = readTar(archive, true, files)
+	default:
+		err = readTar(archive, false, files)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	source := &archiveSource{MemorySource: MemorySource{Files: files}, root: archiveRoot(files)}
+	if info, err := os.Stat(archive); err == nil {
+		source.Modified = info.ModTime()
+	}
+	l.source = source
+
+	return l, nil
+}
+
+func readZip(archive string, files map[string][]byte) error {
+	r, err := zip.OpenReader(archive)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	for _, f := range r.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+
+		rc, err := f.Open()
+		if err != nil {
+			return err
+		}
+		data, err := ioutil.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return err
+		}
+		files[archiveName(f.Name)] = data
+	}
+
+	return nil
+}
+
+func readTar(archive string, gzipped bool, files map[string][]byte) error {
+	f, err := os.Open(archive)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+	if gzipped {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		r = gz
+	}
+
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		data, err := ioutil.ReadAll(tr)
+		if err != nil {
+			return err
+		}
+		files[archiveName(hdr.Name)] = data
+	}
+}
+
+// archiveName returns the slash-separated path of an archive file, relative to the archive root.
+func archiveName(name string) string {
+	return path.Clean(strings.TrimPrefix(name, "/"))
+}
+
+// archiveRoot returns the top level directory all files are nested under, or "" if there's none.
+func archiveRoot(files map[string][]byte) string {
+	root := ""
+	for name := range files {
+		i := strings.IndexByte(name, '/')
+		if i == -1 {
+			return ""
+		}
+		if root == "" {
+			root = name[:i]
+		} else if name[:i] != root {
+			return ""
+		}
+	}
+	return root
+}
+
+// archiveSource is the CatalogSource of an archive. Catalogs are looked up from the archive root,
+// and then from the top level directory all files are nested under, if any.
+type archiveSource struct {
+	MemorySource
+	root string
+}
+
+// Open implements CatalogSource
+func (a *archiveSource) Open(name string) (io.ReadCloser, error) {
+	rc, err := a.MemorySource.Open(name)
+	if err != nil && a.root != "" && isNotExist(err) {
+		return a.MemorySource.Open(path.Join(a.root, name))
+	}
+	return rc, err
+}
+
+// List implements CatalogSource. Files under the top level directory are listed with and without it.
+func (a *archiveSource) List(dir string) ([]string, error) {
+	names, err := a.MemorySource.List(dir)
+	if err != nil || a.root == "" {
+		return names, err
+	}
+
+	nested, err := a.MemorySource.List(a.root + "/" + dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range nested {
+		names = append(names, strings.TrimPrefix(name, a.root+"/"))
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// ModTime implements CatalogSource
+func (a *archiveSource) ModTime(name string) (time.Time, error) {
+	t, err := a.MemorySource.ModTime(name)
+	if err != nil && a.root != "" && isNotExist(err) {
+		return a.MemorySource.ModTime(path.Join(a.root, name))
+	}
+	return t, err
+}
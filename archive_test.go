@@ -0,0 +1,112 @@
+package gotext
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+)
+
+func TestNewLocaleFromArchive(t *testing.T) {
+	files := map[string][]byte{
+		"locales/es/LC_MESSAGES/default.ftl": []byte("hello = ¡Hola!\n"),
+		"locales/it/default.po.gz":           gzipData(t, []byte("msgid \"hello\"\nmsgstr \"Ciao\"\n")),
+		"locales/domains/default/pt_BR.po":   []byte("msgid \"hello\"\nmsgstr \"Olá\"\n"),
+		"locales/README.txt":                 []byte("Not a catalog"),
+	}
+	for name, fixture := range map[string]string{
+		"locales/en_US/default.po":             "fixtures/en_US/default.po",
+		"locales/de/LC_MESSAGES/default.mo":    "fixtures/de/default.mo",
+		"locales/fr/LC_MESSAGES/default.po":    "fixtures/fr/LC_MESSAGES/default.po",
+		"locales/de_DE/LC_MESSAGES/default.po": "fixtures/de_DE/LC_MESSAGES/default.po",
+	} {
+		data, err := ioutil.ReadFile(fixture)
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[name] = data
+	}
+
+	dir, err := ioutil.TempDir("", "gotext-archive")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	zipName := path.Join(dir, "locales.zip")
+	zf, err := os.Create(zipName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zw := zip.NewWriter(zf)
+	for name, data := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write(data)
+	}
+	zw.Close()
+	zf.Close()
+
+	tgzName := path.Join(dir, "locales.tar.gz")
+	tf, err := os.Create(tgzName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gw := gzip.NewWriter(tf)
+	tw := tar.NewWriter(gw)
+	for name, data := range files {
+		tw.WriteHeader(&tar.Header{Name: name, Mode: 0600, Size: int64(len(data)), Typeflag: tar.TypeReg})
+		tw.Write(data)
+	}
+	tw.Close()
+	gw.Close()
+	tf.Close()
+
+	for _, archive := range []string{zipName, tgzName} {
+		l, err := NewLocaleFromArchive(archive, "en_US")
+		if err != nil {
+			t.Fatal(err)
+		}
+		l.AddDomain("default")
+		if tr := l.Get("My text"); tr != translatedText {
+			t.Errorf("Expected '%s' from %s but got '%s'", translatedText, archive, tr)
+		}
+		if doms, err := l.domains(); err != nil || len(doms) != 1 || doms[0] != "default" {
+			t.Errorf("Unexpected domains %v, %v from %s", doms, err, archive)
+		}
+
+		// Simplified language lookup, MO file
+		l, err = NewLocaleFromArchive(archive, "de_AT")
+		if err != nil {
+			t.Fatal(err)
+		}
+		l.AddDomain("default")
+		if tr := l.Get("More"); tr != "More translation" {
+			t.Errorf("Expected 'More translation' from %s but got '%s'", archive, tr)
+		}
+
+		// Other formats, compressed catalogs and path resolvers
+		for lang, want := range map[string]string{"es": "¡Hola!", "it": "Ciao", "pt_BR": "Olá"} {
+			l, err = NewLocaleFromArchive(archive, lang)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if lang == "pt_BR" {
+				l.SetPathResolver(Layout("domains/{dom}/{lang}.{ext}"))
+			}
+			l.AddDomain("default")
+			if tr := l.Get("hello"); tr != want {
+				t.Errorf("Expected '%s' for %s from %s but got '%s'", want, lang, archive, tr)
+			}
+		}
+	}
+
+	if _, err := NewLocaleFromArchive(path.Join(dir, "missing.zip"), "en_US"); err == nil {
+		t.Error("Expected an error for a missing archive")
+	}
+}
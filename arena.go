@@ -0,0 +1,142 @@
+package gotext
+
+import (
+	"strings"
+)
+
+// stringSlab lays strings out in a single allocation, keeping one copy of each.
+type stringSlab struct {
+	offsets map[string]int
+	order   []string
+	size    int
+	all     string
+}
+
+// add reserves room for s in the slab.
+func (s *stringSlab) add(str string) {
+	if _, ok := s.offsets[str]; ok || str == "" {
+		return
+	}
+	s.offsets[str] = s.size
+	s.order = append(s.order, str)
+	s.size += len(str)
+}
+
+// build copies the strings added into the slab.
+func (s *stringSlab) build() {
+	var b strings.Builder
+	b.Grow(s.size)
+	for _, str := range s.order {
+		b.WriteString(str)
+	}
+	s.all = b.String()
+	s.order = nil
+}
+
+// get returns the copy of str in the slab, which shares its memory with the rest of the strings.
+func (s *stringSlab) get(str string) string {
+	off, ok := s.offsets[str]
+	if !ok {
+		return str
+	}
+	return s.all[off : off+len(str)]
+}
+
+/*
+Compact moves every string of the Domain (msgids, contexts, translations and references) to a single allocation,
+and its Translation objects to a single slice, instead of keeping one small allocation for each of them.
+Very large catalogs take much less work from the garbage collector afterwards, since it has a few big objects
+to scan instead of millions of small ones. Identical strings are only stored once.
+
+The catalog memory is released as a whole, when no string of the Domain is referenced anymore.
+Translation objects returned before by GetTranslation or Export aren't used by the Domain anymore.
+*/
+func (do *Domain) Compact() {
+	do.trMutex.Lock()
+	defer do.trMutex.Unlock()
+
+	// Indexed entries would be kept alive
+	do.blocks = nil
+
+	slab := &stringSlab{offsets: make(map[string]int)}
+	count := 0
+	addAll := func(trs map[string]*Translation) {
+		for id, tr := range trs {
+			slab.add(id)
+			slab.add(tr.ID)
+			slab.add(tr.PluralID)
+			for _, str := range tr.Trs {
+				slab.add(str)
+			}
+			for _, ref := range tr.Refs {
+				slab.add(ref)
+			}
+			count++
+		}
+	}
+	addAll(do.translations)
+	for ctx, trs := range do.contexts {
+		slab.add(ctx)
+		addAll(trs)
+	}
+	slab.build()
+
+	// Translations are moved to a single slice, remembering where they went for the normalization indexes
+	objs := make([]Translation, 0, count)
+	moved := make(map[*Translation]*Translation, count)
+	compact := func(trs map[string]*Translation) map[string]*Translation {
+		compacted := make(map[string]*Translation, len(trs))
+		for id, tr := range trs {
+			objs = append(objs, Translation{
+				ID:       slab.get(tr.ID),
+				PluralID: slab.get(tr.PluralID),
+				Trs:      make(map[int]string, len(tr.Trs)),
+				Fuzzy:    tr.Fuzzy,
+				File:     slab.get(tr.File),
+				Line:     tr.Line,
+				dirty:    tr.dirty,
+			})
+			c := &objs[len(objs)-1]
+			for i, str := range tr.Trs {
+				c.Trs[i] = slab.get(str)
+			}
+			if tr.Refs != nil {
+				c.Refs = make([]string, len(tr.Refs))
+				for i, ref := range tr.Refs {
+					c.Refs[i] = slab.get(ref)
+				}
+			}
+			compacted[slab.get(id)] = c
+			moved[tr] = c
+		}
+		return compacted
+	}
+
+	do.translations = compact(do.translations)
+	contexts := make(map[string]map[string]*Translation, len(do.contexts))
+	for ctx, trs := range do.contexts {
+		contexts[slab.get(ctx)] = compact(trs)
+	}
+	do.contexts = contexts
+
+	remap := func(index map[string]*Translation) {
+		for key, tr := range index {
+			if c, ok := moved[tr]; ok {
+				index[key] = c
+			}
+		}
+	}
+	remap(do.normalized)
+	remap(do.pluralTranslations)
+	for _, index := range do.normalizedC {
+		remap(index)
+	}
+}
+
+// SetCompaction makes the catalogs loaded afterwards by AddDomain compact their strings, as done by Domain.Compact,
+// which reduces the work of the garbage collector for very large catalogs.
+func (l *Locale) SetCompaction(on bool) {
+	l.Lock()
+	l.compact = on
+	l.Unlock()
+}
@@ -0,0 +1,70 @@
+package gotext
+
+import (
+	"testing"
+)
+
+func TestDomainCompact(t *testing.T) {
+	po := NewPo()
+	po.Parse([]byte(lookupPo + `
+#: main.go:10
+msgid "Bye"
+msgstr "Adiós"
+`))
+	do := po.GetDomain()
+	do.SetNormalization(NormalizeSpace)
+	do.Compact()
+
+	// Every string lives in the same allocation
+	hello := do.translations["Hello"]
+	start, end := stringData(hello.ID), stringData(hello.ID)+uintptr(len(hello.ID))
+	for id, tr := range do.translations {
+		for _, str := range append([]string{id, tr.ID, tr.Trs[0]}, tr.Refs...) {
+			if str == "" {
+				continue
+			}
+			if p := stringData(str); p < start {
+				start = p
+			} else if p+uintptr(len(str)) > end {
+				end = p + uintptr(len(str))
+			}
+		}
+	}
+	if size := end - start; size > 200 {
+		t.Errorf("Expected the strings to be contiguous, got them spread over %d bytes", size)
+	}
+
+	file, files, bye := "%d file", "%d files", "Bye"
+	if tr := po.GetN(file, files, 2, 2); tr != "2 archivos" {
+		t.Errorf("Unexpected translation %q", tr)
+	}
+	if tr := po.GetC("Open", "menu"); tr != "Abrir" {
+		t.Errorf("Unexpected translation %q", tr)
+	}
+	if tr := po.Get(" Bye  "); tr != "Adiós" {
+		t.Errorf("Expected the normalization index to follow the compaction, got %q", tr)
+	}
+	if tr, _ := do.GetTranslation(bye); len(tr.Refs) != 1 || tr.Refs[0] != "main.go:10" {
+		t.Errorf("Unexpected references %v", tr.Refs)
+	}
+}
+
+func TestLocaleSetCompaction(t *testing.T) {
+	l := NewLocale("", "es")
+	l.SetCompaction(true)
+	if err := l.AddDomainBytes("default", []byte(lookupPo), FormatPO); err != nil {
+		t.Fatal(err)
+	}
+
+	do := l.Domains["default"].GetDomain()
+	id, str := stringData(do.translations["Hello"].ID), stringData(do.translations["Hello"].Trs[0])
+	if id > str {
+		id, str = str, id
+	}
+	if str-id > 200 {
+		t.Error("Expected the catalog strings to be compacted")
+	}
+	if tr := l.Get("Hello"); tr != "Hola" {
+		t.Errorf("Unexpected translation %q", tr)
+	}
+}
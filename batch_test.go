@@ -0,0 +1,57 @@
+package gotext
+
+import (
+	"testing"
+)
+
+func TestLocaleGetMany(t *testing.T) {
+	l := NewLocale("", "es")
+	if err := l.AddDomainBytes("default", []byte(lookupPo), FormatPO); err != nil {
+		t.Fatal(err)
+	}
+	other := []byte("msgid \"Hello\"\nmsgstr \"Buenas\"\n")
+	if err := l.AddDomainBytes("other", other, FormatPO); err != nil {
+		t.Fatal(err)
+	}
+
+	msgs := []Message{
+		{MsgID: "Hello"},
+		{MsgID: "%d file", Plural: "%d files", N: 1, Vars: []interface{}{1}},
+		{MsgID: "%d file", Plural: "%d files", N: 4, Vars: []interface{}{4}},
+		{MsgID: "Open", Context: "menu"},
+		{MsgID: "Hello", Domain: "other"},
+		{MsgID: "Missing %s", Vars: []interface{}{"x"}},
+		{MsgID: "%d thing", Plural: "%d things", N: 2, Domain: "unknown", Vars: []interface{}{2}},
+	}
+	want := []string{"Hola", "1 archivo", "4 archivos", "Abrir", "Buenas", "Missing x", "2 things"}
+
+	got := l.GetMany(msgs)
+	if len(got) != len(want) {
+		t.Fatalf("expected %d translations, got %d", len(want), len(got))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("message %d: got %q, want %q", i, got[i], want[i])
+		}
+	}
+
+	// Same as one call per message
+	for i, m := range msgs {
+		var single string
+		dom := m.Domain
+		if dom == "" {
+			dom = l.GetDomain()
+		}
+		switch {
+		case m.Plural != "":
+			single = l.GetND(dom, m.MsgID, m.Plural, m.N, m.Vars...)
+		case m.Context != "":
+			single = l.GetDC(dom, m.MsgID, m.Context, m.Vars...)
+		default:
+			single = l.GetD(dom, m.MsgID, m.Vars...)
+		}
+		if single != got[i] {
+			t.Errorf("message %d: GetMany returned %q, single call %q", i, got[i], single)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+/*
+Package benchmarks measures the performance of gotext: parsing PO and MO catalogs, the Get functions on hits,
+misses and with vars, concurrent lookups, and marshaling. It has no API besides the generated catalogs
+its benchmarks use, so they can be run against any release:
+
+	go test -run='^$' -bench=. -benchmem -count=10 ./benchmarks > new.txt
+
+Compare the results with the ones of a previous release with benchstat (golang.org/x/perf/cmd/benchstat):
+
+	git stash && go test -run='^$' -bench=. -benchmem -count=10 ./benchmarks > old.txt && git stash pop
+	benchstat old.txt new.txt
+
+Its tests also check that the lookup hot paths don't allocate more than they used to,
+so a regression fails "go test ./..." without running the benchmarks.
+*/
+package benchmarks
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/leonelquinteros/gotext"
+)
+
+// Sizes of the generated catalogs
+const (
+	Small = 100
+	Large = 10000
+)
+
+// Catalog returns a PO catalog in German with n entries. Every 10th entry has plural forms,
+// every 7th has a context, and every 5th has a placeholder.
+func Catalog(n int) []byte {
+	var b strings.Builder
+	b.WriteString("msgid \"\"\nmsgstr \"\"\n")
+	b.WriteString("\"Language: de\\n\"\n")
+	b.WriteString("\"Content-Type: text/plain; charset=UTF-8\\n\"\n")
+	b.WriteString("\"Plural-Forms: nplurals=2; plural=(n != 1);\\n\"\n")
+
+	for i := 0; i < n; i++ {
+		b.WriteString("\n")
+		fmt.Fprintf(&b, "#: src/file%d.go:%d\n", i%50, i)
+		if i%7 == 0 {
+			fmt.Fprintf(&b, "msgctxt \"context %d\"\n", i%3)
+		}
+		switch {
+		case i%10 == 0:
+			fmt.Fprintf(&b, "msgid \"%s\"\nmsgid_plural \"%s\"\n", MsgID(i), Plural(i))
+			fmt.Fprintf(&b, "msgstr[0] \"%%d Datei %d\"\nmsgstr[1] \"%%d Dateien %d\"\n", i, i)
+		case i%5 == 0:
+			fmt.Fprintf(&b, "msgid \"%s\"\nmsgstr \"Nachricht %d für %%s\"\n", MsgID(i), i)
+		default:
+			fmt.Fprintf(&b, "msgid \"%s\"\nmsgstr \"Nachricht %d\"\n", MsgID(i), i)
+		}
+	}
+	return []byte(b.String())
+}
+
+// MsgID returns the msgid of the entry i of the catalogs returned by Catalog.
+func MsgID(i int) string {
+	switch {
+	case i%10 == 0:
+		return fmt.Sprintf("%%d file %d", i)
+	case i%5 == 0:
+		return fmt.Sprintf("Message %d for %%s", i)
+	}
+	return fmt.Sprintf("Message number %d", i)
+}
+
+// Plural returns the msgid_plural of the entry i of the catalogs returned by Catalog, if it has one.
+func Plural(i int) string {
+	if i%10 != 0 {
+		return ""
+	}
+	return fmt.Sprintf("%%d files %d", i)
+}
+
+// MO returns the catalog returned by Catalog compiled to the MO format.
+func MO(n int) []byte {
+	po := gotext.NewPo()
+	po.Parse(Catalog(n))
+	data, err := gotext.Convert(po, gotext.FormatMO)
+	if err != nil {
+		panic(err)
+	}
+	return data
+}
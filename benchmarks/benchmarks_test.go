@@ -0,0 +1,250 @@
+package benchmarks
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/leonelquinteros/gotext"
+)
+
+// Entries looked up by the benchmarks: a singular one, one with a placeholder, a plural one and one with a context
+const (
+	singular    = 1
+	placeholder = 5
+	plural      = 10
+	withContext = 14
+	context     = "context 2"
+)
+
+func newPo(tb testing.TB, n int) *gotext.Po {
+	po, err := gotext.NewPoFromString(string(Catalog(n)))
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return po
+}
+
+func newLocale(tb testing.TB, n int) *gotext.Locale {
+	l := gotext.NewLocale("", "de")
+	if err := l.AddDomainBytes("default", Catalog(n), gotext.FormatPO); err != nil {
+		tb.Fatal(err)
+	}
+	return l
+}
+
+func TestCatalog(t *testing.T) {
+	po := newPo(t, Small)
+	for _, c := range []struct{ got, want string }{
+		{po.Get(MsgID(singular)), "Nachricht 1"},
+		{po.Get(MsgID(placeholder), "Ana"), "Nachricht 5 für Ana"},
+		{po.GetN(MsgID(plural), Plural(plural), 3, 3), "3 Dateien 10"},
+		{po.GetC(MsgID(withContext), context), "Nachricht 14"},
+	} {
+		if c.got != c.want {
+			t.Errorf("expected %q, got %q", c.want, c.got)
+		}
+	}
+
+	mo := gotext.NewMo()
+	mo.Parse(MO(Small))
+	if got := mo.Get(MsgID(singular)); got != "Nachricht 1" {
+		t.Errorf("unexpected MO translation %q", got)
+	}
+}
+
+// TestLookupAllocs fails when the lookup hot paths allocate more than they used to.
+func TestLookupAllocs(t *testing.T) {
+	po := newPo(t, Large)
+	l := newLocale(t, Large)
+	hit, miss := MsgID(singular), "Missing message"
+	one, other := MsgID(plural), Plural(plural)
+
+	for _, c := range []struct {
+		name string
+		max  float64
+		f    func()
+	}{
+		{"Po.Get hit", 0, func() { po.Get(hit) }},
+		{"Po.Get miss", 0, func() { po.Get(miss) }},
+		{"Po.GetN hit", 0, func() { po.GetN(one, other, 1) }},
+		{"Locale.GetD hit", 0, func() { l.GetD("default", hit) }},
+	} {
+		if allocs := testing.AllocsPerRun(100, c.f); allocs > c.max {
+			t.Errorf("%s: %v allocations, expected at most %v", c.name, allocs, c.max)
+		}
+	}
+}
+
+func BenchmarkParsePO(b *testing.B) {
+	for _, n := range []int{Small, Large} {
+		data := Catalog(n)
+		b.Run(strconv.Itoa(n), func(b *testing.B) {
+			b.SetBytes(int64(len(data)))
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				po := gotext.NewPo()
+				po.Parse(data)
+			}
+		})
+	}
+}
+
+func BenchmarkParseMO(b *testing.B) {
+	for _, n := range []int{Small, Large} {
+		data := MO(n)
+		b.Run(strconv.Itoa(n), func(b *testing.B) {
+			b.SetBytes(int64(len(data)))
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				mo := gotext.NewMo()
+				mo.Parse(data)
+			}
+		})
+	}
+}
+
+func BenchmarkGet(b *testing.B) {
+	po := newPo(b, Large)
+	hit, miss, withVars := MsgID(singular), "Missing message", MsgID(placeholder)
+
+	b.Run("hit", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			po.Get(hit)
+		}
+	})
+	b.Run("miss", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			po.Get(miss)
+		}
+	})
+	b.Run("vars", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			po.Get(withVars, "Ana")
+		}
+	})
+	b.Run("context", func(b *testing.B) {
+		ctxID := MsgID(withContext)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			po.GetC(ctxID, context)
+		}
+	})
+}
+
+func BenchmarkGetN(b *testing.B) {
+	po := newPo(b, Large)
+	one, other := MsgID(plural), Plural(plural)
+
+	b.Run("hit", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			po.GetN(one, other, i%5)
+		}
+	})
+	b.Run("miss", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			po.GetN("Missing message", "Missing messages", i%5)
+		}
+	})
+	b.Run("vars", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			po.GetN(one, other, i%5, i%5)
+		}
+	})
+}
+
+func BenchmarkLocaleGet(b *testing.B) {
+	l := newLocale(b, Large)
+	hit, miss := MsgID(singular), "Missing message"
+
+	b.Run("hit", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			l.GetD("default", hit)
+		}
+	})
+	b.Run("miss", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			l.GetD("default", miss)
+		}
+	})
+	b.Run("caller domain", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			l.Get(hit)
+		}
+	})
+}
+
+func BenchmarkParallelGet(b *testing.B) {
+	po := newPo(b, Large)
+	l := newLocale(b, Large)
+	ids := make([]string, 100)
+	for i := range ids {
+		ids[i] = MsgID(i)
+	}
+
+	b.Run("Po", func(b *testing.B) {
+		b.ReportAllocs()
+		b.RunParallel(func(pb *testing.PB) {
+			for i := 0; pb.Next(); i++ {
+				po.Get(ids[i%len(ids)])
+			}
+		})
+	})
+	b.Run("Locale", func(b *testing.B) {
+		b.ReportAllocs()
+		b.RunParallel(func(pb *testing.PB) {
+			for i := 0; pb.Next(); i++ {
+				l.GetD("default", ids[i%len(ids)])
+			}
+		})
+	})
+}
+
+func BenchmarkMarshal(b *testing.B) {
+	po := newPo(b, Large)
+	data, err := po.MarshalBinary()
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.Run("binary", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := po.MarshalBinary(); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("unmarshal binary", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if err := gotext.NewPo().UnmarshalBinary(data); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("text", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := po.MarshalText(); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("mo", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := po.GetDomain().MarshalMO(); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -0,0 +1,63 @@
+package gotext
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Unicode bidi isolation marks
+const (
+	// FSI (First Strong Isolate) starts a run of text whose direction is taken from its first strong character.
+	FSI = "\u2068"
+	// PDI (Pop Directional Isolate) ends the run started by FSI.
+	PDI = "\u2069"
+)
+
+// Isolate wraps s with bidi isolation marks (FSI and PDI), so its direction doesn't affect the surrounding text.
+// It's useful to insert user provided values, which may be in any script, in translated strings.
+func Isolate(s string) string {
+	return FSI + s + PDI
+}
+
+// isolated formats its value with any verb, wrapped with bidi isolation marks.
+type isolated struct {
+	v interface{}
+}
+
+// Format implements fmt.Formatter.
+func (i isolated) Format(f fmt.State, verb rune) {
+	format := "%"
+	for _, flag := range "+-# 0" {
+		if f.Flag(int(flag)) {
+			format += string(flag)
+		}
+	}
+	if width, ok := f.Width(); ok {
+		format += strconv.Itoa(width)
+	}
+	if prec, ok := f.Precision(); ok {
+		format += "." + strconv.Itoa(prec)
+	}
+	format += string(verb)
+
+	fmt.Fprint(f, FSI)
+	fmt.Fprintf(f, format, i.v)
+	fmt.Fprint(f, PDI)
+}
+
+// IsolateVars returns vars wrapped so they're formatted between bidi isolation marks when the Locale language
+// is written from right to left, preventing left to right values, like usernames, from garbling the translation.
+// Values keep their formatting, so they can be used with any verb. vars are returned as is for other languages.
+//
+//	l.Get("%s added a comment", l.IsolateVars(username)...)
+func (l *Locale) IsolateVars(vars ...interface{}) []interface{} {
+	if !l.IsRTL() {
+		return vars
+	}
+
+	wrapped := make([]interface{}, len(vars))
+	for i, v := range vars {
+		wrapped[i] = isolated{v}
+	}
+	return wrapped
+}
@@ -0,0 +1,29 @@
+package gotext
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestIsolate(t *testing.T) {
+	if got := Isolate("Bob"); got != "\u2068Bob\u2069" {
+		t.Errorf("unexpected result %q", got)
+	}
+}
+
+func TestLocaleIsolateVars(t *testing.T) {
+	ar := NewLocale("fixtures/", "ar")
+	vars := ar.IsolateVars("Bob", 3.14159, 42)
+
+	if got := fmt.Sprintf("%s أضاف", vars[0]); got != FSI+"Bob"+PDI+" أضاف" {
+		t.Errorf("unexpected result %q", got)
+	}
+	if got := fmt.Sprintf("%.2f|%-4d|%q", vars[1], vars[2], vars[0]); got != FSI+"3.14"+PDI+"|"+FSI+"42  "+PDI+"|"+FSI+`"Bob"`+PDI {
+		t.Errorf("unexpected result %q", got)
+	}
+
+	en := NewLocale("fixtures/", "en_US")
+	if got := fmt.Sprintf("%s", en.IsolateVars("Bob")...); got != "Bob" {
+		t.Errorf("vars shouldn't be wrapped for LTR languages, got %q", got)
+	}
+}
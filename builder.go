@@ -0,0 +1,113 @@
+package gotext
+
+/*
+DomainBuilder builds catalogs in code, mostly for test fixtures, without writing PO files:
+
+	do := gotext.NewDomainBuilder().
+		Language("es").
+		PluralForms("nplurals=2; plural=(n != 1);").
+		Add("Hello", "Hola").
+		AddN("%d cat", "%d cats", []string{"%d gato", "%d gatos"}).
+		AddC("May", "month", "Mayo").
+		Build()
+
+Entries added again replace the previous ones.
+*/
+type DomainBuilder struct {
+	do *Domain
+}
+
+// NewDomainBuilder returns a builder for an empty catalog, using the Germanic plural rule unless told otherwise.
+func NewDomainBuilder() *DomainBuilder {
+	return &DomainBuilder{do: NewDomain()}
+}
+
+// Language sets the catalog language, and its Language header.
+func (b *DomainBuilder) Language(lang string) *DomainBuilder {
+	b.do.SetLanguage(lang)
+	return b
+}
+
+// PluralForms sets the Plural-Forms header and the plural rule of the catalog.
+// It panics if pf is invalid, as fixtures are expected to be right.
+func (b *DomainBuilder) PluralForms(pf string) *DomainBuilder {
+	if err := b.do.SetPluralForms(pf); err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// Header sets the catalog header key. See Domain.SetHeader. It panics on an invalid Plural-Forms value.
+func (b *DomainBuilder) Header(key, value string) *DomainBuilder {
+	if err := b.do.SetHeader(key, value); err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// Add adds the entry msgid, translated as msgstr. An empty msgstr adds an untranslated entry.
+func (b *DomainBuilder) Add(msgid, msgstr string) *DomainBuilder {
+	return b.add("", msgid, "", []string{msgstr})
+}
+
+// AddN adds the plural entry msgid, with the msgid_plural plural, translated with the plural forms msgstrs in order.
+func (b *DomainBuilder) AddN(msgid, plural string, msgstrs []string) *DomainBuilder {
+	return b.add("", msgid, plural, msgstrs)
+}
+
+// AddC adds the entry msgid in the context ctx, translated as msgstr.
+func (b *DomainBuilder) AddC(msgid, ctx, msgstr string) *DomainBuilder {
+	return b.add(ctx, msgid, "", []string{msgstr})
+}
+
+// AddNC adds the plural entry msgid in the context ctx, translated with the plural forms msgstrs in order.
+func (b *DomainBuilder) AddNC(msgid, plural, ctx string, msgstrs []string) *DomainBuilder {
+	return b.add(ctx, msgid, plural, msgstrs)
+}
+
+// Fuzzy marks the entry msgid in the context ctx, or out of any context when ctx is empty, as fuzzy.
+// It panics if the entry wasn't added.
+func (b *DomainBuilder) Fuzzy(ctx, msgid string) *DomainBuilder {
+	if err := b.do.SetFuzzy(ctx, msgid, true); err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func (b *DomainBuilder) add(ctx, msgid, plural string, msgstrs []string) *DomainBuilder {
+	tr := NewTranslation()
+	tr.ID = msgid
+	tr.PluralID = plural
+	for i, str := range msgstrs {
+		tr.SetN(i, str)
+	}
+
+	b.do.trMutex.Lock()
+	defer b.do.trMutex.Unlock()
+
+	translations := b.do.translations
+	if ctx != "" {
+		if _, ok := b.do.contexts[ctx]; !ok {
+			b.do.contexts[ctx] = make(map[string]*Translation)
+		}
+		translations = b.do.contexts[ctx]
+	}
+	translations[msgid] = tr
+	return b
+}
+
+// Build returns the catalog. The builder shouldn't be used afterwards.
+func (b *DomainBuilder) Build() *Domain {
+	return b.do
+}
+
+// BuildPo returns the catalog as a Po object, to be added to a Locale with AddTranslator.
+// The builder shouldn't be used afterwards.
+func (b *DomainBuilder) BuildPo() *Po {
+	return &Po{
+		Headers:     b.do.Headers,
+		Language:    b.do.Language,
+		PluralForms: b.do.PluralForms,
+		domain:      b.do,
+	}
+}
@@ -0,0 +1,64 @@
+package gotext
+
+import (
+	"testing"
+)
+
+func TestDomainBuilder(t *testing.T) {
+	do := NewDomainBuilder().
+		Language("es").
+		PluralForms("nplurals=2; plural=(n != 1);").
+		Header("Project-Id-Version", "fixtures").
+		Add("Hello", "Hola").
+		Add("Untranslated", "").
+		AddN("%d cat", "%d cats", []string{"%d gato", "%d gatos"}).
+		AddC("May", "month", "Mayo").
+		AddNC("%d day", "%d days", "duration", []string{"%d día", "%d días"}).
+		Fuzzy("month", "May").
+		Build()
+
+	if do.GetLanguage() != "es" || do.GetNPlurals() != 2 || do.Header("Project-Id-Version") != "fixtures" {
+		t.Errorf("Unexpected headers %v", do.Headers)
+	}
+	if tr := do.Get("Hello"); tr != "Hola" {
+		t.Errorf("Expected 'Hola' but got '%s'", tr)
+	}
+	if tr := do.Get("Untranslated"); tr != "Untranslated" {
+		t.Errorf("Expected 'Untranslated' but got '%s'", tr)
+	}
+	if tr := do.GetN("%d cat", "%d cats", 3, 3); tr != "3 gatos" {
+		t.Errorf("Expected '3 gatos' but got '%s'", tr)
+	}
+	if tr := do.GetC("May", "month"); tr != "Mayo" {
+		t.Errorf("Expected 'Mayo' but got '%s'", tr)
+	}
+	if tr := do.GetNC("%d day", "%d days", 1, "duration", 1); tr != "1 día" {
+		t.Errorf("Expected '1 día' but got '%s'", tr)
+	}
+
+	entries := do.entries()
+	if len(entries) != 5 {
+		t.Fatalf("Expected 5 entries, got %d", len(entries))
+	}
+	for _, e := range entries {
+		if fuzzy := e.Context == "month"; e.Translation.Fuzzy != fuzzy {
+			t.Errorf("Unexpected fuzzy flag of %q", e.MsgID)
+		}
+	}
+
+	// Po objects can be added to a Locale
+	l := NewLocale("", "es")
+	l.AddTranslator("default", NewDomainBuilder().Add("Bye", "Adiós").BuildPo())
+	if tr := l.Get("Bye"); tr != "Adiós" {
+		t.Errorf("Expected 'Adiós' but got '%s'", tr)
+	}
+}
+
+func TestDomainBuilderPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected a panic for an invalid Plural-Forms header")
+		}
+	}()
+	NewDomainBuilder().PluralForms("nplurals=2; plural=(n !=")
+}
@@ -0,0 +1,96 @@
+package gotext
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"hash/crc32"
+)
+
+// CacheVersion is the version of the binary cache format written by Locale.MarshalBinary.
+// It changes whenever the layout does, so older binaries refuse newer caches instead of misreading them.
+const CacheVersion = 1
+
+// cacheMagic starts every binary cache.
+const cacheMagic = "GTXC"
+
+var (
+	// ErrCacheVersion is returned when loading a binary cache written in an unsupported format version.
+	ErrCacheVersion = errors.New("gotext: unsupported cache version")
+	// ErrCacheChecksum is returned when loading a binary cache that is truncated or corrupt.
+	ErrCacheChecksum = errors.New("gotext: cache checksum mismatch")
+)
+
+/*
+encodeCache writes obj in the binary cache format:
+
+	magic     "GTXC"
+	version   uint16, big endian
+	length    uint32, big endian, of the payload
+	payload   the Gob encoded LocaleEncoding, with the SHA-256 of every domain
+	checksum  uint32, big endian, CRC-32 (IEEE) of everything before it
+*/
+func encodeCache(obj *LocaleEncoding) ([]byte, error) {
+	obj.Checksums = make(map[string][]byte, len(obj.Domains))
+	for name, data := range obj.Domains {
+		sum := sha256.Sum256(data)
+		obj.Checksums[name] = sum[:]
+	}
+
+	var payload bytes.Buffer
+	if err := gob.NewEncoder(&payload).Encode(obj); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(cacheMagic)
+	binary.Write(&buf, binary.BigEndian, uint16(CacheVersion))
+	binary.Write(&buf, binary.BigEndian, uint32(payload.Len()))
+	buf.Write(payload.Bytes())
+	binary.Write(&buf, binary.BigEndian, crc32.ChecksumIEEE(buf.Bytes()))
+
+	return buf.Bytes(), nil
+}
+
+// decodeCache reads a binary cache written by encodeCache, validating its version and checksums.
+// Caches written before the format was versioned, plain Gob encoded LocaleEncoding values, are read too.
+func decodeCache(data []byte) (*LocaleEncoding, error) {
+	obj := new(LocaleEncoding)
+	if !bytes.HasPrefix(data, []byte(cacheMagic)) {
+		if err := gob.NewDecoder(bytes.NewReader(data)).Decode(obj); err != nil {
+			return nil, err
+		}
+		return obj, nil
+	}
+
+	const headerSize = len(cacheMagic) + 2 + 4
+	if len(data) < headerSize+4 {
+		return nil, fmt.Errorf("%w: truncated header", ErrCacheChecksum)
+	}
+	if v := binary.BigEndian.Uint16(data[len(cacheMagic):]); v != CacheVersion {
+		return nil, fmt.Errorf("%w %d", ErrCacheVersion, v)
+	}
+	size := binary.BigEndian.Uint32(data[len(cacheMagic)+2:])
+	if uint64(len(data)) != uint64(headerSize)+uint64(size)+4 {
+		return nil, fmt.Errorf("%w: cache is %d bytes, expected %d", ErrCacheChecksum, len(data), uint64(headerSize)+uint64(size)+4)
+	}
+	end := headerSize + int(size)
+	if crc32.ChecksumIEEE(data[:end]) != binary.BigEndian.Uint32(data[end:]) {
+		return nil, ErrCacheChecksum
+	}
+
+	if err := gob.NewDecoder(bytes.NewReader(data[headerSize:end])).Decode(obj); err != nil {
+		return nil, err
+	}
+	for name, domain := range obj.Domains {
+		sum := sha256.Sum256(domain)
+		if !bytes.Equal(sum[:], obj.Checksums[name]) {
+			return nil, fmt.Errorf("%w in domain %q", ErrCacheChecksum, name)
+		}
+	}
+
+	return obj, nil
+}
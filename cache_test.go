@@ -0,0 +1,96 @@
+package gotext
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+	"hash/crc32"
+	"testing"
+)
+
+func TestLocaleCache(t *testing.T) {
+	l := NewLocale("fixtures/", "en_US")
+	l.AddDomain("default")
+
+	data, err := l.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(data, []byte("GTXC\x00\x01")) {
+		t.Fatalf("unexpected header %q", data[:6])
+	}
+
+	l2 := new(Locale)
+	if err := l2.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if l2.Get("My text") != l.Get("My text") {
+		t.Errorf("unexpected translation %q", l2.Get("My text"))
+	}
+
+	// Corrupt payload
+	bad := append([]byte(nil), data...)
+	bad[len(bad)/2] ^= 0xff
+	if err := l2.UnmarshalBinary(bad); !errors.Is(err, ErrCacheChecksum) {
+		t.Errorf("expected a checksum error, got %v", err)
+	}
+
+	// Truncated
+	if err := l2.UnmarshalBinary(data[:len(data)-10]); !errors.Is(err, ErrCacheChecksum) {
+		t.Errorf("expected a checksum error, got %v", err)
+	}
+
+	// Newer version
+	newer := append([]byte(nil), data...)
+	binary.BigEndian.PutUint16(newer[4:], CacheVersion+1)
+	if err := l2.UnmarshalBinary(newer); !errors.Is(err, ErrCacheVersion) {
+		t.Errorf("expected a version error, got %v", err)
+	}
+
+	// The Locale is unchanged on error
+	if l2.Get("My text") != l.Get("My text") {
+		t.Error("the Locale shouldn't change when loading fails")
+	}
+}
+
+func TestLocaleCacheDomainChecksum(t *testing.T) {
+	obj := &LocaleEncoding{Lang: "en", Domains: map[string][]byte{"default": []byte("data")}}
+	data, err := encodeCache(obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := decodeCache(data); err != nil {
+		t.Fatal(err)
+	}
+
+	obj.Checksums["default"][0] ^= 0xff
+	var payload bytes.Buffer
+	gob.NewEncoder(&payload).Encode(obj)
+	data = append([]byte("GTXC\x00\x01"), 0, 0, 0, 0)
+	binary.BigEndian.PutUint32(data[6:], uint32(payload.Len()))
+	data = append(data, payload.Bytes()...)
+	data = append(data, 0, 0, 0, 0)
+	binary.BigEndian.PutUint32(data[len(data)-4:], crc32.ChecksumIEEE(data[:len(data)-4]))
+
+	if _, err := decodeCache(data); !errors.Is(err, ErrCacheChecksum) {
+		t.Errorf("expected a checksum error, got %v", err)
+	}
+}
+
+func TestLocaleCacheLegacy(t *testing.T) {
+	l := NewLocale("fixtures/", "en_US")
+	l.AddDomain("default")
+
+	dom, _ := l.Domains["default"].MarshalBinary()
+	var buf bytes.Buffer
+	gob.NewEncoder(&buf).Encode(&LocaleEncoding{Lang: "en_US", DefaultDomain: "default", Domains: map[string][]byte{"default": dom}})
+
+	l2 := new(Locale)
+	if err := l2.UnmarshalBinary(buf.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+	if l2.Get("My text") != l.Get("My text") {
+		t.Errorf("unexpected translation %q", l2.Get("My text"))
+	}
+}
@@ -0,0 +1,98 @@
+package gotext
+
+import (
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// packageDomains maps Go package paths to the domain used by calls from those packages
+var packageDomains = struct {
+	sync.RWMutex
+	m map[string]string
+}{}
+
+// callerPackages caches the package path resolved for each caller program counter
+var callerPackages sync.Map
+
+// RegisterPackageDomain makes calls that don't name a domain (Get, GetN, GetC, GetNC, both at package level
+// and on Locale objects) use the domain dom when they come from the package pkgPath or any package below it.
+// When several registered paths match, the longest one wins.
+//
+// Registering a mapping is what enables the caller lookup: as long as no mapping exists,
+// the configured default domain is used without inspecting the call stack.
+//
+//	gotext.RegisterPackageDomain("github.com/acme/shop/checkout", "checkout")
+func RegisterPackageDomain(pkgPath, dom string) {
+	packageDomains.Lock()
+	if packageDomains.m == nil {
+		packageDomains.m = make(map[string]string)
+	}
+	packageDomains.m[strings.TrimSuffix(pkgPath, "/")] = dom
+	packageDomains.Unlock()
+}
+
+// UnregisterPackageDomain removes a mapping added by RegisterPackageDomain.
+func UnregisterPackageDomain(pkgPath string) {
+	packageDomains.Lock()
+	delete(packageDomains.m, strings.TrimSuffix(pkgPath, "/"))
+	packageDomains.Unlock()
+}
+
+// callerDomain returns the domain registered for the package of the function that called
+// the public API method, or def if there is none.
+// skip is the number of stack frames between the public API method and callerDomain.
+func callerDomain(skip int, def string) string {
+	packageDomains.RLock()
+	defer packageDomains.RUnlock()
+
+	if len(packageDomains.m) == 0 {
+		return def
+	}
+
+	pc, _, _, ok := runtime.Caller(skip + 2)
+	if !ok {
+		return def
+	}
+
+	pkg := callerPackage(pc)
+	for pkg != "" {
+		if dom, ok := packageDomains.m[pkg]; ok {
+			return dom
+		}
+
+		idx := strings.LastIndex(pkg, "/")
+		if idx == -1 {
+			break
+		}
+		pkg = pkg[:idx]
+	}
+
+	return def
+}
+
+// callerPackage returns the package path of the function containing pc.
+func callerPackage(pc uintptr) string {
+	if pkg, ok := callerPackages.Load(pc); ok {
+		return pkg.(string)
+	}
+
+	fn := runtime.FuncForPC(pc)
+	if fn == nil {
+		return ""
+	}
+
+	// Function names look like "github.com/acme/shop/checkout.(*Cart).Total"
+	name := fn.Name()
+	dir := ""
+	if idx := strings.LastIndex(name, "/"); idx != -1 {
+		dir, name = name[:idx+1], name[idx+1:]
+	}
+	if idx := strings.Index(name, "."); idx != -1 {
+		name = name[:idx]
+	}
+
+	pkg := dir + name
+	callerPackages.Store(pc, pkg)
+	return pkg
+}
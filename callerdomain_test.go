@@ -0,0 +1,56 @@
+package gotext
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCallerPackage(t *testing.T) {
+	pc := reflect.ValueOf(TestCallerPackage).Pointer()
+	if pkg := callerPackage(pc); pkg != "github.com/leonelquinteros/gotext" {
+		t.Errorf("Expected 'github.com/leonelquinteros/gotext' but got '%s'", pkg)
+	}
+
+	pc = reflect.ValueOf((*Locale).Get).Pointer()
+	if pkg := callerPackage(pc); pkg != "github.com/leonelquinteros/gotext" {
+		t.Errorf("Expected 'github.com/leonelquinteros/gotext' but got '%s'", pkg)
+	}
+}
+
+func TestRegisterPackageDomain(t *testing.T) {
+	l := NewLocale("fixtures/", "en_US")
+
+	def := NewPo()
+	def.Parse([]byte(`msgid "Pay now"
+msgstr "Pay now (default)"`))
+	l.AddTranslator("default", def)
+
+	checkout := NewPo()
+	checkout.Parse([]byte(`msgid "Pay now"
+msgstr "Pay now (checkout)"`))
+	l.AddTranslator("checkout", checkout)
+
+	if tr := l.Get("Pay now"); tr != "Pay now (default)" {
+		t.Errorf("Expected 'Pay now (default)' but got '%s'", tr)
+	}
+
+	// Parent path match
+	RegisterPackageDomain("github.com/leonelquinteros", "other")
+	RegisterPackageDomain("github.com/leonelquinteros/gotext/", "checkout")
+	defer UnregisterPackageDomain("github.com/leonelquinteros")
+	defer UnregisterPackageDomain("github.com/leonelquinteros/gotext")
+
+	if tr := l.Get("Pay now"); tr != "Pay now (checkout)" {
+		t.Errorf("Expected 'Pay now (checkout)' but got '%s'", tr)
+	}
+
+	// Explicit domains aren't affected
+	if tr := l.GetD("default", "Pay now"); tr != "Pay now (default)" {
+		t.Errorf("Expected 'Pay now (default)' but got '%s'", tr)
+	}
+
+	UnregisterPackageDomain("github.com/leonelquinteros/gotext")
+	if tr := l.Get("Pay now"); tr != "Pay now" {
+		t.Errorf("Expected untranslated 'Pay now' from the missing 'other' domain but got '%s'", tr)
+	}
+}
@@ -0,0 +1,76 @@
+package gotext
+
+import (
+	"github.com/razor-1/localizer"
+	"golang.org/x/text/feature/plural"
+	"golang.org/x/text/message"
+	"golang.org/x/text/message/catalog"
+)
+
+// Catalog returns a golang.org/x/text/message/catalog.Catalog holding the translations of all loaded domains,
+// keyed by msgid, so gettext catalogs can back x/text message printers.
+//
+// Plural translations become plural.Selectf messages selected by the first argument,
+// with msgstr[i] mapped to the i-th CLDR cardinal category of the Locale language.
+// Entries with a msgctxt are not included, as x/text catalogs have no notion of context.
+// When the same msgid exists in several domains, the default domain wins.
+func (l *Locale) Catalog() catalog.Catalog {
+	b := catalog.NewBuilder(catalog.Fallback(l.tag))
+
+	l.RLock()
+	defer l.RUnlock()
+
+	add := func(tr Translator) {
+		do, err := messageDomain(tr)
+		if err != nil {
+			logWarn("gotext: catalog skipped", "lang", l.lang, "err", err)
+			return
+		}
+		do.addToCatalog(b, l)
+	}
+	for name, tr := range l.Domains {
+		if tr != nil && name != l.defaultDomain {
+			add(tr)
+		}
+	}
+	if tr, ok := l.Domains[l.defaultDomain]; ok && tr != nil {
+		add(tr)
+	}
+
+	return b
+}
+
+// Printer returns a golang.org/x/text/message.Printer for the Locale language, backed by Catalog().
+// It allows mixing gotext catalogs with x/text formatting features:
+//
+//	p := l.Printer()
+//	p.Printf("You have %d new messages", n)
+func (l *Locale) Printer() *message.Printer {
+	return message.NewPrinter(l.tag, message.Catalog(l.Catalog()))
+}
+
+// addToCatalog adds every context-less translation of the domain to the catalog builder, under the Locale language.
+func (do *Domain) addToCatalog(b *catalog.Builder, l *Locale) {
+	var forms []plural.Form
+	if lcData, err := localizer.GetLocaleData(l.tag); err == nil && lcData != nil {
+		forms = lcData.Plural.Cardinal.Forms
+	}
+
+	for _, e := range do.entries() {
+		if e.Context != "" {
+			continue
+		}
+
+		tr := e.Translation
+		if tr.PluralID == "" || len(forms) == 0 {
+			b.SetString(l.tag, e.MsgID, tr.Get())
+			continue
+		}
+
+		cases := make([]interface{}, 0, 2*len(forms))
+		for i, form := range forms {
+			cases = append(cases, cldrCategory(form), tr.GetN(i))
+		}
+		b.Set(l.tag, e.MsgID, plural.Selectf(1, "", cases...))
+	}
+}
@@ -0,0 +1,65 @@
+package gotext
+
+import (
+	"testing"
+)
+
+func TestLocalePrinter(t *testing.T) {
+	l := NewLocale("fixtures/", "en_US")
+	po := NewPo()
+	po.Parse([]byte(`
+msgid ""
+msgstr ""
+"Language: en\n"
+"Plural-Forms: nplurals=2; plural=(n != 1);\n"
+
+msgid "Hello %s"
+msgstr "Hi %s"
+
+msgid "%d file"
+msgid_plural "%d files"
+msgstr[0] "%d document"
+msgstr[1] "%d documents"
+
+msgctxt "menu"
+msgid "Open"
+msgstr "Open file"
+`))
+	l.AddTranslator("default", po)
+
+	cat := l.Catalog()
+	if len(cat.Languages()) != 1 {
+		t.Errorf("Expected 1 catalog language but got %d", len(cat.Languages()))
+	}
+
+	p := l.Printer()
+	if s := p.Sprintf("Hello %s", "John"); s != "Hi John" {
+		t.Errorf("Expected 'Hi John' but got '%s'", s)
+	}
+	if s := p.Sprintf("%d file", 1); s != "1 document" {
+		t.Errorf("Expected '1 document' but got '%s'", s)
+	}
+	if s := p.Sprintf("%d file", 3); s != "3 documents" {
+		t.Errorf("Expected '3 documents' but got '%s'", s)
+	}
+	if s := p.Sprintf("Open"); s != "Open" {
+		t.Errorf("Expected context entries to be skipped but got '%s'", s)
+	}
+}
+
+func TestLocaleCatalogLoadedMessages(t *testing.T) {
+	indexed := NewIndexedMo(0)
+	indexed.ParseFile("fixtures/en_US/default.mo")
+	l := NewLocale("fixtures/", "en_US")
+	l.AddTranslator("default", indexed)
+
+	lazy := NewLocale("fixtures/", "en_US")
+	lazy.SetLazyLoading(true)
+	lazy.AddDomain("default")
+
+	for _, l := range []*Locale{l, lazy} {
+		if s := l.Printer().Sprintf("language"); s != "en_US" {
+			t.Errorf("Expected 'en_US' but got '%s'", s)
+		}
+	}
+}
@@ -0,0 +1,201 @@
+package gotext
+
+import (
+	"bytes"
+	"errors"
+	"time"
+)
+
+// catalogParser holds the Locale settings used to parse its catalogs, so they can be parsed later without the Locale lock.
+type catalogParser struct {
+	lang       string
+	policy     PluralPolicy
+	onMismatch func(dom string, m *PluralMismatch)
+	fallback   PluralFallback
+	hook       func(dom string, e PluralOutOfRange)
+	compact    bool
+	pool       *InternPool
+	values     bool
+	norm       Normalization
+	positional PositionalMode
+	percent    PercentPolicy
+	fuzzy      float64
+	missing    func(dom string, m MissingTranslation)
+	onDemand   bool
+	moCache    int
+	duplicates DuplicatePolicy
+
+	// PO catalog being reloaded, whose unchanged entries are reused. See Po.reparse.
+	previous *Po
+}
+
+// catalogParser returns the current catalog settings of the Locale.
+func (l *Locale) catalogParser() catalogParser {
+	l.RLock()
+	defer l.RUnlock()
+
+	return catalogParser{
+		lang:       l.lang,
+		policy:     l.pluralPolicy,
+		onMismatch: l.onPluralMismatch,
+		fallback:   l.pluralFallback,
+		hook:       l.pluralHook,
+		compact:    l.compact,
+		pool:       l.internPool,
+		values:     l.internValues,
+		norm:       l.normalization,
+		positional: l.positionalMode,
+		percent:    l.percentPolicy,
+		fuzzy:      l.fuzzyThreshold,
+		missing:    l.missingHook,
+		onDemand:   l.moOnDemand,
+		moCache:    l.moCache,
+		duplicates: l.duplicatePolicy,
+	}
+}
+
+// parseCatalog parses the catalog data of the domain dom with the plural policy and fallback of the Locale.
+// ext tells the catalog format: the extension of its file, or the name of its Format.
+func (l *Locale) parseCatalog(dom, ext string, data []byte) (Translator, error) {
+	return l.catalogParser().parse(dom, ext, "", data)
+}
+
+// parse parses the catalog data of the domain dom, read from the file named file, if any. See Locale.parseCatalog.
+// When the format loads what it can of a malformed catalog, like PO and Fluent do, the catalog is returned
+// along with the ParseErrors found, so callers can choose whether to use it.
+func (p catalogParser) parse(dom, ext, file string, data []byte) (tr Translator, err error) {
+	start := time.Now()
+	defer func() {
+		metrics().Parse(p.lang, dom, ext, time.Since(start), err)
+		if err != nil {
+			logWarn("gotext: catalog parse failed", "lang", p.lang, "domain", dom, "format", ext, "err", err)
+		}
+	}()
+
+	switch ext {
+	case "mo":
+		if p.onDemand {
+			tr = NewIndexedMo(p.moCache)
+		} else {
+			tr = NewMo()
+		}
+	case "ftl":
+		tr = NewFluent(p.lang)
+	case "arb":
+		tr = NewARB(p.lang)
+	case "properties":
+		tr = NewProperties(p.lang)
+	case "yml":
+		tr = NewYAML(p.lang)
+	default:
+		tr = NewPo()
+	}
+
+	p.setup(dom, tr.GetDomain())
+
+	switch ext {
+	case "json":
+		err = tr.GetDomain().ImportJSON(bytes.NewReader(data))
+	case "xliff":
+		err = tr.GetDomain().ImportXLIFF(bytes.NewReader(data))
+	case "csv":
+		err = tr.GetDomain().ImportCSV(bytes.NewReader(data))
+	default:
+		err = p.parseData(tr, data, file)
+	}
+	var syntax ParseErrors
+	if errors.As(err, &syntax) {
+		err = nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if m := tr.GetDomain().PluralMismatch(); m != nil {
+		logWarn("gotext: Plural-Forms header disagrees with CLDR", "lang", p.lang, "domain", dom, "err", m)
+		if p.onMismatch != nil {
+			p.onMismatch(dom, m)
+		}
+		if p.policy == PluralError {
+			return nil, m
+		}
+	}
+
+	if dups := tr.GetDomain().Duplicates(); len(dups) > 0 && p.duplicates == DuplicateError {
+		return nil, dups[0]
+	}
+
+	if err = tr.GetDomain().ApplyPercentPolicy(p.percent); err != nil {
+		return nil, err
+	}
+
+	p.finish(dom, tr.GetDomain())
+	if len(syntax) > 0 {
+		return tr, syntax
+	}
+	return tr, nil
+}
+
+// parseData parses data into tr, returning the syntax errors found by the ParseWithError method of the format.
+// Formats without one, like Java properties, accept any data.
+func (p catalogParser) parseData(tr Translator, data []byte, file string) error {
+	switch tr := tr.(type) {
+	case *Po:
+		var syntax ParseErrors
+		if p.previous != nil {
+			syntax = tr.reparse(p.previous, data, file)
+		} else {
+			syntax = tr.parse(data, file)
+		}
+
+		// Duplicate entries are handled by the DuplicatePolicy
+		var errs ParseErrors
+		for _, err := range syntax {
+			if !err.duplicate {
+				errs = append(errs, err)
+			}
+		}
+		if len(errs) > 0 {
+			return errs
+		}
+		return nil
+	case interface{ ParseWithError([]byte) error }:
+		return tr.ParseWithError(data)
+	}
+	tr.Parse(data)
+	return nil
+}
+
+// setup applies the settings used while parsing to do, the catalog of the domain dom.
+func (p catalogParser) setup(dom string, do *Domain) {
+	do.SetPluralPolicy(p.policy)
+	do.SetDuplicatePolicy(p.duplicates)
+	do.setMetricsLabels(p.lang, dom)
+	if p.hook != nil {
+		do.SetPluralFallback(p.fallback, func(e PluralOutOfRange) {
+			p.hook(dom, e)
+		})
+	} else {
+		do.SetPluralFallback(p.fallback, nil)
+	}
+}
+
+// finish applies the settings used after parsing to do, the catalog of the domain dom.
+func (p catalogParser) finish(dom string, do *Domain) {
+	do.RewritePositional(p.positional)
+	if p.compact {
+		do.Compact()
+	}
+	if p.pool != nil {
+		do.Intern(p.pool, p.values)
+	}
+	if p.norm != 0 {
+		do.SetNormalization(p.norm)
+	}
+	do.SetFuzzyMatch(p.fuzzy)
+	if p.missing != nil {
+		do.SetMissingHook(func(m MissingTranslation) {
+			p.missing(dom, m)
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package gotext
+
+import (
+	"fmt"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/ianaindex"
+	"golang.org/x/text/encoding/unicode"
+)
+
+/*
+SetOutputCharset makes the Locale return its translations in the given charset, like bind_textdomain_codeset
+does in GNU gettext, for terminals and protocols that can't handle UTF-8:
+
+	l.SetOutputCharset("ISO-8859-1")
+
+The charset is any IANA name or alias, like "latin1" or "windows-1252". Characters the charset can't encode
+are replaced by its substitution character, SUB (\x1a) for single byte charsets.
+Strings are transcoded after formatting, so the vars inserted are too.
+An empty charset, or UTF-8, restores the default UTF-8 output.
+It returns an error if the charset is unknown, leaving the output charset unchanged.
+*/
+func (l *Locale) SetOutputCharset(charset string) error {
+	var enc encoding.Encoding
+	if charset != "" {
+		var err error
+		if enc, err = ianaindex.IANA.Encoding(charset); err != nil {
+			return fmt.Errorf("gotext: unknown charset %q: %w", charset, err)
+		}
+		if enc == nil {
+			return fmt.Errorf("gotext: unsupported charset %q", charset)
+		}
+		if enc == unicode.UTF8 {
+			enc = nil
+		}
+	}
+
+	l.Lock()
+	l.charset = enc
+	l.Unlock()
+	return nil
+}
+
+// encodeOutput transcodes str to the output charset of the Locale, if any. The Locale must be locked.
+func (l *Locale) encodeOutput(str string) string {
+	if l.charset == nil {
+		return str
+	}
+	out, err := encoding.ReplaceUnsupported(l.charset.NewEncoder()).String(str)
+	if err != nil {
+		return str
+	}
+	return out
+}
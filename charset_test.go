@@ -0,0 +1,54 @@
+package gotext
+
+import (
+	"testing"
+)
+
+func TestLocaleSetOutputCharset(t *testing.T) {
+	l := NewLocale("", "es")
+	po := []byte("msgid \"Yes\"\nmsgstr \"Sí\"\n\nmsgid \"Price %s\"\nmsgstr \"Precio %s\"\n")
+	if err := l.AddDomainBytes("default", po, FormatPO); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := l.SetOutputCharset("ISO-8859-1"); err != nil {
+		t.Fatal(err)
+	}
+	yes, price := "Yes", "Price %s"
+	if got := l.Get(yes); got != "S\xed" {
+		t.Errorf("unexpected Latin-1 output %q", got)
+	}
+	// Vars are transcoded, and characters out of the charset replaced
+	if got := l.Get(price, "5 €"); got != "Precio 5 \x1a" {
+		t.Errorf("unexpected Latin-1 output %q", got)
+	}
+	if got, _ := l.Lookup(yes); got != "S\xed" {
+		t.Errorf("unexpected Latin-1 lookup %q", got)
+	}
+	if got := l.GetMany([]Message{{MsgID: yes}}); got[0] != "S\xed" {
+		t.Errorf("unexpected Latin-1 batch %q", got[0])
+	}
+
+	if err := l.SetOutputCharset("windows-1252"); err != nil {
+		t.Fatal(err)
+	}
+	if got := l.Get(price, "5 €"); got != "Precio 5 \x80" {
+		t.Errorf("unexpected Windows-1252 output %q", got)
+	}
+
+	if err := l.SetOutputCharset("no-such-charset"); err == nil {
+		t.Error("expected an error for an unknown charset")
+	}
+	if got := l.Get(yes); got != "S\xed" {
+		t.Error("unknown charsets shouldn't change the output charset")
+	}
+
+	for _, utf8 := range []string{"UTF-8", ""} {
+		if err := l.SetOutputCharset(utf8); err != nil {
+			t.Fatal(err)
+		}
+		if got := l.Get(yes); got != "Sí" {
+			t.Errorf("%q: unexpected output %q", utf8, got)
+		}
+	}
+}
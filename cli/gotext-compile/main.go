@@ -0,0 +1,250 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"flag"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/leonelquinteros/gotext"
+)
+
+var (
+	outFile       = flag.String("o", "catalogs.go", "output file: /path/to/catalogs.go")
+	pkgName       = flag.String("pkg", "", "package of the output file (default $GOPACKAGE, set by go generate, or main)")
+	defaultDomain = flag.String("default", "default", "Name of default domain")
+	compress      = flag.Bool("gzip", false, "embed the catalogs gzip compressed, to be decompressed at init")
+	check         = flag.Bool("check", true, "fail when translations don't use the placeholders of their source strings")
+)
+
+func init() {
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] /path/to/locales\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+}
+
+func main() {
+	flag.Parse()
+
+	// Init logger
+	log.SetFlags(0)
+
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	dir := flag.Arg(0)
+
+	catalogs, err := compile(dir)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if len(catalogs) == 0 {
+		log.Fatalf("%s: no catalogs found", dir)
+	}
+
+	pkg := *pkgName
+	if pkg == "" {
+		pkg = os.Getenv("GOPACKAGE")
+	}
+	if pkg == "" {
+		pkg = "main"
+	}
+
+	src, err := generate(pkg, dir, catalogs)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := ioutil.WriteFile(*outFile, src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// catalog is a compiled catalog, in MO format
+type catalog struct {
+	lang, dom string
+	path      string
+	data      []byte
+}
+
+// compile parses and validates the .po and .mo catalogs under dir, laid out like "de/LC_MESSAGES/default.po"
+// or "de/default.po", and returns them in MO format, sorted by language and domain.
+func compile(dir string) ([]catalog, error) {
+	var catalogs []catalog
+	seen := make(map[string]string)
+	failed := false
+
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		ext := strings.ToLower(filepath.Ext(path))
+		if info.IsDir() || (ext != ".po" && ext != ".mo") {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		parts := strings.Split(filepath.ToSlash(rel), "/")
+		if len(parts) < 2 {
+			log.Printf("%s: skipped, not in a language directory", path)
+			return nil
+		}
+		c := catalog{
+			lang: parts[0],
+			dom:  strings.TrimSuffix(parts[len(parts)-1], filepath.Ext(path)),
+			path: filepath.ToSlash(rel),
+		}
+
+		key := c.lang + "/" + c.dom
+		if other, ok := seen[key]; ok {
+			return fmt.Errorf("%s: domain %q of %q already compiled from %s", path, c.dom, c.lang, other)
+		}
+		seen[key] = path
+
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		tr, err := parse(ext, data)
+		if err != nil {
+			log.Printf("%s: %v", path, err)
+			failed = true
+			return nil
+		}
+		if *check {
+			for _, issue := range gotext.ValidateDomain(tr.GetDomain()) {
+				log.Printf("%s: %s", path, issue)
+				failed = true
+			}
+		}
+
+		if c.data, err = tr.GetDomain().MarshalMO(); err != nil {
+			return fmt.Errorf("%s: %v", path, err)
+		}
+		catalogs = append(catalogs, c)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if failed {
+		return nil, fmt.Errorf("%s: invalid catalogs", dir)
+	}
+
+	sort.Slice(catalogs, func(i, j int) bool {
+		if catalogs[i].lang != catalogs[j].lang {
+			return catalogs[i].lang < catalogs[j].lang
+		}
+		return catalogs[i].dom < catalogs[j].dom
+	})
+	return catalogs, nil
+}
+
+// parse reads the catalog data in the format of the file extension ext
+func parse(ext string, data []byte) (gotext.Translator, error) {
+	if ext == ".mo" {
+		mo := gotext.NewMo()
+		return mo, mo.ParseWithError(data)
+	}
+	po := gotext.NewPo()
+	return po, po.ParseWithError(data)
+}
+
+// generate returns the Go source of the package pkg embedding the catalogs, formatted.
+func generate(pkg, dir string, catalogs []catalog) ([]byte, error) {
+	var b bytes.Buffer
+
+	fmt.Fprintf(&b, "// Code generated by gotext-compile from %s. DO NOT EDIT.\n\n", filepath.ToSlash(dir))
+	fmt.Fprintf(&b, "package %s\n\n", pkg)
+	b.WriteString("import (\n")
+	if *compress {
+		b.WriteString("\t\"bytes\"\n\t\"compress/gzip\"\n\t\"io/ioutil\"\n\n")
+	}
+	b.WriteString("\t\"github.com/leonelquinteros/gotext\"\n\t\"golang.org/x/text/language\"\n)\n\n")
+
+	encoding := "MO format"
+	if *compress {
+		encoding = "gzip compressed MO format"
+	}
+	fmt.Fprintf(&b, "// catalogs holds the compiled catalogs, in %s, by language and domain\n", encoding)
+	b.WriteString("var catalogs = []struct{ lang, dom, data string }{\n")
+	for _, c := range catalogs {
+		data := c.data
+		if *compress {
+			var err error
+			if data, err = gzipped(data); err != nil {
+				return nil, err
+			}
+		}
+		fmt.Fprintf(&b, "\t// %s\n\t{%q, %q, %s},\n", c.path, c.lang, c.dom, strconv.Quote(string(data)))
+	}
+	b.WriteString("}\n\n")
+
+	b.WriteString(`// Locales holds the Locale of each compiled language, also registered with gotext.Register
+var Locales = make(map[string]*gotext.Locale)
+
+func init() {
+	for _, c := range catalogs {
+		l, ok := Locales[c.lang]
+		if !ok {
+			l = gotext.NewLocale("", c.lang)
+			Locales[c.lang] = l
+			gotext.Register(language.Make(c.lang), l)
+		}
+`)
+	if *compress {
+		b.WriteString(`
+		r, err := gzip.NewReader(bytes.NewReader([]byte(c.data)))
+		if err != nil {
+			panic(err)
+		}
+		data, err := ioutil.ReadAll(r)
+		if err != nil {
+			panic(err)
+		}
+		if err := l.AddDomainBytes(c.dom, data, gotext.FormatMO); err != nil {
+			panic(err)
+		}
+	}
+`)
+	} else {
+		b.WriteString(`		if err := l.AddDomainBytes(c.dom, []byte(c.data), gotext.FormatMO); err != nil {
+			panic(err)
+		}
+	}
+`)
+	}
+	fmt.Fprintf(&b, `
+	for _, l := range Locales {
+		l.SetDomain(%q)
+	}
+}
+`, *defaultDomain)
+
+	return format.Source(b.Bytes())
+}
+
+// gzipped returns data gzip compressed
+func gzipped(data []byte) ([]byte, error) {
+	var b bytes.Buffer
+	w := gzip.NewWriter(&b)
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/leonelquinteros/gotext"
+)
+
+var (
+	inFile   = flag.String("in", "", "input file: /path/to/catalog.po")
+	outFile  = flag.String("out", "", "output file, or stdout if empty: /path/to/catalog.mo")
+	toFormat = flag.String("to", "", "output format: po, mo, json, xliff or csv (default from the output file extension)")
+	lang     = flag.String("lang", "", "language of the input, for formats without a language header (ftl, arb, properties, yml)")
+)
+
+func main() {
+	flag.Parse()
+
+	// Init logger
+	log.SetFlags(0)
+
+	if *inFile == "" {
+		log.Fatal("No input file given")
+	}
+
+	name := *toFormat
+	if name == "" {
+		if *outFile == "" {
+			log.Fatal("No output format given")
+		}
+		name = filepath.Ext(*outFile)
+	}
+	format, err := gotext.ParseFormat(name)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	src, err := load(*inFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	out, err := gotext.Convert(src, format)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if *outFile == "" {
+		_, err = os.Stdout.Write(out)
+	} else {
+		err = ioutil.WriteFile(*outFile, out, 0644)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// load reads the catalog in path, in the format given by its extension.
+func load(path string) (gotext.Translator, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".po", ".pot":
+		po := gotext.NewPo()
+		return po, po.ParseWithError(data)
+	case ".mo":
+		mo := gotext.NewMo()
+		return mo, mo.ParseWithError(data)
+	case ".json":
+		po := gotext.NewPo()
+		return po, po.GetDomain().ImportJSON(bytes.NewReader(data))
+	case ".xliff", ".xlf":
+		po := gotext.NewPo()
+		return po, po.GetDomain().ImportXLIFF(bytes.NewReader(data))
+	case ".csv":
+		po := gotext.NewPo()
+		return po, po.GetDomain().ImportCSV(bytes.NewReader(data))
+	case ".ftl":
+		ftl := gotext.NewFluent(*lang)
+		return ftl, ftl.ParseWithError(data)
+	case ".arb":
+		arb := gotext.NewARB(*lang)
+		return arb, arb.ParseWithError(data)
+	case ".properties":
+		p := gotext.NewProperties(*lang)
+		p.Parse(data)
+		return p, nil
+	case ".yml", ".yaml":
+		y := gotext.NewYAML(*lang)
+		return y, y.ParseWithError(data)
+	default:
+		return nil, fmt.Errorf("unknown input format %q", ext)
+	}
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+
+	"github.com/leonelquinteros/gotext"
+)
+
+var (
+	format   = flag.String("format", "text", "output format: text, json or sarif")
+	warnings = flag.Bool("warnings", true, "report warnings, besides errors")
+	strict   = flag.Bool("strict", false, "exit with status 1 on warnings too")
+)
+
+func init() {
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] path...\n", os.Args[0])
+		fmt.Fprintln(flag.CommandLine.Output(), "Paths are .po/.pot files or directories, like a locales tree.")
+		flag.PrintDefaults()
+	}
+}
+
+func main() {
+	flag.Parse()
+
+	// Init logger
+	log.SetFlags(0)
+
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	var issues []gotext.CatalogIssue
+	for _, path := range flag.Args() {
+		found, err := lint(path)
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, issue := range found {
+			if *warnings || issue.Severity == gotext.LintError {
+				issues = append(issues, issue)
+			}
+		}
+	}
+
+	var err error
+	switch *format {
+	case "text":
+		for _, issue := range issues {
+			fmt.Println(issue)
+		}
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if issues == nil {
+			issues = []gotext.CatalogIssue{}
+		}
+		err = enc.Encode(issues)
+	case "sarif":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(sarifLog(issues))
+	default:
+		log.Fatalf("Unknown output format %q", *format)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for _, issue := range issues {
+		if issue.Severity == gotext.LintError || *strict {
+			os.Exit(1)
+		}
+	}
+}
+
+// lint checks the catalog file or the catalogs of the directory at path.
+func lint(path string) ([]gotext.CatalogIssue, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return gotext.LintTree(path)
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return gotext.LintCatalog(path, data), nil
+}
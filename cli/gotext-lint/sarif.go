@@ -0,0 +1,105 @@
+package main
+
+import (
+	"path/filepath"
+
+	"github.com/leonelquinteros/gotext"
+)
+
+// Minimal SARIF 2.1.0 log, as read by code scanning tools.
+type sarif struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID string `json:"id"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifact `json:"artifactLocation"`
+	Region           *sarifRegion  `json:"region,omitempty"`
+}
+
+type sarifArtifact struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+var lintRules = []string{
+	gotext.LintRuleSyntax,
+	gotext.LintRuleDuplicate,
+	gotext.LintRuleHeader,
+	gotext.LintRuleEncoding,
+	gotext.LintRulePlaceholder,
+	gotext.LintRulePlural,
+}
+
+// sarifLog converts the issues to a SARIF log.
+func sarifLog(issues []gotext.CatalogIssue) sarif {
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           "gotext-lint",
+			InformationURI: "https://github.com/leonelquinteros/gotext",
+		}},
+		Results: []sarifResult{},
+	}
+	for _, rule := range lintRules {
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{ID: rule})
+	}
+
+	for _, issue := range issues {
+		loc := sarifLocation{PhysicalLocation: sarifPhysicalLocation{
+			ArtifactLocation: sarifArtifact{URI: filepath.ToSlash(issue.File)},
+		}}
+		if issue.Line > 0 {
+			loc.PhysicalLocation.Region = &sarifRegion{StartLine: issue.Line}
+		}
+		run.Results = append(run.Results, sarifResult{
+			RuleID:    issue.Rule,
+			Level:     issue.Severity.String(),
+			Message:   sarifMessage{Text: issue.Message},
+			Locations: []sarifLocation{loc},
+		})
+	}
+
+	return sarif{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{run},
+	}
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/leonelquinteros/gotext"
+)
+
+var (
+	outFile    = flag.String("o", "messages.mo", "output file: /path/to/catalog.mo")
+	check      bool
+	strictCLDR = flag.Bool("cldr", false, "report Plural-Forms headers disagreeing with the CLDR rules of the language as problems, instead of warnings")
+	progress   = flag.Bool("progress", false, "print the parse progress to stderr, for very large catalogs")
+)
+
+func init() {
+	flag.BoolVar(&check, "check", false, "check the header, plural forms and format strings, and exit with status 1 on problems")
+	flag.BoolVar(&check, "c", false, "shorthand for -check")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] input.po\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+}
+
+func main() {
+	flag.Parse()
+
+	// Init logger
+	log.SetFlags(0)
+
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	inFile := flag.Arg(0)
+
+	data, err := ioutil.ReadFile(inFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	po := gotext.NewPo()
+	if *progress {
+		po.SetProgressFunc(func(p gotext.ParseProgress) {
+			percent := int64(100)
+			if p.TotalBytes > 0 {
+				percent = p.BytesProcessed * 100 / p.TotalBytes
+			}
+			fmt.Fprintf(os.Stderr, "\r%s: %3d%% parsed, %d entries", inFile, percent, p.Entries)
+			if p.BytesProcessed == p.TotalBytes {
+				fmt.Fprintln(os.Stderr)
+			}
+		})
+	}
+	parseErr := po.ParseWithError(data)
+	if parseErr != nil && !check {
+		log.Fatalf("%s: %v", inFile, parseErr)
+	}
+
+	if check {
+		problems, warnings := checkCatalog(po, parseErr)
+		for _, w := range warnings {
+			log.Printf("%s: warning: %s", inFile, w)
+		}
+		for _, p := range problems {
+			log.Printf("%s: %s", inFile, p)
+		}
+		if len(problems) > 0 {
+			log.Fatalf("%s: %d problems found", inFile, len(problems))
+		}
+	}
+
+	mo, err := po.GetDomain().MarshalMO()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := ioutil.WriteFile(*outFile, mo, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// requiredHeaders are the header fields msgfmt --check requires.
+var requiredHeaders = []string{"MIME-Version", "Content-Type", "Content-Transfer-Encoding"}
+
+// checkCatalog returns the problems found in the parsed catalog, like msgfmt --check does,
+// and the warnings that don't make the check fail.
+func checkCatalog(po *gotext.Po, parseErr error) (problems, warnings []string) {
+	if errs, ok := parseErr.(gotext.ParseErrors); ok {
+		for _, err := range errs {
+			problems = append(problems, strings.TrimPrefix(err.Error(), "gotext: "))
+		}
+	} else if parseErr != nil {
+		problems = append(problems, parseErr.Error())
+	}
+
+	do := po.GetDomain()
+
+	// Header
+	for _, key := range requiredHeaders {
+		if do.Header(key) == "" {
+			problems = append(problems, fmt.Sprintf("header field %q missing", key))
+		}
+	}
+	if do.Header("Language") == "" {
+		warnings = append(warnings, "header field \"Language\" missing, plural forms can't be checked against CLDR")
+	}
+	if ct := do.Header("Content-Type"); ct != "" {
+		if i := strings.Index(ct, "charset="); i == -1 {
+			problems = append(problems, "charset missing in header field \"Content-Type\"")
+		} else if charset := strings.TrimSpace(ct[i+len("charset="):]); charset == "CHARSET" {
+			problems = append(problems, "charset \"CHARSET\" is not a portable encoding name")
+		}
+	}
+
+	// Plural forms
+	hasPlurals := false
+	do.Iterate(func(ctx, msgid string, tr *gotext.Translation) bool {
+		if tr.PluralID != "" {
+			hasPlurals = true
+			return false
+		}
+		return true
+	})
+
+	pf := do.Header("Plural-Forms")
+	nplurals := 0
+	switch {
+	case pf == "" && hasPlurals:
+		problems = append(problems, "message catalog has plural form translations, but lacks a \"Plural-Forms\" header field")
+	case pf != "":
+		if err := gotext.NewDomain().SetPluralForms(pf); err != nil {
+			problems = append(problems, fmt.Sprintf("invalid \"Plural-Forms\" header field: %s", strings.TrimPrefix(err.Error(), "gotext: ")))
+		} else {
+			nplurals = do.GetNPlurals()
+		}
+	}
+
+	if nplurals > 0 {
+		do.Iterate(func(ctx, msgid string, tr *gotext.Translation) bool {
+			if tr.PluralID == "" || !tr.IsTranslated() {
+				return true
+			}
+			if len(tr.Trs) != nplurals {
+				problems = append(problems, fmt.Sprintf("%s: %d plural forms, but \"Plural-Forms\" declares %d", entryName(ctx, msgid), len(tr.Trs), nplurals))
+			}
+			return true
+		})
+
+		if m := do.PluralMismatch(); m != nil {
+			msg := strings.TrimPrefix(m.Error(), "gotext: ")
+			if *strictCLDR {
+				problems = append(problems, msg)
+			} else {
+				warnings = append(warnings, msg)
+			}
+		}
+	}
+
+	// Format strings
+	for _, issue := range gotext.ValidateDomain(do) {
+		problems = append(problems, issue.String())
+	}
+
+	return problems, warnings
+}
+
+// entryName returns how a message is named in the reports.
+func entryName(ctx, msgid string) string {
+	if ctx != "" {
+		return fmt.Sprintf("msgctxt %q msgid %q", ctx, msgid)
+	}
+	return fmt.Sprintf("msgid %q", msgid)
+}
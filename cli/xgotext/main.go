@@ -14,6 +14,8 @@ var (
 	defaultDomain = flag.String("default", "default", "Name of default domain")
 	excludeDirs   = flag.String("exclude", ".git", "Comma separated list of directories to exclude")
 	verbose       = flag.Bool("v", false, "print currently handled directory")
+	templateExt   = flag.String("template-ext", ".gohtml,.gotmpl,.tmpl", "Comma separated list of template file extensions")
+	templateFuncs = flag.String("template-funcs", "T=Get,TN=GetN", "Comma separated list of template functions, with the getter whose arguments they take")
 )
 
 func main() {
@@ -29,11 +31,18 @@ func main() {
 		log.Fatal("No output directory given")
 	}
 
+	funcs, err := parser.ParseTemplateFuncs(*templateFuncs)
+	if err != nil {
+		log.Fatal(err)
+	}
+	parser.TemplateFuncs = funcs
+	parser.TemplateExtensions = strings.Split(*templateExt, ",")
+
 	data := &parser.DomainMap{
 		Default: *defaultDomain,
 	}
 
-	err := parser.ParseDirRec(*dirName, strings.Split(*excludeDirs, ","), data, *verbose)
+	err = parser.ParseDirRec(*dirName, strings.Split(*excludeDirs, ","), data, *verbose)
 	if err != nil {
 		log.Fatal(err)
 	}
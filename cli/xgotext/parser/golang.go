@@ -45,6 +45,23 @@ var gotextGetter = map[string]GetterDef{
 	"GetNC":  {0, 1, 3, -1},
 	"GetDC":  {1, -1, 2, 0},
 	"GetNDC": {1, 2, 4, 0},
+
+	"GetOrdinal":  {0, -1, -1, -1},
+	"GetOrdinalD": {1, -1, -1, 0},
+	"GetRange":    {0, 1, -1, -1},
+	"GetRangeD":   {1, 2, -1, 0},
+
+	"GetCtx":    {1, -1, -1, -1},
+	"GetNCtx":   {1, 2, -1, -1},
+	"GetDCtx":   {2, -1, -1, 1},
+	"GetNDCtx":  {2, 3, -1, 1},
+	"GetCCtx":   {1, -1, 2, -1},
+	"GetNCCtx":  {1, 2, 4, -1},
+	"GetDCCtx":  {2, -1, 3, 1},
+	"GetNDCCtx": {2, 3, 5, 1},
+
+	"Errorf":  {0, -1, -1, -1},
+	"ErrorfD": {1, -1, -1, 0},
 }
 
 // register go parser
@@ -0,0 +1,271 @@
+package parser
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template/parse"
+)
+
+// TemplateExtensions lists the extensions of the files parsed as html/template or text/template files
+var TemplateExtensions = []string{".gohtml", ".gotmpl", ".tmpl"}
+
+// TemplateFuncs maps the names of the template functions to extract to the getter whose arguments they take,
+// so {{TN "One file" "%d files" .Count}} is extracted like GetN
+var TemplateFuncs = map[string]GetterDef{
+	"T":  gotextGetter["Get"],
+	"TN": gotextGetter["GetN"],
+}
+
+// ParseTemplateFuncs parses a comma separated list of template function names, each optionally followed by
+// the getter whose arguments it takes, like "T=Get,TN=GetN,TC=GetC". Names without getter take the arguments
+// of the getter with the same name, like "GetD".
+func ParseTemplateFuncs(list string) (map[string]GetterDef, error) {
+	funcs := make(map[string]GetterDef)
+	for _, item := range strings.Split(list, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		name, getter := item, item
+		if i := strings.Index(item, "="); i != -1 {
+			name, getter = strings.TrimSpace(item[:i]), strings.TrimSpace(item[i+1:])
+		}
+
+		def, ok := gotextGetter[getter]
+		if !ok {
+			return nil, fmt.Errorf("unknown getter %q for template function %q", getter, name)
+		}
+		funcs[name] = def
+	}
+	return funcs, nil
+}
+
+// register template parser
+func init() {
+	AddParser(templateParser)
+}
+
+// parse the template files of a directory
+func templateParser(dirPath, basePath string, data *DomainMap) error {
+	files, err := ioutil.ReadDir(dirPath)
+	if err != nil {
+		return err
+	}
+
+	for _, info := range files {
+		if info.IsDir() || !isTemplate(info.Name()) {
+			continue
+		}
+
+		filePath := filepath.Join(dirPath, info.Name())
+		text, err := ioutil.ReadFile(filePath)
+		if err != nil {
+			return err
+		}
+
+		path, _ := filepath.Rel(basePath, filePath)
+		file := TemplateFile{
+			filePath: path,
+			text:     string(text),
+			data:     data,
+		}
+		if err := file.parse(); err != nil {
+			// not a template, or one using other delimiters
+			log.Printf("ERR: Unsupported template %s (%s)", path, err)
+		}
+	}
+	return nil
+}
+
+// isTemplate reports whether the file name has one of the TemplateExtensions
+func isTemplate(name string) bool {
+	ext := filepath.Ext(name)
+	for _, e := range TemplateExtensions {
+		if ext == e {
+			return true
+		}
+	}
+	return false
+}
+
+// TemplateFile handles the parsing of one template file
+type TemplateFile struct {
+	filePath string
+	text     string
+	data     *DomainMap
+}
+
+// undefinedFuncRe matches the error of the template parser for functions it doesn't know
+var undefinedFuncRe = regexp.MustCompile(`function "([^"]+)" not defined`)
+
+// parse the template and extract its calls to the TemplateFuncs
+func (t *TemplateFile) parse() error {
+	// The parser refuses functions it doesn't know, and the ones of the application are unknown here,
+	// so they're declared as they're found, with any non-nil value
+	funcs := make(map[string]interface{})
+	for {
+		trees, err := parse.Parse(t.filePath, t.text, "", "", funcs)
+		if err == nil {
+			// inspect the templates defined by the file in order, for a consistent output
+			names := make([]string, 0, len(trees))
+			for name := range trees {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				t.inspectNode(trees[name].Root)
+			}
+			return nil
+		}
+
+		m := undefinedFuncRe.FindStringSubmatch(err.Error())
+		if m == nil {
+			return err
+		}
+		if _, ok := funcs[m[1]]; ok {
+			return err
+		}
+		funcs[m[1]] = struct{}{}
+	}
+}
+
+func (t *TemplateFile) inspectNode(node parse.Node) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			t.inspectNode(child)
+		}
+
+	case *parse.ActionNode:
+		t.inspectPipe(n.Pipe)
+
+	case *parse.IfNode:
+		t.inspectBranch(&n.BranchNode)
+
+	case *parse.RangeNode:
+		t.inspectBranch(&n.BranchNode)
+
+	case *parse.WithNode:
+		t.inspectBranch(&n.BranchNode)
+
+	case *parse.TemplateNode:
+		t.inspectPipe(n.Pipe)
+
+	case *parse.PipeNode:
+		t.inspectPipe(n)
+
+	case *parse.ChainNode:
+		t.inspectNode(n.Node)
+	}
+}
+
+func (t *TemplateFile) inspectBranch(n *parse.BranchNode) {
+	t.inspectPipe(n.Pipe)
+	t.inspectNode(n.List)
+	t.inspectNode(n.ElseList)
+}
+
+func (t *TemplateFile) inspectPipe(n *parse.PipeNode) {
+	if n == nil {
+		return
+	}
+
+	for i, cmd := range n.Cmds {
+		for _, arg := range cmd.Args {
+			t.inspectNode(arg)
+		}
+
+		// the value piped into the function is its last argument: {{"Hello" | T}}
+		var piped parse.Node
+		if i > 0 {
+			piped = n.Cmds[i-1]
+			if len(n.Cmds[i-1].Args) == 1 {
+				piped = n.Cmds[i-1].Args[0]
+			}
+		}
+		t.inspectCommand(cmd, piped)
+	}
+}
+
+func (t *TemplateFile) inspectCommand(n *parse.CommandNode, piped parse.Node) {
+	if len(n.Args) == 0 {
+		return
+	}
+	ident, ok := n.Args[0].(*parse.IdentifierNode)
+	if !ok {
+		return
+	}
+	def, ok := TemplateFuncs[ident.Ident]
+	if !ok {
+		return
+	}
+
+	// convert args
+	nodes := n.Args[1:]
+	if piped != nil {
+		nodes = append(nodes[:len(nodes):len(nodes)], piped)
+	}
+	args := make([]*parse.StringNode, len(nodes))
+	for idx, arg := range nodes {
+		args[idx], _ = arg.(*parse.StringNode)
+	}
+
+	// get position
+	line := 1 + strings.Count(t.text[:int(n.Position())], "\n")
+	position := fmt.Sprintf("%s:%d", t.filePath, line)
+
+	t.parseGetter(def, args, position)
+}
+
+func (t *TemplateFile) parseGetter(def GetterDef, args []*parse.StringNode, pos string) {
+	// check if enough arguments are given
+	if len(args) <= def.maxArgIndex() {
+		log.Printf("ERR: Unsupported call at %s (not enough arguments)", pos)
+		return
+	}
+
+	// get domain
+	var domain string
+	if def.Domain != -1 && args[def.Domain] != nil {
+		domain = args[def.Domain].Text
+	}
+
+	// only handle function calls with strings as ID
+	if args[def.Id] == nil {
+		log.Printf("ERR: Unsupported call at %s (ID not a string)", pos)
+		return
+	}
+
+	trans := Translation{
+		MsgId:           strconv.Quote(args[def.Id].Text),
+		SourceLocations: []string{pos},
+	}
+	if def.Plural > 0 {
+		// plural ID must be a string
+		if args[def.Plural] == nil {
+			log.Printf("ERR: Unsupported call at %s (Plural not a string)", pos)
+			return
+		}
+		trans.MsgIdPlural = strconv.Quote(args[def.Plural].Text)
+	}
+	if def.Context > 0 {
+		// Context must be a string
+		if args[def.Context] == nil {
+			log.Printf("ERR: Unsupported call at %s (Context not a string)", pos)
+			return
+		}
+		trans.Context = strconv.Quote(args[def.Context].Text)
+	}
+
+	t.data.AddTranslation(domain, &trans)
+}
@@ -0,0 +1,123 @@
+package gotext
+
+/*
+Clone returns a copy of the Locale, with the same configuration and catalogs, for per-request customizations
+that must not race with or change the shared Locale:
+
+	l := shared.Clone()
+	l.AddOverlayDomain("default", tenant.Catalog)
+	l.SetDomain("emails")
+
+The copy is cheap, as catalogs are shared until replaced: adding, reloading or overlaying a domain in either Locale
+doesn't change the other one. Changes made to a catalog itself, like Domain.SetMsgstr, are seen by both.
+The clone doesn't refresh the remote catalogs of NewLocaleHTTP, nor activate the catalogs scheduled by
+ScheduleTranslator, and has nothing to Rollback.
+*/
+func (l *Locale) Clone() *Locale {
+	l.RLock()
+	defer l.RUnlock()
+
+	c := &Locale{
+		path:           l.path,
+		lang:           l.lang,
+		tag:            l.tag,
+		Domains:        make(map[string]Translator, len(l.Domains)),
+		defaultDomain:  l.defaultDomain,
+		domainOrder:    append([]string(nil), l.domainOrder...),
+		domainPriority: append([]string(nil), l.domainPriority...),
+		localeSettings: l.localeSettings.clone(),
+		catalogVersion: l.catalogVersion,
+	}
+	for dom, tr := range l.Domains {
+		c.Domains[dom] = tr
+	}
+	if len(l.overlays) > 0 {
+		c.overlays = make(map[string][]Translator, len(l.overlays))
+		for dom, trs := range l.overlays {
+			c.overlays[dom] = append([]Translator(nil), trs...)
+		}
+	}
+	if len(l.domainFiles) > 0 {
+		c.domainFiles = make(map[string][]string, len(l.domainFiles))
+		for dom, paths := range l.domainFiles {
+			c.domainFiles[dom] = paths
+		}
+	}
+	return c
+}
+
+/*
+LocaleView is a read-only snapshot of a Locale, returned by ReadOnlyView.
+It only has lookup methods, so code given a view can't change the catalogs or configuration of the Locale.
+Changes made to the Locale afterwards, like adding, reloading or overlaying domains and changing its settings,
+aren't seen by the view. The catalogs themselves are shared, as Clone does, so changes made to one in place,
+like Domain.SetMsgstr or Po.Set, are seen by the view too.
+*/
+type LocaleView struct {
+	l *Locale
+}
+
+// ReadOnlyView returns a read-only snapshot of the Locale, taken like Clone does, to hand to request handlers.
+func (l *Locale) ReadOnlyView() *LocaleView {
+	return &LocaleView{l: l.Clone()}
+}
+
+// GetLanguage returns the language of the view.
+func (v *LocaleView) GetLanguage() string {
+	return v.l.GetLanguage()
+}
+
+// GetDomain returns the default domain of the view.
+func (v *LocaleView) GetDomain() string {
+	return v.l.GetDomain()
+}
+
+// Get works like Locale.Get.
+func (v *LocaleView) Get(str string, vars ...interface{}) string {
+	return v.l.GetD(callerDomain(0, v.l.GetDomain()), str, vars...)
+}
+
+// GetN works like Locale.GetN.
+func (v *LocaleView) GetN(str, plural string, n int, vars ...interface{}) string {
+	return v.l.GetND(callerDomain(0, v.l.GetDomain()), str, plural, n, vars...)
+}
+
+// GetD works like Locale.GetD.
+func (v *LocaleView) GetD(dom, str string, vars ...interface{}) string {
+	return v.l.GetD(dom, str, vars...)
+}
+
+// GetND works like Locale.GetND.
+func (v *LocaleView) GetND(dom, str, plural string, n int, vars ...interface{}) string {
+	return v.l.GetND(dom, str, plural, n, vars...)
+}
+
+// GetC works like Locale.GetC.
+func (v *LocaleView) GetC(str, ctx string, vars ...interface{}) string {
+	return v.l.GetDC(callerDomain(0, v.l.GetDomain()), str, ctx, vars...)
+}
+
+// GetNC works like Locale.GetNC.
+func (v *LocaleView) GetNC(str, plural string, n int, ctx string, vars ...interface{}) string {
+	return v.l.GetNDC(callerDomain(0, v.l.GetDomain()), str, plural, n, ctx, vars...)
+}
+
+// GetDC works like Locale.GetDC.
+func (v *LocaleView) GetDC(dom, str, ctx string, vars ...interface{}) string {
+	return v.l.GetDC(dom, str, ctx, vars...)
+}
+
+// GetNDC works like Locale.GetNDC.
+func (v *LocaleView) GetNDC(dom, str, plural string, n int, ctx string, vars ...interface{}) string {
+	return v.l.GetNDC(dom, str, plural, n, ctx, vars...)
+}
+
+// Lookup works like Locale.Lookup.
+func (v *LocaleView) Lookup(str string, vars ...interface{}) (string, bool) {
+	return v.l.LookupD(callerDomain(0, v.l.GetDomain()), str, vars...)
+}
+
+// LookupD works like Locale.LookupD.
+func (v *LocaleView) LookupD(dom, str string, vars ...interface{}) (string, bool) {
+	return v.l.LookupD(dom, str, vars...)
+}
@@ -0,0 +1,90 @@
+package gotext
+
+import (
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestLocaleClone(t *testing.T) {
+	shared := NewLocale("fixtures/", "de_DE")
+	shared.AddDomain("default")
+	lang := "language"
+
+	c := shared.Clone()
+	if c.GetLanguage() != "de_DE" || c.GetDomain() != "default" {
+		t.Errorf("Unexpected clone %s/%s", c.GetLanguage(), c.GetDomain())
+	}
+	if tr := c.Get(lang); tr != "de_DE" {
+		t.Errorf("Expected 'de_DE' but got '%s'", tr)
+	}
+
+	// Customizing the clone leaves the shared Locale alone
+	c.AddOverlayDomain("default", NewDomainBuilder().Add(lang, "Deutsch").BuildPo())
+	c.AddTranslator("emails", NewDomainBuilder().Add("Bye", "Tschüss").BuildPo())
+	c.SetDomain("emails")
+	if tr := c.GetD("default", lang); tr != "Deutsch" {
+		t.Errorf("Expected the overlay, got '%s'", tr)
+	}
+	if tr := shared.Get(lang); tr != "de_DE" {
+		t.Errorf("Expected the shared catalog, got '%s'", tr)
+	}
+	if _, ok := shared.Domains["emails"]; ok || shared.GetDomain() != "default" {
+		t.Error("Expected the shared Locale to be unchanged")
+	}
+
+	// And the other way around
+	shared.AddTranslator("default", NewDomainBuilder().Add(lang, "Sprache").BuildPo())
+	if tr := c.GetD("default", lang); tr != "Deutsch" {
+		t.Errorf("Expected the clone catalog, got '%s'", tr)
+	}
+
+	// Settings are copied too
+	shared.SetStrictMode(true)
+	c = shared.Clone()
+	if !c.strict {
+		t.Error("Expected the settings to be copied")
+	}
+	c.AddPostProcessor(func(msgid, out string) string {
+		return strings.ToUpper(out)
+	})
+	if tr := shared.Get(lang); tr != "Sprache" {
+		t.Errorf("Expected the shared post-processors to be unchanged, got '%s'", tr)
+	}
+}
+
+func TestLocaleReadOnlyView(t *testing.T) {
+	shared := NewLocale("fixtures/", "de_DE")
+	shared.AddDomain("default")
+	lang := "language"
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v := shared.ReadOnlyView()
+			if tr := v.Get(lang); tr != "de_DE" {
+				t.Errorf("Expected 'de_DE' but got '%s'", tr)
+			}
+		}()
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		shared.AddTranslator("extra", NewDomainBuilder().Add(lang, "Sprache").BuildPo())
+	}()
+	wg.Wait()
+
+	v := shared.ReadOnlyView()
+	shared.AddTranslator("default", NewDomainBuilder().Add(lang, "Sprache").BuildPo())
+	if tr := v.GetD("default", lang); tr != "de_DE" {
+		t.Errorf("Expected the snapshot catalog, got '%s'", tr)
+	}
+	if tr, ok := v.Lookup(lang); !ok || tr != "de_DE" {
+		t.Errorf("Expected 'de_DE' to be found, got '%s'", tr)
+	}
+	if v.GetLanguage() != "de_DE" || v.GetDomain() != "default" {
+		t.Errorf("Unexpected view %s/%s", v.GetLanguage(), v.GetDomain())
+	}
+}
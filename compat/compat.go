@@ -0,0 +1,211 @@
+/*
+Package compat exposes the classic gettext functions, with their names and semantics, on top of gotext Locale objects,
+to ease porting C, PHP or Python code that calls them directly:
+
+	compat.Setlocale(compat.LCAll, "")
+	compat.Bindtextdomain("myapp", "/usr/share/locale")
+	compat.Textdomain("myapp")
+
+	fmt.Println(compat.Gettext("Hello, world!"))
+	fmt.Printf(compat.Ngettext("%d file removed", "%d files removed", n), n)
+
+As in gettext, catalogs are looked up in the directory bound to their domain, in the
+{lang}/LC_MESSAGES/{dom}.{po,mo} layout, for the language of the environment (see gotext.NewLocaleFromEnv),
+and loaded on first use. Translations aren't formatted: they're returned as found, like gettext does.
+
+The state of the package is global, like the one of gettext, and safe for concurrent use.
+*/
+package compat
+
+import (
+	"sync"
+
+	"github.com/leonelquinteros/gotext"
+)
+
+// Category is a locale category, naming the folder where the catalogs of a domain are looked up.
+type Category string
+
+// Locale categories. Catalogs are looked up in the folder named after their category,
+// like "de/LC_TIME/myapp.mo" for LCTime. LCAll is only used by Setlocale.
+const (
+	LCAll      Category = "LC_ALL"
+	LCCtype    Category = "LC_CTYPE"
+	LCNumeric  Category = "LC_NUMERIC"
+	LCTime     Category = "LC_TIME"
+	LCCollate  Category = "LC_COLLATE"
+	LCMonetary Category = "LC_MONETARY"
+	LCMessages Category = gotext.LCMessages
+)
+
+// DefaultDir is the directory of the domains not bound by Bindtextdomain.
+var DefaultDir = "/usr/share/locale"
+
+// DefaultDomain is the domain used before Textdomain is called, like in gettext.
+const DefaultDomain = "messages"
+
+// localeKey identifies the Locale loading the catalogs of a directory for a category.
+type localeKey struct {
+	dir string
+	cat Category
+}
+
+// catalogs is a Locale with the domains loaded so far.
+type catalogs struct {
+	l      *gotext.Locale
+	loaded map[string]bool
+}
+
+var state = struct {
+	sync.Mutex
+	domain  string
+	lang    string
+	dirs    map[string]string
+	locales map[localeKey]*catalogs
+}{domain: DefaultDomain}
+
+// reset restores the initial state of the package.
+func reset() {
+	state.Lock()
+	state.domain = DefaultDomain
+	state.lang = ""
+	state.dirs = nil
+	state.locales = nil
+	state.Unlock()
+}
+
+// locale returns the Locale with the catalog of the domain dom for the category cat loaded.
+func locale(dom string, cat Category) *gotext.Locale {
+	state.Lock()
+	defer state.Unlock()
+
+	dir, ok := state.dirs[dom]
+	if !ok {
+		dir = DefaultDir
+	}
+	if cat == "" || cat == LCAll {
+		cat = LCMessages
+	}
+
+	key := localeKey{dir: dir, cat: cat}
+	c, ok := state.locales[key]
+	if !ok {
+		c = &catalogs{loaded: make(map[string]bool)}
+		if state.lang == "" {
+			c.l = gotext.NewLocaleFromEnv(dir)
+		} else {
+			c.l = gotext.NewLocale(dir, state.lang)
+		}
+		if cat != LCMessages {
+			c.l.SetPathResolver(gotext.Layout("{lang}/"+string(cat)+"/{dom}.{ext}", "{base}/"+string(cat)+"/{dom}.{ext}"))
+		}
+		if state.locales == nil {
+			state.locales = make(map[localeKey]*catalogs)
+		}
+		state.locales[key] = c
+	}
+
+	if !c.loaded[dom] {
+		c.l.AddDomain(dom)
+		c.loaded[dom] = true
+	}
+	return c.l
+}
+
+// Setlocale sets the language of the messages when cat is LCAll or LCMessages, and returns it.
+// An empty locale reads it from the environment, like setlocale(LC_ALL, "") does.
+// Other categories don't change the messages, and only return the current language.
+func Setlocale(cat Category, locale string) string {
+	state.Lock()
+	defer state.Unlock()
+
+	if cat == LCAll || cat == LCMessages {
+		state.lang = gotext.SimplifiedLocale(locale)
+		state.locales = nil
+	}
+	if state.lang == "" {
+		return gotext.NewLocaleFromEnv("").GetLanguage()
+	}
+	return state.lang
+}
+
+// Textdomain sets the domain of Gettext, Ngettext, Pgettext and Npgettext, and returns it.
+// An empty dom only returns the current domain.
+func Textdomain(dom string) string {
+	state.Lock()
+	defer state.Unlock()
+
+	if dom != "" {
+		state.domain = dom
+	}
+	return state.domain
+}
+
+// Bindtextdomain sets the directory where the catalogs of the domain dom are looked up, and returns it.
+// An empty dir only returns the current directory of dom.
+func Bindtextdomain(dom, dir string) string {
+	state.Lock()
+	defer state.Unlock()
+
+	if dir != "" {
+		if state.dirs == nil {
+			state.dirs = make(map[string]string)
+		}
+		state.dirs[dom] = dir
+	}
+	if d, ok := state.dirs[dom]; ok {
+		return d
+	}
+	return DefaultDir
+}
+
+// Gettext returns the translation of msgid in the current domain, or msgid if there is none.
+func Gettext(msgid string) string {
+	return Dcgettext(Textdomain(""), msgid, LCMessages)
+}
+
+// Ngettext returns the plural form for n of the translation of msgid in the current domain,
+// or msgid when n is 1 and plural otherwise if there is none.
+func Ngettext(msgid, plural string, n int) string {
+	return Dcngettext(Textdomain(""), msgid, plural, n, LCMessages)
+}
+
+// Pgettext works like Gettext, for the entry of msgid in the context ctx.
+func Pgettext(ctx, msgid string) string {
+	return Dpgettext(Textdomain(""), ctx, msgid)
+}
+
+// Npgettext works like Ngettext, for the entry of msgid in the context ctx.
+func Npgettext(ctx, msgid, plural string, n int) string {
+	return Dnpgettext(Textdomain(""), ctx, msgid, plural, n)
+}
+
+// Dgettext works like Gettext, in the domain dom.
+func Dgettext(dom, msgid string) string {
+	return Dcgettext(dom, msgid, LCMessages)
+}
+
+// Dngettext works like Ngettext, in the domain dom.
+func Dngettext(dom, msgid, plural string, n int) string {
+	return Dcngettext(dom, msgid, plural, n, LCMessages)
+}
+
+// Dpgettext works like Pgettext, in the domain dom.
+func Dpgettext(dom, ctx, msgid string) string {
+	return locale(dom, LCMessages).GetDC(dom, msgid, ctx)
+}
+
+// Dnpgettext works like Npgettext, in the domain dom.
+func Dnpgettext(dom, ctx, msgid, plural string, n int) string {
+	return locale(dom, LCMessages).GetNDC(dom, msgid, plural, n, ctx)
+}
+
+// Dcgettext works like Gettext, in the domain dom, with the catalogs of the category cat.
+func Dcgettext(dom, msgid string, cat Category) string {
+	return locale(dom, cat).GetD(dom, msgid)
+}
+
+// Dcngettext works like Ngettext, in the domain dom, with the catalogs of the category cat.
+func Dcngettext(dom, msgid, plural string, n int, cat Category) string {
+	return locale(dom, cat).GetND(dom, msgid, plural, n)
+}
@@ -0,0 +1,101 @@
+package compat
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const messagesPo = `msgid ""
+msgstr ""
+"Language: de\n"
+"Plural-Forms: nplurals=2; plural=(n != 1);\n"
+
+msgid "Hello"
+msgstr "Hallo"
+
+msgid "%d file"
+msgid_plural "%d files"
+msgstr[0] "%d Datei"
+msgstr[1] "%d Dateien"
+
+msgctxt "menu"
+msgid "Open"
+msgstr "Öffnen"
+`
+
+func writeCatalog(t *testing.T, dir, cat, dom, data string) {
+	path := filepath.Join(dir, "de", cat, dom+".po")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGettext(t *testing.T) {
+	defer reset()
+
+	dir, err := ioutil.TempDir("", "gotext-compat")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	writeCatalog(t, dir, "LC_MESSAGES", "app", messagesPo)
+	writeCatalog(t, dir, "LC_MESSAGES", "other", "msgid \"Hello\"\nmsgstr \"Servus\"\n")
+	writeCatalog(t, dir, "LC_TIME", "app", "msgid \"Monday\"\nmsgstr \"Montag\"\n")
+
+	if got := Setlocale(LCAll, "de_DE.UTF-8"); got != "de_DE" {
+		t.Errorf("unexpected language %q", got)
+	}
+	if got := Bindtextdomain("app", dir); got != dir {
+		t.Errorf("unexpected directory %q", got)
+	}
+	Bindtextdomain("other", dir)
+	if got := Bindtextdomain("unbound", ""); got != DefaultDir {
+		t.Errorf("unexpected directory %q", got)
+	}
+
+	if got := Textdomain(""); got != DefaultDomain {
+		t.Errorf("unexpected domain %q", got)
+	}
+	if got := Gettext("Hello"); got != "Hello" {
+		t.Errorf("expected no translation without catalog, got %q", got)
+	}
+	if got := Textdomain("app"); got != "app" {
+		t.Errorf("unexpected domain %q", got)
+	}
+
+	for _, c := range []struct{ got, want string }{
+		{Gettext("Hello"), "Hallo"},
+		{Gettext("Bye"), "Bye"},
+		{Ngettext("%d file", "%d files", 1), "%d Datei"},
+		{Ngettext("%d file", "%d files", 3), "%d Dateien"},
+		{Ngettext("%d dir", "%d dirs", 3), "%d dirs"},
+		{Pgettext("menu", "Open"), "Öffnen"},
+		{Npgettext("menu", "Close", "Close all", 2), "Close all"},
+		{Dgettext("other", "Hello"), "Servus"},
+		{Dngettext("other", "Hello", "Hellos", 1), "Servus"},
+		{Dpgettext("app", "menu", "Open"), "Öffnen"},
+		{Dnpgettext("app", "menu", "Open", "Open all", 1), "Öffnen"},
+		{Dcgettext("app", "Monday", LCTime), "Montag"},
+		{Dcgettext("app", "Hello", LCTime), "Hello"},
+		{Dcngettext("app", "%d file", "%d files", 2, LCMessages), "%d Dateien"},
+	} {
+		if c.got != c.want {
+			t.Errorf("expected %q, got %q", c.want, c.got)
+		}
+	}
+
+	// Changing the language reloads the catalogs
+	Setlocale(LCMessages, "fr")
+	if got := Gettext("Hello"); got != "Hello" {
+		t.Errorf("expected no French translation, got %q", got)
+	}
+	if got := Setlocale(LCTime, "de"); got != "fr" {
+		t.Errorf("unexpected language %q", got)
+	}
+}
@@ -0,0 +1,142 @@
+package gotext
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Decompressor returns a reader of the decompressed content of r.
+type Decompressor func(r io.Reader) (io.ReadCloser, error)
+
+// compression is a way catalog files can be compressed, recognized by file extension and magic bytes.
+type compression struct {
+	ext   string
+	magic []byte
+	open  Decompressor
+}
+
+var (
+	compressionsMutex sync.RWMutex
+
+	compressions = []compression{
+		{".gz", []byte{0x1f, 0x8b}, func(r io.Reader) (io.ReadCloser, error) { return gzip.NewReader(r) }},
+		{".zst", []byte{0x28, 0xb5, 0x2f, 0xfd}, openZstd},
+	}
+)
+
+// maxDecompressedSize is the largest decompressed catalog accepted, so a small malicious file
+// can't make the program run out of memory.
+const maxDecompressedSize = 64 << 20
+
+var errCatalogTooLarge = fmt.Errorf("gotext: decompressed catalog larger than %d MiB", maxDecompressedSize>>20)
+
+/*
+RegisterDecompressor makes catalog files compressed with another format, recognized by the file extension ext
+and the magic bytes their content starts with, decompressed when they're loaded. It replaces the Decompressor
+of a format registered before with the same extension.
+
+gzip and zstd (.zst) are supported without registering anything. The built-in zstd decoder is minimal, without
+dictionary support, so a faster or more complete one can be registered, like the one of
+github.com/klauspost/compress/zstd:
+
+	gotext.RegisterDecompressor(".zst", []byte{0x28, 0xb5, 0x2f, 0xfd}, func(r io.Reader) (io.ReadCloser, error) {
+		d, err := zstd.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		return d.IOReadCloser(), nil
+	})
+
+Whatever the decompressor, catalogs larger than 64 MiB once decompressed are rejected.
+*/
+func RegisterDecompressor(ext string, magic []byte, d Decompressor) {
+	compressionsMutex.Lock()
+	defer compressionsMutex.Unlock()
+
+	c := compression{ext: ext, magic: append([]byte(nil), magic...), open: d}
+	for i := range compressions {
+		if compressions[i].ext == ext {
+			compressions[i] = c
+			return
+		}
+	}
+	compressions = append(compressions, c)
+}
+
+// compressedExts returns the extensions of the compressed catalog files, in order.
+func compressedExts() []string {
+	compressionsMutex.RLock()
+	defer compressionsMutex.RUnlock()
+
+	exts := make([]string, len(compressions))
+	for i, c := range compressions {
+		exts[i] = c.ext
+	}
+	return exts
+}
+
+// trimCompressedExt returns path without the extension of a compressed catalog file, so "default.po.gz" is "default.po".
+func trimCompressedExt(path string) string {
+	ext := filepath.Ext(path)
+	for _, e := range compressedExts() {
+		if strings.EqualFold(ext, e) {
+			return strings.TrimSuffix(path, ext)
+		}
+	}
+	return path
+}
+
+// decompress returns data decompressed when it starts with the magic bytes of a compression format,
+// and as is otherwise. Catalogs never start with those, so files are recognized whatever their name.
+func decompress(data []byte) ([]byte, error) {
+	compressionsMutex.RLock()
+	var c compression
+	for _, comp := range compressions {
+		if len(comp.magic) > 0 && bytes.HasPrefix(data, comp.magic) {
+			c = comp
+			break
+		}
+	}
+	compressionsMutex.RUnlock()
+
+	if c.ext == "" {
+		return data, nil
+	}
+	if c.open == nil {
+		return nil, fmt.Errorf("gotext: no decompressor for %s compressed catalogs, see RegisterDecompressor", c.ext)
+	}
+
+	r, err := c.open(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("gotext: decompressing catalog: %w", err)
+	}
+	defer r.Close()
+
+	out, err := ioutil.ReadAll(io.LimitReader(r, maxDecompressedSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("gotext: decompressing catalog: %w", err)
+	}
+	if len(out) > maxDecompressedSize {
+		return nil, errCatalogTooLarge
+	}
+	return out, nil
+}
+
+// openZstd is the built-in Decompressor of zstd files.
+func openZstd(r io.Reader) (io.ReadCloser, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	out, err := decodeZstd(data, maxDecompressedSize)
+	if err != nil {
+		return nil, err
+	}
+	return ioutil.NopCloser(bytes.NewReader(out)), nil
+}
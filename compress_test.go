@@ -0,0 +1,141 @@
+package gotext
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const compressPo = `
+msgid "Save"
+msgstr "Speichern"
+`
+
+func gzipData(t *testing.T, data []byte) []byte {
+	var b bytes.Buffer
+	w := gzip.NewWriter(&b)
+	if _, err := w.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return b.Bytes()
+}
+
+func TestLocaleCompressedCatalogs(t *testing.T) {
+	po := NewPo()
+	po.Parse([]byte(compressPo))
+	mo, err := po.GetDomain().MarshalMO()
+	if err != nil {
+		t.Fatal(err)
+	}
+	zst, err := ioutil.ReadFile("fixtures/zstd/default.po.zst")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	src := &MemorySource{Files: map[string][]byte{
+		"de/LC_MESSAGES/default.po.gz": gzipData(t, []byte(compressPo)),
+		// Recognized by the magic bytes, whatever the name
+		"de/LC_MESSAGES/app.mo":     gzipData(t, mo),
+		"de/LC_MESSAGES/zst.po.zst": zst,
+		"de/LC_MESSAGES/broken.po":  {0x28, 0xb5, 0x2f, 0xfd, 0x00},
+	}}
+
+	l := NewLocaleWithSource(src, "de")
+	l.AddDomain("default")
+	l.AddDomain("app")
+	l.AddDomain("zst")
+	if got := l.Get("Save"); got != "Speichern" {
+		t.Errorf("unexpected translation %q", got)
+	}
+	if got := l.GetD("app", "Save"); got != "Speichern" {
+		t.Errorf("unexpected translation %q", got)
+	}
+
+	if got := l.GetD("zst", "My text"); got != "Translated text" {
+		t.Errorf("unexpected translation %q", got)
+	}
+
+	if _, err := readCatalog(src, "de/LC_MESSAGES/broken.po"); err == nil || !strings.Contains(err.Error(), "zstd") {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestDecompressLimit(t *testing.T) {
+	// A gzip bomb, tiny once compressed
+	bomb := gzipData(t, make([]byte, maxDecompressedSize+1))
+	if _, err := decompress(bomb); err != errCatalogTooLarge {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	if _, err := decompress(gzipData(t, make([]byte, 1024))); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestLocaleAddDomainFileCompressed(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gotext")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "de.po.gz")
+	if err := ioutil.WriteFile(path, gzipData(t, []byte(compressPo)), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	l := NewLocale("", "de")
+	if err := l.AddDomainFile("default", path); err != nil {
+		t.Fatal(err)
+	}
+	if got := l.Get("Save"); got != "Speichern" {
+		t.Errorf("unexpected translation %q", got)
+	}
+
+	po := NewPo()
+	po.ParseFile(path)
+	if got := po.Get("Save"); got != "Speichern" {
+		t.Errorf("unexpected translation %q", got)
+	}
+}
+
+func TestRegisterDecompressor(t *testing.T) {
+	compressionsMutex.RLock()
+	saved := append([]compression(nil), compressions...)
+	compressionsMutex.RUnlock()
+	defer func() {
+		compressionsMutex.Lock()
+		compressions = saved
+		compressionsMutex.Unlock()
+	}()
+
+	// A format prefixing the content with "RAW!"
+	magic := []byte("RAW!")
+	RegisterDecompressor(".raw", magic, func(r io.Reader) (io.ReadCloser, error) {
+		if _, err := io.ReadFull(r, make([]byte, len(magic))); err != nil {
+			return nil, err
+		}
+		return ioutil.NopCloser(r), nil
+	})
+
+	src := &MemorySource{Files: map[string][]byte{
+		"de/default.po.raw": append([]byte("RAW!"), compressPo...),
+	}}
+	l := NewLocaleWithSource(src, "de")
+	l.AddDomain("default")
+	if got := l.Get("Save"); got != "Speichern" {
+		t.Errorf("unexpected translation %q", got)
+	}
+
+	if got := trimCompressedExt("default.po.RAW"); got != "default.po" {
+		t.Errorf("unexpected path %q", got)
+	}
+}
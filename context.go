@@ -0,0 +1,272 @@
+package gotext
+
+import (
+	"container/list"
+	"context"
+	"strings"
+	"sync"
+
+	"golang.org/x/text/language"
+)
+
+// localeKey is the context key for the scoped Locale
+type localeKey struct{}
+
+// domainKey is the context key for the domain set by WithDomain
+type domainKey struct{}
+
+// localeScope is the value stored in a context by NewContext and WithLocaleScope.
+type localeScope struct {
+	locale *Locale
+
+	// scoped is set by WithLocaleScope, whose scopes load missing domains on first use,
+	// like the package level functions do
+	scoped *scopedLocale
+}
+
+// scopedLocale is a Locale created by WithLocaleScope, with the domains it already tried to load.
+type scopedLocale struct {
+	key    [2]string
+	locale *Locale
+
+	mu    sync.Mutex
+	tried map[string]bool
+}
+
+// load loads the domain dom the first time it's used. Domains without catalog are remembered too,
+// so they aren't looked up again on every lookup.
+func (s *scopedLocale) load(dom string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.tried[dom] {
+		return
+	}
+	s.tried[dom] = true
+
+	s.locale.RLock()
+	_, loaded := s.locale.Domains[dom]
+	s.locale.RUnlock()
+	if !loaded {
+		s.locale.AddDomain(dom)
+	}
+}
+
+// maxScopedLocales is the number of Locale objects kept by WithLocaleScope. The least recently used ones are dropped.
+const maxScopedLocales = 64
+
+// scopedLocales caches the Locale objects created by WithLocaleScope, by library path and language tag,
+// most recently used first
+var scopedLocales = struct {
+	sync.Mutex
+	order *list.List
+	m     map[[2]string]*list.Element
+}{order: list.New(), m: make(map[[2]string]*list.Element)}
+
+// NewContext returns a copy of ctx carrying the Locale l.
+// All the Ctx lookups (GetCtx, GetNCtx, GetDCtx...) made with the returned context, or any context derived from it, use l.
+func NewContext(ctx context.Context, l *Locale) context.Context {
+	return context.WithValue(ctx, localeKey{}, localeScope{locale: l})
+}
+
+// FromContext returns the Locale carried by ctx, if any.
+func FromContext(ctx context.Context) (*Locale, bool) {
+	s, ok := ctx.Value(localeKey{}).(localeScope)
+	if !ok || s.locale == nil {
+		return nil, false
+	}
+	return s.locale, true
+}
+
+/*
+WithLocaleScope returns a copy of ctx that makes all the Ctx lookups beneath it use the language lang,
+without changing the package configuration. It's safe to use from many goroutines at the same time,
+so a background job can switch languages for every item it processes:
+
+	for _, item := range items {
+		ctx := gotext.WithLocaleScope(ctx, item.User.Language)
+		notify(item.User, gotext.GetCtx(ctx, "Your order has shipped"))
+	}
+
+The Locale for each language is created from the package library path the first time it's needed
+and reused afterwards, for the last few languages used. Languages are told apart by their BCP 47 tag,
+so "de_DE" and "de-DE" share their Locale, created for "de_DE". Strings that aren't language tags are told apart as they are.
+Domains are loaded on first use, like the package level functions do.
+*/
+func WithLocaleScope(ctx context.Context, lang string) context.Context {
+	lang = SimplifiedLocale(lang)
+	return context.WithValue(ctx, localeKey{}, scopeFor(GetLibrary(), lang))
+}
+
+// scopeFor returns the scope of the language lang, cached by scopedLocales.
+func scopeFor(lib, lang string) localeScope {
+	key := [2]string{lib, lang}
+	if tag, err := language.Parse(strings.Replace(lang, "_", "-", -1)); err == nil {
+		key[1] = tag.String()
+	}
+	// Catalogs are looked up as ll_CC
+	lang = strings.Replace(lang, "-", "_", -1)
+
+	scopedLocales.Lock()
+	defer scopedLocales.Unlock()
+
+	if e, ok := scopedLocales.m[key]; ok {
+		scopedLocales.order.MoveToFront(e)
+		s := e.Value.(*scopedLocale)
+		return localeScope{locale: s.locale, scoped: s}
+	}
+
+	s := &scopedLocale{key: key, locale: NewLocale(lib, lang), tried: make(map[string]bool)}
+	scopedLocales.m[key] = scopedLocales.order.PushFront(s)
+	if scopedLocales.order.Len() > maxScopedLocales {
+		last := scopedLocales.order.Back()
+		scopedLocales.order.Remove(last)
+		delete(scopedLocales.m, last.Value.(*scopedLocale).key)
+	}
+	return localeScope{locale: s.locale, scoped: s}
+}
+
+// scopeLocale returns the Locale to use for a Ctx lookup in the domain dom, or nil to use the package level one.
+func scopeLocale(ctx context.Context, dom string) *Locale {
+	s, ok := ctx.Value(localeKey{}).(localeScope)
+	if !ok || s.locale == nil {
+		return nil
+	}
+
+	if s.scoped != nil {
+		s.scoped.load(dom)
+	}
+
+	return s.locale
+}
+
+// scopeDomain returns the default domain for a Ctx lookup
+func scopeDomain(ctx context.Context) string {
+	if s, ok := ctx.Value(localeKey{}).(localeScope); ok && s.locale != nil && s.scoped == nil {
+		if dom := s.locale.GetDomain(); dom != "" {
+			return dom
+		}
+	}
+	return GetDomain()
+}
+
+/*
+WithDomain returns a copy of ctx that makes the Ctx lookups beneath it that don't name a domain
+(GetCtx, GetNCtx, GetCCtx, GetNCCtx, T and TN) use the domain dom, without changing the shared Locale:
+
+	func sendWelcome(ctx context.Context, u *User) error {
+		ctx = gotext.WithDomain(ctx, "emails")
+		return mail.Send(u.Email, gotext.T(ctx, "Welcome, %s!", u.Name))
+	}
+
+It takes precedence over the default domain of the scoped Locale and over RegisterPackageDomain.
+Nested calls override the outer one.
+*/
+func WithDomain(ctx context.Context, dom string) context.Context {
+	return context.WithValue(ctx, domainKey{}, dom)
+}
+
+// DomainFromContext returns the domain set by WithDomain on ctx, if any.
+func DomainFromContext(ctx context.Context) (string, bool) {
+	dom, ok := ctx.Value(domainKey{}).(string)
+	return dom, ok
+}
+
+// ctxDomain returns the domain for a Ctx lookup that doesn't name one.
+// It must be called directly by the public API function, for callerDomain.
+func ctxDomain(ctx context.Context) string {
+	if dom, ok := DomainFromContext(ctx); ok {
+		return dom
+	}
+	return callerDomain(1, scopeDomain(ctx))
+}
+
+// GetCtx uses the default domain to return the corresponding Translation of a given string,
+// in the language of the Locale scoped by ctx, or the package language when there is none.
+// Supports optional parameters (vars... interface{}) to be inserted on the formatted string using the fmt.Printf syntax.
+func GetCtx(ctx context.Context, str string, vars ...interface{}) string {
+	return GetDCtx(ctx, ctxDomain(ctx), str, vars...)
+}
+
+// GetNCtx retrieves the (N)th plural form of Translation for the given string in the default domain,
+// in the language of the Locale scoped by ctx.
+// Supports optional parameters (vars... interface{}) to be inserted on the formatted string using the fmt.Printf syntax.
+func GetNCtx(ctx context.Context, str, plural string, n int, vars ...interface{}) string {
+	return GetNDCtx(ctx, ctxDomain(ctx), str, plural, n, vars...)
+}
+
+// GetDCtx returns the corresponding Translation in the given domain for a given string,
+// in the language of the Locale scoped by ctx.
+// Supports optional parameters (vars... interface{}) to be inserted on the formatted string using the fmt.Printf syntax.
+func GetDCtx(ctx context.Context, dom, str string, vars ...interface{}) string {
+	if l := scopeLocale(ctx, dom); l != nil {
+		return l.GetD(dom, str, vars...)
+	}
+	return GetD(dom, str, vars...)
+}
+
+// GetNDCtx retrieves the (N)th plural form of Translation in the given domain for a given string,
+// in the language of the Locale scoped by ctx.
+// Supports optional parameters (vars... interface{}) to be inserted on the formatted string using the fmt.Printf syntax.
+func GetNDCtx(ctx context.Context, dom, str, plural string, n int, vars ...interface{}) string {
+	if l := scopeLocale(ctx, dom); l != nil {
+		return l.GetND(dom, str, plural, n, vars...)
+	}
+	return GetND(dom, str, plural, n, vars...)
+}
+
+// GetCCtx uses the default domain to return the corresponding Translation of the given string in the given msgctxt,
+// in the language of the Locale scoped by ctx.
+// Supports optional parameters (vars... interface{}) to be inserted on the formatted string using the fmt.Printf syntax.
+func GetCCtx(ctx context.Context, str, msgctxt string, vars ...interface{}) string {
+	return GetDCCtx(ctx, ctxDomain(ctx), str, msgctxt, vars...)
+}
+
+// GetNCCtx retrieves the (N)th plural form of Translation for the given string in the given msgctxt in the default domain,
+// in the language of the Locale scoped by ctx.
+// Supports optional parameters (vars... interface{}) to be inserted on the formatted string using the fmt.Printf syntax.
+func GetNCCtx(ctx context.Context, str, plural string, n int, msgctxt string, vars ...interface{}) string {
+	return GetNDCCtx(ctx, ctxDomain(ctx), str, plural, n, msgctxt, vars...)
+}
+
+// GetDCCtx returns the corresponding Translation in the given domain for the given string in the given msgctxt,
+// in the language of the Locale scoped by ctx.
+// Supports optional parameters (vars... interface{}) to be inserted on the formatted string using the fmt.Printf syntax.
+func GetDCCtx(ctx context.Context, dom, str, msgctxt string, vars ...interface{}) string {
+	if l := scopeLocale(ctx, dom); l != nil {
+		return l.GetDC(dom, str, msgctxt, vars...)
+	}
+	return GetDC(dom, str, msgctxt, vars...)
+}
+
+// GetNDCCtx retrieves the (N)th plural form of Translation in the given domain for the given string in the given msgctxt,
+// in the language of the Locale scoped by ctx.
+// Supports optional parameters (vars... interface{}) to be inserted on the formatted string using the fmt.Printf syntax.
+func GetNDCCtx(ctx context.Context, dom, str, plural string, n int, msgctxt string, vars ...interface{}) string {
+	if l := scopeLocale(ctx, dom); l != nil {
+		return l.GetNDC(dom, str, plural, n, msgctxt, vars...)
+	}
+	return GetNDC(dom, str, plural, n, msgctxt, vars...)
+}
+
+// WithLocale returns a copy of ctx carrying the Locale l, to be used by T, TN and the other Ctx lookups.
+// It's the same as NewContext.
+func WithLocale(ctx context.Context, l *Locale) context.Context {
+	return NewContext(ctx, l)
+}
+
+// T is a shorthand for GetCtx, translating str in the default domain and the language of the Locale carried by ctx,
+// so code deep in the call stack can translate without a Locale parameter:
+//
+//	ctx = gotext.WithLocale(ctx, locale)
+//	...
+//	msg := gotext.T(ctx, "Hello %s", name)
+func T(ctx context.Context, str string, vars ...interface{}) string {
+	return GetDCtx(ctx, ctxDomain(ctx), str, vars...)
+}
+
+// TN is a shorthand for GetNCtx, returning the (N)th plural form of the Translation for str in the default domain,
+// in the language of the Locale carried by ctx.
+func TN(ctx context.Context, str, plural string, n int, vars ...interface{}) string {
+	return GetNDCtx(ctx, ctxDomain(ctx), str, plural, n, vars...)
+}
@@ -0,0 +1,187 @@
+package gotext
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestWithLocaleScope(t *testing.T) {
+	fixPath, _ := filepath.Abs("./fixtures/")
+	Configure(fixPath, "en_US", "default")
+
+	langs := []string{"de_DE", "fr", "en_AU", "en_US"}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		lang := langs[i%len(langs)]
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx := WithLocaleScope(context.Background(), lang)
+			if tr := GetCtx(ctx, "language"); tr != lang {
+				t.Errorf("Expected '%s' but got '%s'", lang, tr)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Package configuration is unchanged
+	if lang := GetLanguage(); lang != "en_US" {
+		t.Errorf("Expected package language 'en_US' but got '%s'", lang)
+	}
+	if tr := GetCtx(context.Background(), "language"); tr != "en_US" {
+		t.Errorf("Expected 'en_US' but got '%s'", tr)
+	}
+
+	// Nested scopes override the outer one
+	ctx := WithLocaleScope(context.Background(), "fr")
+	inner := WithLocaleScope(ctx, "de_DE")
+	if tr := GetDCtx(inner, "default", "language"); tr != "de_DE" {
+		t.Errorf("Expected 'de_DE' but got '%s'", tr)
+	}
+	if tr := GetNCtx(ctx, "One with var: %s", "Several with vars: %s", 2, "v"); tr != "This one is the plural: v" {
+		t.Errorf("Expected 'This one is the plural: v' but got '%s'", tr)
+	}
+}
+
+func TestWithLocaleScopeCache(t *testing.T) {
+	fixPath, _ := filepath.Abs("./fixtures/")
+	Configure(fixPath, "en_US", "default")
+
+	a := WithLocaleScope(context.Background(), "de_DE").Value(localeKey{}).(localeScope)
+	b := WithLocaleScope(context.Background(), "de-DE").Value(localeKey{}).(localeScope)
+	if a.scoped != b.scoped {
+		t.Error("Expected de_DE and de-DE to share their Locale")
+	}
+
+	// The Locale is created for ll_CC, whatever the spelling used first
+	c := WithLocaleScope(context.Background(), "en-US")
+	if lang := c.Value(localeKey{}).(localeScope).locale.GetLanguage(); lang != "en_US" {
+		t.Errorf("Expected the Locale to be created for en_US, got %q", lang)
+	}
+	if tr := GetCtx(c, "language"); tr != "en_US" {
+		t.Errorf("Expected 'en_US' but got '%s'", tr)
+	}
+
+	// Strings that aren't language tags are cached too
+	d := WithLocaleScope(context.Background(), "not a language!").Value(localeKey{}).(localeScope)
+	e := WithLocaleScope(context.Background(), "not a language!").Value(localeKey{}).(localeScope)
+	if d.scoped != e.scoped {
+		t.Error("Expected the same string to share its Locale")
+	}
+
+	// Domains without catalog are looked up once
+	ctx := context.WithValue(context.Background(), localeKey{}, a)
+	if tr := GetDCtx(ctx, "missing", "language"); tr != "language" {
+		t.Errorf("Expected 'language' but got '%s'", tr)
+	}
+	a.scoped.mu.Lock()
+	tried := a.scoped.tried["missing"]
+	a.scoped.mu.Unlock()
+	if !tried {
+		t.Error("Expected the missing domain to be remembered")
+	}
+
+	for i := 0; i < maxScopedLocales+10; i++ {
+		WithLocaleScope(context.Background(), fmt.Sprintf("en-x-%d", i))
+	}
+	WithLocaleScope(context.Background(), "not a language!")
+	scopedLocales.Lock()
+	n := len(scopedLocales.m)
+	scopedLocales.Unlock()
+	if n != maxScopedLocales {
+		t.Errorf("Expected %d cached Locale objects, got %d", maxScopedLocales, n)
+	}
+}
+
+func TestNewContext(t *testing.T) {
+	l := NewLocale("fixtures/", "de_DE")
+	l.AddDomain("default")
+
+	ctx := NewContext(context.Background(), l)
+	if got, ok := FromContext(ctx); !ok || got != l {
+		t.Error("Expected the Locale to be carried by the context")
+	}
+	if _, ok := FromContext(context.Background()); ok {
+		t.Error("Expected no Locale in an empty context")
+	}
+
+	if tr := GetCtx(ctx, "language"); tr != "de_DE" {
+		t.Errorf("Expected 'de_DE' but got '%s'", tr)
+	}
+	if tr := GetCCtx(ctx, "Some random in a context", "Ctx"); tr != "Some random translation in a context" {
+		t.Errorf("Expected 'Some random translation in a context' but got '%s'", tr)
+	}
+
+	// Domains aren't loaded on demand for explicitly created Locale objects
+	GetDCtx(ctx, "missing", "language")
+	if _, ok := l.Domains["missing"]; ok {
+		t.Error("Expected missing domain not to be added")
+	}
+}
+
+func TestT(t *testing.T) {
+	l := NewLocale("fixtures/", "de_DE")
+	l.AddDomain("default")
+
+	ctx := WithLocale(context.Background(), l)
+	if got, ok := FromContext(ctx); !ok || got != l {
+		t.Error("Expected the Locale to be carried by the context")
+	}
+
+	if tr := T(ctx, "language"); tr != "de_DE" {
+		t.Errorf("Expected 'de_DE' but got '%s'", tr)
+	}
+	if tr := TN(ctx, "One with var: %s", "Several with vars: %s", 2, "x"); tr != "This one is the plural: x" {
+		t.Errorf("Unexpected plural '%s'", tr)
+	}
+}
+
+func TestWithDomain(t *testing.T) {
+	l := NewLocale("fixtures/", "de_DE")
+	l.AddDomain("default")
+	if err := l.AddDomainBytes("emails", []byte("msgid \"language\"\nmsgstr \"Sprache\"\n\nmsgctxt \"menu\"\nmsgid \"language\"\nmsgstr \"Sprache wählen\"\n"), FormatPO); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := WithLocale(context.Background(), l)
+	emails := WithDomain(ctx, "emails")
+	if dom, ok := DomainFromContext(emails); !ok || dom != "emails" {
+		t.Errorf("Unexpected domain '%s'", dom)
+	}
+	if _, ok := DomainFromContext(ctx); ok {
+		t.Error("Expected no domain on the parent context")
+	}
+
+	if tr := T(emails, "language"); tr != "Sprache" {
+		t.Errorf("Expected 'Sprache' but got '%s'", tr)
+	}
+	if tr := GetCCtx(emails, "language", "menu"); tr != "Sprache wählen" {
+		t.Errorf("Expected 'Sprache wählen' but got '%s'", tr)
+	}
+	// Named domains and the shared Locale are unchanged
+	if tr := GetDCtx(emails, "default", "language"); tr != "de_DE" {
+		t.Errorf("Expected 'de_DE' but got '%s'", tr)
+	}
+	if tr := T(ctx, "language"); tr != "de_DE" {
+		t.Errorf("Expected 'de_DE' but got '%s'", tr)
+	}
+	if dom := l.GetDomain(); dom != "default" {
+		t.Errorf("Expected the default domain to be kept, got '%s'", dom)
+	}
+
+	// Nested calls override the outer one
+	if tr := T(WithDomain(emails, "default"), "language"); tr != "de_DE" {
+		t.Errorf("Expected 'de_DE' but got '%s'", tr)
+	}
+
+	// Package mappings are overridden too
+	RegisterPackageDomain("github.com/leonelquinteros/gotext", "default")
+	defer UnregisterPackageDomain("github.com/leonelquinteros/gotext")
+	if tr := T(emails, "language"); tr != "Sprache" {
+		t.Errorf("Expected 'Sprache' but got '%s'", tr)
+	}
+}
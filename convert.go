@@ -0,0 +1,100 @@
+package gotext
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// Format is a catalog file format that translations can be converted to with Convert.
+type Format int
+
+const (
+	// FormatPO is the gettext .po text format.
+	FormatPO Format = iota
+	// FormatMO is the compiled gettext .mo format.
+	FormatMO
+	// FormatJSON is the JSON layout written by Domain.ExportJSON.
+	FormatJSON
+	// FormatXLIFF is XLIFF 1.2, as written by Domain.ExportXLIFF.
+	FormatXLIFF
+	// FormatCSV is the spreadsheet layout written by Domain.ExportCSV.
+	FormatCSV
+)
+
+var formatNames = []string{"po", "mo", "json", "xliff", "csv"}
+
+// String returns the name of the format, which is also its usual file extension.
+func (f Format) String() string {
+	if f < 0 || int(f) >= len(formatNames) {
+		return fmt.Sprintf("Format(%d)", int(f))
+	}
+	return formatNames[f]
+}
+
+// ParseFormat returns the Format with the given name or file extension, like "po" or ".xlf".
+func ParseFormat(name string) (Format, error) {
+	name = strings.ToLower(strings.TrimPrefix(name, "."))
+	switch name {
+	case "pot":
+		return FormatPO, nil
+	case "xlf":
+		return FormatXLIFF, nil
+	}
+	for i, n := range formatNames {
+		if n == name {
+			return Format(i), nil
+		}
+	}
+	return 0, fmt.Errorf("gotext: unknown format %q", name)
+}
+
+// Convert writes the translations of src in the dst format.
+// Any Translator can be converted, so catalogs loaded from Fluent, ARB or other files can be written as .po or .mo files.
+//
+//	po := gotext.NewPo()
+//	po.ParseFile("/path/to/es.po")
+//	data, err := gotext.Convert(po, gotext.FormatXLIFF)
+func Convert(src Translator, dst Format) ([]byte, error) {
+	do := src.GetDomain()
+
+	var buf bytes.Buffer
+	var err error
+	switch dst {
+	case FormatPO:
+		return do.MarshalText()
+	case FormatMO:
+		return do.MarshalMO()
+	case FormatJSON:
+		err = do.ExportJSON(&buf)
+	case FormatXLIFF:
+		err = do.ExportXLIFF(&buf)
+	case FormatCSV:
+		err = do.ExportCSV(&buf)
+	default:
+		return nil, fmt.Errorf("gotext: unknown format %v", dst)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// msgstrs returns the strings of tr in order, with one for each plural form of plural entries.
+func msgstrs(tr *Translation, nplurals int) []string {
+	forms := 1
+	if tr.PluralID != "" && nplurals > forms {
+		forms = nplurals
+	}
+	for i := range tr.Trs {
+		if i >= forms {
+			forms = i + 1
+		}
+	}
+
+	strs := make([]string, forms)
+	for i := range strs {
+		strs[i] = tr.Trs[i]
+	}
+	return strs
+}
@@ -0,0 +1,116 @@
+package gotext
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+const convertPo = `msgid ""
+msgstr ""
+"Language: es\n"
+"Plural-Forms: nplurals=2; plural=(n != 1);\n"
+
+msgctxt "menu"
+msgid "Open"
+msgstr "Abrir"
+
+msgid "Untranslated"
+msgstr ""
+
+msgid "Say <hi> & bye"
+msgstr "Di <hola> y adiós"
+
+#: main.go:10 util.go
+msgid "%d file"
+msgid_plural "%d files"
+msgstr[0] "%d archivo"
+msgstr[1] "%d archivos"
+`
+
+func TestConvert(t *testing.T) {
+	po := NewPo()
+	po.Parse([]byte(convertPo))
+
+	for _, f := range []Format{FormatPO, FormatMO, FormatJSON, FormatXLIFF, FormatCSV} {
+		out, err := Convert(po, f)
+		if err != nil {
+			t.Fatalf("%v: %v", f, err)
+		}
+
+		back := NewPo()
+		do := back.GetDomain()
+		switch f {
+		case FormatPO:
+			back.Parse(out)
+		case FormatMO:
+			mo := NewMo()
+			if err := mo.ParseWithError(out); err != nil {
+				t.Fatalf("%v: %v", f, err)
+			}
+			do = mo.GetDomain()
+		case FormatJSON:
+			err = do.ImportJSON(bytes.NewReader(out))
+		case FormatXLIFF:
+			err = do.ImportXLIFF(bytes.NewReader(out))
+		case FormatCSV:
+			err = do.ImportCSV(bytes.NewReader(out))
+		}
+		if err != nil {
+			t.Fatalf("%v: %v\n%s", f, err, out)
+		}
+
+		if got := do.GetC("Open", "menu"); got != "Abrir" {
+			t.Errorf("%v: unexpected translation %q", f, got)
+		}
+		if got := do.Get("Say <hi> & bye"); got != "Di <hola> y adiós" {
+			t.Errorf("%v: unexpected translation %q", f, got)
+		}
+		if got := do.GetN("%d file", "%d files", 2); got != "%d archivos" {
+			t.Errorf("%v: unexpected plural %q", f, got)
+		}
+		if f == FormatCSV {
+			// CSV has no headers
+			continue
+		}
+		if lang := do.GetLanguage(); lang != "es" {
+			t.Errorf("%v: unexpected language %q", f, lang)
+		}
+		if got := do.GetN("%d file", "%d files", 1); got != "%d archivo" {
+			t.Errorf("%v: unexpected plural %q", f, got)
+		}
+		if f == FormatMO {
+			continue
+		}
+		if refs := do.GetRefs("%d file"); strings.Join(refs, " ") != "main.go:10 util.go" {
+			t.Errorf("%v: unexpected refs %v", f, refs)
+		}
+	}
+}
+
+func TestConvertFromFluent(t *testing.T) {
+	ftl := NewFluent("en")
+	ftl.Parse([]byte("hello = Hello, world!\n"))
+
+	out, err := Convert(ftl, FormatPO)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(out), "msgid \"hello\"\nmsgstr \"Hello, world!\"") {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	for name, want := range map[string]Format{"po": FormatPO, ".pot": FormatPO, "MO": FormatMO, ".xlf": FormatXLIFF, "xliff": FormatXLIFF, "json": FormatJSON, ".csv": FormatCSV} {
+		if f, err := ParseFormat(name); err != nil || f != want {
+			t.Errorf("ParseFormat(%q) = %v, %v", name, f, err)
+		}
+	}
+	if _, err := ParseFormat("docx"); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+	if s := FormatXLIFF.String(); s != "xliff" {
+		t.Errorf("unexpected name %q", s)
+	}
+}
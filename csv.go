@@ -0,0 +1,170 @@
+package gotext
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ExportCSV writes the translations of the Domain as CSV, so they can be reviewed and edited in a spreadsheet,
+// and loaded back with ImportCSV.
+//
+// The first row has the column names: context, msgid, plural, msgstr[0] to msgstr[n] and comments.
+// There's a msgstr column for every plural form of the catalog, and the comments column holds
+// the source references of the entry (#: comments), one per line.
+// Entries are sorted by context and msgid, and the header entry is skipped.
+func (do *Domain) ExportCSV(w io.Writer) error {
+	return do.exportDelimited(w, ',')
+}
+
+// ExportTSV works like ExportCSV, with tab separated columns.
+func (do *Domain) ExportTSV(w io.Writer) error {
+	return do.exportDelimited(w, '\t')
+}
+
+func (do *Domain) exportDelimited(w io.Writer, comma rune) error {
+	entries := do.entries()
+
+	forms := do.GetNPlurals()
+	if forms < 1 {
+		forms = 1
+	}
+	for _, e := range entries {
+		for i := range e.Translation.Trs {
+			if i >= forms {
+				forms = i + 1
+			}
+		}
+	}
+
+	cw := csv.NewWriter(w)
+	cw.Comma = comma
+
+	row := []string{"context", "msgid", "plural"}
+	for i := 0; i < forms; i++ {
+		row = append(row, "msgstr["+strconv.Itoa(i)+"]")
+	}
+	row = append(row, "comments")
+	if err := cw.Write(row); err != nil {
+		return err
+	}
+
+	for _, e := range entries {
+		row = append(row[:0], e.Context, e.MsgID, e.Translation.PluralID)
+		for i := 0; i < forms; i++ {
+			row = append(row, e.Translation.Trs[i])
+		}
+		row = append(row, strings.Join(e.Translation.Refs, "\n"))
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// ImportCSV loads translations from CSV data in the ExportCSV layout, replacing the ones with the same context and msgid.
+// Columns are found by the names in the first row, so they can be reordered, and only msgid is required.
+// Empty msgstr cells leave the form untranslated. Rows with an empty msgid are skipped.
+//
+// Nothing is loaded when the data can't be read, and the error tells the line of the problem.
+func (do *Domain) ImportCSV(r io.Reader) error {
+	return do.importDelimited(r, ',')
+}
+
+// ImportTSV works like ImportCSV, with tab separated columns.
+func (do *Domain) ImportTSV(r io.Reader) error {
+	return do.importDelimited(r, '\t')
+}
+
+func (do *Domain) importDelimited(r io.Reader, comma rune) error {
+	cr := csv.NewReader(r)
+	cr.Comma = comma
+	cr.LazyQuotes = true
+
+	records, err := cr.ReadAll()
+	if err != nil {
+		return err
+	}
+	if len(records) == 0 {
+		return nil
+	}
+
+	// Column indexes
+	ctxCol, idCol, pluralCol, commentsCol := -1, -1, -1, -1
+	msgstrCols := make(map[int]int)
+	for col, name := range records[0] {
+		switch name = strings.ToLower(strings.TrimSpace(name)); {
+		case name == "context" || name == "msgctxt":
+			ctxCol = col
+		case name == "msgid":
+			idCol = col
+		case name == "plural" || name == "msgid_plural":
+			pluralCol = col
+		case name == "comments":
+			commentsCol = col
+		case name == "msgstr":
+			msgstrCols[col] = 0
+		case strings.HasPrefix(name, "msgstr[") && strings.HasSuffix(name, "]"):
+			i, err := strconv.Atoi(name[len("msgstr[") : len(name)-1])
+			if err != nil || i < 0 {
+				return fmt.Errorf("gotext: invalid column name %q", records[0][col])
+			}
+			msgstrCols[col] = i
+		}
+	}
+	if idCol == -1 {
+		return fmt.Errorf("gotext: missing msgid column")
+	}
+
+	cell := func(row []string, col int) string {
+		if col == -1 || col >= len(row) {
+			return ""
+		}
+		return row[col]
+	}
+
+	do.trMutex.Lock()
+	do.pluralMutex.Lock()
+	defer do.trMutex.Unlock()
+	defer do.pluralMutex.Unlock()
+
+	for _, row := range records[1:] {
+		msgid := cell(row, idCol)
+		if msgid == "" {
+			continue
+		}
+
+		tr := NewTranslation()
+		tr.ID = msgid
+		tr.PluralID = cell(row, pluralCol)
+		for col, i := range msgstrCols {
+			tr.SetN(i, cell(row, col))
+		}
+		if commentsCol != -1 {
+			tr.Refs = strings.Fields(cell(row, commentsCol))
+		}
+
+		ctx := cell(row, ctxCol)
+		if ctx == "" {
+			if old, ok := do.translations[msgid]; ok && commentsCol == -1 {
+				tr.Refs = old.Refs
+			}
+			do.translations[msgid] = tr
+			continue
+		}
+
+		if _, ok := do.contexts[ctx]; !ok {
+			do.contexts[ctx] = make(map[string]*Translation)
+		}
+		if old, ok := do.contexts[ctx][msgid]; ok && commentsCol == -1 {
+			tr.Refs = old.Refs
+		}
+		do.contexts[ctx][msgid] = tr
+	}
+
+	return nil
+}
@@ -0,0 +1,133 @@
+package gotext
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+const csvPo = `
+msgid ""
+msgstr ""
+"Language: en\n"
+"Plural-Forms: nplurals=2; plural=(n != 1);\n"
+
+msgid "Hello, \"world\""
+msgstr "Hola, \"mundo\""
+
+msgid "%d file"
+msgid_plural "%d files"
+msgstr[0] "%d archivo"
+msgstr[1] "%d archivos"
+
+msgctxt "menu"
+msgid "Open"
+msgstr "Abrir"
+
+#: main.go:10 main.go:20
+msgid "Untranslated"
+msgstr ""
+`
+
+func TestDomain_ExportCSV(t *testing.T) {
+	po := NewPo()
+	po.Parse([]byte(csvPo))
+
+	var buf bytes.Buffer
+	if err := po.GetDomain().ExportCSV(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `context,msgid,plural,msgstr[0],msgstr[1],comments
+,%d file,%d files,%d archivo,%d archivos,
+,"Hello, ""world""",,"Hola, ""mundo""",,
+,Untranslated,,,,"main.go:10
+main.go:20"
+menu,Open,,Abrir,,
+`
+	if buf.String() != want {
+		t.Errorf("unexpected CSV:\n%s", buf.String())
+	}
+}
+
+func TestDomain_ImportCSV(t *testing.T) {
+	po := NewPo()
+	po.Parse([]byte(csvPo))
+
+	// Reordered columns, without comments
+	data := `msgid,msgstr[0],context,plural,msgstr[1]
+Untranslated,Sin traducir,,,
+Open,Abrir archivo,menu,,
+%d file,%d fichero,,%d files,%d ficheros
+New,Nuevo,,,
+,ignored,,,
+`
+	if err := po.GetDomain().ImportCSV(strings.NewReader(data)); err != nil {
+		t.Fatal(err)
+	}
+
+	if tr := po.Get("Untranslated"); tr != "Sin traducir" {
+		t.Errorf("unexpected translation %q", tr)
+	}
+	if tr := po.Get("New"); tr != "Nuevo" {
+		t.Errorf("unexpected translation %q", tr)
+	}
+	if tr := po.GetC("Open", "menu"); tr != "Abrir archivo" {
+		t.Errorf("unexpected translation %q", tr)
+	}
+	if tr := po.GetN("%d file", "%d files", 3, 3); tr != "3 ficheros" {
+		t.Errorf("unexpected translation %q", tr)
+	}
+	if tr := po.Get("Hello, \"world\""); tr != "Hola, \"mundo\"" {
+		t.Errorf("rows not in the CSV should be kept, got %q", tr)
+	}
+	if refs := po.GetRefs("Untranslated"); len(refs) != 2 {
+		t.Errorf("unexpected refs %v", refs)
+	}
+}
+
+func TestDomain_CSVRoundTrip(t *testing.T) {
+	po := NewPo()
+	po.Parse([]byte(csvPo))
+
+	var buf bytes.Buffer
+	if err := po.GetDomain().ExportTSV(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	other := NewPo()
+	other.Parse([]byte(csvPo))
+	other.GetDomain().Iterate(func(ctx, msgid string, tr *Translation) bool {
+		tr.Trs = map[int]string{}
+		tr.Refs = nil
+		return true
+	})
+	if err := other.GetDomain().ImportTSV(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	// MarshalText writes plural forms in map order, so compare the exports
+	var a, b bytes.Buffer
+	po.GetDomain().ExportTSV(&a)
+	other.GetDomain().ExportTSV(&b)
+	if !bytes.Equal(a.Bytes(), b.Bytes()) {
+		t.Errorf("round trip mismatch:\n%s\n---\n%s", a.Bytes(), b.Bytes())
+	}
+}
+
+func TestDomain_ImportCSVErrors(t *testing.T) {
+	do := NewDomain()
+
+	if err := do.ImportCSV(strings.NewReader("context,msgstr\nmenu,Abrir\n")); err == nil {
+		t.Error("expected an error for a missing msgid column")
+	}
+	if err := do.ImportCSV(strings.NewReader("msgid,msgstr[x]\nOpen,Abrir\n")); err == nil {
+		t.Error("expected an error for an invalid msgstr column")
+	}
+	if err := do.ImportCSV(strings.NewReader("msgid,msgstr\nOpen,Abrir\nClose\n")); err == nil {
+		t.Error("expected an error for a row with missing columns")
+	}
+	if _, ok := do.GetTranslation("Open"); ok {
+		t.Error("nothing should be loaded on error")
+	}
+}
@@ -0,0 +1,126 @@
+package gotext
+
+import (
+	"fmt"
+	"strings"
+)
+
+// EntryChange is an entry whose translation differs between two versions of a catalog.
+type EntryChange struct {
+	Context string
+	MsgID   string
+
+	// Translation of the entry in each version
+	Old *Translation
+	New *Translation
+}
+
+// DomainDiff lists the differences between two versions of a catalog, as computed by DiffDomains.
+// Entries of every list are copies, sorted by context and msgid.
+type DomainDiff struct {
+	// Entries only in the new version
+	Added []Entry
+
+	// Entries only in the old version
+	Removed []Entry
+
+	// Entries translated differently in the new version, including a different msgid_plural
+	Changed []EntryChange
+
+	// Entries translated in the old version with empty msgstrs in the new one
+	Untranslated []EntryChange
+
+	// Entries marked fuzzy in the new version only, whether their translation changed or not
+	Fuzzy []EntryChange
+}
+
+// Empty reports whether both versions of the catalog have the same entries and translations.
+func (d DomainDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0 && len(d.Untranslated) == 0 && len(d.Fuzzy) == 0
+}
+
+// String returns a summary of the differences, with one line per entry.
+func (d DomainDiff) String() string {
+	var b strings.Builder
+	key := func(ctx, msgid string) string {
+		if ctx != "" {
+			return fmt.Sprintf("msgctxt %q msgid %q", ctx, msgid)
+		}
+		return fmt.Sprintf("msgid %q", msgid)
+	}
+	for _, e := range d.Added {
+		fmt.Fprintf(&b, "added %s\n", key(e.Context, e.MsgID))
+	}
+	for _, e := range d.Removed {
+		fmt.Fprintf(&b, "removed %s\n", key(e.Context, e.MsgID))
+	}
+	for _, c := range d.Changed {
+		fmt.Fprintf(&b, "changed %s: %q -> %q\n", key(c.Context, c.MsgID), c.Old.Get(), c.New.Get())
+	}
+	for _, c := range d.Untranslated {
+		fmt.Fprintf(&b, "untranslated %s\n", key(c.Context, c.MsgID))
+	}
+	for _, c := range d.Fuzzy {
+		fmt.Fprintf(&b, "fuzzy %s\n", key(c.Context, c.MsgID))
+	}
+	return b.String()
+}
+
+/*
+DiffDomains compares two versions of a catalog, a being the old one and b the new one,
+to write release notes for translators or check in CI that translations didn't regress:
+
+	diff := gotext.DiffDomains(old.GetDomain(), po.GetDomain())
+	if len(diff.Untranslated) > 0 {
+		t.Errorf("translations lost:\n%s", diff)
+	}
+
+Entries are matched by context and msgid. Headers and source references aren't compared.
+Every changed entry is in only one of the Changed, Untranslated and Fuzzy lists.
+*/
+func DiffDomains(a, b *Domain) DomainDiff {
+	var diff DomainDiff
+
+	old := a.Export()
+	for _, e := range b.entries() {
+		k := CatalogKey{Context: e.Context, MsgID: e.MsgID}
+		prev, ok := old[k]
+		if !ok {
+			e.Translation = copyTranslation(e.Translation)
+			diff.Added = append(diff.Added, e)
+			continue
+		}
+		delete(old, k)
+
+		c := EntryChange{Context: e.Context, MsgID: e.MsgID, Old: prev.Translation, New: copyTranslation(e.Translation)}
+		switch {
+		case c.Old.IsTranslated() && !c.New.IsTranslated():
+			diff.Untranslated = append(diff.Untranslated, c)
+		case c.New.Fuzzy && !c.Old.Fuzzy:
+			diff.Fuzzy = append(diff.Fuzzy, c)
+		case !sameTranslation(c.Old, c.New):
+			diff.Changed = append(diff.Changed, c)
+		}
+	}
+
+	for _, e := range a.entries() {
+		if prev, ok := old[CatalogKey{Context: e.Context, MsgID: e.MsgID}]; ok {
+			diff.Removed = append(diff.Removed, prev)
+		}
+	}
+
+	return diff
+}
+
+// sameTranslation reports whether x and y have the same msgid_plural and msgstrs.
+func sameTranslation(x, y *Translation) bool {
+	if x.PluralID != y.PluralID || len(x.Trs) != len(y.Trs) {
+		return false
+	}
+	for i, str := range x.Trs {
+		if other, ok := y.Trs[i]; !ok || other != str {
+			return false
+		}
+	}
+	return true
+}
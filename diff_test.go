@@ -0,0 +1,97 @@
+package gotext
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDiffDomains(t *testing.T) {
+	old := NewPo()
+	old.Parse([]byte(`
+msgid "Hello"
+msgstr "Hola"
+
+msgid "Bye"
+msgstr "Adiós"
+
+msgid "Save"
+msgstr "Guardar"
+
+msgid "Open"
+msgstr "Abrir"
+
+msgid "Removed"
+msgstr "Eliminado"
+
+msgctxt "menu"
+msgid "%d file"
+msgid_plural "%d files"
+msgstr[0] "%d archivo"
+msgstr[1] "%d archivos"
+`))
+	po := NewPo()
+	po.Parse([]byte(`
+#: main.go:1
+msgid "Hello"
+msgstr "Hola"
+
+msgid "Bye"
+msgstr "Chao"
+
+msgid "Save"
+msgstr ""
+
+#, fuzzy
+msgid "Open"
+msgstr "Abrir"
+
+msgid "Added"
+msgstr "Añadido"
+
+msgctxt "menu"
+msgid "%d file"
+msgid_plural "%d files"
+msgstr[0] "%d archivo"
+msgstr[1] "%d ficheros"
+`))
+
+	diff := DiffDomains(old.GetDomain(), po.GetDomain())
+	if diff.Empty() {
+		t.Fatal("Expected differences")
+	}
+	if len(diff.Added) != 1 || diff.Added[0].MsgID != "Added" {
+		t.Errorf("Unexpected added entries %v", diff.Added)
+	}
+	if len(diff.Removed) != 1 || diff.Removed[0].MsgID != "Removed" {
+		t.Errorf("Unexpected removed entries %v", diff.Removed)
+	}
+	if len(diff.Changed) != 2 || diff.Changed[0].MsgID != "Bye" || diff.Changed[1].Context != "menu" {
+		t.Errorf("Unexpected changed entries %v", diff.Changed)
+	}
+	if c := diff.Changed[0]; c.Old.Get() != "Adiós" || c.New.Get() != "Chao" {
+		t.Errorf("Unexpected change %v", c)
+	}
+	if len(diff.Untranslated) != 1 || diff.Untranslated[0].MsgID != "Save" {
+		t.Errorf("Unexpected untranslated entries %v", diff.Untranslated)
+	}
+	if len(diff.Fuzzy) != 1 || diff.Fuzzy[0].MsgID != "Open" {
+		t.Errorf("Unexpected fuzzy entries %v", diff.Fuzzy)
+	}
+
+	s := diff.String()
+	for _, line := range []string{`added msgid "Added"`, `changed msgid "Bye": "Adiós" -> "Chao"`, `untranslated msgid "Save"`, `fuzzy msgid "Open"`, `msgctxt "menu" msgid "%d file"`} {
+		if !strings.Contains(s, line) {
+			t.Errorf("Expected %q in the summary:\n%s", line, s)
+		}
+	}
+
+	// The entries are copies
+	diff.Added[0].Translation.Set("x")
+	if tr := po.Get("Added"); tr != "Añadido" {
+		t.Errorf("Expected the Domain to be unchanged, got %q", tr)
+	}
+
+	if diff := DiffDomains(po.GetDomain(), po.GetDomain()); !diff.Empty() {
+		t.Errorf("Unexpected differences:\n%s", diff)
+	}
+}
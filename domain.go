@@ -6,7 +6,6 @@ import (
 	"sort"
 	"strconv"
 	"strings"
-	"sync"
 
 	"golang.org/x/text/feature/plural"
 	"golang.org/x/text/language"
@@ -32,24 +31,64 @@ type Domain struct {
 	// Preserve comments at head of PO for round-trip
 	headerComments []string
 
+	// Obsolete (#~) entries of a PO file, kept for round-trip
+	Obsolete []Entry
+
 	// Parsed Plural-Forms header values
 	nplurals    int
 	plural      string
 	pluralforms plurals.Expression
+	pluralCache *pluralCache
+
+	// Plural-Forms header check against CLDR
+	pluralPolicy   PluralPolicy
+	pluralMismatch *PluralMismatch
+
+	// Handling of duplicate msgids while parsing, and the ones found by the last Parse
+	duplicatePolicy DuplicatePolicy
+	duplicates      []*DuplicateEntry
+
+	// Handling of plural forms not defined by a translation
+	pluralFallback PluralFallback
+	pluralHook     func(PluralOutOfRange)
 
 	// Storage
 	translations       map[string]*Translation
 	contexts           map[string]map[string]*Translation
 	pluralTranslations map[string]*Translation
 
+	// Index of the normalized msgids, by context for the ones with a context
+	normalization Normalization
+	normalized    map[string]*Translation
+	normalizedC   map[string]map[string]*Translation
+
+	// Handling of lookups with a missing msgid
+	fuzzyThreshold float64
+	missingHook    func(MissingTranslation)
+
+	// Language and domain name reported to Metrics
+	metricsLang   string
+	metricsDomain string
+
 	// Sync Mutex
-	trMutex     sync.RWMutex
-	pluralMutex sync.RWMutex
+	trMutex     domainMutex
+	pluralMutex domainMutex
 
 	// Parsing buffers
 	trBuffer  *Translation
 	ctxBuffer string
 	refBuffer string
+	fuzBuffer bool
+
+	// File being parsed, and line where the buffered entry starts
+	fileBuffer string
+	lineBuffer int
+
+	// Entries by the block of text they were parsed from, kept by Locale.Reload to reparse only the changed ones
+	blocks map[blockKey]*indexedBlock
+
+	// Parse progress reporting
+	progress progressTracker
 }
 
 // Preserve MIMEHeader behaviour, without the canonicalisation
@@ -106,11 +145,13 @@ func (do *Domain) pluralForm(n int) int {
 		}
 		return 1
 	}
-	return do.pluralforms.Eval(uint32(n))
+	return do.pluralCache.eval(do.pluralforms, n)
 }
 
 // parseHeaders retrieves data from previously parsed headers. it's called by both Mo and Po when parsing
 func (do *Domain) parseHeaders() {
+	defer do.checkPlurals()
+
 	raw := ""
 	if _, ok := do.translations[raw]; ok {
 		raw = do.translations[raw].Get()
@@ -153,28 +194,14 @@ func (do *Domain) parseHeaders() {
 		return
 	}
 
-	// Split plural form header value
-	pfs := strings.Split(do.PluralForms, ";")
-
-	// Parse values
-	for _, i := range pfs {
-		vs := strings.SplitN(i, "=", 2)
-		if len(vs) != 2 {
-			continue
-		}
-
-		switch strings.TrimSpace(vs[0]) {
-		case "nplurals":
-			do.nplurals, _ = strconv.Atoi(vs[1])
-
-		case "plural":
-			do.plural = vs[1]
-
-			if expr, err := plurals.Compile(do.plural); err == nil {
-				do.pluralforms = expr
-			}
-
-		}
+	nplurals, plural, expr, err := parsePluralForms(do.PluralForms)
+	if err != nil {
+		logWarn("gotext: invalid Plural-Forms header", "language", do.Language, "err", err)
+	}
+	do.nplurals = nplurals
+	do.plural = plural
+	if expr != nil {
+		do.setPluralExpression(expr)
 	}
 }
 
@@ -186,6 +213,8 @@ func (do *Domain) DropStaleTranslations() {
 	defer do.trMutex.Unlock()
 	defer do.pluralMutex.Unlock()
 
+	do.blocks = nil
+
 	for name, ctx := range do.contexts {
 		for id, trans := range ctx {
 			if trans.IsStale() {
@@ -211,6 +240,8 @@ func (do *Domain) SetRefs(str string, refs []string) {
 	defer do.trMutex.Unlock()
 	defer do.pluralMutex.Unlock()
 
+	do.blocks = nil
+
 	if trans, ok := do.translations[str]; ok {
 		trans.Refs = refs
 	} else {
@@ -242,6 +273,8 @@ func (do *Domain) Set(id, str string) {
 	defer do.trMutex.Unlock()
 	defer do.pluralMutex.Unlock()
 
+	do.blocks = nil
+
 	if trans, ok := do.translations[id]; ok {
 		trans.Set(str)
 	} else {
@@ -257,10 +290,8 @@ func (do *Domain) Get(str string, vars ...interface{}) string {
 	do.trMutex.RLock()
 	defer do.trMutex.RUnlock()
 
-	if do.translations != nil {
-		if _, ok := do.translations[str]; ok {
-			return Printf(do.translations[str].Get(), vars...)
-		}
+	if tr, ok := do.lookup(str); ok {
+		return Printf(tr.Get(), vars...)
 	}
 
 	// Return the same we received by default
@@ -277,6 +308,8 @@ func (do *Domain) SetN(id, plural string, n int, str string) {
 	defer do.trMutex.Unlock()
 	defer do.pluralMutex.Unlock()
 
+	do.blocks = nil
+
 	if trans, ok := do.translations[id]; ok {
 		trans.SetN(pluralForm, str)
 	} else {
@@ -295,10 +328,8 @@ func (do *Domain) GetN(str, plural string, n int, vars ...interface{}) string {
 	do.trMutex.RLock()
 	defer do.trMutex.RUnlock()
 
-	if do.translations != nil {
-		if _, ok := do.translations[str]; ok {
-			return Printf(do.translations[str].GetN(do.pluralForm(n)), vars...)
-		}
+	if tr, ok := do.lookup(str); ok {
+		return Printf(do.pluralString("", tr, do.pluralForm(n)), vars...)
 	}
 
 	// Parse plural forms to distinguish between plural and singular
@@ -315,6 +346,8 @@ func (do *Domain) SetC(id, ctx, str string) {
 	defer do.trMutex.Unlock()
 	defer do.pluralMutex.Unlock()
 
+	do.blocks = nil
+
 	if context, ok := do.contexts[ctx]; ok {
 		if trans, hasTrans := context[id]; hasTrans {
 			trans.Set(str)
@@ -340,14 +373,8 @@ func (do *Domain) GetC(str, ctx string, vars ...interface{}) string {
 	do.trMutex.RLock()
 	defer do.trMutex.RUnlock()
 
-	if do.contexts != nil {
-		if _, ok := do.contexts[ctx]; ok {
-			if do.contexts[ctx] != nil {
-				if _, ok := do.contexts[ctx][str]; ok {
-					return Printf(do.contexts[ctx][str].Get(), vars...)
-				}
-			}
-		}
+	if tr, ok := do.lookupC(str, ctx); ok {
+		return Printf(tr.Get(), vars...)
 	}
 
 	// Return the string we received by default
@@ -364,6 +391,8 @@ func (do *Domain) SetNC(id, plural, ctx string, n int, str string) {
 	defer do.trMutex.Unlock()
 	defer do.pluralMutex.Unlock()
 
+	do.blocks = nil
+
 	if context, ok := do.contexts[ctx]; ok {
 		if trans, hasTrans := context[id]; hasTrans {
 			trans.SetN(pluralForm, str)
@@ -389,14 +418,8 @@ func (do *Domain) GetNC(str, plural string, n int, ctx string, vars ...interface
 	do.trMutex.RLock()
 	defer do.trMutex.RUnlock()
 
-	if do.contexts != nil {
-		if _, ok := do.contexts[ctx]; ok {
-			if do.contexts[ctx] != nil {
-				if _, ok := do.contexts[ctx][str]; ok {
-					return Printf(do.contexts[ctx][str].GetN(do.pluralForm(n)), vars...)
-				}
-			}
-		}
+	if tr, ok := do.lookupC(str, ctx); ok {
+		return Printf(do.pluralString(ctx, tr, do.pluralForm(n)), vars...)
 	}
 
 	if n == 1 {
@@ -426,17 +449,8 @@ func extractPathAndLine(ref string) (string, int) {
 	return path, line
 }
 
-// MarshalText implements encoding.TextMarshaler interface
-// Assists round-trip of POT/PO content
-func (do *Domain) MarshalText() ([]byte, error) {
-	var buf bytes.Buffer
-	if len(do.headerComments) > 0 {
-		buf.WriteString(strings.Join(do.headerComments, "\n"))
-		buf.WriteByte(byte('\n'))
-	}
-	buf.WriteString("msgid \"\"\nmsgstr \"\"")
-
-	// Standard order consistent with xgettext
+// headerKeys returns the keys of the Headers map in the standard xgettext order.
+func (do *Domain) headerKeys() []string {
 	headerOrder := map[string]int{
 		"project-id-version":        0,
 		"report-msgid-bugs-to":      1,
@@ -478,7 +492,20 @@ func (do *Domain) MarshalText() ([]byte, error) {
 		return headerKeys[i] < headerKeys[j]
 	})
 
-	for _, k := range headerKeys {
+	return headerKeys
+}
+
+// MarshalText implements encoding.TextMarshaler interface
+// Assists round-trip of POT/PO content
+func (do *Domain) MarshalText() ([]byte, error) {
+	var buf bytes.Buffer
+	if len(do.headerComments) > 0 {
+		buf.WriteString(strings.Join(do.headerComments, "\n"))
+		buf.WriteByte(byte('\n'))
+	}
+	buf.WriteString("msgid \"\"\nmsgstr \"\"")
+
+	for _, k := range do.headerKeys() {
 		// Access Headers map directly so as not to canonicalise
 		v := do.Headers[k]
 
@@ -566,6 +593,9 @@ func (do *Domain) MarshalText() ([]byte, error) {
 		} else {
 			buf.WriteByte(byte('\n'))
 		}
+		if trans.Fuzzy {
+			buf.WriteString("\n#, fuzzy")
+		}
 
 		if ref.context == "" {
 			buf.WriteString("\nmsgid \"" + trans.ID + "\"")
@@ -583,6 +613,25 @@ func (do *Domain) MarshalText() ([]byte, error) {
 		}
 	}
 
+	// Obsolete entries go last, commented out
+	for _, e := range do.Obsolete {
+		trans := e.Translation
+		buf.WriteByte(byte('\n'))
+		if e.Context != "" {
+			buf.WriteString("\n#~ msgctxt \"" + e.Context + "\"")
+		}
+		buf.WriteString("\n#~ msgid \"" + e.MsgID + "\"")
+
+		if trans.PluralID == "" {
+			buf.WriteString("\n#~ msgstr \"" + trans.Trs[0] + "\"")
+		} else {
+			buf.WriteString("\n#~ msgid_plural \"" + trans.PluralID + "\"")
+			for i := 0; i < len(trans.Trs); i++ {
+				buf.WriteString("\n#~ msgstr[" + strconv.Itoa(i) + "] \"" + trans.Trs[i] + "\"")
+			}
+		}
+	}
+
 	return buf.Bytes(), nil
 }
 
@@ -617,6 +666,7 @@ func (do *Domain) UnmarshalBinary(data []byte) error {
 
 	do.Headers = obj.Headers
 	do.Language = obj.Language
+	do.tag = language.Make(do.Language)
 	do.PluralForms = obj.PluralForms
 	do.nplurals = obj.Nplurals
 	do.plural = obj.Plural
@@ -624,13 +674,14 @@ func (do *Domain) UnmarshalBinary(data []byte) error {
 	do.contexts = obj.Contexts
 
 	if expr, err := plurals.Compile(do.plural); err == nil {
-		do.pluralforms = expr
+		do.setPluralExpression(expr)
 	}
 
 	return nil
 }
 
 //GetAll retrieves all translations in the domain
+//Messages in a context are keyed by their CatalogKey string. See Export for a lossless copy of the translations.
 func (do *Domain) GetAll() (map[string]*store.Translation, error) {
 	lcData, err := localizer.GetLocaleData(do.tag)
 	if err != nil {
@@ -640,26 +691,85 @@ func (do *Domain) GetAll() (map[string]*store.Translation, error) {
 	do.trMutex.RLock()
 	defer do.trMutex.RUnlock()
 
+	var forms []plural.Form
+	if lcData != nil {
+		forms = lcData.Plural.Cardinal.Forms
+	}
+	newTranslation := func(msg *Translation) *store.Translation {
+		return storeTranslation(forms, msg)
+	}
+
 	all := make(map[string]*store.Translation, len(do.translations))
 	for messageID, msg := range do.translations {
-		newTranslation := &store.Translation{
-			ID:       msg.ID,
-			PluralID: msg.PluralID,
-			String:   msg.Get(),
+		all[messageID] = newTranslation(msg)
+		if msg.PluralID != "" {
+			all[msg.PluralID] = all[messageID]
 		}
+	}
 
-		if msg.PluralID != "" && lcData != nil && len(lcData.Plural.Cardinal.Forms) > 0 {
-			plForms := make(map[plural.Form]string, len(lcData.Plural.Cardinal.Forms))
-			for i, form := range lcData.Plural.Cardinal.Forms {
-				plForms[form] = msg.GetN(i)
+	// Messages in a context are keyed like in MO files, see CatalogKey
+	for ctx, translations := range do.contexts {
+		for messageID, msg := range translations {
+			key := CatalogKey{Context: ctx, MsgID: messageID}
+			all[key.String()] = newTranslation(msg)
+			if msg.PluralID != "" {
+				all[CatalogKey{Context: ctx, MsgID: msg.PluralID}.String()] = all[key.String()]
 			}
-			newTranslation.Plurals = plForms
 		}
-		all[messageID] = newTranslation
+	}
+
+	return all, nil
+}
+
+// GetAllByContext retrieves the translations of the messages in the context ctx, keyed by msgid,
+// like GetAll does for the messages without context. An empty ctx retrieves the messages without context.
+// See Contexts for the contexts used by the domain.
+func (do *Domain) GetAllByContext(ctx string) (map[string]*store.Translation, error) {
+	lcData, err := localizer.GetLocaleData(do.tag)
+	if err != nil {
+		return nil, err
+	}
+
+	do.trMutex.RLock()
+	defer do.trMutex.RUnlock()
+
+	var forms []plural.Form
+	if lcData != nil {
+		forms = lcData.Plural.Cardinal.Forms
+	}
+
+	translations := do.translations
+	if ctx != "" {
+		translations = do.contexts[ctx]
+	}
+
+	all := make(map[string]*store.Translation, len(translations))
+	for messageID, msg := range translations {
+		if messageID == "" {
+			continue
+		}
+		all[messageID] = storeTranslation(forms, msg)
 		if msg.PluralID != "" {
-			all[msg.PluralID] = newTranslation
+			all[msg.PluralID] = all[messageID]
 		}
 	}
-
 	return all, nil
 }
+
+// storeTranslation converts msg to a store.Translation, with its plural forms keyed by the CLDR categories of the language.
+func storeTranslation(forms []plural.Form, msg *Translation) *store.Translation {
+	tr := &store.Translation{
+		ID:       msg.ID,
+		PluralID: msg.PluralID,
+		String:   msg.Get(),
+	}
+
+	if msg.PluralID != "" && len(forms) > 0 {
+		plForms := make(map[plural.Form]string, len(forms))
+		for i, form := range forms {
+			plForms[form] = msg.GetN(i)
+		}
+		tr.Plurals = plForms
+	}
+	return tr
+}
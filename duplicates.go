@@ -0,0 +1,84 @@
+package gotext
+
+import "fmt"
+
+// DuplicatePolicy tells what to do when a PO catalog has the same msgid twice in the same context,
+// in one file or across the files parsed into the same Po object.
+type DuplicatePolicy int
+
+const (
+	// DuplicateKeepLast uses the last entry, silently. It's the default.
+	DuplicateKeepLast DuplicatePolicy = iota
+	// DuplicateKeepFirst uses the first entry, ignoring the next ones.
+	DuplicateKeepFirst
+	// DuplicateWarn uses the last entry, and logs a warning for every duplicate with the Logger.
+	DuplicateWarn
+	// DuplicateError refuses the catalog: Locale objects don't load it and report the first duplicate as an error.
+	DuplicateError
+)
+
+// DuplicateEntry is a msgid found more than once in the same context of a catalog.
+type DuplicateEntry struct {
+	Context string
+	MsgID   string
+}
+
+func (e *DuplicateEntry) Error() string {
+	if e.Context != "" {
+		return fmt.Sprintf("gotext: duplicate msgid %q in context %q", e.MsgID, e.Context)
+	}
+	return fmt.Sprintf("gotext: duplicate msgid %q", e.MsgID)
+}
+
+// SetDuplicatePolicy sets how the next parsed PO catalog handles duplicate msgids. See DuplicatePolicy.
+func (do *Domain) SetDuplicatePolicy(p DuplicatePolicy) {
+	do.trMutex.Lock()
+	do.duplicatePolicy = p
+	do.trMutex.Unlock()
+}
+
+// Duplicates returns the duplicate msgids found by the last Parse, whatever the policy is, in the order found.
+func (do *Domain) Duplicates() []*DuplicateEntry {
+	do.trMutex.RLock()
+	defer do.trMutex.RUnlock()
+
+	return append([]*DuplicateEntry(nil), do.duplicates...)
+}
+
+// keepDuplicate records tr as a duplicate of old, the entry with the same context and msgid,
+// and reports whether tr replaces it. It's called while parsing, so the Domain is already locked.
+func (do *Domain) keepDuplicate(ctx string, old, tr *Translation) bool {
+	do.duplicates = append(do.duplicates, &DuplicateEntry{Context: ctx, MsgID: tr.ID})
+
+	switch do.duplicatePolicy {
+	case DuplicateKeepFirst:
+		if tr.PluralID != "" && do.pluralTranslations[tr.PluralID] == tr {
+			if old.PluralID == tr.PluralID {
+				do.pluralTranslations[tr.PluralID] = old
+			} else {
+				delete(do.pluralTranslations, tr.PluralID)
+			}
+		}
+		return false
+	case DuplicateWarn:
+		logWarn("gotext: duplicate msgid in catalog", "lang", do.metricsLang, "domain", do.metricsDomain, "msgctxt", ctx, "msgid", tr.ID)
+	}
+	return true
+}
+
+/*
+SetDuplicatePolicy sets how the PO catalogs loaded from then on by the Locale handle duplicate msgids.
+See DuplicatePolicy. With DuplicateError, AddDomain doesn't load a catalog with duplicates,
+and AddDomainFile, AddDomainBytes, Reload and Locales.LoadAll return the first one as a *DuplicateEntry:
+
+	l := gotext.NewLocale("/path/to/i18n/dir", "es")
+	l.SetDuplicatePolicy(gotext.DuplicateError)
+	if err := l.AddDomainFile("default", "/path/to/i18n/dir/es/default.po"); err != nil {
+		log.Fatal(err)
+	}
+*/
+func (l *Locale) SetDuplicatePolicy(p DuplicatePolicy) {
+	l.Lock()
+	l.duplicatePolicy = p
+	l.Unlock()
+}
@@ -0,0 +1,124 @@
+package gotext
+
+import (
+	"errors"
+	"testing"
+)
+
+const duplicatesPo = `msgid ""
+msgstr "Language: es\n"
+
+msgid "Hello"
+msgstr "Hola"
+
+msgctxt "menu"
+msgid "Open"
+msgstr "Abrir"
+
+msgid "Hello"
+msgstr "Buenas"
+
+msgctxt "menu"
+msgid "Open"
+msgstr "Abre"
+
+msgid "Open"
+msgstr "Abierto"
+`
+
+func TestDuplicatePolicy(t *testing.T) {
+	hello, open := "Hello", "Open"
+	tests := []struct {
+		policy      DuplicatePolicy
+		hello, open string
+	}{
+		{DuplicateKeepLast, "Buenas", "Abre"},
+		{DuplicateKeepFirst, "Hola", "Abrir"},
+		{DuplicateWarn, "Buenas", "Abre"},
+		{DuplicateError, "Buenas", "Abre"},
+	}
+
+	log := &testLogger{}
+	SetLogger(log)
+	defer SetLogger(nil)
+
+	for _, test := range tests {
+		log.lines = nil
+		po := NewPo()
+		po.SetDuplicatePolicy(test.policy)
+		po.Parse([]byte(duplicatesPo))
+
+		if tr := po.Get(hello); tr != test.hello {
+			t.Errorf("Policy %d: expected '%s' but got '%s'", test.policy, test.hello, tr)
+		}
+		if tr := po.GetC(open, "menu"); tr != test.open {
+			t.Errorf("Policy %d: expected '%s' but got '%s'", test.policy, test.open, tr)
+		}
+		if tr := po.Get(open); tr != "Abierto" {
+			t.Errorf("Policy %d: expected 'Abierto' but got '%s'", test.policy, tr)
+		}
+
+		dups := po.Duplicates()
+		if len(dups) != 2 || *dups[0] != (DuplicateEntry{MsgID: hello}) || *dups[1] != (DuplicateEntry{Context: "menu", MsgID: open}) {
+			t.Errorf("Policy %d: unexpected duplicates %v", test.policy, dups)
+		}
+		if warned := log.has("WARN gotext: duplicate msgid"); warned != (test.policy == DuplicateWarn) {
+			t.Errorf("Policy %d: unexpected warnings %q", test.policy, log.lines)
+		}
+	}
+
+	// Across the files parsed into the same Po object
+	po := NewPo()
+	po.SetDuplicatePolicy(DuplicateKeepFirst)
+	po.Parse([]byte("msgid \"Hello\"\nmsgstr \"Hola\"\n"))
+	po.Parse([]byte("msgid \"\"\nmsgstr \"Language: es\\n\"\n\nmsgid \"Hello\"\nmsgstr \"Buenas\"\n"))
+	if tr := po.Get(hello); tr != "Hola" {
+		t.Errorf("Expected 'Hola' but got '%s'", tr)
+	}
+	if dups := po.Duplicates(); len(dups) != 1 {
+		t.Errorf("Expected one duplicate but got %v", dups)
+	}
+}
+
+func TestDuplicatePolicyPlural(t *testing.T) {
+	po := NewPo()
+	po.SetDuplicatePolicy(DuplicateKeepFirst)
+	po.Parse([]byte(`msgid "%d file"
+msgid_plural "%d files"
+msgstr[0] "%d archivo"
+msgstr[1] "%d archivos"
+
+msgid "%d file"
+msgid_plural "%d files"
+msgstr[0] "%d fichero"
+msgstr[1] "%d ficheros"
+`))
+	if tr := po.GetN("%d file", "%d files", 2, 2); tr != "2 archivos" {
+		t.Errorf("Expected '2 archivos' but got '%s'", tr)
+	}
+	if tr := po.domain.pluralTranslations["%d files"]; tr == nil || tr.Get() != "%d archivo" {
+		t.Errorf("Expected the plural index to keep the first entry, got %v", tr)
+	}
+}
+
+func TestLocaleDuplicatePolicy(t *testing.T) {
+	l := NewLocale("", "es")
+	l.SetDuplicatePolicy(DuplicateError)
+
+	err := l.AddDomainBytes("default", []byte(duplicatesPo), FormatPO)
+	var dup *DuplicateEntry
+	if !errors.As(err, &dup) || dup.MsgID != "Hello" {
+		t.Fatalf("Expected a *DuplicateEntry but got %v", err)
+	}
+	if _, ok := l.Domains["default"]; ok {
+		t.Error("Expected the catalog not to be loaded")
+	}
+
+	l.SetDuplicatePolicy(DuplicateKeepFirst)
+	if err := l.AddDomainBytes("default", []byte(duplicatesPo), FormatPO); err != nil {
+		t.Fatal(err)
+	}
+	if tr := l.Get("Hello"); tr != "Hola" {
+		t.Errorf("Expected 'Hola' but got '%s'", tr)
+	}
+}
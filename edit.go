@@ -0,0 +1,64 @@
+package gotext
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrEntryNotFound is returned when editing an entry that isn't in the Domain.
+var ErrEntryNotFound = errors.New("gotext: entry not found")
+
+// entry returns the stored Translation for msgid in the context ctx, or out of any context when ctx is empty.
+// The Domain must be locked.
+func (do *Domain) entry(ctx, msgid string) (*Translation, error) {
+	var tr *Translation
+	if ctx == "" {
+		tr = do.translations[msgid]
+	} else {
+		tr = do.contexts[ctx][msgid]
+	}
+	if tr == nil || msgid == "" {
+		return nil, fmt.Errorf("%w: %q in context %q", ErrEntryNotFound, msgid, ctx)
+	}
+	return tr, nil
+}
+
+// SetMsgstr replaces the translation of the existing entry msgid, in the context ctx or out of any context
+// when ctx is empty, with msgstr: one string for singular entries, the plural forms in order for plural ones.
+// Editing an entry clears its fuzzy flag.
+func (do *Domain) SetMsgstr(ctx, msgid string, msgstr ...string) error {
+	do.trMutex.Lock()
+	defer do.trMutex.Unlock()
+
+	tr, err := do.entry(ctx, msgid)
+	if err != nil {
+		return err
+	}
+	if tr.PluralID == "" && len(msgstr) > 1 {
+		return fmt.Errorf("gotext: %d strings for the singular entry %q", len(msgstr), msgid)
+	}
+
+	tr.Trs = make(map[int]string, len(msgstr))
+	for i, str := range msgstr {
+		tr.Trs[i] = str
+	}
+	tr.Fuzzy = false
+	tr.dirty = true
+	do.blocks = nil
+	return nil
+}
+
+// SetFuzzy sets the fuzzy flag of the existing entry msgid, in the context ctx or out of any context
+// when ctx is empty. Fuzzy entries are written with a "#, fuzzy" flag by MarshalText.
+func (do *Domain) SetFuzzy(ctx, msgid string, fuzzy bool) error {
+	do.trMutex.Lock()
+	defer do.trMutex.Unlock()
+
+	tr, err := do.entry(ctx, msgid)
+	if err != nil {
+		return err
+	}
+	tr.Fuzzy = fuzzy
+	do.blocks = nil
+	return nil
+}
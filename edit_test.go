@@ -0,0 +1,95 @@
+package gotext
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestPoFuzzyFlag(t *testing.T) {
+	po := NewPo()
+	po.Parse([]byte(`
+msgid "Hello"
+msgstr "Hallo"
+
+#, fuzzy, c-format
+msgid "Bye %s"
+msgstr "Tschüss %s"
+
+#, fuzzy
+msgctxt "menu"
+msgid "Open"
+msgstr "Öffnen"
+`))
+
+	for _, test := range []struct {
+		ctx, msgid string
+		fuzzy      bool
+	}{
+		{"", "Hello", false},
+		{"", "Bye %s", true},
+		{"menu", "Open", true},
+	} {
+		var tr *Translation
+		if test.ctx == "" {
+			tr, _ = po.GetDomain().GetTranslation(test.msgid)
+		} else {
+			tr, _ = po.GetDomain().GetTranslationC(test.msgid, test.ctx)
+		}
+		if tr == nil || tr.Fuzzy != test.fuzzy {
+			t.Errorf("Expected fuzzy %v for %q, got %+v", test.fuzzy, test.msgid, tr)
+		}
+	}
+
+	data, err := po.MarshalText()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(string(data), "#, fuzzy"); n != 2 {
+		t.Errorf("Expected 2 fuzzy flags, got %d:\n%s", n, data)
+	}
+}
+
+func TestDomainSetMsgstr(t *testing.T) {
+	po := NewPo()
+	po.Parse([]byte(`
+#, fuzzy
+msgid "Hello"
+msgstr "Halo"
+
+msgid "One file"
+msgid_plural "%d files"
+msgstr[0] ""
+msgstr[1] ""
+`))
+	do := po.GetDomain()
+
+	if err := do.SetMsgstr("", "Hello", "Hallo"); err != nil {
+		t.Fatal(err)
+	}
+	if tr, _ := do.GetTranslation("Hello"); tr.Get() != "Hallo" || tr.Fuzzy {
+		t.Errorf("Unexpected translation %+v", tr)
+	}
+
+	if err := do.SetMsgstr("", "One file", "Eine Datei", "%d Dateien"); err != nil {
+		t.Fatal(err)
+	}
+	one := "One file"
+	if tr := po.GetN(one, "%d files", 2, 2); tr != "2 Dateien" {
+		t.Errorf("Unexpected translation %q", tr)
+	}
+
+	if err := do.SetFuzzy("", "One file", true); err != nil {
+		t.Fatal(err)
+	}
+	if tr, _ := do.GetTranslation("One file"); !tr.Fuzzy {
+		t.Error("Expected the fuzzy flag")
+	}
+
+	if err := do.SetMsgstr("menu", "Hello", "Hallo"); !errors.Is(err, ErrEntryNotFound) {
+		t.Errorf("Expected ErrEntryNotFound, got %v", err)
+	}
+	if err := do.SetMsgstr("", "Hello", "a", "b"); err == nil {
+		t.Error("Expected an error for plural forms of a singular entry")
+	}
+}
@@ -0,0 +1,197 @@
+/*
+Package editor provides an HTTP handler to edit the translations of a live gotext.Locale,
+so developers can fix translations against a running development server and export the result.
+
+	l := gotext.NewLocale("/path/to/i18n/dir", "de_DE")
+	l.AddDomain("default")
+
+	http.Handle("/_i18n/", http.StripPrefix("/_i18n", editor.New(l)))
+
+It serves a small JSON API:
+
+	GET /domains                  names of the loaded domains
+	GET /domains/{dom}/entries    entries of a domain, sorted by context and msgid
+	PUT /domains/{dom}/entries    update the msgstr or fuzzy flag of an entry, sent as an Entry
+	GET /domains/{dom}/po         the domain as a PO file
+
+It has no authentication, so it must never be served in production.
+*/
+package editor
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/leonelquinteros/gotext"
+)
+
+// Entry is an entry of a domain, as served and accepted by the API.
+//
+// Updates set Msgstr when it isn't nil, which clears the fuzzy flag, and then Fuzzy when it isn't nil.
+type Entry struct {
+	Context    string   `json:"msgctxt,omitempty"`
+	ID         string   `json:"msgid"`
+	PluralID   string   `json:"msgid_plural,omitempty"`
+	Msgstr     []string `json:"msgstr"`
+	Fuzzy      *bool    `json:"fuzzy,omitempty"`
+	References []string `json:"references,omitempty"`
+}
+
+// Handler serves the editing API over a Locale.
+type Handler struct {
+	l *gotext.Locale
+}
+
+// New creates a Handler to edit the domains of l.
+func New(l *gotext.Locale) *Handler {
+	return &Handler{l: l}
+}
+
+// ServeHTTP implements http.Handler.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	switch {
+	case len(parts) == 1 && parts[0] == "domains":
+		if r.Method != http.MethodGet {
+			methodNotAllowed(w, http.MethodGet)
+			return
+		}
+		writeJSON(w, h.domains())
+
+	case len(parts) == 3 && parts[0] == "domains":
+		dom := h.domain(parts[1])
+		if dom == nil {
+			http.Error(w, "unknown domain", http.StatusNotFound)
+			return
+		}
+
+		switch {
+		case parts[2] == "entries" && r.Method == http.MethodGet:
+			writeJSON(w, entries(dom))
+		case parts[2] == "entries" && r.Method == http.MethodPut:
+			update(w, r, dom)
+		case parts[2] == "entries":
+			methodNotAllowed(w, http.MethodGet, http.MethodPut)
+		case parts[2] == "po" && r.Method == http.MethodGet:
+			exportPO(w, parts[1], dom)
+		case parts[2] == "po":
+			methodNotAllowed(w, http.MethodGet)
+		default:
+			http.NotFound(w, r)
+		}
+
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// domains returns the names of the domains of the Locale, sorted.
+func (h *Handler) domains() []string {
+	h.l.RLock()
+	defer h.l.RUnlock()
+
+	names := make([]string, 0, len(h.l.Domains))
+	for name := range h.l.Domains {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// domain returns the domain named name, or nil if the Locale has none.
+func (h *Handler) domain(name string) *gotext.Domain {
+	h.l.RLock()
+	tr, ok := h.l.Domains[name]
+	h.l.RUnlock()
+
+	if !ok {
+		return nil
+	}
+	return tr.GetDomain()
+}
+
+// entries returns the entries of dom, with as many msgstr as plural forms for plural ones.
+func entries(dom *gotext.Domain) []Entry {
+	nplurals := dom.GetNPlurals()
+	list := make([]Entry, 0)
+	dom.Iterate(func(ctx, msgid string, tr *gotext.Translation) bool {
+		fuzzy := tr.Fuzzy
+		e := Entry{Context: ctx, ID: msgid, PluralID: tr.PluralID, Fuzzy: &fuzzy, References: tr.Refs}
+
+		n := 1
+		if tr.PluralID != "" {
+			n = nplurals
+			for i := range tr.Trs {
+				if i >= n {
+					n = i + 1
+				}
+			}
+		}
+		e.Msgstr = make([]string, n)
+		for i := range e.Msgstr {
+			e.Msgstr[i] = tr.Trs[i]
+		}
+
+		list = append(list, e)
+		return true
+	})
+	return list
+}
+
+// update applies the Entry in the request body to dom, and answers with the updated entry.
+func update(w http.ResponseWriter, r *http.Request, dom *gotext.Domain) {
+	var e Entry
+	if err := json.NewDecoder(r.Body).Decode(&e); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var err error
+	if e.Msgstr != nil {
+		err = dom.SetMsgstr(e.Context, e.ID, e.Msgstr...)
+	}
+	if err == nil && e.Fuzzy != nil {
+		err = dom.SetFuzzy(e.Context, e.ID, *e.Fuzzy)
+	}
+	switch {
+	case errors.Is(err, gotext.ErrEntryNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	for _, updated := range entries(dom) {
+		if updated.Context == e.Context && updated.ID == e.ID {
+			writeJSON(w, updated)
+			return
+		}
+	}
+}
+
+// exportPO writes dom as the PO file name.po.
+func exportPO(w http.ResponseWriter, name string, dom *gotext.Domain) {
+	data, err := dom.MarshalText()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/x-gettext-translation; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+name+`.po"`)
+	w.Write(data)
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+func methodNotAllowed(w http.ResponseWriter, methods ...string) {
+	w.Header().Set("Allow", strings.Join(methods, ", "))
+	http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+}
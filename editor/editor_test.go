@@ -0,0 +1,115 @@
+package editor
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/leonelquinteros/gotext"
+)
+
+const catalog = `
+msgid ""
+msgstr ""
+"Language: de\n"
+"Plural-Forms: nplurals=2; plural=(n != 1);\n"
+
+#, fuzzy
+msgid "Hello"
+msgstr "Halo"
+
+msgctxt "menu"
+msgid "One file"
+msgid_plural "%d files"
+msgstr[0] "Eine Datei"
+msgstr[1] ""
+`
+
+func newHandler(t *testing.T) (*Handler, *gotext.Locale) {
+	l := gotext.NewLocale("", "de")
+	if err := l.AddDomainBytes("app", []byte(catalog), gotext.FormatPO); err != nil {
+		t.Fatal(err)
+	}
+	return New(l), l
+}
+
+func serve(h http.Handler, method, path, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(method, path, strings.NewReader(body)))
+	return w
+}
+
+func TestDomainsAndEntries(t *testing.T) {
+	h, _ := newHandler(t)
+
+	w := serve(h, http.MethodGet, "/domains", "")
+	if w.Code != http.StatusOK || strings.TrimSpace(w.Body.String()) != `["app"]` {
+		t.Errorf("Unexpected response %d %s", w.Code, w.Body)
+	}
+
+	w = serve(h, http.MethodGet, "/domains/app/entries", "")
+	var list []Entry
+	if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 2 {
+		t.Fatalf("Expected 2 entries, got %+v", list)
+	}
+	if e := list[0]; e.ID != "Hello" || !*e.Fuzzy || len(e.Msgstr) != 1 || e.Msgstr[0] != "Halo" {
+		t.Errorf("Unexpected entry %+v", e)
+	}
+	if e := list[1]; e.Context != "menu" || e.PluralID != "%d files" || len(e.Msgstr) != 2 || *e.Fuzzy {
+		t.Errorf("Unexpected entry %+v", e)
+	}
+
+	if w := serve(h, http.MethodGet, "/domains/other/entries", ""); w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for an unknown domain, got %d", w.Code)
+	}
+	if w := serve(h, http.MethodPost, "/domains", ""); w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected 405, got %d", w.Code)
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	h, l := newHandler(t)
+
+	w := serve(h, http.MethodPut, "/domains/app/entries", `{"msgid": "Hello", "msgstr": ["Hallo"]}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Unexpected response %d %s", w.Code, w.Body)
+	}
+	var e Entry
+	json.Unmarshal(w.Body.Bytes(), &e)
+	if e.Msgstr[0] != "Hallo" || *e.Fuzzy {
+		t.Errorf("Unexpected entry %+v", e)
+	}
+	hello := "Hello"
+	if tr := l.GetD("app", hello); tr != "Hallo" {
+		t.Errorf("Expected the Locale to be updated, got %q", tr)
+	}
+
+	w = serve(h, http.MethodPut, "/domains/app/entries", `{"msgctxt": "menu", "msgid": "One file", "msgstr": ["Eine Datei", "%d Dateien"], "fuzzy": true}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Unexpected response %d %s", w.Code, w.Body)
+	}
+	one := "One file"
+	if tr := l.GetNDC("app", one, "%d files", 3, "menu", 3); tr != "3 Dateien" {
+		t.Errorf("Unexpected translation %q", tr)
+	}
+
+	if w := serve(h, http.MethodPut, "/domains/app/entries", `{"msgid": "Bye", "msgstr": ["Tschüss"]}`); w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for an unknown entry, got %d", w.Code)
+	}
+	if w := serve(h, http.MethodPut, "/domains/app/entries", `{"msgid": "Hello", "msgstr": ["a", "b"]}`); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for plural forms of a singular entry, got %d", w.Code)
+	}
+	if w := serve(h, http.MethodPut, "/domains/app/entries", `{`); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for invalid JSON, got %d", w.Code)
+	}
+
+	w = serve(h, http.MethodGet, "/domains/app/po", "")
+	if !strings.Contains(w.Body.String(), "#, fuzzy\nmsgctxt \"menu\"") || !strings.Contains(w.Body.String(), `msgstr "Hallo"`) {
+		t.Errorf("Unexpected PO file:\n%s", w.Body)
+	}
+}
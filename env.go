@@ -0,0 +1,166 @@
+package gotext
+
+import (
+	"context"
+	"os"
+	"strings"
+)
+
+/*
+NewLocaleFromEnv creates a Locale for the language set in the environment, with the library path p,
+following the GNU gettext precedence rules, so command line tools behave like C gettext programs:
+
+  - The locale is the first of LC_ALL, LC_MESSAGES and LANG that is set.
+  - Unless the locale is unset, "C" or "POSIX", LANGUAGE takes precedence with a colon-separated list of
+    languages, like "de_AT:de:en". The first one is the language of the Locale, and the others are looked up,
+    in order, for the messages the catalogs of the previous ones don't translate.
+
+Encodings and modifiers, like ".UTF-8" and "@euro", are ignored. The "C" and "POSIX" locales leave messages untranslated.
+*/
+func NewLocaleFromEnv(p string) *Locale {
+	langs := envLanguages(os.Getenv)
+	l := NewLocale(p, langs[0])
+	l.fallbackLangs = langs[1:]
+	return l
+}
+
+// envLanguages returns the languages set in the environment read by getenv, in order. See NewLocaleFromEnv.
+func envLanguages(getenv func(string) string) []string {
+	locale := "C"
+	for _, key := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if v := SimplifiedLocale(getenv(key)); v != "" {
+			locale = v
+			break
+		}
+	}
+	if locale == "C" || locale == "POSIX" {
+		return []string{locale}
+	}
+
+	var langs []string
+	seen := make(map[string]bool)
+	for _, lang := range strings.Split(getenv("LANGUAGE"), ":") {
+		lang = SimplifiedLocale(lang)
+		if lang != "" && !seen[lang] {
+			seen[lang] = true
+			langs = append(langs, lang)
+		}
+	}
+	if len(langs) == 0 {
+		return []string{locale}
+	}
+	return langs
+}
+
+// catalogLookup is where and how the catalog of a domain is looked up for one language.
+type catalogLookup struct {
+	files  []catalogFile
+	parser catalogParser
+}
+
+// catalogLookups returns the lookups of the catalog of the domain dom, for the language of the Locale
+// and then its fallback languages.
+func (l *Locale) catalogLookups(dom string) []catalogLookup {
+	parser := l.catalogParser()
+	lookups := []catalogLookup{{files: l.catalogFiles(dom), parser: parser}}
+
+	l.RLock()
+	fallbacks := l.fallbackLangs
+	l.RUnlock()
+
+	for _, lang := range fallbacks {
+		p := parser
+		p.lang = lang
+		lookups = append(lookups, catalogLookup{files: l.langCatalogFiles(lang, dom), parser: p})
+	}
+	return lookups
+}
+
+// loadCatalogs loads the catalog of the domain dom for every lookup, returning a Translator falling back
+// from one to the next when there are several. Only the errors of the first lookup are returned,
+// the catalogs of the fallback languages are skipped when they can't be parsed.
+// It returns nil without error when none is found.
+func loadCatalogs(ctx context.Context, src CatalogSource, lookups []catalogLookup, dom string) (Translator, error) {
+	var trs []Translator
+	for i, lk := range lookups {
+		tr, err := loadCatalog(ctx, src, lk.files, lk.parser, dom)
+		if err != nil && i == 0 {
+			return nil, err
+		}
+		if err != nil {
+			logWarn("gotext: fallback catalog skipped", "lang", lk.parser.lang, "domain", dom, "err", err)
+		}
+		if tr != nil {
+			trs = append(trs, tr)
+		}
+	}
+
+	switch len(trs) {
+	case 0:
+		return nil, nil
+	case 1:
+		return trs[0], nil
+	}
+	return &fallbackTranslator{trs: trs}, nil
+}
+
+// fallbackTranslator looks messages up in several catalogs of the same domain, in order,
+// using the first one that translates them. The first catalog answers the rest of calls.
+type fallbackTranslator struct {
+	trs []Translator
+}
+
+// find returns the first catalog translating str, or the first catalog when none does.
+func (ft *fallbackTranslator) find(str, ctx string, withCtx bool) Translator {
+	for _, tr := range ft.trs {
+		if translated(tr, str, ctx, withCtx) {
+			return tr
+		}
+	}
+	return ft.trs[0]
+}
+
+func (ft *fallbackTranslator) translated(str, ctx string, withCtx bool) bool {
+	for _, tr := range ft.trs {
+		if translated(tr, str, ctx, withCtx) {
+			return true
+		}
+	}
+	return false
+}
+
+func (ft *fallbackTranslator) ParseFile(f string) {
+	ft.trs[0].ParseFile(f)
+}
+
+func (ft *fallbackTranslator) Parse(buf []byte) {
+	ft.trs[0].Parse(buf)
+}
+
+func (ft *fallbackTranslator) Get(str string, vars ...interface{}) string {
+	return ft.find(str, "", false).Get(str, vars...)
+}
+
+func (ft *fallbackTranslator) GetN(str, plural string, n int, vars ...interface{}) string {
+	return ft.find(str, "", false).GetN(str, plural, n, vars...)
+}
+
+func (ft *fallbackTranslator) GetC(str, ctx string, vars ...interface{}) string {
+	return ft.find(str, ctx, true).GetC(str, ctx, vars...)
+}
+
+func (ft *fallbackTranslator) GetNC(str, plural string, n int, ctx string, vars ...interface{}) string {
+	return ft.find(str, ctx, true).GetNC(str, plural, n, ctx, vars...)
+}
+
+func (ft *fallbackTranslator) MarshalBinary() ([]byte, error) {
+	return ft.trs[0].MarshalBinary()
+}
+
+func (ft *fallbackTranslator) UnmarshalBinary(data []byte) error {
+	return ft.trs[0].UnmarshalBinary(data)
+}
+
+func (ft *fallbackTranslator) GetDomain() *Domain {
+	return ft.trs[0].GetDomain()
+}
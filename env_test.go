@@ -0,0 +1,89 @@
+package gotext
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestEnvLanguages(t *testing.T) {
+	for _, tc := range []struct {
+		env  map[string]string
+		want []string
+	}{
+		{map[string]string{}, []string{"C"}},
+		{map[string]string{"LANG": "de_DE.UTF-8"}, []string{"de_DE"}},
+		{map[string]string{"LANG": "de_DE", "LC_MESSAGES": "fr_FR"}, []string{"fr_FR"}},
+		{map[string]string{"LANG": "de_DE", "LC_MESSAGES": "fr_FR", "LC_ALL": "es_ES@euro"}, []string{"es_ES"}},
+		{map[string]string{"LANG": "de_DE", "LANGUAGE": "pt_BR:pt::pt_BR:en"}, []string{"pt_BR", "pt", "en"}},
+		// LANGUAGE is ignored for the C locale
+		{map[string]string{"LANG": "C.UTF-8", "LANGUAGE": "pt_BR"}, []string{"C"}},
+		{map[string]string{"LC_ALL": "POSIX", "LANGUAGE": "pt_BR"}, []string{"POSIX"}},
+		{map[string]string{"LANGUAGE": "pt_BR"}, []string{"C"}},
+		{map[string]string{"LANG": "de_DE", "LANGUAGE": ":"}, []string{"de_DE"}},
+	} {
+		getenv := func(key string) string { return tc.env[key] }
+		if got := envLanguages(getenv); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%v: got %v, want %v", tc.env, got, tc.want)
+		}
+	}
+}
+
+func TestNewLocaleFromEnv(t *testing.T) {
+	for _, key := range []string{"LANGUAGE", "LC_ALL", "LC_MESSAGES", "LANG"} {
+		old, ok := os.LookupEnv(key)
+		if ok {
+			defer os.Setenv(key, old)
+		} else {
+			defer os.Unsetenv(key)
+		}
+		os.Unsetenv(key)
+	}
+	os.Setenv("LANG", "es_ES.UTF-8")
+	os.Setenv("LANGUAGE", "de_AT:fr:es")
+
+	src := &MemorySource{Files: map[string][]byte{
+		"de_AT/LC_MESSAGES/default.po": []byte("msgid \"Hello\"\nmsgstr \"Servus\"\n\nmsgid \"Bye\"\nmsgstr \"\"\n"),
+		"fr/default.po": []byte(`msgid ""
+msgstr ""
+"Language: fr\n"
+"Plural-Forms: nplurals=2; plural=(n > 1);\n"
+
+msgid "Bye"
+msgstr "Au revoir"
+
+msgid "%d file"
+msgid_plural "%d files"
+msgstr[0] "%d fichier"
+msgstr[1] "%d fichiers"
+`),
+		"es/default.po": []byte("msgid \"Yes\"\nmsgstr \"Sí\"\n"),
+	}}
+
+	l := NewLocaleFromEnv("")
+	l.SetSource(src)
+	l.AddDomain("default")
+
+	if l.GetLanguage() != "de_AT" {
+		t.Errorf("unexpected language %q", l.GetLanguage())
+	}
+
+	hello, bye, yes, no := "Hello", "Bye", "Yes", "No"
+	for str, want := range map[string]string{hello: "Servus", bye: "Au revoir", yes: "Sí", no: no} {
+		if got := l.Get(str); got != want {
+			t.Errorf("%q: got %q, want %q", str, got, want)
+		}
+	}
+
+	// Fallback catalogs use their own plural rule
+	file, files := "%d file", "%d files"
+	if got := l.GetN(file, files, 0, 0); got != "0 fichier" {
+		t.Errorf("unexpected plural %q", got)
+	}
+	if _, found := l.Lookup(no); found {
+		t.Error("missing messages shouldn't be found")
+	}
+	if _, found := l.Lookup(yes); !found {
+		t.Error("messages of fallback languages should be found")
+	}
+}
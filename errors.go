@@ -0,0 +1,129 @@
+package gotext
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Error is a translatable error created by Errorf or ErrorfD.
+// It holds the msgid and arguments, so it can be rendered in any language when presented (see Locale.LocalizeError).
+type Error struct {
+	// Domain to translate from. The Locale default domain is used when empty.
+	Domain string
+
+	MsgID string
+	Vars  []interface{}
+}
+
+// Errorf returns a translatable error for the msgid and optional parameters (vars... interface{}),
+// to be inserted on the formatted string using the fmt.Printf syntax.
+// Its Error method returns the untranslated message. Use Locale.LocalizeError to render it in a language.
+//
+// Errors in vars are localized too, and the %w verb can be used to wrap them like fmt.Errorf does.
+func Errorf(msgid string, vars ...interface{}) error {
+	return &Error{Domain: callerDomain(0, ""), MsgID: msgid, Vars: vars}
+}
+
+// ErrorfD returns a translatable error for the msgid in the given domain. See Errorf.
+func ErrorfD(dom, msgid string, vars ...interface{}) error {
+	return &Error{Domain: dom, MsgID: msgid, Vars: vars}
+}
+
+// Error returns the untranslated message.
+func (e *Error) Error() string {
+	return formatError(e.MsgID, e.Vars)
+}
+
+// Unwrap returns the first error in the parameters, if any.
+func (e *Error) Unwrap() error {
+	for _, v := range e.Vars {
+		if err, ok := v.(error); ok {
+			return err
+		}
+	}
+	return nil
+}
+
+// formatError formats an error message, supporting the %w verb.
+func formatError(format string, vars []interface{}) string {
+	if len(vars) == 0 {
+		return format
+	}
+	return fmt.Errorf(format, vars...).Error()
+}
+
+/*
+LocalizeError returns the message of err translated in the Locale language.
+
+When err is, or wraps, an Error created by Errorf, its message is translated and replaces
+the untranslated one in the message of err. Any other error message is returned as is.
+
+	// In a library
+	return gotext.Errorf("File %s not found", name)
+
+	// When presenting the error
+	fmt.Println(l.LocalizeError(err))
+*/
+func (l *Locale) LocalizeError(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	var te *Error
+	if !errors.As(err, &te) {
+		return err.Error()
+	}
+
+	vars := make([]interface{}, len(te.Vars))
+	for i, v := range te.Vars {
+		if inner, ok := v.(error); ok {
+			v = localizedError{msg: l.LocalizeError(inner), err: inner}
+		}
+		vars[i] = v
+	}
+
+	dom := te.Domain
+	if dom == "" {
+		dom = l.GetDomain()
+	}
+
+	l.RLock()
+	str := te.MsgID
+	if tr, ok := l.Domains[dom]; ok && tr != nil {
+		str = tr.GetDomain().Get(te.MsgID)
+	}
+	l.RUnlock()
+
+	msg := formatError(str, vars)
+	if te == err {
+		return msg
+	}
+	return strings.Replace(err.Error(), te.Error(), msg, 1)
+}
+
+// localizedError is an error whose message has been translated. It's used to format the arguments of an Error.
+type localizedError struct {
+	msg string
+	err error
+}
+
+func (e localizedError) Error() string {
+	return e.msg
+}
+
+func (e localizedError) Unwrap() error {
+	return e.err
+}
+
+// LocalizeError returns the message of err translated in the package language. See Locale.LocalizeError.
+func LocalizeError(err error) string {
+	// Try to load default package Locale storage
+	loadStorage(false)
+
+	globalConfig.RLock()
+	msg := globalConfig.storage.LocalizeError(err)
+	globalConfig.RUnlock()
+
+	return msg
+}
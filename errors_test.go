@@ -0,0 +1,54 @@
+package gotext
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+)
+
+func TestLocalizeError(t *testing.T) {
+	src := &MemorySource{Files: map[string][]byte{
+		"es/default.po": []byte(`
+msgid "File %s not found"
+msgstr "Archivo %s no encontrado"
+
+msgid "Loading failed: %w"
+msgstr "Falló la carga: %w"
+`),
+		"es/extras.po": []byte(`
+msgid "Access denied"
+msgstr "Acceso denegado"
+`),
+	}}
+	l := NewLocaleWithSource(src, "es")
+	l.AddDomain("default")
+	l.AddDomain("extras")
+
+	err := Errorf("File %s not found", "a.txt")
+	if err.Error() != "File a.txt not found" {
+		t.Errorf("Unexpected untranslated message '%s'", err.Error())
+	}
+	if msg := l.LocalizeError(err); msg != "Archivo a.txt no encontrado" {
+		t.Errorf("Expected 'Archivo a.txt no encontrado' but got '%s'", msg)
+	}
+
+	// Wrapped by and wrapping other errors
+	wrapped := fmt.Errorf("config: %w", Errorf("Loading failed: %w", ErrorfD("extras", "Access denied")))
+	if msg := l.LocalizeError(wrapped); msg != "config: Falló la carga: Acceso denegado" {
+		t.Errorf("Unexpected message '%s'", msg)
+	}
+
+	inner := Errorf("Loading failed: %w", os.ErrNotExist)
+	if !errors.Is(inner, os.ErrNotExist) {
+		t.Error("Expected the error to wrap os.ErrNotExist")
+	}
+
+	// Other errors are kept as is
+	if msg := l.LocalizeError(errors.New("plain")); msg != "plain" {
+		t.Errorf("Expected 'plain' but got '%s'", msg)
+	}
+	if msg := l.LocalizeError(nil); msg != "" {
+		t.Errorf("Expected empty message but got '%s'", msg)
+	}
+}
@@ -0,0 +1,29 @@
+package gotext
+
+// CatalogKey identifies a message of a catalog by its context and msgid.
+type CatalogKey struct {
+	Context string
+	MsgID   string
+}
+
+// String returns the key the way MO files store it: the msgid, preceded by the context and an EOT (\x04)
+// separator when there is a context.
+func (k CatalogKey) String() string {
+	if k.Context == "" {
+		return k.MsgID
+	}
+	return k.Context + "\x04" + k.MsgID
+}
+
+// Export returns a copy of every message of the Domain, in or out of a context, with all its plural forms
+// and source references, keyed by context and msgid. The header entry isn't included.
+// Unlike GetAll, it doesn't depend on the CLDR data of the language, so nothing is lost.
+func (do *Domain) Export() map[CatalogKey]Entry {
+	entries := do.entries()
+	all := make(map[CatalogKey]Entry, len(entries))
+	for _, e := range entries {
+		e.Translation = copyTranslation(e.Translation)
+		all[CatalogKey{Context: e.Context, MsgID: e.MsgID}] = e
+	}
+	return all
+}
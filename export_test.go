@@ -0,0 +1,77 @@
+package gotext
+
+import (
+	"testing"
+)
+
+func TestCatalogKeyString(t *testing.T) {
+	if k := (CatalogKey{MsgID: "Open"}).String(); k != "Open" {
+		t.Errorf("Unexpected key %q", k)
+	}
+	if k := (CatalogKey{Context: "menu", MsgID: "Open"}).String(); k != "menu\x04Open" {
+		t.Errorf("Unexpected key %q", k)
+	}
+}
+
+func TestDomainExport(t *testing.T) {
+	po := NewPo()
+	po.Parse([]byte(`
+msgid ""
+msgstr ""
+"Language: de\n"
+"Plural-Forms: nplurals=2; plural=(n != 1);\n"
+
+msgid "Open"
+msgstr "Öffnen"
+
+msgctxt "menu"
+msgid "Open"
+msgstr "Öffnen…"
+
+#: main.go:12
+msgctxt "cart"
+msgid "One item"
+msgid_plural "%d items"
+msgstr[0] "Ein Artikel"
+msgstr[1] "%d Artikel"
+`))
+
+	all := po.GetDomain().Export()
+	if len(all) != 3 {
+		t.Fatalf("Expected 3 entries, got %d", len(all))
+	}
+	if e := all[CatalogKey{MsgID: "Open"}]; e.Translation.Get() != "Öffnen" {
+		t.Errorf("Unexpected entry %+v", e)
+	}
+	if e := all[CatalogKey{Context: "menu", MsgID: "Open"}]; e.Context != "menu" || e.Translation.Get() != "Öffnen…" {
+		t.Errorf("Unexpected entry %+v", e)
+	}
+
+	e := all[CatalogKey{Context: "cart", MsgID: "One item"}]
+	if e.Translation.PluralID != "%d items" || e.Translation.GetN(1) != "%d Artikel" {
+		t.Errorf("Unexpected plural entry %+v", e.Translation)
+	}
+	if len(e.Translation.Refs) != 1 || e.Translation.Refs[0] != "main.go:12" {
+		t.Errorf("Unexpected references %v", e.Translation.Refs)
+	}
+
+	// Entries are copies
+	e.Translation.Trs[0] = "Changed"
+	one := "One item"
+	if tr := po.GetNC(one, "%d items", 1, "cart"); tr != "Ein Artikel" {
+		t.Errorf("Expected the Domain to be unchanged, got %q", tr)
+	}
+}
+
+func TestGetAllContexts(t *testing.T) {
+	po := NewPo()
+	po.Parse([]byte("msgctxt \"menu\"\nmsgid \"Open\"\nmsgstr \"Öffnen\"\n"))
+
+	all, err := po.GetDomain().GetAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tr, ok := all["menu\x04Open"]; !ok || tr.Get() != "Öffnen" {
+		t.Errorf("Expected the message in a context, got %v", all)
+	}
+}
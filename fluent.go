@@ -0,0 +1,882 @@
+package gotext
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	ftlEntryRe = regexp.MustCompile(`^(-?[a-zA-Z][a-zA-Z0-9_-]*) *= *(.*)$`)
+	ftlAttrRe  = regexp.MustCompile(`^\.([a-zA-Z][a-zA-Z0-9_-]*) *= *(.*)$`)
+)
+
+// minFtlExpansion is the least total size the references of a Fluent resource can expand to,
+// which is otherwise ftlExpansionFactor times the size of the resource
+const (
+	minFtlExpansion    = 1 << 20
+	ftlExpansionFactor = 16
+)
+
+/*
+Fluent parses Mozilla Fluent (.ftl) resources and provides all the Translation functions needed,
+so Fluent catalogs can be used through the same Locale and Domain API as gettext ones.
+
+Messages are looked up by id, and their attributes by "id.attribute":
+
+	hello = Hello, { $name }!
+	login = Log in
+	    .title = Click to log in
+
+Variables are passed as positional vars, numbered by their first appearance in the message,
+so the message above is rendered with Get("hello", "World").
+References to terms (-brand) and to other messages are resolved when parsing.
+
+The first select expression of a message is mapped to gettext entries:
+
+  - When its variant keys are CLDR plural categories (one, few, other...), the message is a plural entry,
+    looked up with GetN(id, id, n, n). Numeric variant keys, like [0], are ignored.
+  - Otherwise, every variant is stored in a context named after its key, looked up with GetC(id, key),
+    and the default variant is the message without context.
+
+Selectors on term attributes and literals are resolved when parsing, and any other select expression
+is rendered with its default variant. Functions, like NUMBER($n), are replaced by their first argument.
+*/
+type Fluent struct {
+	domain *Domain
+}
+
+// NewFluent should always be used to instantiate a new Fluent object.
+// lang is the language of the resources, used to map plural variants to msgstr indexes.
+func NewFluent(lang string) *Fluent {
+	f := &Fluent{domain: NewDomain()}
+	f.domain.setCLDRLanguage(lang)
+
+	return f
+}
+
+func (f *Fluent) GetDomain() *Domain {
+	return f.domain
+}
+
+func (f *Fluent) Get(str string, vars ...interface{}) string {
+	return printfIndexed(f.domain.Get(str), vars...)
+}
+
+func (f *Fluent) GetN(str, plural string, n int, vars ...interface{}) string {
+	return printfIndexed(f.domain.GetN(str, plural, n), vars...)
+}
+
+func (f *Fluent) GetC(str, ctx string, vars ...interface{}) string {
+	return printfIndexed(f.domain.GetC(str, ctx), vars...)
+}
+
+func (f *Fluent) GetNC(str, plural string, n int, ctx string, vars ...interface{}) string {
+	return printfIndexed(f.domain.GetNC(str, plural, n, ctx), vars...)
+}
+
+// printfIndexed formats a message converted from a catalog format with its own placeholders (like Fluent),
+// which are written with explicit argument indexes (%[1]v). Messages without placeholders are returned as is,
+// so variants that don't use the vars passed (like "one email") don't get fmt's EXTRA noise.
+func printfIndexed(str string, vars ...interface{}) string {
+	if len(vars) == 0 || !strings.Contains(str, "%[") {
+		return str
+	}
+	return fmt.Sprintf(str, vars...)
+}
+
+func (f *Fluent) MarshalBinary() ([]byte, error) {
+	return f.domain.MarshalBinary()
+}
+
+func (f *Fluent) UnmarshalBinary(data []byte) error {
+	return f.domain.UnmarshalBinary(data)
+}
+
+func (f *Fluent) ParseFile(file string) {
+	data, err := getFileData(file)
+	if err != nil {
+		return
+	}
+
+	f.Parse(data)
+}
+
+// Parse loads the translations specified in the provided byte slice, in the Fluent syntax.
+func (f *Fluent) Parse(buf []byte) {
+	f.ParseWithError(buf)
+}
+
+// ParseWithError works like Parse, loading every valid entry, but returns the invalid ones
+// as a ParseErrors value with their line numbers. It fails when references expand to much more text
+// than the resource has, as a few self-referencing messages otherwise grow exponentially.
+func (f *Fluent) ParseWithError(buf []byte) error {
+	entries, errs := parseFtl(string(buf))
+
+	do := f.domain
+	do.trMutex.Lock()
+	do.pluralMutex.Lock()
+	defer do.trMutex.Unlock()
+	defer do.pluralMutex.Unlock()
+
+	limit := ftlExpansionFactor * len(buf)
+	if limit < minFtlExpansion {
+		limit = minFtlExpansion
+	}
+	r := newFtlResolver(entries, limit)
+	for _, id := range r.messageIDs() {
+		e := entries[id]
+		if e.value != nil {
+			do.addFtlMessage(id, r.expandRef(id, e.value), make(map[string]int))
+		}
+		for _, attr := range e.attrOrder {
+			do.addFtlMessage(id+"."+attr, r.expandRef(id+"."+attr, e.attrs[attr]), make(map[string]int))
+		}
+		if r.size > r.limit {
+			return fmt.Errorf("gotext: Fluent references expand to more than %d bytes", r.limit)
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// ftlNodeKind is the kind of a ftlNode.
+type ftlNodeKind int
+
+const (
+	ftlText ftlNodeKind = iota
+	ftlVariable
+	ftlMessageRef
+	ftlTermRef
+	ftlSelect
+)
+
+// ftlNode is an element of a Fluent pattern.
+type ftlNode struct {
+	kind ftlNodeKind
+
+	// Text of text nodes, or name of variables and references
+	text string
+	// Attribute of references
+	attr string
+
+	// Select expressions
+	selector *ftlNode
+	variants []ftlVariant
+}
+
+// ftlVariant is a variant of a select expression.
+type ftlVariant struct {
+	key   string
+	def   bool
+	value []ftlNode
+}
+
+// ftlEntry is a parsed Fluent message or term.
+type ftlEntry struct {
+	value     []ftlNode
+	attrs     map[string][]ftlNode
+	attrOrder []string
+}
+
+// parseFtl parses a Fluent resource into its entries, keyed by id (with the leading '-' for terms).
+func parseFtl(src string) (map[string]*ftlEntry, ParseErrors) {
+	var errs ParseErrors
+	entries := make(map[string]*ftlEntry)
+
+	lines := strings.Split(strings.Replace(src, "\r\n", "\n", -1), "\n")
+	for i := 0; i < len(lines); {
+		l := lines[i]
+		if strings.TrimSpace(l) == "" || strings.HasPrefix(l, "#") {
+			i++
+			continue
+		}
+
+		line := i + 1
+		m := ftlEntryRe.FindStringSubmatch(l)
+
+		// Collect the indented lines of the entry, which may be separated by blank lines.
+		// The closing brace of a select expression is usually not indented.
+		body := []string{""}
+		if m != nil {
+			body[0] = m[2]
+		}
+		for i++; i < len(lines) && (lines[i] == "" || lines[i][0] == ' ' || lines[i][0] == '}'); i++ {
+			body = append(body, lines[i])
+		}
+		for len(body) > 1 && strings.TrimSpace(body[len(body)-1]) == "" {
+			body = body[:len(body)-1]
+		}
+
+		if m == nil {
+			errs = append(errs, &ParseError{Line: line, Msg: fmt.Sprintf("invalid entry %q", l)})
+			continue
+		}
+
+		e, err := parseFtlEntry(body)
+		if err != nil {
+			errs = append(errs, &ParseError{Line: line, Msg: fmt.Sprintf("%s: %s", m[1], err)})
+			continue
+		}
+		if e.value == nil && (strings.HasPrefix(m[1], "-") || len(e.attrs) == 0) {
+			errs = append(errs, &ParseError{Line: line, Msg: fmt.Sprintf("%s has no value", m[1])})
+			continue
+		}
+		entries[m[1]] = e
+	}
+
+	return entries, errs
+}
+
+// parseFtlEntry parses the value and attributes of an entry, from its lines.
+// The first line is the text after the '=', the rest are its indented lines.
+func parseFtlEntry(body []string) (*ftlEntry, error) {
+	e := &ftlEntry{attrs: make(map[string][]ftlNode)}
+
+	// Split the lines of the value and of each attribute, outside of placeables
+	var attr string
+	chunks := map[string][]string{"": {body[0]}}
+	depth := ftlBraceDepth(body[0], 0)
+
+	for _, l := range body[1:] {
+		l = strings.TrimLeft(l, " ")
+		if depth == 0 {
+			if m := ftlAttrRe.FindStringSubmatch(l); m != nil {
+				attr = m[1]
+				if _, ok := chunks[attr]; ok {
+					return nil, fmt.Errorf("duplicate attribute %q", attr)
+				}
+				e.attrOrder = append(e.attrOrder, attr)
+				chunks[attr] = []string{m[2]}
+				depth = ftlBraceDepth(m[2], 0)
+				continue
+			}
+		}
+		chunks[attr] = append(chunks[attr], l)
+		depth = ftlBraceDepth(l, depth)
+	}
+
+	for name, lines := range chunks {
+		text := strings.TrimRight(strings.Join(lines, "\n"), " \n")
+		text = strings.TrimLeft(text, "\n")
+		if text == "" {
+			continue
+		}
+
+		p := &ftlParser{s: text}
+		pattern, err := p.parsePattern(false)
+		if err != nil {
+			return nil, err
+		}
+		if name == "" {
+			e.value = pattern
+		} else {
+			e.attrs[name] = pattern
+		}
+	}
+	for _, attr := range e.attrOrder {
+		if _, ok := e.attrs[attr]; !ok {
+			return nil, fmt.Errorf("attribute %q has no value", attr)
+		}
+	}
+
+	return e, nil
+}
+
+// ftlBraceDepth returns the placeable nesting depth after the line l, starting at depth.
+// String literals inside placeables are skipped.
+func ftlBraceDepth(l string, depth int) int {
+	for i := 0; i < len(l); i++ {
+		switch {
+		case l[i] == '{':
+			depth++
+		case l[i] == '}' && depth > 0:
+			depth--
+		case l[i] == '"' && depth > 0:
+			for i++; i < len(l) && l[i] != '"'; i++ {
+				if l[i] == '\\' {
+					i++
+				}
+			}
+		}
+	}
+	return depth
+}
+
+// ftlParser parses the text of a Fluent pattern.
+type ftlParser struct {
+	s   string
+	pos int
+}
+
+// parsePattern parses text and placeables up to the end of the input,
+// or up to the end of a variant when inVariant is set.
+func (p *ftlParser) parsePattern(inVariant bool) ([]ftlNode, error) {
+	var nodes []ftlNode
+	var text strings.Builder
+	flush := func() {
+		if text.Len() > 0 {
+			nodes = append(nodes, ftlNode{kind: ftlText, text: text.String()})
+			text.Reset()
+		}
+	}
+
+	for p.pos < len(p.s) {
+		c := p.s[p.pos]
+		switch {
+		case c == '{':
+			flush()
+			p.pos++
+			n, err := p.parsePlaceable()
+			if err != nil {
+				return nil, err
+			}
+			nodes = append(nodes, n)
+		case inVariant && c == '}',
+			inVariant && c == '\n' && p.pos+1 < len(p.s) && strings.IndexByte("[*}", p.s[p.pos+1]) != -1:
+			flush()
+			return trimFtlPattern(nodes), nil
+		default:
+			text.WriteByte(c)
+			p.pos++
+		}
+	}
+
+	if inVariant {
+		return nil, errors.New("unterminated select expression")
+	}
+	flush()
+	return nodes, nil
+}
+
+// trimFtlPattern removes the whitespace around the text of a variant.
+func trimFtlPattern(nodes []ftlNode) []ftlNode {
+	if len(nodes) > 0 && nodes[0].kind == ftlText {
+		nodes[0].text = strings.TrimLeft(nodes[0].text, " \n")
+	}
+	if last := len(nodes) - 1; last >= 0 && nodes[last].kind == ftlText {
+		nodes[last].text = strings.TrimRight(nodes[last].text, " \n")
+	}
+	return nodes
+}
+
+// parsePlaceable parses a placeable, after its opening brace, up to its closing brace.
+func (p *ftlParser) parsePlaceable() (ftlNode, error) {
+	p.skipBlank()
+	expr, err := p.parseExpression()
+	if err != nil {
+		return expr, err
+	}
+	p.skipBlank()
+
+	if strings.HasPrefix(p.s[p.pos:], "->") {
+		p.pos += 2
+		variants, err := p.parseVariants()
+		if err != nil {
+			return expr, err
+		}
+		return ftlNode{kind: ftlSelect, selector: &expr, variants: variants}, nil
+	}
+
+	if p.pos >= len(p.s) || p.s[p.pos] != '}' {
+		return expr, errors.New("expected '}'")
+	}
+	p.pos++
+	return expr, nil
+}
+
+// parseVariants parses the variants of a select expression, and its closing brace.
+func (p *ftlParser) parseVariants() ([]ftlVariant, error) {
+	var variants []ftlVariant
+	defaults := 0
+
+	for {
+		p.skipBlank()
+		if p.pos >= len(p.s) {
+			return nil, errors.New("unterminated select expression")
+		}
+		if p.s[p.pos] == '}' {
+			p.pos++
+			break
+		}
+
+		v := ftlVariant{}
+		if p.s[p.pos] == '*' {
+			v.def = true
+			defaults++
+			p.pos++
+		}
+		end := strings.IndexByte(p.s[p.pos:], ']')
+		if p.pos >= len(p.s) || p.s[p.pos] != '[' || end == -1 {
+			return nil, errors.New("expected variant key")
+		}
+		v.key = strings.TrimSpace(p.s[p.pos+1 : p.pos+end])
+		p.pos += end + 1
+
+		value, err := p.parsePattern(true)
+		if err != nil {
+			return nil, err
+		}
+		v.value = value
+		variants = append(variants, v)
+	}
+
+	if defaults != 1 {
+		return nil, errors.New("select expression needs exactly one default variant")
+	}
+	return variants, nil
+}
+
+// parseExpression parses an inline expression: a literal, variable, reference, function call or nested placeable.
+func (p *ftlParser) parseExpression() (ftlNode, error) {
+	if p.pos >= len(p.s) {
+		return ftlNode{}, errors.New("unterminated placeable")
+	}
+
+	c := p.s[p.pos]
+	switch {
+	case c == '"':
+		return p.parseString()
+
+	case c == '$':
+		p.pos++
+		id := p.ident()
+		if id == "" {
+			return ftlNode{}, errors.New("invalid variable name")
+		}
+		return ftlNode{kind: ftlVariable, text: id}, nil
+
+	case c == '{':
+		p.pos++
+		return p.parsePlaceable()
+
+	case c >= '0' && c <= '9', c == '-' && p.pos+1 < len(p.s) && p.s[p.pos+1] >= '0' && p.s[p.pos+1] <= '9':
+		start := p.pos
+		for p.pos++; p.pos < len(p.s) && (p.s[p.pos] == '.' || p.s[p.pos] >= '0' && p.s[p.pos] <= '9'); p.pos++ {
+		}
+		return ftlNode{kind: ftlText, text: p.s[start:p.pos]}, nil
+
+	case c == '-':
+		p.pos++
+		id, attr := p.ident(), p.attr()
+		if id == "" {
+			return ftlNode{}, errors.New("invalid term name")
+		}
+		// Term arguments aren't supported
+		if err := p.skipArgs(); err != nil {
+			return ftlNode{}, err
+		}
+		return ftlNode{kind: ftlTermRef, text: "-" + id, attr: attr}, nil
+	}
+
+	id, attr := p.ident(), p.attr()
+	if id == "" {
+		return ftlNode{}, fmt.Errorf("unexpected %q in placeable", c)
+	}
+	if p.pos >= len(p.s) || p.s[p.pos] != '(' {
+		return ftlNode{kind: ftlMessageRef, text: id, attr: attr}, nil
+	}
+
+	// Function call: use its first positional argument
+	p.pos++
+	p.skipBlank()
+	arg := ftlNode{kind: ftlText}
+	if p.pos < len(p.s) && p.s[p.pos] != ')' {
+		var err error
+		if arg, err = p.parseExpression(); err != nil {
+			return arg, err
+		}
+	}
+	return arg, p.closeArgs()
+}
+
+// parseString parses a string literal.
+func (p *ftlParser) parseString() (ftlNode, error) {
+	var b strings.Builder
+	for p.pos++; p.pos < len(p.s); p.pos++ {
+		c := p.s[p.pos]
+		switch c {
+		case '"':
+			p.pos++
+			return ftlNode{kind: ftlText, text: b.String()}, nil
+		case '\n':
+			return ftlNode{}, errors.New("unterminated string literal")
+		case '\\':
+			p.pos++
+			if p.pos >= len(p.s) {
+				return ftlNode{}, errors.New("unterminated string literal")
+			}
+			switch p.s[p.pos] {
+			case '"', '\\':
+				b.WriteByte(p.s[p.pos])
+			case 'u', 'U':
+				size := 4
+				if p.s[p.pos] == 'U' {
+					size = 6
+				}
+				if p.pos+size >= len(p.s) {
+					return ftlNode{}, errors.New("invalid unicode escape")
+				}
+				r, err := strconv.ParseUint(p.s[p.pos+1:p.pos+1+size], 16, 32)
+				if err != nil {
+					return ftlNode{}, errors.New("invalid unicode escape")
+				}
+				b.WriteRune(rune(r))
+				p.pos += size
+			default:
+				return ftlNode{}, fmt.Errorf("invalid escape sequence \\%c", p.s[p.pos])
+			}
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return ftlNode{}, errors.New("unterminated string literal")
+}
+
+// ident reads an identifier, returning an empty string if there's none.
+func (p *ftlParser) ident() string {
+	start := p.pos
+	for ; p.pos < len(p.s); p.pos++ {
+		c := p.s[p.pos]
+		letter := c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+		if !letter && (p.pos == start || !(c >= '0' && c <= '9' || c == '_' || c == '-')) {
+			break
+		}
+	}
+	return p.s[start:p.pos]
+}
+
+// attr reads an attribute accessor, like ".title", returning an empty string if there's none.
+func (p *ftlParser) attr() string {
+	if p.pos < len(p.s) && p.s[p.pos] == '.' {
+		p.pos++
+		return p.ident()
+	}
+	return ""
+}
+
+// skipArgs skips the argument list of a call, if any, up to its closing parenthesis.
+func (p *ftlParser) skipArgs() error {
+	if p.pos >= len(p.s) || p.s[p.pos] != '(' {
+		return nil
+	}
+	p.pos++
+	return p.closeArgs()
+}
+
+// closeArgs skips the rest of an argument list, up to its closing parenthesis.
+func (p *ftlParser) closeArgs() error {
+	depth := 1
+	for ; p.pos < len(p.s); p.pos++ {
+		switch p.s[p.pos] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				p.pos++
+				return nil
+			}
+		case '"':
+			for p.pos++; p.pos < len(p.s) && p.s[p.pos] != '"'; p.pos++ {
+				if p.s[p.pos] == '\\' {
+					p.pos++
+				}
+			}
+		}
+	}
+	return errors.New("unterminated argument list")
+}
+
+// skipBlank skips spaces and line breaks.
+func (p *ftlParser) skipBlank() {
+	for p.pos < len(p.s) && strings.IndexByte(" \t\r\n", p.s[p.pos]) != -1 {
+		p.pos++
+	}
+}
+
+// ftlResolver resolves the references between the entries of a Fluent resource.
+type ftlResolver struct {
+	entries map[string]*ftlEntry
+
+	// References being expanded, rendered as their id when found again to stop cycles
+	active map[string]bool
+	// Expanded references, by id. Those cut by a cycle aren't kept, as they depend on where they're found.
+	expanded map[string][]ftlNode
+	cycles   int
+
+	// Size of the expanded patterns so far, and its limit
+	size, limit int
+}
+
+func newFtlResolver(entries map[string]*ftlEntry, limit int) *ftlResolver {
+	return &ftlResolver{
+		entries:  entries,
+		active:   make(map[string]bool),
+		expanded: make(map[string][]ftlNode),
+		limit:    limit,
+	}
+}
+
+// messageIDs returns the ids of the messages, skipping terms.
+func (r *ftlResolver) messageIDs() []string {
+	ids := make([]string, 0, len(r.entries))
+	for id := range r.entries {
+		if !strings.HasPrefix(id, "-") {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// pattern returns the value or attribute of an entry, or nil if it doesn't exist.
+func (r *ftlResolver) pattern(id, attr string) []ftlNode {
+	e, ok := r.entries[id]
+	if !ok {
+		return nil
+	}
+	if attr != "" {
+		return e.attrs[attr]
+	}
+	return e.value
+}
+
+// expand replaces the references in nodes by the patterns they refer to,
+// and resolves the select expressions that don't depend on a variable.
+// It stops expanding once the limit size is reached.
+func (r *ftlResolver) expand(nodes []ftlNode) []ftlNode {
+	out := make([]ftlNode, 0, len(nodes))
+
+	for _, n := range nodes {
+		if r.size > r.limit {
+			break
+		}
+
+		switch n.kind {
+		case ftlMessageRef, ftlTermRef:
+			name := n.text
+			if n.attr != "" {
+				name += "." + n.attr
+			}
+			ref := r.pattern(n.text, n.attr)
+			if ref == nil || r.active[name] {
+				// Fluent renders missing references as their id
+				if ref != nil {
+					r.cycles++
+				}
+				out = append(out, r.text("{"+name+"}"))
+				continue
+			}
+
+			exp := r.expandRef(name, ref)
+			for _, e := range exp {
+				r.size += ftlNodeSize(e)
+			}
+			out = append(out, exp...)
+
+		case ftlSelect:
+			if n.selector.kind == ftlVariable {
+				sel := n
+				sel.variants = make([]ftlVariant, len(n.variants))
+				for i, v := range n.variants {
+					v.value = r.expand(v.value)
+					sel.variants[i] = v
+				}
+				out = append(out, sel)
+				continue
+			}
+
+			// Static selector: pick its variant now
+			key := ftlPlainText(r.expand([]ftlNode{*n.selector}))
+			out = append(out, r.expand(ftlPickVariant(n.variants, key).value)...)
+
+		default:
+			r.size += ftlNodeSize(n)
+			out = append(out, n)
+		}
+	}
+
+	return out
+}
+
+// expandRef returns the expanded pattern of the message or term name ("id" or "id.attr").
+func (r *ftlResolver) expandRef(name string, pattern []ftlNode) []ftlNode {
+	if exp, ok := r.expanded[name]; ok {
+		return exp
+	}
+
+	cycles := r.cycles
+	r.active[name] = true
+	exp := r.expand(pattern)
+	delete(r.active, name)
+	if r.cycles == cycles {
+		r.expanded[name] = exp
+	}
+	return exp
+}
+
+// text returns a text node, counting its size.
+func (r *ftlResolver) text(s string) ftlNode {
+	r.size += len(s)
+	return ftlNode{kind: ftlText, text: s}
+}
+
+// ftlNodeSize returns the size a node counts for in the expansion limit.
+func ftlNodeSize(n ftlNode) int {
+	size := len(n.text) + 1
+	for _, v := range n.variants {
+		for _, e := range v.value {
+			size += ftlNodeSize(e)
+		}
+	}
+	return size
+}
+
+// ftlPlainText returns the text of nodes, without variables, rendering select expressions with their default variant.
+func ftlPlainText(nodes []ftlNode) string {
+	var b strings.Builder
+	for _, n := range nodes {
+		switch n.kind {
+		case ftlText:
+			b.WriteString(n.text)
+		case ftlSelect:
+			b.WriteString(ftlPlainText(ftlPickVariant(n.variants, "").value))
+		}
+	}
+	return b.String()
+}
+
+// ftlPickVariant returns the variant with the given key, or the default one.
+func ftlPickVariant(variants []ftlVariant, key string) ftlVariant {
+	def := variants[0]
+	for _, v := range variants {
+		if v.key == key {
+			return v
+		}
+		if v.def {
+			def = v
+		}
+	}
+	return def
+}
+
+// addFtlMessage stores an expanded Fluent message, mapping its first select expression to plural forms or contexts.
+// vars has the argument numbers of the variables with a known order, and the rest are numbered by their first appearance.
+// The Domain must be locked.
+func (do *Domain) addFtlMessage(id string, nodes []ftlNode, vars map[string]int) {
+	ftlCollectVars(nodes, vars)
+
+	tr := NewTranslation()
+	tr.ID = id
+	do.translations[id] = tr
+
+	sel := -1
+	for i, n := range nodes {
+		if n.kind == ftlSelect {
+			sel = i
+			break
+		}
+	}
+	if sel == -1 {
+		tr.Set(ftlFormat(nodes, vars))
+		return
+	}
+
+	variant := func(v ftlVariant) string {
+		expanded := make([]ftlNode, 0, len(nodes)+len(v.value))
+		expanded = append(expanded, nodes[:sel]...)
+		expanded = append(expanded, v.value...)
+		expanded = append(expanded, nodes[sel+1:]...)
+		return ftlFormat(expanded, vars)
+	}
+
+	variants := nodes[sel].variants
+	if ftlPluralVariants(variants) {
+		tr.PluralID = id
+		for i, form := range cardinalForms(do.tag) {
+			tr.Trs[i] = variant(ftlPickVariant(variants, cldrCategory(form)))
+		}
+		return
+	}
+
+	tr.Set(variant(ftlPickVariant(variants, "")))
+	for _, v := range variants {
+		if _, ok := do.contexts[v.key]; !ok {
+			do.contexts[v.key] = make(map[string]*Translation)
+		}
+		ctr := NewTranslation()
+		ctr.ID = id
+		ctr.Set(variant(v))
+		do.contexts[v.key][id] = ctr
+	}
+}
+
+// ftlPluralVariants tells whether the keys of the variants are CLDR plural categories or numbers.
+func ftlPluralVariants(variants []ftlVariant) bool {
+	for _, v := range variants {
+		if _, ok := cldrCategories[v.key]; ok {
+			continue
+		}
+		if _, err := strconv.ParseFloat(v.key, 64); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// ftlCollectVars numbers the variables of nodes that aren't in vars yet, in order of appearance.
+func ftlCollectVars(nodes []ftlNode, vars map[string]int) {
+	for _, n := range nodes {
+		switch n.kind {
+		case ftlVariable:
+			if _, ok := vars[n.text]; !ok {
+				vars[n.text] = len(vars) + 1
+			}
+		case ftlSelect:
+			ftlCollectVars([]ftlNode{*n.selector}, vars)
+			for _, v := range n.variants {
+				ftlCollectVars(v.value, vars)
+			}
+		}
+	}
+}
+
+// ftlFormat renders nodes as a fmt format string, with explicit argument indexes for the variables.
+// Nested select expressions are rendered with their default variant.
+func ftlFormat(nodes []ftlNode, vars map[string]int) string {
+	var flat []ftlNode
+	var flatten func([]ftlNode)
+	flatten = func(nodes []ftlNode) {
+		for _, n := range nodes {
+			if n.kind == ftlSelect {
+				flatten(ftlPickVariant(n.variants, "").value)
+			} else {
+				flat = append(flat, n)
+			}
+		}
+	}
+	flatten(nodes)
+
+	hasVars := false
+	for _, n := range flat {
+		hasVars = hasVars || n.kind == ftlVariable
+	}
+
+	var b strings.Builder
+	for _, n := range flat {
+		switch {
+		case n.kind == ftlVariable:
+			b.WriteString("%[" + strconv.Itoa(vars[n.text]) + "]v")
+		case hasVars:
+			b.WriteString(strings.Replace(n.text, "%", "%%", -1))
+		default:
+			b.WriteString(n.text)
+		}
+	}
+	return b.String()
+}
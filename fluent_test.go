@@ -0,0 +1,182 @@
+package gotext
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+const fluentResource = `
+### Resource comment
+
+-brand = Firefox
+    .gender = masculine
+
+# Simple messages
+hello = Hello, { $name }!
+welcome = Welcome to { -brand }
+about = About { welcome }
+literal = Opening brace: {"{"}, escaped: {"é"}, number: { 42 }
+percent = 100% sure
+percent-var = { $n }% done
+
+login = Log in
+    .title = Click to log in
+    .aria-label = Login button
+
+only-attrs =
+    .placeholder = Search
+
+multiline =
+    First line
+    second line
+
+emails = { $count ->
+    [one] { $count } письмо от { $from }
+    [few] { $count } письма от { $from }
+   *[many] { $count } писем от { $from }
+}
+
+unread = У вас { NUMBER($count, minimumFractionDigits: 0) ->
+    [one] одно сообщение
+   *[other] { $count } сообщений
+} сегодня
+
+greeting = { $gender ->
+    [male] Welcome, sir
+    [female] Welcome, madam
+   *[other] Welcome
+}
+
+brand-update = { -brand.gender ->
+    [masculine] Il est à jour
+   *[other] Elle est à jour
+}
+
+invalid line here
+broken = { $x
+`
+
+func TestFluent(t *testing.T) {
+	f := NewFluent("ru")
+	err := f.ParseWithError([]byte(fluentResource))
+
+	errs, ok := err.(ParseErrors)
+	if !ok || len(errs) != 2 {
+		t.Fatalf("expected 2 parse errors, got %v", err)
+	}
+	if errs[0].Line != 48 || errs[1].Line != 49 {
+		t.Errorf("unexpected error lines: %v", errs)
+	}
+
+	for _, c := range []struct {
+		id   string
+		vars []interface{}
+		want string
+	}{
+		{"hello", []interface{}{"World"}, "Hello, World!"},
+		{"welcome", nil, "Welcome to Firefox"},
+		{"about", nil, "About Welcome to Firefox"},
+		{"literal", nil, "Opening brace: {, escaped: é, number: 42"},
+		{"percent", nil, "100% sure"},
+		{"percent-var", []interface{}{50}, "50% done"},
+		{"login", nil, "Log in"},
+		{"login.title", nil, "Click to log in"},
+		{"login.aria-label", nil, "Login button"},
+		{"only-attrs.placeholder", nil, "Search"},
+		{"multiline", nil, "First line\nsecond line"},
+		{"greeting", nil, "Welcome"},
+		{"brand-update", nil, "Il est à jour"},
+		{"-brand", nil, "-brand"},
+		{"broken", nil, "broken"},
+	} {
+		if got := f.Get(c.id, c.vars...); got != c.want {
+			t.Errorf("Get(%q) = %q, want %q", c.id, got, c.want)
+		}
+	}
+
+	for n, want := range map[int]string{
+		1:  "1 письмо от Anna",
+		3:  "3 письма от Anna",
+		5:  "5 писем от Anna",
+		21: "21 письмо от Anna",
+	} {
+		if got := f.GetN("emails", "emails", n, n, "Anna"); got != want {
+			t.Errorf("GetN(emails, %d) = %q, want %q", n, got, want)
+		}
+	}
+
+	// Variants that don't use the variable
+	if got := f.GetN("unread", "unread", 1, 1); got != "У вас одно сообщение сегодня" {
+		t.Errorf("unexpected unread form: %q", got)
+	}
+	if got := f.GetN("unread", "unread", 7, 7); got != "У вас 7 сообщений сегодня" {
+		t.Errorf("unexpected unread form: %q", got)
+	}
+
+	if got := f.GetC("greeting", "female"); got != "Welcome, madam" {
+		t.Errorf("unexpected greeting: %q", got)
+	}
+}
+
+func TestFluentReferenceCycle(t *testing.T) {
+	f := NewFluent("en")
+	f.Parse([]byte("a = A { b }\nb = B { a }\n"))
+
+	if got := f.Get("a"); got == "" {
+		t.Error("expected a value for a message with a reference cycle")
+	}
+}
+
+func TestFluentSelfReference(t *testing.T) {
+	f := NewFluent("en")
+	if err := f.ParseWithError([]byte("a = {a}{a}{a}{a}{a}{a}\n")); err != nil {
+		t.Fatal(err)
+	}
+	if got := f.Get("a"); got != "{a}{a}{a}{a}{a}{a}" {
+		t.Errorf("unexpected value %q", got)
+	}
+
+	// A cycle renders the same whatever message is expanded first
+	f = NewFluent("en")
+	f.Parse([]byte("a = A { b }\nb = B { a }\n"))
+	if a, b := f.Get("a"), f.Get("b"); a != "A B {a}" || b != "B A {b}" {
+		t.Errorf("unexpected values %q, %q", a, b)
+	}
+
+	// Each message referencing the next one 6 times expands to 6^12 copies of the last one
+	var res strings.Builder
+	for i := 0; i < 12; i++ {
+		fmt.Fprintf(&res, "m%d = ", i)
+		for j := 0; j < 6; j++ {
+			fmt.Fprintf(&res, "{m%d}", i+1)
+		}
+		res.WriteString("\n")
+	}
+	res.WriteString("m12 = boom\n")
+	if err := NewFluent("en").ParseWithError([]byte(res.String())); err == nil {
+		t.Error("expected an error for references expanding too much")
+	}
+}
+
+func TestFluentLocale(t *testing.T) {
+	src := &MemorySource{Files: map[string][]byte{
+		"ru/LC_MESSAGES/app.ftl": []byte(fluentResource),
+	}}
+
+	l := NewLocaleWithSource(src, "ru")
+	l.AddDomain("app")
+
+	// Fluent ids aren't format strings
+	hello := "hello"
+	if got := l.GetD("app", hello, "Мир"); got != "Hello, Мир!" {
+		t.Errorf("unexpected translation %q", got)
+	}
+	if got := l.GetND("app", "emails", "emails", 2, 2, "Anna"); got != "2 письма от Anna" {
+		t.Errorf("unexpected plural translation %q", got)
+	}
+}
+
+func TestFluentTranslator(t *testing.T) {
+	var _ Translator = NewFluent("en")
+}
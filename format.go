@@ -0,0 +1,119 @@
+package gotext
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/text/currency"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/number"
+)
+
+// dateLayouts holds the short numeric date layout (in Go time layout syntax) used by each language or language-region pair.
+// Languages not listed here use ISO 8601 (2006-01-02).
+var dateLayouts = map[string]string{
+	"en":    "1/2/2006",
+	"en-AU": "02/01/2006",
+	"en-CA": "2006-01-02",
+	"en-GB": "02/01/2006",
+	"en-IE": "02/01/2006",
+	"en-IN": "02/01/2006",
+	"en-NZ": "02/01/2006",
+	"en-SG": "02/01/2006",
+	"en-ZA": "2006/01/02",
+	"de":    "02.01.2006",
+	"es":    "2/1/2006",
+	"fr":    "02/01/2006",
+	"fr-CA": "2006-01-02",
+	"it":    "02/01/2006",
+	"pt":    "02/01/2006",
+	"nl":    "2-1-2006",
+	"ru":    "02.01.2006",
+	"uk":    "02.01.2006",
+	"pl":    "2.01.2006",
+	"cs":    "2. 1. 2006",
+	"tr":    "02.01.2006",
+	"fi":    "2.1.2006",
+	"da":    "02.01.2006",
+	"nb":    "02.01.2006",
+	"sv":    "2006-01-02",
+	"hu":    "2006. 01. 02.",
+	"ja":    "2006/01/02",
+	"zh":    "2006/1/2",
+	"ko":    "2006. 1. 2.",
+	"ar":    "2/1/2006",
+	"he":    "2.1.2006",
+}
+
+// printer returns a x/text message.Printer for the Locale language.
+func (l *Locale) printer() *message.Printer {
+	return message.NewPrinter(l.tag)
+}
+
+// FormatNumber formats a number (any integer or floating point type) using the Locale's grouping and decimal separators.
+func (l *Locale) FormatNumber(n interface{}) string {
+	return l.printer().Sprint(number.Decimal(n))
+}
+
+// FormatPercent formats a ratio as a percentage (0.25 is "25%") using the Locale's conventions.
+func (l *Locale) FormatPercent(n interface{}) string {
+	return l.printer().Sprint(number.Percent(n))
+}
+
+// FormatCurrency formats an amount in the given currency using the Locale's conventions and the currency symbol.
+//
+//	l.FormatCurrency(12.5, currency.EUR)
+func (l *Locale) FormatCurrency(amount interface{}, unit currency.Unit) string {
+	return l.formatAmount(unit.Amount(amount))
+}
+
+// formatAmount formats a currency amount with its symbol, using the Locale's grouping and decimal separators
+// and the number of decimals of the currency.
+func (l *Locale) formatAmount(a currency.Amount) string {
+	p := l.printer()
+
+	// x/text neither localizes the value of amounts nor exposes it: it's read back from their ISO format, "EUR 1234.5"
+	var iso amountState
+	currency.ISO(a).Format(&iso, 'v')
+	value, err := strconv.ParseFloat(strings.TrimSpace(strings.TrimLeft(iso.String(), "ABCDEFGHIJKLMNOPQRSTUVWXYZ")), 64)
+	if err != nil {
+		return p.Sprint(currency.Symbol(a))
+	}
+	scale, _ := currency.Standard.Rounding(a.Currency())
+	return p.Sprint(currency.Symbol(a.Currency())) + " " + p.Sprint(number.Decimal(value, number.Scale(scale)))
+}
+
+// amountState is a fmt.State collecting the text written. It has a precision, so amounts are written as they are.
+type amountState struct {
+	bytes.Buffer
+}
+
+func (*amountState) Width() (int, bool)     { return 0, false }
+func (*amountState) Precision() (int, bool) { return 0, true }
+func (*amountState) Flag(int) bool          { return false }
+
+// FormatDate formats the date part of t using the Locale's short numeric date layout.
+func (l *Locale) FormatDate(t time.Time) string {
+	return t.Format(dateLayout(l.tag))
+}
+
+// dateLayout looks up the date layout for the tag, trying the language-region pair first and then the base language.
+func dateLayout(tag language.Tag) string {
+	if tag.IsRoot() {
+		return "2006-01-02"
+	}
+	base, _ := tag.Base()
+	region, conf := tag.Region()
+	if conf == language.Exact {
+		if layout, ok := dateLayouts[base.String()+"-"+region.String()]; ok {
+			return layout
+		}
+	}
+	if layout, ok := dateLayouts[base.String()]; ok {
+		return layout
+	}
+	return "2006-01-02"
+}
@@ -0,0 +1,51 @@
+package gotext
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/text/currency"
+)
+
+func TestLocaleFormat(t *testing.T) {
+	en := NewLocale("fixtures/", "en_US")
+	de := NewLocale("fixtures/", "de_DE")
+
+	if s := en.FormatNumber(1234.5); s != "1,234.5" {
+		t.Errorf("Expected '1,234.5' but got '%s'", s)
+	}
+	if s := de.FormatNumber(1234.5); s != "1.234,5" {
+		t.Errorf("Expected '1.234,5' but got '%s'", s)
+	}
+	if s := en.FormatPercent(0.25); s != "25%" {
+		t.Errorf("Expected '25%%' but got '%s'", s)
+	}
+	if s := en.FormatCurrency(12.5, currency.USD); !strings.Contains(s, "12.50") {
+		t.Errorf("Expected '12.50' in '%s'", s)
+	}
+
+	date := time.Date(2020, time.March, 4, 0, 0, 0, 0, time.UTC)
+	if s := en.FormatDate(date); s != "3/4/2020" {
+		t.Errorf("Expected '3/4/2020' but got '%s'", s)
+	}
+	if s := de.FormatDate(date); s != "04.03.2020" {
+		t.Errorf("Expected '04.03.2020' but got '%s'", s)
+	}
+	if s := NewLocale("fixtures/", "xx").FormatDate(date); s != "2020-03-04" {
+		t.Errorf("Expected '2020-03-04' but got '%s'", s)
+	}
+	for lang, want := range map[string]string{"en": "3/4/2020", "en_GB": "04/03/2020", "en_AU": "04/03/2020", "en_CA": "2020-03-04"} {
+		if s := NewLocale("fixtures/", lang).FormatDate(date); s != want {
+			t.Errorf("Expected '%s' for %s but got '%s'", want, lang, s)
+		}
+	}
+
+	// Amounts use the separators of the language and the decimals of the currency
+	if s := de.FormatCurrency(1234.5, currency.EUR); s != "€ 1.234,50" {
+		t.Errorf("Expected '€ 1.234,50' but got '%s'", s)
+	}
+	if s := en.FormatCurrency(1234567, currency.JPY); s != "¥ 1,234,567" {
+		t.Errorf("Expected '¥ 1,234,567' but got '%s'", s)
+	}
+}
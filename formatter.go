@@ -0,0 +1,72 @@
+package gotext
+
+import (
+	"sync/atomic"
+)
+
+// Formatter inserts the parameters of a lookup into its translation. It's used by every Get function
+// instead of fmt.Sprintf once set with SetFormatter.
+type Formatter interface {
+	Format(msg string, vars ...interface{}) string
+}
+
+// FormatterFunc is a function used as a Formatter.
+type FormatterFunc func(msg string, vars ...interface{}) string
+
+// Format calls f(msg, vars...).
+func (f FormatterFunc) Format(msg string, vars ...interface{}) string {
+	return f(msg, vars...)
+}
+
+// formatterHolder keeps the concrete type stored in the atomic.Value the same.
+type formatterHolder struct {
+	f Formatter
+}
+
+var currentFormatter atomic.Value
+
+/*
+SetFormatter replaces the fmt.Sprintf formatting done by Printf, and so by all the Get functions of the package,
+Locale and Translator objects, with f. It allows using a templating engine for messages, escaping the parameters
+inserted in HTML, or any other formatting:
+
+	gotext.SetFormatter(gotext.FormatterFunc(func(msg string, vars ...interface{}) string {
+		for i, v := range vars {
+			if s, ok := v.(string); ok {
+				vars[i] = html.EscapeString(s)
+			}
+		}
+		return fmt.Sprintf(msg, vars...)
+	}))
+
+f is called for every string returned, even when there are no parameters (see SetRawWithoutVars),
+and must be safe for concurrent use. A nil f restores the default formatting.
+*/
+func SetFormatter(f Formatter) {
+	currentFormatter.Store(formatterHolder{f})
+}
+
+// formatter returns the Formatter set by SetFormatter, or nil.
+func formatter() Formatter {
+	if h, ok := currentFormatter.Load().(formatterHolder); ok {
+		return h.f
+	}
+	return nil
+}
+
+var rawWithoutVars int32
+
+/*
+SetRawWithoutVars makes Printf, and so all the Get functions, return the strings looked up without any vars as they are,
+without calling the Formatter set by SetFormatter. Translations with literal percent signs, like "100% juice",
+then can't be mangled into "100%!j(MISSING)uice" by a Formatter using fmt.Sprintf.
+
+The default formatting never changes strings without vars, so it only matters with a Formatter.
+*/
+func SetRawWithoutVars(on bool) {
+	var v int32
+	if on {
+		v = 1
+	}
+	atomic.StoreInt32(&rawWithoutVars, v)
+}
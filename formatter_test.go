@@ -0,0 +1,67 @@
+package gotext
+
+import (
+	"fmt"
+	"html"
+	"testing"
+)
+
+func TestSetFormatter(t *testing.T) {
+	l := NewLocale("", "es")
+	if err := l.AddDomainBytes("default", []byte(lookupPo), FormatPO); err != nil {
+		t.Fatal(err)
+	}
+
+	SetFormatter(FormatterFunc(func(msg string, vars ...interface{}) string {
+		escaped := make([]interface{}, len(vars))
+		for i, v := range vars {
+			if s, ok := v.(string); ok {
+				v = html.EscapeString(s)
+			}
+			escaped[i] = v
+		}
+		return "[" + fmt.Sprintf(msg, escaped...) + "]"
+	}))
+	defer SetFormatter(nil)
+
+	file, files, missing := "%d file", "%d files", "Missing %s"
+	if tr := l.GetN(file, files, 2, 2); tr != "[2 archivos]" {
+		t.Errorf("Unexpected translation %q", tr)
+	}
+	if tr := l.Get(missing, "<b>"); tr != "[Missing &lt;b&gt;]" {
+		t.Errorf("Unexpected translation %q", tr)
+	}
+	if tr := l.Get("Hello"); tr != "[Hola]" {
+		t.Errorf("Expected the formatter to be called without vars, got %q", tr)
+	}
+
+	SetFormatter(nil)
+	if tr := l.Get(missing, "<b>"); tr != "Missing <b>" {
+		t.Errorf("Expected the default formatting, got %q", tr)
+	}
+}
+
+func TestSetRawWithoutVars(t *testing.T) {
+	po := NewPo()
+	po.Parse([]byte("msgid \"100% juice\"\nmsgstr \"100% Saft\"\n\nmsgid \"%d%% juice\"\nmsgstr \"%d%% Saft\"\n"))
+
+	SetFormatter(FormatterFunc(func(msg string, vars ...interface{}) string {
+		return fmt.Sprintf(msg, vars...)
+	}))
+	defer SetFormatter(nil)
+
+	juice, percent := "100% juice", "%d%% juice"
+	if tr := po.Get(juice); tr == "100% Saft" {
+		t.Error("expected the formatter to mangle the translation")
+	}
+
+	SetRawWithoutVars(true)
+	defer SetRawWithoutVars(false)
+
+	if tr := po.Get(juice); tr != "100% Saft" {
+		t.Errorf("Unexpected translation %q", tr)
+	}
+	if tr := po.Get(percent, 50); tr != "50% Saft" {
+		t.Errorf("Unexpected translation %q", tr)
+	}
+}
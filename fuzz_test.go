@@ -0,0 +1,82 @@
+//go:build go1.18
+// +build go1.18
+
+package gotext
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+// addFixtures adds the fixture files matching pattern to the corpus of f.
+func addFixtures(f *testing.F, pattern string) {
+	files, err := filepath.Glob(pattern)
+	if err != nil {
+		f.Fatal(err)
+	}
+	for _, file := range files {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(data)
+	}
+}
+
+// exercise looks up every entry of tr, so the fuzz targets also check catalogs that parse but can't be used.
+func exercise(tr Translator) {
+	for _, e := range tr.GetDomain().entries() {
+		tr.GetC(e.MsgID, e.Context)
+		for n := 0; n < 3; n++ {
+			tr.GetNC(e.MsgID, e.Translation.PluralID, n, e.Context)
+		}
+	}
+}
+
+func FuzzPoParse(f *testing.F) {
+	addFixtures(f, "fixtures/*/*.po")
+	addFixtures(f, "fixtures/*/LC_MESSAGES/*.po")
+	f.Add([]byte("msgid \"\"\nmsgstr \"\"\n\"Plural-Forms: nplurals=2; plural=(n != 1);\\n\"\n\nmsgid \"a\"\nmsgid_plural \"b\"\nmsgstr[0] \"c\"\n"))
+	f.Add([]byte("msgctxt \"x\"\n#~ msgid \"old\"\nmsgstr[5] \"\\\"\"\n\"\\n"))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		po := NewPo()
+		po.Parse(data)
+		exercise(po)
+
+		// Catalogs written back must parse again
+		text, err := po.MarshalText()
+		if err != nil {
+			return
+		}
+		again := NewPo()
+		again.Parse(text)
+		exercise(again)
+
+		NewPoFromString(string(data))
+	})
+}
+
+func FuzzMoParse(f *testing.F) {
+	addFixtures(f, "fixtures/*/*.mo")
+	addFixtures(f, "fixtures/*/LC_MESSAGES/*.mo")
+	f.Add([]byte{0xde, 0x12, 0x04, 0x95, 0, 0, 0, 0, 1, 0, 0, 0, 28, 0, 0, 0, 36, 0, 0, 0})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		mo := NewMo()
+		if err := mo.ParseWithError(data); err == nil {
+			exercise(mo)
+		}
+
+		indexed := NewIndexedMo(4)
+		if err := indexed.ParseWithError(data); err == nil {
+			for _, id := range []string{"", "a", "One with var: %s"} {
+				indexed.Get(id)
+				indexed.GetN(id, id, 2)
+				indexed.GetC(id, "Ctx")
+			}
+			indexed.Load()
+		}
+	})
+}
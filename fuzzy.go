@@ -0,0 +1,144 @@
+package gotext
+
+import (
+	"sort"
+	"unicode/utf8"
+)
+
+// MissingTranslation describes a lookup whose msgid isn't in the catalog.
+type MissingTranslation struct {
+	Context string
+	MsgID   string
+
+	// Closest translated msgid used instead by the fuzzy matching, and its similarity, from 0 to 1.
+	// Match is empty when there was no close enough msgid.
+	Match      string
+	Similarity float64
+}
+
+// SetMissingHook sets a function called by every lookup that doesn't find its msgid, even after normalization,
+// so missing translations can be logged or collected. It's called with the Domain locked for reading.
+func (do *Domain) SetMissingHook(hook func(MissingTranslation)) {
+	do.trMutex.Lock()
+	do.missingHook = hook
+	do.trMutex.Unlock()
+}
+
+/*
+SetFuzzyMatch makes the lookups that don't find their msgid use the translation of the closest translated msgid
+in the same context, when their similarity is at least threshold. The similarity goes from 0 to 1, and is 1 minus
+the Levenshtein distance between the msgids divided by the length of the longest one.
+A threshold of 0 turns fuzzy matching off.
+
+Every msgid of the context is compared with the missing one, so it's meant for development and times of
+source string churn, along with a missing hook (see SetMissingHook) that reports the matches used.
+*/
+func (do *Domain) SetFuzzyMatch(threshold float64) {
+	do.trMutex.Lock()
+	do.fuzzyThreshold = threshold
+	do.trMutex.Unlock()
+}
+
+// miss handles a lookup of str in ctx that didn't find its msgid among trs, returning the fuzzy match if any.
+// The Domain must be locked.
+func (do *Domain) miss(str, ctx string, trs map[string]*Translation) (*Translation, bool) {
+	if do.missingHook == nil && do.fuzzyThreshold <= 0 {
+		return nil, false
+	}
+
+	m := MissingTranslation{Context: ctx, MsgID: str}
+	var match *Translation
+	if do.fuzzyThreshold > 0 {
+		match, m.Match, m.Similarity = closestTranslation(str, trs, do.fuzzyThreshold)
+	}
+	if do.missingHook != nil {
+		do.missingHook(m)
+	}
+	return match, match != nil
+}
+
+// closestTranslation returns the translated msgid of trs most similar to str, and its similarity,
+// or nil if none is at least as similar as threshold. Ties go to the first msgid in sort order.
+func closestTranslation(str string, trs map[string]*Translation, threshold float64) (*Translation, string, float64) {
+	ids := make([]string, 0, len(trs))
+	for id, tr := range trs {
+		if id != "" && tr.IsTranslated() {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+
+	var best string
+	bestSimilarity := 0.0
+	strLen := utf8.RuneCountInString(str)
+	for _, id := range ids {
+		idLen := utf8.RuneCountInString(id)
+		longest, diff := idLen, idLen-strLen
+		if strLen > longest {
+			longest = strLen
+		}
+		if diff < 0 {
+			diff = -diff
+		}
+		// The distance is at least the length difference, so skip the msgids that can't be close enough
+		if 1-float64(diff)/float64(longest) < threshold {
+			continue
+		}
+
+		similarity := 1 - float64(levenshtein(str, id))/float64(longest)
+		if similarity >= threshold && similarity > bestSimilarity {
+			best, bestSimilarity = id, similarity
+		}
+	}
+
+	if best == "" {
+		return nil, "", 0
+	}
+	return trs[best], best, bestSimilarity
+}
+
+// levenshtein returns the number of rune insertions, deletions and substitutions needed to turn a into b.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = prev[j-1] + cost
+			if prev[j]+1 < cur[j] {
+				cur[j] = prev[j] + 1
+			}
+			if cur[j-1]+1 < cur[j] {
+				cur[j] = cur[j-1] + 1
+			}
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
+
+// EnableFuzzyMatch makes the catalogs loaded afterwards by AddDomain use the translation of the closest msgid
+// when the looked up one is missing, as long as their similarity is at least threshold (see Domain.SetFuzzyMatch).
+// The matches are reported to the missing hook set by SetMissingHook.
+func (l *Locale) EnableFuzzyMatch(threshold float64) {
+	l.Lock()
+	l.fuzzyThreshold = threshold
+	l.Unlock()
+}
+
+// SetMissingHook sets a function called, with the domain name, by the lookups of the catalogs loaded afterwards
+// by AddDomain that don't find their msgid (see Domain.SetMissingHook).
+func (l *Locale) SetMissingHook(hook func(dom string, m MissingTranslation)) {
+	l.Lock()
+	l.missingHook = hook
+	l.Unlock()
+}
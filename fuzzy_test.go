@@ -0,0 +1,93 @@
+package gotext
+
+import (
+	"testing"
+)
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"abc", "", 3},
+		{"kitten", "sitting", 3},
+		{"Grüße", "Grüsse", 2},
+	}
+	for _, test := range tests {
+		if got := levenshtein(test.a, test.b); got != test.want {
+			t.Errorf("levenshtein(%q, %q): expected %d, got %d", test.a, test.b, test.want, got)
+		}
+	}
+}
+
+func TestDomainSetFuzzyMatch(t *testing.T) {
+	po := NewPo()
+	po.Parse([]byte(`
+msgid "Save your changes"
+msgstr "Änderungen speichern"
+
+msgid "Save your change"
+msgstr ""
+
+msgctxt "menu"
+msgid "Close window"
+msgstr "Fenster schließen"
+`))
+
+	var misses []MissingTranslation
+	po.GetDomain().SetMissingHook(func(m MissingTranslation) {
+		misses = append(misses, m)
+	})
+
+	near, far, closeWin := "Save your changes!", "Quit", "Close windows"
+	if tr := po.Get(near); tr != near {
+		t.Errorf("Expected no fuzzy matching by default, got %q", tr)
+	}
+
+	po.GetDomain().SetFuzzyMatch(0.8)
+	if tr := po.Get(near); tr != "Änderungen speichern" {
+		t.Errorf("Unexpected translation %q", tr)
+	}
+	if tr := po.Get(far); tr != far {
+		t.Errorf("Expected no match, got %q", tr)
+	}
+	if tr := po.GetC(closeWin, "menu"); tr != "Fenster schließen" {
+		t.Errorf("Unexpected translation %q", tr)
+	}
+
+	if len(misses) != 4 {
+		t.Fatalf("Expected 4 misses, got %v", misses)
+	}
+	if m := misses[1]; m.MsgID != near || m.Match != "Save your changes" || m.Similarity < 0.9 {
+		t.Errorf("Unexpected miss %+v", m)
+	}
+	if m := misses[2]; m.MsgID != far || m.Match != "" {
+		t.Errorf("Unexpected miss %+v", m)
+	}
+	if m := misses[3]; m.Context != "menu" || m.Match != "Close window" {
+		t.Errorf("Unexpected miss %+v", m)
+	}
+}
+
+func TestLocaleEnableFuzzyMatch(t *testing.T) {
+	src := &MemorySource{Files: map[string][]byte{
+		"de/default.po": []byte("msgid \"Delete file\"\nmsgstr \"Datei löschen\"\n"),
+	}}
+
+	var doms []string
+	l := NewLocaleWithSource(src, "de")
+	l.EnableFuzzyMatch(0.7)
+	l.SetMissingHook(func(dom string, m MissingTranslation) {
+		doms = append(doms, dom)
+	})
+	l.AddDomain("default")
+
+	del := "Delete files"
+	if tr := l.Get(del); tr != "Datei löschen" {
+		t.Errorf("Unexpected translation %q", tr)
+	}
+	if len(doms) != 1 || doms[0] != "default" {
+		t.Errorf("Unexpected misses %v", doms)
+	}
+}
@@ -159,13 +159,13 @@ func Configure(lib, lang, dom string) {
 // Get uses the default domain globally set to return the corresponding Translation of a given string.
 // Supports optional parameters (vars... interface{}) to be inserted on the formatted string using the fmt.Printf syntax.
 func Get(str string, vars ...interface{}) string {
-	return GetD(GetDomain(), str, vars...)
+	return GetD(callerDomain(0, GetDomain()), str, vars...)
 }
 
 // GetN retrieves the (N)th plural form of Translation for the given string in the default domain.
 // Supports optional parameters (vars... interface{}) to be inserted on the formatted string using the fmt.Printf syntax.
 func GetN(str, plural string, n int, vars ...interface{}) string {
-	return GetND(GetDomain(), str, plural, n, vars...)
+	return GetND(callerDomain(0, GetDomain()), str, plural, n, vars...)
 }
 
 // GetD returns the corresponding Translation in the given domain for a given string.
@@ -209,13 +209,13 @@ func GetND(dom, str, plural string, n int, vars ...interface{}) string {
 // GetC uses the default domain globally set to return the corresponding Translation of the given string in the given context.
 // Supports optional parameters (vars... interface{}) to be inserted on the formatted string using the fmt.Printf syntax.
 func GetC(str, ctx string, vars ...interface{}) string {
-	return GetDC(GetDomain(), str, ctx, vars...)
+	return GetDC(callerDomain(0, GetDomain()), str, ctx, vars...)
 }
 
 // GetNC retrieves the (N)th plural form of Translation for the given string in the given context in the default domain.
 // Supports optional parameters (vars... interface{}) to be inserted on the formatted string using the fmt.Printf syntax.
 func GetNC(str, plural string, n int, ctx string, vars ...interface{}) string {
-	return GetNDC(GetDomain(), str, plural, n, ctx, vars...)
+	return GetNDC(callerDomain(0, GetDomain()), str, plural, n, ctx, vars...)
 }
 
 // GetDC returns the corresponding Translation in the given domain for the given string in the given context.
@@ -245,3 +245,43 @@ func GetNDC(dom, str, plural string, n int, ctx string, vars ...interface{}) str
 
 	return tr
 }
+
+// GetOrdinal retrieves the ordinal form of Translation for the given string in the default domain.
+// Supports optional parameters (vars... interface{}) to be inserted on the formatted string using the fmt.Printf syntax.
+func GetOrdinal(str string, n int, vars ...interface{}) string {
+	return GetOrdinalD(callerDomain(0, GetDomain()), str, n, vars...)
+}
+
+// GetOrdinalD retrieves the ordinal form of Translation in the given domain for the given string.
+// Supports optional parameters (vars... interface{}) to be inserted on the formatted string using the fmt.Printf syntax.
+func GetOrdinalD(dom, str string, n int, vars ...interface{}) string {
+	// Try to load default package Locale storage
+	loadStorage(false)
+
+	// Return Translation
+	globalConfig.RLock()
+	tr := globalConfig.storage.GetOrdinalD(dom, str, n, vars...)
+	globalConfig.RUnlock()
+
+	return tr
+}
+
+// GetRange retrieves the plural form of Translation for a range of values (from-to) in the default domain.
+// Supports optional parameters (vars... interface{}) to be inserted on the formatted string using the fmt.Printf syntax.
+func GetRange(str, plural string, from, to int, vars ...interface{}) string {
+	return GetRangeD(callerDomain(0, GetDomain()), str, plural, from, to, vars...)
+}
+
+// GetRangeD retrieves the plural form of Translation for a range of values (from-to) in the given domain.
+// Supports optional parameters (vars... interface{}) to be inserted on the formatted string using the fmt.Printf syntax.
+func GetRangeD(dom, str, plural string, from, to int, vars ...interface{}) string {
+	// Try to load default package Locale storage
+	loadStorage(false)
+
+	// Return Translation
+	globalConfig.RLock()
+	tr := globalConfig.storage.GetRangeD(dom, str, plural, from, to, vars...)
+	globalConfig.RUnlock()
+
+	return tr
+}
@@ -0,0 +1,161 @@
+/*
+Package gotexttest provides a Translator recording the messages it's asked to translate,
+so tests can assert which strings a handler attempted to translate:
+
+	rec := gotexttest.NewRecorder(gotext.NewDomainBuilder().Add("Hello", "Hola").BuildPo())
+	l := gotext.NewLocale("", "es")
+	l.AddTranslator("default", rec)
+
+	handler(l).ServeHTTP(w, r)
+
+	if rec.Count("Hello") != 1 {
+		t.Errorf("expected one greeting, got calls %v", rec.Calls())
+	}
+*/
+package gotexttest
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/leonelquinteros/gotext"
+)
+
+// Call is a request for a translation made to a Recorder.
+type Call struct {
+	// Name of the Translator method called: Get, GetN, GetC or GetNC
+	Method string
+
+	MsgID   string
+	Plural  string
+	Context string
+	N       int
+	Vars    []interface{}
+}
+
+// Recorder is a gotext.Translator logging every call to Get, GetN, GetC and GetNC,
+// and answering them with another Translator. It's safe for concurrent use.
+type Recorder struct {
+	tr gotext.Translator
+
+	mu    sync.Mutex
+	calls []Call
+}
+
+// NewRecorder returns a Recorder answering with the translations of tr, the canned responses.
+// A nil tr answers every call with the untranslated strings.
+func NewRecorder(tr gotext.Translator) *Recorder {
+	if tr == nil {
+		tr = gotext.NewPo()
+	}
+	return &Recorder{tr: tr}
+}
+
+func (r *Recorder) record(c Call) {
+	r.mu.Lock()
+	r.calls = append(r.calls, c)
+	r.mu.Unlock()
+}
+
+// Calls returns the calls made so far, in order.
+func (r *Recorder) Calls() []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]Call(nil), r.calls...)
+}
+
+// Count returns the number of calls made for msgid, in any context.
+func (r *Recorder) Count(msgid string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	n := 0
+	for _, c := range r.calls {
+		if c.MsgID == msgid {
+			n++
+		}
+	}
+	return n
+}
+
+// CountC returns the number of calls made for msgid in the context ctx. An empty ctx counts the calls without context.
+func (r *Recorder) CountC(msgid, ctx string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	n := 0
+	for _, c := range r.calls {
+		if c.MsgID == msgid && c.Context == ctx {
+			n++
+		}
+	}
+	return n
+}
+
+// MsgIDs returns the msgids requested so far, sorted and without duplicates.
+func (r *Recorder) MsgIDs() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	seen := make(map[string]bool, len(r.calls))
+	var ids []string
+	for _, c := range r.calls {
+		if !seen[c.MsgID] {
+			seen[c.MsgID] = true
+			ids = append(ids, c.MsgID)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Reset forgets the calls made so far.
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	r.calls = nil
+	r.mu.Unlock()
+}
+
+// ParseFile parses the catalog file f into the canned responses.
+func (r *Recorder) ParseFile(f string) {
+	r.tr.ParseFile(f)
+}
+
+// Parse parses the catalog data buf into the canned responses.
+func (r *Recorder) Parse(buf []byte) {
+	r.tr.Parse(buf)
+}
+
+func (r *Recorder) Get(str string, vars ...interface{}) string {
+	r.record(Call{Method: "Get", MsgID: str, Vars: vars})
+	return r.tr.Get(str, vars...)
+}
+
+func (r *Recorder) GetN(str, plural string, n int, vars ...interface{}) string {
+	r.record(Call{Method: "GetN", MsgID: str, Plural: plural, N: n, Vars: vars})
+	return r.tr.GetN(str, plural, n, vars...)
+}
+
+func (r *Recorder) GetC(str, ctx string, vars ...interface{}) string {
+	r.record(Call{Method: "GetC", MsgID: str, Context: ctx, Vars: vars})
+	return r.tr.GetC(str, ctx, vars...)
+}
+
+func (r *Recorder) GetNC(str, plural string, n int, ctx string, vars ...interface{}) string {
+	r.record(Call{Method: "GetNC", MsgID: str, Plural: plural, N: n, Context: ctx, Vars: vars})
+	return r.tr.GetNC(str, plural, n, ctx, vars...)
+}
+
+func (r *Recorder) MarshalBinary() ([]byte, error) {
+	return r.tr.MarshalBinary()
+}
+
+func (r *Recorder) UnmarshalBinary(data []byte) error {
+	return r.tr.UnmarshalBinary(data)
+}
+
+// GetDomain returns the catalog of the canned responses.
+func (r *Recorder) GetDomain() *gotext.Domain {
+	return r.tr.GetDomain()
+}
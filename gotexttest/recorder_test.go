@@ -0,0 +1,84 @@
+package gotexttest
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/leonelquinteros/gotext"
+)
+
+func TestRecorder(t *testing.T) {
+	rec := NewRecorder(gotext.NewDomainBuilder().
+		Add("Hello %s", "Hola %s").
+		AddC("May", "month", "Mayo").
+		BuildPo())
+
+	l := gotext.NewLocale("", "es")
+	l.AddTranslator("default", rec)
+
+	hello, bye := "Hello %s", "Bye"
+	if tr := l.Get(hello, "Ana"); tr != "Hola Ana" {
+		t.Errorf("Expected the canned response, got '%s'", tr)
+	}
+	if tr := l.Get(bye); tr != bye {
+		t.Errorf("Expected the msgid, got '%s'", tr)
+	}
+	if tr := l.GetC("May", "month"); tr != "Mayo" {
+		t.Errorf("Expected the canned response, got '%s'", tr)
+	}
+	l.GetN("%d file", "%d files", 2, 2)
+	l.Get(hello, "Luis")
+
+	calls := rec.Calls()
+	if len(calls) != 5 {
+		t.Fatalf("Expected 5 calls, got %v", calls)
+	}
+	want := Call{Method: "GetN", MsgID: "%d file", Plural: "%d files", N: 2, Vars: []interface{}{2}}
+	if !reflect.DeepEqual(calls[3], want) {
+		t.Errorf("Unexpected call %+v", calls[3])
+	}
+	if n := rec.Count(hello); n != 2 {
+		t.Errorf("Expected 2 calls for %q, got %d", hello, n)
+	}
+	if n := rec.CountC("May", "month"); n != 1 {
+		t.Errorf("Expected 1 call in the context, got %d", n)
+	}
+	if n := rec.CountC("May", ""); n != 0 {
+		t.Errorf("Expected no call without context, got %d", n)
+	}
+	if ids := rec.MsgIDs(); !reflect.DeepEqual(ids, []string{"%d file", bye, hello, "May"}) {
+		t.Errorf("Unexpected msgids %q", ids)
+	}
+
+	// Canned responses count as translations
+	if _, ok := l.Lookup(hello, "x"); !ok {
+		t.Error("Expected the canned response to be found")
+	}
+
+	rec.Reset()
+	if calls := rec.Calls(); len(calls) != 0 {
+		t.Errorf("Expected no calls after Reset, got %v", calls)
+	}
+}
+
+func TestRecorderConcurrent(t *testing.T) {
+	rec := NewRecorder(nil)
+	rec.Parse([]byte("msgid \"Yes\"\nmsgstr \"Sí\"\n"))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if tr := rec.Get("Yes"); tr != "Sí" {
+				t.Errorf("Expected 'Sí' but got '%s'", tr)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := rec.Count("Yes"); n != 10 {
+		t.Errorf("Expected 10 calls, got %d", n)
+	}
+}
@@ -0,0 +1,10 @@
+module github.com/leonelquinteros/gotext/grpc
+
+require (
+	github.com/leonelquinteros/gotext v1.4.0
+	google.golang.org/grpc v1.27.0
+)
+
+replace github.com/leonelquinteros/gotext => ../
+
+go 1.13
@@ -0,0 +1,104 @@
+/*
+Package gotextgrpc provides gRPC server interceptors that pick the language of each RPC from its metadata.
+
+It lives in its own module, so the gotext package doesn't depend on gRPC.
+
+	ls := gotext.NewLocales("/path/to/i18n/dir", "en_US", "de_DE", "fr")
+	ls.AddDomain("default")
+
+	srv := grpc.NewServer(
+		grpc.UnaryInterceptor(gotextgrpc.UnaryServerInterceptor(ls)),
+		grpc.StreamInterceptor(gotextgrpc.StreamServerInterceptor(ls)),
+	)
+
+Handlers then translate with the Ctx lookups of gotext:
+
+	func (s *server) Hello(ctx context.Context, req *pb.HelloRequest) (*pb.HelloReply, error) {
+		if req.Name == "" {
+			return nil, gotextgrpc.Errorf(ctx, codes.InvalidArgument, "Name is required")
+		}
+		return &pb.HelloReply{Message: gotext.GetCtx(ctx, "Hello %s", req.Name)}, nil
+	}
+*/
+package gotextgrpc
+
+import (
+	"context"
+
+	"github.com/leonelquinteros/gotext"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// MetadataKey is the metadata key read to find the languages requested by the client.
+// Its values use the Accept-Language header syntax.
+const MetadataKey = "accept-language"
+
+// UnaryServerInterceptor returns an interceptor that resolves the Locale matching the accept-language metadata
+// of each unary RPC from ls and injects it into the RPC context (see gotext.NewContext).
+func UnaryServerInterceptor(ls *gotext.Locales) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		return handler(NewContext(ctx, ls), req)
+	}
+}
+
+// StreamServerInterceptor returns an interceptor that resolves the Locale matching the accept-language metadata
+// of each streaming RPC from ls and injects it into the stream context.
+func StreamServerInterceptor(ls *gotext.Locales) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &localeStream{ServerStream: ss, ctx: NewContext(ss.Context(), ls)})
+	}
+}
+
+// NewContext returns a copy of the incoming RPC context ctx carrying the Locale from ls
+// that best matches its accept-language metadata.
+func NewContext(ctx context.Context, ls *gotext.Locales) context.Context {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	l := ls.Match(md.Get(MetadataKey)...)
+	if l == nil {
+		return ctx
+	}
+	return gotext.NewContext(ctx, l)
+}
+
+// Errorf returns a status error with code c and the message format translated in the language of the RPC context,
+// using the domain set by gotext.WithDomain, or the default domain of its Locale.
+// Supports optional parameters (a... interface{}) to be inserted on the formatted string using the fmt.Printf syntax.
+func Errorf(ctx context.Context, c codes.Code, format string, a ...interface{}) error {
+	return status.Error(c, gotext.GetDCtx(ctx, domain(ctx), format, a...))
+}
+
+// ErrorfD returns a status error with code c and the message format translated in the given domain,
+// in the language of the RPC context.
+// Supports optional parameters (a... interface{}) to be inserted on the formatted string using the fmt.Printf syntax.
+func ErrorfD(ctx context.Context, c codes.Code, dom, format string, a ...interface{}) error {
+	return status.Error(c, gotext.GetDCtx(ctx, dom, format, a...))
+}
+
+// domain returns the domain set by gotext.WithDomain on ctx, or the default domain of the Locale carried by ctx,
+// or the package level one.
+func domain(ctx context.Context) string {
+	if dom, ok := gotext.DomainFromContext(ctx); ok {
+		return dom
+	}
+	if l, ok := gotext.FromContext(ctx); ok {
+		if dom := l.GetDomain(); dom != "" {
+			return dom
+		}
+	}
+	return gotext.GetDomain()
+}
+
+// localeStream overrides the context of a grpc.ServerStream.
+type localeStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context returns the stream context carrying the Locale.
+func (s *localeStream) Context() context.Context {
+	return s.ctx
+}
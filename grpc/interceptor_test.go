@@ -0,0 +1,68 @@
+package gotextgrpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/leonelquinteros/gotext"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func newLocales() *gotext.Locales {
+	ls := gotext.NewLocales("../fixtures/", "en_US", "de_DE", "fr")
+	ls.AddDomain("default")
+	return ls
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	interceptor := UnaryServerInterceptor(newLocales())
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("Accept-Language", "fr-CH, fr;q=0.9"))
+	resp, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return gotext.GetCtx(ctx, "language"), Errorf(ctx, codes.InvalidArgument, "My text")
+	})
+
+	if resp != "fr" {
+		t.Errorf("Expected 'fr' but got '%v'", resp)
+	}
+	if s := status.Convert(err); s.Code() != codes.InvalidArgument || s.Message() != "Translated text" {
+		t.Errorf("Unexpected status %v: %s", s.Code(), s.Message())
+	}
+}
+
+type testStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *testStream) Context() context.Context {
+	return s.ctx
+}
+
+func TestStreamServerInterceptor(t *testing.T) {
+	interceptor := StreamServerInterceptor(newLocales())
+
+	var lang string
+	ss := &testStream{ctx: metadata.NewIncomingContext(context.Background(), metadata.Pairs("accept-language", "de-DE"))}
+	interceptor(nil, ss, &grpc.StreamServerInfo{}, func(srv interface{}, stream grpc.ServerStream) error {
+		lang = gotext.GetCtx(stream.Context(), "language")
+		return nil
+	})
+
+	if lang != "de_DE" {
+		t.Errorf("Expected 'de_DE' but got '%s'", lang)
+	}
+
+	// Without metadata the first Locale is used
+	ss.ctx = context.Background()
+	interceptor(nil, ss, &grpc.StreamServerInfo{}, func(srv interface{}, stream grpc.ServerStream) error {
+		lang = gotext.GetCtx(stream.Context(), "language")
+		return nil
+	})
+	if lang != "en_US" {
+		t.Errorf("Expected 'en_US' but got '%s'", lang)
+	}
+}
@@ -0,0 +1,142 @@
+package gotext
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang.org/x/text/language"
+
+	"github.com/leonelquinteros/gotext/plurals"
+)
+
+// parsePluralForms parses a Plural-Forms header value like "nplurals=2; plural=(n != 1);".
+// It returns the compiled plural expression, or an error if it's missing or can't be compiled.
+func parsePluralForms(pf string) (nplurals int, plural string, expr plurals.Expression, err error) {
+	for _, part := range strings.Split(pf, ";") {
+		vs := strings.SplitN(part, "=", 2)
+		if len(vs) != 2 {
+			continue
+		}
+
+		switch strings.TrimSpace(vs[0]) {
+		case "nplurals":
+			nplurals, _ = strconv.Atoi(strings.TrimSpace(vs[1]))
+		case "plural":
+			plural = strings.TrimSpace(vs[1])
+		}
+	}
+
+	if plural == "" {
+		return nplurals, plural, nil, fmt.Errorf("gotext: missing plural expression in Plural-Forms %q", pf)
+	}
+	if expr, err = plurals.Compile(plural); err != nil {
+		return nplurals, plural, nil, err
+	}
+	if nplurals < 1 {
+		return nplurals, plural, expr, fmt.Errorf("gotext: invalid nplurals in Plural-Forms %q", pf)
+	}
+	return nplurals, plural, expr, nil
+}
+
+// headerKey returns the key used in the Headers map for key, compared case insensitively, or key itself if there's none.
+func (do *Domain) headerKey(key string) string {
+	if _, ok := do.Headers[key]; ok {
+		return key
+	}
+	for k := range do.Headers {
+		if strings.EqualFold(k, key) {
+			return k
+		}
+	}
+	return key
+}
+
+// setHeader sets the value of the header key, compared case insensitively. The Domain must be locked.
+func (do *Domain) setHeader(key, value string) {
+	if do.Headers == nil {
+		do.Headers = make(HeaderMap)
+	}
+	do.Headers.Set(do.headerKey(key), value)
+}
+
+// Header returns the value of the catalog header key, compared case insensitively.
+func (do *Domain) Header(key string) string {
+	do.trMutex.RLock()
+	defer do.trMutex.RUnlock()
+
+	return do.Headers.Get(do.headerKey(key))
+}
+
+// SetHeader sets the value of the catalog header key, compared case insensitively.
+// Setting the Language or Plural-Forms headers updates the Domain language or plural rule too.
+// It returns an error, without changing anything, for an invalid Plural-Forms value.
+func (do *Domain) SetHeader(key, value string) error {
+	switch strings.ToLower(key) {
+	case "language":
+		do.SetLanguage(value)
+		return nil
+	case "plural-forms":
+		return do.SetPluralForms(value)
+	}
+
+	do.trMutex.Lock()
+	do.setHeader(key, value)
+	do.trMutex.Unlock()
+	return nil
+}
+
+// DelHeader removes the catalog header key, compared case insensitively.
+func (do *Domain) DelHeader(key string) {
+	do.trMutex.Lock()
+	do.Headers.Del(do.headerKey(key))
+	do.trMutex.Unlock()
+}
+
+// GetLanguage returns the catalog language, from the Language header.
+func (do *Domain) GetLanguage() string {
+	do.trMutex.RLock()
+	defer do.trMutex.RUnlock()
+
+	return do.Language
+}
+
+// SetLanguage sets the catalog language and its Language header.
+func (do *Domain) SetLanguage(lang string) {
+	do.trMutex.Lock()
+	do.setHeader("Language", lang)
+	do.Language = lang
+	do.tag = language.Make(lang)
+	do.trMutex.Unlock()
+}
+
+// SetPluralForms sets the Plural-Forms header and compiles its plural rule, which is used from then on.
+// It returns an error, without changing anything, when the value is invalid.
+//
+//	err := do.SetPluralForms("nplurals=3; plural=(n%10==1 && n%100!=11 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2);")
+func (do *Domain) SetPluralForms(pf string) error {
+	nplurals, plural, expr, err := parsePluralForms(pf)
+	if err != nil {
+		return err
+	}
+
+	do.trMutex.Lock()
+	do.pluralMutex.Lock()
+	defer do.trMutex.Unlock()
+	defer do.pluralMutex.Unlock()
+
+	do.setHeader("Plural-Forms", pf)
+	do.PluralForms = pf
+	do.nplurals = nplurals
+	do.plural = plural
+	do.setPluralExpression(expr)
+	return nil
+}
+
+// GetNPlurals returns the number of plural forms of the catalog, from the Plural-Forms header.
+func (do *Domain) GetNPlurals() int {
+	do.pluralMutex.RLock()
+	defer do.pluralMutex.RUnlock()
+
+	return do.nplurals
+}
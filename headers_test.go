@@ -0,0 +1,66 @@
+package gotext
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDomainHeaders(t *testing.T) {
+	po := NewPo()
+	po.Parse([]byte(`
+msgid ""
+msgstr ""
+"language: en\n"
+"Plural-Forms: nplurals=2; plural=(n != 1);\n"
+"X-Generator: test\n"
+
+msgid "file"
+msgid_plural "files"
+msgstr[0] "файл"
+msgstr[1] "файла"
+msgstr[2] "файлов"
+`))
+	do := po.GetDomain()
+
+	if v := do.Header("x-generator"); v != "test" {
+		t.Errorf("Expected 'test' but got '%s'", v)
+	}
+	if v := do.GetLanguage(); v != "en" {
+		t.Errorf("Expected 'en' but got '%s'", v)
+	}
+
+	// Original key case is kept
+	if err := po.SetHeader("Language", "ru"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := do.Headers["language"]; !ok || po.Language != "ru" || do.GetLanguage() != "ru" {
+		t.Errorf("Unexpected language headers %v", do.Headers)
+	}
+
+	if err := po.SetPluralForms("nplurals=3; plural=(n%10==1 && n%100!=11 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2);"); err != nil {
+		t.Fatal(err)
+	}
+	if do.GetNPlurals() != 3 || !strings.HasPrefix(po.PluralForms, "nplurals=3") {
+		t.Errorf("Unexpected plural forms %d %s", do.GetNPlurals(), po.PluralForms)
+	}
+	if tr := po.GetN("file", "files", 5); tr != "файлов" {
+		t.Errorf("Expected 'файлов' but got '%s'", tr)
+	}
+
+	// Invalid values are refused
+	for _, pf := range []string{"nplurals=2;", "nplurals=0; plural=(n != 1);", "nplurals=2; plural=(n !=;"} {
+		if err := do.SetPluralForms(pf); err == nil {
+			t.Errorf("Expected an error for '%s'", pf)
+		}
+	}
+	if do.GetNPlurals() != 3 {
+		t.Error("Expected invalid Plural-Forms not to change anything")
+	}
+
+	do.SetHeader("Last-Translator", "Jane")
+	do.DelHeader("x-GENERATOR")
+	buff, _ := po.MarshalText()
+	if !strings.Contains(string(buff), `"Last-Translator: Jane\n"`) || strings.Contains(string(buff), "X-Generator") {
+		t.Errorf("Unexpected headers in:\n%s", buff)
+	}
+}
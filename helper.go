@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+	"sync/atomic"
 )
 
 var re = regexp.MustCompile(`%\(([a-zA-Z0-9_]+)\)[.0-9]*[svTtbcdoqXxUeEfFgGp]`)
@@ -28,8 +29,16 @@ func SimplifiedLocale(lang string) string {
 	return strings.TrimSpace(lang)
 }
 
-// Printf applies text formatting only when needed to parse variables.
+// Printf applies text formatting only when needed to parse variables, or calls the Formatter set by SetFormatter.
 func Printf(str string, vars ...interface{}) string {
+	if len(vars) == 0 && atomic.LoadInt32(&rawWithoutVars) == 1 {
+		return str
+	}
+
+	if f := formatter(); f != nil {
+		return f.Format(str, vars...)
+	}
+
 	if len(vars) > 0 {
 		return fmt.Sprintf(str, vars...)
 	}
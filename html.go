@@ -0,0 +1,172 @@
+package gotext
+
+import (
+	"fmt"
+	"html"
+	"html/template"
+	"strconv"
+	"strings"
+)
+
+// defaultHTMLTags are the tags GetHTML keeps in translations, unless others are set with SetHTMLTags.
+var defaultHTMLTags = []string{"b", "strong", "i", "em", "u", "s", "small", "mark", "code", "sub", "sup", "br"}
+
+// SetHTMLTags sets the tags GetHTML trusts in translations: b, strong, i, em, u, s, small, mark, code, sub, sup and br
+// by default. Tag names are case insensitive. Calling it without tags makes GetHTML escape every tag.
+func (l *Locale) SetHTMLTags(tags ...string) {
+	allowed := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		allowed[strings.ToLower(tag)] = true
+	}
+
+	l.Lock()
+	l.htmlTags = allowed
+	l.Unlock()
+}
+
+// htmlAllowed returns the tags trusted by GetHTML. The Locale must be locked.
+func (l *Locale) htmlAllowed() map[string]bool {
+	if l.htmlTags != nil {
+		return l.htmlTags
+	}
+	allowed := make(map[string]bool, len(defaultHTMLTags))
+	for _, tag := range defaultHTMLTags {
+		allowed[tag] = true
+	}
+	return allowed
+}
+
+/*
+GetHTML translates str in the default domain for use in HTML templates, like Get does,
+trusting the markup of the translation but not the parameters:
+
+	// msgid "Welcome back, <strong>%s</strong>!"
+	l.GetHTML("Welcome back, <strong>%s</strong>!", user.Name)
+
+Tags of the translation in the allowlist (see SetHTMLTags) are kept when they have no attributes,
+and any other markup is escaped, so a bad translation can't inject scripts or links.
+Parameters are escaped after being formatted, unless they are template.HTML values, which are trusted.
+*/
+func (l *Locale) GetHTML(str string, vars ...interface{}) template.HTML {
+	return l.GetHTMLD(callerDomain(0, l.GetDomain()), str, vars...)
+}
+
+// GetHTMLD translates str in the domain dom for use in HTML templates. See GetHTML.
+// The translation is formatted like GetD does, and its post-processors are applied, before it's sanitized.
+func (l *Locale) GetHTMLD(dom, str string, vars ...interface{}) template.HTML {
+	l.RLock()
+	defer l.RUnlock()
+
+	// Trusted parameters are kept aside while the rest is sanitized
+	var trusted []string
+	vars = l.localVars(vars)
+	params := make([]interface{}, len(vars))
+	for i, v := range vars {
+		if h, ok := v.(template.HTML); ok {
+			params[i] = htmlTrusted{h, &trusted}
+		} else {
+			params[i] = htmlEscaped{v}
+		}
+	}
+
+	msg := l.getD(dom, str, params)
+	for _, f := range l.postProcessors {
+		msg = f(str, msg)
+	}
+	msg = sanitizeHTML(msg, l.htmlAllowed())
+	for i, h := range trusted {
+		msg = strings.Replace(msg, htmlSlot(i), h, 1)
+	}
+	return template.HTML(l.encodeOutput(msg))
+}
+
+// sanitizeHTML escapes the markup of msg but the tags in allowed without attributes, like <strong> or </strong>.
+func sanitizeHTML(msg string, allowed map[string]bool) string {
+	var b strings.Builder
+	for {
+		i := strings.IndexByte(msg, '<')
+		if i == -1 {
+			b.WriteString(msg)
+			return b.String()
+		}
+		b.WriteString(msg[:i])
+		msg = msg[i:]
+
+		end := strings.IndexByte(msg, '>')
+		if end != -1 && allowedTag(msg[1:end], allowed) {
+			b.WriteString(strings.ToLower(msg[:end+1]))
+			msg = msg[end+1:]
+			continue
+		}
+		b.WriteString("&lt;")
+		msg = msg[1:]
+	}
+}
+
+// allowedTag reports whether tag, the text between < and >, is an opening, closing or self-closing tag in allowed,
+// without attributes.
+func allowedTag(tag string, allowed map[string]bool) bool {
+	tag = strings.TrimPrefix(tag, "/")
+	tag = strings.TrimSpace(strings.TrimSuffix(tag, "/"))
+	if tag == "" {
+		return false
+	}
+	for _, r := range tag {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9') {
+			return false
+		}
+	}
+	return allowed[strings.ToLower(tag)]
+}
+
+// htmlEscaped formats its value like fmt does, then escapes it for HTML.
+type htmlEscaped struct {
+	v interface{}
+}
+
+func (e htmlEscaped) Format(f fmt.State, verb rune) {
+	str := html.EscapeString(fmt.Sprintf(formatDirective(f, verb), e.v))
+	fmt.Fprint(f, strings.NewReplacer(htmlSlotStart, "&#xE000;", htmlSlotEnd, "&#xE001;").Replace(str))
+}
+
+// htmlTrusted formats a template.HTML value as is. It's written as a slot, replaced by the value once
+// the translation is sanitized, and the value is added to slots.
+type htmlTrusted struct {
+	h     template.HTML
+	slots *[]string
+}
+
+func (h htmlTrusted) Format(f fmt.State, verb rune) {
+	fmt.Fprint(f, htmlSlot(len(*h.slots)))
+	*h.slots = append(*h.slots, fmt.Sprintf(formatDirective(f, verb), string(h.h)))
+}
+
+// Private use characters delimiting the slots of trusted parameters
+const (
+	htmlSlotStart = "\uE000"
+	htmlSlotEnd   = "\uE001"
+)
+
+// htmlSlot returns the slot of the i-th trusted parameter.
+func htmlSlot(i int) string {
+	return htmlSlotStart + strconv.Itoa(i) + htmlSlotEnd
+}
+
+// formatDirective rebuilds the fmt directive, like "%-5.2f", being formatted by f.
+func formatDirective(f fmt.State, verb rune) string {
+	var b strings.Builder
+	b.WriteByte('%')
+	for _, flag := range "+-# 0" {
+		if f.Flag(int(flag)) {
+			b.WriteRune(flag)
+		}
+	}
+	if w, ok := f.Width(); ok {
+		b.WriteString(strconv.Itoa(w))
+	}
+	if p, ok := f.Precision(); ok {
+		b.WriteString("." + strconv.Itoa(p))
+	}
+	b.WriteRune(verb)
+	return b.String()
+}
@@ -0,0 +1,88 @@
+package gotext
+
+import (
+	"fmt"
+	"html/template"
+	"testing"
+)
+
+const htmlPo = `
+msgid "Welcome back, <strong>%s</strong>!"
+msgstr "¡Bienvenido, <STRONG>%s</STRONG>!<br/>"
+
+msgid "Click <a href=\"%s\">here</a>"
+msgstr "Haz clic <a href=\"%s\">aquí</a> <script>alert(1)</script>"
+
+msgid "%d < %d"
+msgstr "%5d < %-3d|"
+`
+
+func TestLocaleGetHTML(t *testing.T) {
+	l := NewLocale("", "es")
+	if err := l.AddDomainBytes("default", []byte(htmlPo), FormatPO); err != nil {
+		t.Fatal(err)
+	}
+
+	welcome, click, less := "Welcome back, <strong>%s</strong>!", "Click <a href=\"%s\">here</a>", "%d < %d"
+	for _, tc := range []struct {
+		str  string
+		vars []interface{}
+		want template.HTML
+	}{
+		{welcome, []interface{}{"<b>Bob</b> & co"}, "¡Bienvenido, <strong>&lt;b&gt;Bob&lt;/b&gt; &amp; co</strong>!<br/>"},
+		{welcome, []interface{}{template.HTML("<em>Bob</em>")}, "¡Bienvenido, <strong><em>Bob</em></strong>!<br/>"},
+		{click, []interface{}{"/x"}, "Haz clic &lt;a href=\"/x\">aquí&lt;/a> &lt;script>alert(1)&lt;/script>"},
+		{less, []interface{}{1, 2}, "    1 &lt; 2  |"},
+		{"Missing <i>tag</i> <img>", nil, "Missing <i>tag</i> &lt;img>"},
+	} {
+		if got := l.GetHTML(tc.str, tc.vars...); got != tc.want {
+			t.Errorf("%q: got %q, want %q", tc.str, got, tc.want)
+		}
+	}
+
+	l.SetHTMLTags("a")
+	if got := l.GetHTML("<a>link</a> <b>bold</b>"); got != "<a>link</a> &lt;b>bold&lt;/b>" {
+		t.Errorf("unexpected translation %q", got)
+	}
+	l.SetHTMLTags()
+	if got := l.GetHTML("<a>link</a>"); got != "&lt;a>link&lt;/a>" {
+		t.Errorf("unexpected translation %q", got)
+	}
+}
+
+func TestLocaleGetHTMLFormatting(t *testing.T) {
+	l := NewLocale("", "es")
+	if err := l.AddDomainBytes("default", []byte(htmlPo), FormatPO); err != nil {
+		t.Fatal(err)
+	}
+
+	// Post-processors see the formatted translation, before it's sanitized
+	var seen string
+	l.AddPostProcessor(func(msgid, out string) string {
+		seen = out
+		return out + "<script>"
+	})
+	SetFormatter(FormatterFunc(func(msg string, vars ...interface{}) string {
+		return "[" + fmt.Sprintf(msg, vars...) + "]"
+	}))
+	defer SetFormatter(nil)
+
+	welcome := "Welcome back, <strong>%s</strong>!"
+	got := l.GetHTML(welcome, "<i>Bob</i>")
+	if want := template.HTML("[¡Bienvenido, <strong>&lt;i&gt;Bob&lt;/i&gt;</strong>!<br/>]&lt;script>"); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if seen != "[¡Bienvenido, <STRONG>&lt;i&gt;Bob&lt;/i&gt;</STRONG>!<br/>]" {
+		t.Errorf("unexpected post-processor input %q", seen)
+	}
+
+	// Trusted parameters are kept as they are, and can't be forged by the others
+	got = l.GetHTML(welcome, template.HTML(`<a href="/bob">Bob</a>`))
+	if want := template.HTML(`[¡Bienvenido, <strong><a href="/bob">Bob</a></strong>!<br/>]&lt;script>`); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	got = l.GetHTML(welcome, htmlSlot(0))
+	if want := template.HTML("[¡Bienvenido, <strong>&#xE000;0&#xE001;</strong>!<br/>]&lt;script>"); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,281 @@
+package gotext
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// HTTPOptions configures the remote catalog loading of NewLocaleHTTP.
+type HTTPOptions struct {
+	// Client used for all requests. Defaults to http.DefaultClient.
+	Client *http.Client
+
+	// RefreshInterval enables the periodic refresh of all loaded domains when it's greater than zero.
+	RefreshInterval time.Duration
+
+	// MissingTTL is how long a domain without catalog on the server isn't looked up again,
+	// so adding it repeatedly doesn't request every candidate URL each time. Defaults to one minute.
+	MissingTTL time.Duration
+
+	// OnError is called with the errors found while loading or refreshing a domain in the background.
+	// Previously loaded translations are kept when a refresh fails.
+	OnError func(dom string, err error)
+}
+
+// httpLoader fetches the catalogs of a Locale created by NewLocaleHTTP.
+type httpLoader struct {
+	baseURL string
+	locale  *Locale
+	opts    HTTPOptions
+
+	mu      sync.Mutex
+	entries map[string]*httpEntry
+	// Time until which the domains without catalog aren't looked up again
+	missing map[string]time.Time
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// remoteExtensions are the catalog file extensions looked up by NewLocaleHTTP Locale objects, in order
+var remoteExtensions = append(append([]string(nil), catalogExtensions...), "json")
+
+// defaultMissingTTL is the HTTPOptions.MissingTTL used when it's not set
+const defaultMissingTTL = time.Minute
+
+// httpEntry holds the location and cache validators of a remote domain.
+type httpEntry struct {
+	url          string
+	ext          string
+	etag         string
+	lastModified string
+}
+
+/*
+NewLocaleHTTP creates a Locale that loads its domains over HTTP(S) instead of the filesystem.
+Catalogs are looked up under baseURL using the same layout and language simplification as NewLocale,
+i.e. AddDomain("default") tries "<baseURL>/de_DE/LC_MESSAGES/default.po", "<baseURL>/de/LC_MESSAGES/default.po" and so on,
+for the same formats plus JSON catalogs (as written by Domain.ExportJSON). Domains without catalog are remembered
+for HTTPOptions.MissingTTL, so they aren't looked up again on every AddDomain.
+
+Refreshes use the ETag and Last-Modified headers sent by the server, so unchanged catalogs aren't downloaded nor parsed again.
+
+	l := gotext.NewLocaleHTTP("https://cdn.example.com/i18n", "de_DE", gotext.HTTPOptions{
+		RefreshInterval: 5 * time.Minute,
+	})
+	defer l.StopRefresh()
+
+	l.AddDomain("default")
+*/
+func NewLocaleHTTP(baseURL, lang string, opts HTTPOptions) *Locale {
+	if opts.Client == nil {
+		opts.Client = http.DefaultClient
+	}
+	if opts.MissingTTL <= 0 {
+		opts.MissingTTL = defaultMissingTTL
+	}
+
+	l := NewLocale(baseURL, lang)
+	l.remote = &httpLoader{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		locale:  l,
+		opts:    opts,
+		entries: make(map[string]*httpEntry),
+		missing: make(map[string]time.Time),
+		stop:    make(chan struct{}),
+	}
+
+	if opts.RefreshInterval > 0 {
+		go l.refreshLoop(opts.RefreshInterval)
+	}
+
+	return l
+}
+
+// RefreshRemote re-fetches every domain loaded by a Locale created with NewLocaleHTTP,
+// replacing the ones that changed on the server. It returns the first error found, if any.
+// It does nothing for Locale objects loading catalogs from the filesystem.
+func (l *Locale) RefreshRemote() error {
+	if l.remote == nil {
+		return nil
+	}
+
+	l.remote.mu.Lock()
+	doms := make([]string, 0, len(l.remote.entries))
+	for dom := range l.remote.entries {
+		doms = append(doms, dom)
+	}
+	l.remote.mu.Unlock()
+
+	var firstErr error
+	for _, dom := range doms {
+		tr, err := l.remote.load(dom)
+		if err != nil {
+			logWarn("gotext: catalog reload failed", "lang", l.lang, "domain", dom, "err", err)
+			l.remote.report(dom, err)
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		if tr != nil {
+			l.CancelSchedule(dom)
+			l.AddTranslator(dom, tr)
+			logInfo("gotext: catalog reloaded", "lang", l.lang, "domain", dom)
+		}
+	}
+
+	return firstErr
+}
+
+// StopRefresh stops the periodic refresh started by NewLocaleHTTP.
+func (l *Locale) StopRefresh() {
+	if l.remote == nil {
+		return
+	}
+	l.remote.stopOnce.Do(func() {
+		close(l.remote.stop)
+	})
+}
+
+func (l *Locale) refreshLoop(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			l.RefreshRemote()
+		case <-l.remote.stop:
+			return
+		}
+	}
+}
+
+// addRemoteDomain is the AddDomain implementation for Locale objects created by NewLocaleHTTP.
+func (l *Locale) addRemoteDomain(dom string) {
+	tr, err := l.remote.load(dom)
+	if err != nil {
+		l.remote.report(dom, err)
+		return
+	}
+	if tr != nil {
+		l.CancelSchedule(dom)
+		l.AddTranslator(dom, tr)
+	}
+}
+
+func (h *httpLoader) report(dom string, err error) {
+	if h.opts.OnError != nil {
+		h.opts.OnError(dom, err)
+	}
+}
+
+// load fetches the domain catalog. It returns a nil Translator when the catalog didn't change since the last load.
+func (h *httpLoader) load(dom string) (Translator, error) {
+	h.mu.Lock()
+	entry, known := h.entries[dom]
+	until, missing := h.missing[dom]
+	h.mu.Unlock()
+
+	if known {
+		cached := *entry
+		return h.fetch(dom, &cached)
+	}
+	if missing && time.Now().Before(until) {
+		return nil, h.notFound(dom)
+	}
+
+	// The URL found on the first load is remembered for refreshes
+	for _, f := range h.locale.catalogFiles(dom) {
+		candidate := httpEntry{url: h.baseURL + "/" + f.path, ext: f.ext}
+		tr, err := h.fetch(dom, &candidate)
+		if err == errNotFound {
+			continue
+		}
+		return tr, err
+	}
+
+	h.mu.Lock()
+	h.missing[dom] = time.Now().Add(h.opts.MissingTTL)
+	h.mu.Unlock()
+
+	return nil, h.notFound(dom)
+}
+
+func (h *httpLoader) notFound(dom string) error {
+	return fmt.Errorf("gotext: no catalog found for domain %q under %s", dom, h.baseURL)
+}
+
+var errNotFound = errors.New("gotext: catalog not found")
+
+// fetch requests a single catalog URL, using the cache validators of entry, and saves the new validators on success.
+func (h *httpLoader) fetch(dom string, entry *httpEntry) (tr Translator, err error) {
+	_, span := startSpan(context.Background(), "gotext.FetchCatalog", "domain", dom, "url", entry.url)
+	defer func() {
+		if err == errNotFound {
+			span.End(nil)
+			return
+		}
+		if tr != nil {
+			span.SetAttribute("entries", tr.GetDomain().entryCount())
+		}
+		span.End(err)
+	}()
+
+	req, err := http.NewRequest(http.MethodGet, entry.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if entry.etag != "" {
+		req.Header.Set("If-None-Match", entry.etag)
+	}
+	if entry.lastModified != "" {
+		req.Header.Set("If-Modified-Since", entry.lastModified)
+	}
+
+	resp, err := h.opts.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	span.SetAttribute("status", resp.StatusCode)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotModified:
+		return nil, nil
+	case http.StatusNotFound:
+		return nil, errNotFound
+	default:
+		return nil, fmt.Errorf("gotext: fetching %s: %s", entry.url, resp.Status)
+	}
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if data, err = decompress(data); err != nil {
+		return nil, err
+	}
+
+	tr, err = h.locale.parseCatalog(dom, entry.ext, data)
+	if err != nil {
+		return nil, err
+	}
+
+	entry.etag = resp.Header.Get("ETag")
+	entry.lastModified = resp.Header.Get("Last-Modified")
+	h.mu.Lock()
+	h.entries[dom] = entry
+	delete(h.missing, dom)
+	h.mu.Unlock()
+
+	return tr, nil
+}
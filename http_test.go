@@ -0,0 +1,127 @@
+package gotext
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestNewLocaleHTTP(t *testing.T) {
+	po, err := ioutil.ReadFile("fixtures/en_US/default.po")
+	if err != nil {
+		t.Fatal(err)
+	}
+	mo, err := ioutil.ReadFile("fixtures/de/default.mo")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var downloads int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var data []byte
+		switch r.URL.Path {
+		case "/i18n/en_US/default.po":
+			data = po
+		case "/i18n/en/LC_MESSAGES/extra.mo":
+			data = mo
+		default:
+			http.NotFound(w, r)
+			return
+		}
+
+		w.Header().Set("ETag", `"v1"`)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		atomic.AddInt32(&downloads, 1)
+		w.Write(data)
+	}))
+	defer srv.Close()
+
+	var errs []string
+	l := NewLocaleHTTP(srv.URL+"/i18n/", "en_US", HTTPOptions{
+		OnError: func(dom string, err error) {
+			errs = append(errs, dom)
+		},
+	})
+	defer l.StopRefresh()
+
+	l.AddDomain("default")
+	l.AddDomain("extra")
+	l.AddDomain("missing")
+
+	if tr := l.Get("My text"); tr != translatedText {
+		t.Errorf("Expected '%s' but got '%s'", translatedText, tr)
+	}
+	if tr := l.GetD("extra", "More"); tr != "More translation" {
+		t.Errorf("Expected 'More translation' but got '%s'", tr)
+	}
+	if len(errs) != 1 || errs[0] != "missing" {
+		t.Errorf("Expected an error for the missing domain but got %v", errs)
+	}
+	if n := atomic.LoadInt32(&downloads); n != 2 {
+		t.Errorf("Expected 2 downloads but got %d", n)
+	}
+
+	// Unchanged catalogs aren't downloaded again
+	if err := l.RefreshRemote(); err != nil {
+		t.Fatal(err)
+	}
+	if n := atomic.LoadInt32(&downloads); n != 2 {
+		t.Errorf("Expected 2 downloads after refresh but got %d", n)
+	}
+	if tr := l.Get("My text"); tr != translatedText {
+		t.Errorf("Expected '%s' after refresh but got '%s'", translatedText, tr)
+	}
+}
+
+func TestNewLocaleHTTPJSONAndMissing(t *testing.T) {
+	po := NewPo()
+	po.Parse([]byte(compressPo))
+	var catalog bytes.Buffer
+	if err := po.GetDomain().ExportJSON(&catalog); err != nil {
+		t.Fatal(err)
+	}
+
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if r.URL.Path == "/de/default.json" {
+			w.Write(catalog.Bytes())
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer srv.Close()
+
+	l := NewLocaleHTTP(srv.URL, "de", HTTPOptions{})
+	l.AddDomain("default")
+	if got := l.Get("Save"); got != "Speichern" {
+		t.Errorf("unexpected translation %q", got)
+	}
+
+	// Missing domains are looked up once until MissingTTL expires
+	atomic.StoreInt32(&requests, 0)
+	l.AddDomain("missing")
+	probes := atomic.LoadInt32(&requests)
+	if probes == 0 {
+		t.Fatal("expected the missing domain to be looked up")
+	}
+	l.AddDomain("missing")
+	if n := atomic.LoadInt32(&requests); n != probes {
+		t.Errorf("expected no requests for a missing domain, got %d", n-probes)
+	}
+
+	l.remote.mu.Lock()
+	l.remote.missing["missing"] = time.Now().Add(-time.Second)
+	l.remote.mu.Unlock()
+	l.AddDomain("missing")
+	if n := atomic.LoadInt32(&requests); n != 2*probes {
+		t.Errorf("expected the missing domain to be looked up again after MissingTTL, got %d requests", n-probes)
+	}
+}
@@ -0,0 +1,103 @@
+package gotext
+
+import (
+	"sync"
+)
+
+/*
+InternPool holds a single copy of every string added to it, so catalogs of the same domain in many languages
+can share their msgid strings instead of keeping a copy each.
+
+	pool := gotext.NewInternPool()
+	for _, lang := range langs {
+		l := gotext.NewLocale("/path/to/i18n/dir", lang)
+		l.SetInternPool(pool, false)
+		l.AddDomain("default")
+	}
+*/
+type InternPool struct {
+	mu   sync.Mutex
+	strs map[string]string
+}
+
+// NewInternPool creates an empty InternPool.
+func NewInternPool() *InternPool {
+	return &InternPool{strs: make(map[string]string)}
+}
+
+// Intern returns the copy of s held by the pool, adding s when there is none.
+func (p *InternPool) Intern(s string) string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.strs == nil {
+		p.strs = make(map[string]string)
+	}
+	if str, ok := p.strs[s]; ok {
+		return str
+	}
+	p.strs[s] = s
+	return s
+}
+
+// Len returns the number of strings in the pool.
+func (p *InternPool) Len() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return len(p.strs)
+}
+
+// Intern replaces the msgid, plural msgid and context strings of the Domain with their copies in the pool p,
+// so other catalogs interned in the same pool share them. When values is true, translations are interned too,
+// which pays off only when catalogs share many of them, like untranslated messages or regional variants.
+func (do *Domain) Intern(p *InternPool, values bool) {
+	do.trMutex.Lock()
+	defer do.trMutex.Unlock()
+
+	intern := func(trs map[string]*Translation) map[string]*Translation {
+		interned := make(map[string]*Translation, len(trs))
+		for id, tr := range trs {
+			tr.ID = p.Intern(tr.ID)
+			if tr.PluralID != "" {
+				tr.PluralID = p.Intern(tr.PluralID)
+			}
+			if values {
+				for i, str := range tr.Trs {
+					tr.Trs[i] = p.Intern(str)
+				}
+			}
+			interned[p.Intern(id)] = tr
+		}
+		return interned
+	}
+
+	do.translations = intern(do.translations)
+	contexts := make(map[string]map[string]*Translation, len(do.contexts))
+	for ctx, trs := range do.contexts {
+		contexts[p.Intern(ctx)] = intern(trs)
+	}
+	do.contexts = contexts
+}
+
+// SetInternPool makes the catalogs loaded afterwards by AddDomain intern their strings in the pool p,
+// as done by Domain.Intern. A nil pool stops interning.
+func (l *Locale) SetInternPool(p *InternPool, values bool) {
+	l.Lock()
+	l.internPool = p
+	l.internValues = values
+	l.Unlock()
+}
+
+// SetInterning makes the catalogs loaded afterwards by every Locale of the pool, including the ones added later,
+// intern their strings in a pool shared by all of them. See Locale.SetInternPool.
+func (ls *Locales) SetInterning(values bool) {
+	ls.mu.Lock()
+	ls.internPool = NewInternPool()
+	ls.internValues = values
+	ls.mu.Unlock()
+
+	for _, l := range ls.all() {
+		l.SetInternPool(ls.internPool, values)
+	}
+}
@@ -0,0 +1,60 @@
+package gotext
+
+import (
+	"testing"
+	"unsafe"
+)
+
+// stringData returns the address of the bytes of s, to tell whether two strings share them.
+func stringData(s string) uintptr {
+	return *(*uintptr)(unsafe.Pointer(&s))
+}
+
+func TestInternPool(t *testing.T) {
+	p := NewInternPool()
+	a := p.Intern(string([]byte("Hello")))
+	b := p.Intern(string([]byte("Hello")))
+	if stringData(a) != stringData(b) {
+		t.Error("Expected the same copy of the string")
+	}
+	if p.Len() != 1 {
+		t.Errorf("Expected 1 string in the pool, got %d", p.Len())
+	}
+}
+
+func TestLocalesSetInterning(t *testing.T) {
+	src := &MemorySource{Files: map[string][]byte{
+		"de/default.po": []byte("msgid \"Hello\"\nmsgstr \"Hallo\"\n\nmsgctxt \"menu\"\nmsgid \"Open\"\nmsgstr \"Öffnen\"\n"),
+		"at/default.po": []byte("msgid \"Hello\"\nmsgstr \"Hallo\"\n\nmsgctxt \"menu\"\nmsgid \"Open\"\nmsgstr \"Öffnen\"\n"),
+	}}
+
+	ls := NewLocales("")
+	ls.SetInterning(true)
+	ls.Add(NewLocaleWithSource(src, "de"))
+	ls.Add(NewLocaleWithSource(src, "at"))
+	ls.AddDomain("default")
+
+	de := ls.Get("de").Domains["default"].GetDomain()
+	at := ls.Get("at").Domains["default"].GetDomain()
+	if stringData(de.translations["Hello"].ID) != stringData(at.translations["Hello"].ID) {
+		t.Error("Expected msgids to be shared")
+	}
+	if stringData(de.translations["Hello"].Trs[0]) != stringData(at.translations["Hello"].Trs[0]) {
+		t.Error("Expected translations to be shared")
+	}
+	for ctx := range de.contexts {
+		for ctx2 := range at.contexts {
+			if stringData(ctx) != stringData(ctx2) {
+				t.Error("Expected contexts to be shared")
+			}
+		}
+	}
+
+	hello, open := "Hello", "Open"
+	if tr := ls.Get("at").Get(hello); tr != "Hallo" {
+		t.Errorf("Unexpected translation %q", tr)
+	}
+	if tr := ls.Get("de").GetC(open, "menu"); tr != "Öffnen" {
+		t.Errorf("Unexpected translation %q", tr)
+	}
+}
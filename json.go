@@ -0,0 +1,118 @@
+package gotext
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+	"strings"
+)
+
+// jsonCatalog is the layout of ExportJSON.
+type jsonCatalog struct {
+	Headers  map[string]string `json:"headers,omitempty"`
+	Messages []jsonMessage     `json:"messages"`
+}
+
+type jsonMessage struct {
+	Context    string   `json:"msgctxt,omitempty"`
+	ID         string   `json:"msgid"`
+	Plural     string   `json:"msgid_plural,omitempty"`
+	Strs       []string `json:"msgstr"`
+	References []string `json:"references,omitempty"`
+}
+
+// ExportJSON writes the headers and translations of the Domain as JSON, for tools and web clients
+// that don't read gettext files. It can be loaded back with ImportJSON.
+//
+//	{
+//	  "headers": {"Language": "es", "Plural-Forms": "nplurals=2; plural=(n != 1);"},
+//	  "messages": [
+//	    {"msgctxt": "menu", "msgid": "Open", "msgstr": ["Abrir"]},
+//	    {"msgid": "%d file", "msgid_plural": "%d files", "msgstr": ["%d archivo", "%d archivos"], "references": ["main.go:10"]}
+//	  ]
+//	}
+//
+// Messages are sorted by context and msgid, and plural ones have a msgstr for every plural form.
+func (do *Domain) ExportJSON(w io.Writer) error {
+	cat := jsonCatalog{Headers: make(map[string]string)}
+
+	do.trMutex.RLock()
+	for k := range do.Headers {
+		cat.Headers[k] = do.Headers.Get(k)
+	}
+	nplurals := do.nplurals
+	do.trMutex.RUnlock()
+
+	entries := do.entries()
+	cat.Messages = make([]jsonMessage, len(entries))
+	for i, e := range entries {
+		cat.Messages[i] = jsonMessage{
+			Context:    e.Context,
+			ID:         e.MsgID,
+			Plural:     e.Translation.PluralID,
+			Strs:       msgstrs(e.Translation, nplurals),
+			References: e.Translation.Refs,
+		}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	return enc.Encode(cat)
+}
+
+// ImportJSON loads headers and translations from JSON data in the ExportJSON layout,
+// replacing the ones with the same context and msgid.
+// Nothing is loaded when the data can't be read or has an invalid Plural-Forms header.
+func (do *Domain) ImportJSON(r io.Reader) error {
+	var cat jsonCatalog
+	if err := json.NewDecoder(r).Decode(&cat); err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(cat.Headers))
+	for k, value := range cat.Headers {
+		if strings.EqualFold(k, "Plural-Forms") {
+			if _, _, _, err := parsePluralForms(value); err != nil {
+				return err
+			}
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		if err := do.SetHeader(k, cat.Headers[k]); err != nil {
+			return err
+		}
+	}
+
+	do.trMutex.Lock()
+	do.pluralMutex.Lock()
+	defer do.trMutex.Unlock()
+	defer do.pluralMutex.Unlock()
+
+	for _, m := range cat.Messages {
+		if m.ID == "" {
+			continue
+		}
+
+		tr := NewTranslationWithRefs(m.References)
+		tr.ID = m.ID
+		tr.PluralID = m.Plural
+		for i, str := range m.Strs {
+			tr.Trs[i] = str
+		}
+
+		if m.Context == "" {
+			do.translations[m.ID] = tr
+			continue
+		}
+		if _, ok := do.contexts[m.Context]; !ok {
+			do.contexts[m.Context] = make(map[string]*Translation)
+		}
+		do.contexts[m.Context][m.ID] = tr
+	}
+
+	return nil
+}
@@ -0,0 +1,48 @@
+package gotext
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestDomain_ExportJSON(t *testing.T) {
+	po := NewPo()
+	po.Parse([]byte(convertPo))
+
+	var buf bytes.Buffer
+	if err := po.GetDomain().ExportJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{
+		`"Language": "es"`,
+		`"msgctxt": "menu",
+      "msgid": "Open",
+      "msgstr": [
+        "Abrir"
+      ]`,
+		`"msgid": "Say <hi> & bye"`,
+		`"msgid_plural": "%d files"`,
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("expected output to contain %s, got:\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestDomain_ImportJSONErrors(t *testing.T) {
+	do := NewDomain()
+
+	if err := do.ImportJSON(strings.NewReader(`{"messages": [`)); err == nil {
+		t.Error("expected an error for invalid JSON")
+	}
+
+	err := do.ImportJSON(strings.NewReader(`{"headers": {"Language": "fr", "Plural-Forms": "nplurals=2;"}, "messages": [{"msgid": "a", "msgstr": ["b"]}]}`))
+	if err == nil {
+		t.Error("expected an error for an invalid Plural-Forms header")
+	}
+	if do.GetLanguage() != "" || do.Get("a") != "a" {
+		t.Error("nothing should be loaded on error")
+	}
+}
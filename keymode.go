@@ -0,0 +1,42 @@
+package gotext
+
+/*
+SetKeyMode makes the Locale treat msgids as symbolic keys, like "checkout.pay_button", instead of source strings.
+The domain dom provides the default display strings of the keys, usually from the catalog of the source language:
+
+	l := gotext.NewLocale("locales", "de_DE")
+	l.AddDomain("default")
+	l.AddDomainFile("keys", "locales/en/keys.po")
+	l.SetKeyMode("keys")
+
+	l.Get("checkout.pay_button") // "Jetzt bezahlen", or "Pay now" until it's translated
+
+Keys a domain doesn't translate are looked up in dom, for all the domains and lookup functions.
+Keys dom doesn't translate either are returned as they are. Calling it with an empty dom disables key mode.
+*/
+func (l *Locale) SetKeyMode(dom string) {
+	l.Lock()
+	l.keySource = dom
+	l.Unlock()
+}
+
+// KeySource returns the domain set by SetKeyMode, or an empty string when key mode is disabled.
+func (l *Locale) KeySource() string {
+	l.RLock()
+	defer l.RUnlock()
+
+	return l.keySource
+}
+
+// translator returns the Translator answering the lookups of str in the domain dom, or nil if dom isn't loaded.
+// In key mode, keys dom doesn't translate are looked up in the source domain. The Locale must be locked.
+func (l *Locale) translator(dom, str, ctx string, withCtx bool) Translator {
+	tr := l.Domains[dom]
+	if l.keySource == "" || l.keySource == dom || (tr != nil && translated(tr, str, ctx, withCtx)) {
+		return tr
+	}
+	if src := l.Domains[l.keySource]; src != nil && translated(src, str, ctx, withCtx) {
+		return src
+	}
+	return tr
+}
@@ -0,0 +1,76 @@
+package gotext
+
+import (
+	"testing"
+)
+
+func TestLocaleKeyMode(t *testing.T) {
+	l := NewLocale("fixtures/", "de_DE")
+	if err := l.AddDomainBytes("default", []byte(`msgid "checkout.pay_button"
+msgstr "Jetzt bezahlen"
+
+msgid "checkout.cancel"
+msgstr ""
+`), FormatPO); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.AddDomainBytes("keys", []byte(`msgid ""
+msgstr "Plural-Forms: nplurals=2; plural=(n != 1);\n"
+
+msgid "checkout.pay_button"
+msgstr "Pay now"
+
+msgid "checkout.cancel"
+msgstr "Cancel"
+
+msgctxt "menu"
+msgid "checkout.cancel"
+msgstr "Cancel order"
+
+msgid "cart.items"
+msgid_plural "cart.items"
+msgstr[0] "%d item"
+msgstr[1] "%d items"
+`), FormatPO); err != nil {
+		t.Fatal(err)
+	}
+	pay, cancel, items, unknown := "checkout.pay_button", "checkout.cancel", "cart.items", "checkout.unknown"
+
+	if tr := l.Get(cancel); tr != cancel {
+		t.Errorf("Expected the key without key mode, got '%s'", tr)
+	}
+
+	l.SetKeyMode("keys")
+	if dom := l.KeySource(); dom != "keys" {
+		t.Errorf("Unexpected key source '%s'", dom)
+	}
+	if tr := l.Get(pay); tr != "Jetzt bezahlen" {
+		t.Errorf("Expected the translation, got '%s'", tr)
+	}
+	if tr := l.Get(cancel); tr != "Cancel" {
+		t.Errorf("Expected the source string, got '%s'", tr)
+	}
+	if tr := l.GetC(cancel, "menu"); tr != "Cancel order" {
+		t.Errorf("Expected the source string, got '%s'", tr)
+	}
+	if tr := l.GetN(items, items, 3, 3); tr != "3 items" {
+		t.Errorf("Expected the source plural, got '%s'", tr)
+	}
+	if tr := l.GetD("emails", pay); tr != "Pay now" {
+		t.Errorf("Expected the source string for a domain not loaded, got '%s'", tr)
+	}
+	if tr, ok := l.Lookup(cancel); !ok || tr != "Cancel" {
+		t.Errorf("Expected the source string to be found, got '%s'", tr)
+	}
+	if tr, ok := l.Lookup(unknown); ok || tr != unknown {
+		t.Errorf("Expected the key, got '%s'", tr)
+	}
+	if _, err := l.TryGet(unknown); err == nil {
+		t.Error("Expected an error for an unknown key")
+	}
+
+	l.SetKeyMode("")
+	if tr := l.Get(cancel); tr != cancel {
+		t.Errorf("Expected the key after disabling key mode, got '%s'", tr)
+	}
+}
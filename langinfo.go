@@ -0,0 +1,68 @@
+package gotext
+
+import (
+	"golang.org/x/text/language"
+)
+
+// rtlScripts are the scripts written from right to left.
+var rtlScripts = map[string]bool{
+	"Adlm": true, // Adlam
+	"Arab": true, // Arabic
+	"Hebr": true, // Hebrew
+	"Mand": true, // Mandaic
+	"Mend": true, // Mende Kikakui
+	"Nkoo": true, // N'Ko
+	"Rohg": true, // Hanifi Rohingya
+	"Samr": true, // Samaritan
+	"Syrc": true, // Syriac
+	"Thaa": true, // Thaana
+}
+
+// isRTL tells whether the language of tag is written from right to left, from its script,
+// which is the most likely one for the language when the tag doesn't have it.
+func isRTL(tag language.Tag) bool {
+	script, _ := tag.Script()
+	return rtlScripts[script.String()]
+}
+
+// Tag returns the language tag of the Locale.
+func (l *Locale) Tag() language.Tag {
+	l.RLock()
+	defer l.RUnlock()
+	return l.tag
+}
+
+// GetLanguage returns the language code of the Locale, like "en_US", as used to find its catalogs.
+func (l *Locale) GetLanguage() string {
+	l.RLock()
+	defer l.RUnlock()
+	return l.lang
+}
+
+// Base returns the base language of the Locale, like "en" for en_US.
+func (l *Locale) Base() string {
+	base, _ := l.Tag().Base()
+	return base.String()
+}
+
+// Script returns the script of the Locale language, like "Latn" for en_US.
+// It's the most likely one for the language when the locale doesn't have it, as in "sr" (Cyrillic).
+func (l *Locale) Script() string {
+	script, _ := l.Tag().Script()
+	return script.String()
+}
+
+// Region returns the region of the Locale, like "US" for en_US, or an empty string if it has none.
+func (l *Locale) Region() string {
+	region, c := l.Tag().Region()
+	if c != language.Exact {
+		return ""
+	}
+	return region.String()
+}
+
+// IsRTL tells whether the Locale language is written from right to left, like Arabic or Hebrew,
+// so user interfaces can set their text direction.
+func (l *Locale) IsRTL() bool {
+	return isRTL(l.Tag())
+}
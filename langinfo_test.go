@@ -0,0 +1,50 @@
+package gotext
+
+import (
+	"testing"
+)
+
+func TestLocaleLanguageInfo(t *testing.T) {
+	l := NewLocale("fixtures/", "en_US")
+
+	if lang := l.GetLanguage(); lang != "en_US" {
+		t.Errorf("unexpected language %q", lang)
+	}
+	if tag := l.Tag().String(); tag != "en-US" {
+		t.Errorf("unexpected tag %q", tag)
+	}
+	if base := l.Base(); base != "en" {
+		t.Errorf("unexpected base %q", base)
+	}
+	if script := l.Script(); script != "Latn" {
+		t.Errorf("unexpected script %q", script)
+	}
+	if region := l.Region(); region != "US" {
+		t.Errorf("unexpected region %q", region)
+	}
+	if l.IsRTL() {
+		t.Error("en_US isn't written from right to left")
+	}
+
+	if region := NewLocale("fixtures/", "fr").Region(); region != "" {
+		t.Errorf("expected no region, got %q", region)
+	}
+}
+
+func TestLocaleIsRTL(t *testing.T) {
+	for lang, want := range map[string]bool{
+		"ar":      true,
+		"ar_EG":   true,
+		"he":      true,
+		"fa":      true,
+		"ur":      true,
+		"yi":      true,
+		"de":      false,
+		"ru":      false,
+		"uz-Arab": true,
+	} {
+		if got := NewLocale("fixtures/", lang).IsRTL(); got != want {
+			t.Errorf("IsRTL(%q) = %v, want %v", lang, got, want)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package gotext
+
+import (
+	"path"
+	"strings"
+)
+
+// PathResolver tells where the catalog files of a Locale are, for Locale objects whose files
+// don't follow the gettext directory layout. See Locale.SetPathResolver.
+type PathResolver interface {
+	// Paths returns the paths, relative to the library path (or CatalogSource root), where the file
+	// of the domain dom with the extension ext is looked up for the language lang, in lookup order.
+	Paths(lang, dom, ext string) []string
+}
+
+// PathResolverFunc is a function used as a PathResolver.
+type PathResolverFunc func(lang, dom, ext string) []string
+
+// Paths calls f(lang, dom, ext).
+func (f PathResolverFunc) Paths(lang, dom, ext string) []string {
+	return f(lang, dom, ext)
+}
+
+// GettextLayout is the default PathResolver. It looks up files in the gettext directory layout, with or without
+// the LC_MESSAGES folder, and with the full language code before the base language:
+//
+//	en_US/LC_MESSAGES/default.po
+//	en/LC_MESSAGES/default.po
+//	en_US/default.po
+//	en/default.po
+var GettextLayout = Layout(
+	"{lang}/"+LCMessages+"/{dom}.{ext}",
+	"{base}/"+LCMessages+"/{dom}.{ext}",
+	"{lang}/{dom}.{ext}",
+	"{base}/{dom}.{ext}",
+)
+
+/*
+Layout returns a PathResolver for the given path templates, tried in order. Templates can use these placeholders:
+
+	{lang}  the Locale language, like "en_US"
+	{base}  the base language, like "en"
+	{dom}   the domain name
+	{ext}   the file extension, like "po"
+
+So a repository with one folder per domain and flat files per language is read with
+
+	l.SetPathResolver(gotext.Layout("locales/{dom}/{lang}.{ext}", "locales/{dom}/{base}.{ext}"))
+
+Any folder can take the place of LC_MESSAGES, like "{lang}/LC_TIME/{dom}.{ext}".
+Paths repeated after replacing the placeholders, like {lang} and {base} for "en", are only tried once.
+*/
+func Layout(templates ...string) PathResolver {
+	return PathResolverFunc(func(lang, dom, ext string) []string {
+		base := lang
+		if i := strings.IndexAny(lang, "_-"); i != -1 {
+			base = lang[:i]
+		}
+		r := strings.NewReplacer("{lang}", lang, "{base}", base, "{dom}", dom, "{ext}", ext)
+
+		paths := make([]string, 0, len(templates))
+		seen := make(map[string]bool, len(templates))
+		for _, t := range templates {
+			p := path.Clean(r.Replace(t))
+			if !seen[p] {
+				seen[p] = true
+				paths = append(paths, p)
+			}
+		}
+		return paths
+	})
+}
+
+// SetPathResolver changes where the Locale looks up the catalog files of its domains on the next AddDomain calls.
+// A nil resolver restores the default GettextLayout.
+func (l *Locale) SetPathResolver(r PathResolver) {
+	l.Lock()
+	l.resolver = r
+	l.Unlock()
+}
+
+// categoryPath returns the path p with its LC_MESSAGES folder replaced by the folder of category.
+// Paths without a LC_MESSAGES folder aren't looked up for other categories, like in GNU gettext.
+func categoryPath(p, category string) (string, bool) {
+	parts := strings.Split(p, "/")
+	found := false
+	for i, part := range parts {
+		if part == LCMessages {
+			parts[i] = category
+			found = true
+		}
+	}
+	return strings.Join(parts, "/"), found
+}
+
+/*
+AddDomainWithCategory works like AddDomain, but looks the catalog of dom up in the folder of the locale category,
+like LCTime, instead of LC_MESSAGES, following the GNU gettext directory layout:
+
+	/path/to/locales/de_DE/LC_TIME/dates.mo
+
+With a PathResolver, its LC_MESSAGES folders are replaced by the category one, and the paths without it skipped.
+The category is remembered, so later AddDomain calls reload the catalog from the same folder.
+Passing LCMessages restores the default lookup.
+*/
+func (l *Locale) AddDomainWithCategory(dom, category string) {
+	l.Lock()
+	if category == LCMessages {
+		delete(l.categories, dom)
+	} else {
+		if l.categories == nil {
+			l.categories = make(map[string]string)
+		}
+		l.categories[dom] = category
+	}
+	l.Unlock()
+
+	l.AddDomain(dom)
+}
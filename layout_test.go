@@ -0,0 +1,101 @@
+package gotext
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGettextLayout(t *testing.T) {
+	want := []string{"en_US/LC_MESSAGES/default.po", "en/LC_MESSAGES/default.po", "en_US/default.po", "en/default.po"}
+	if got := GettextLayout.Paths("en_US", "default", "po"); !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected paths %v", got)
+	}
+
+	// Paths aren't repeated when the language has no region
+	want = []string{"fr/LC_MESSAGES/default.mo", "fr/default.mo"}
+	if got := GettextLayout.Paths("fr", "default", "mo"); !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected paths %v", got)
+	}
+}
+
+func TestLocaleSetPathResolver(t *testing.T) {
+	src := &MemorySource{Files: map[string][]byte{
+		"locales/checkout/de.po":  []byte("msgid \"Pay\"\nmsgstr \"Zahlen\"\n"),
+		"de_AT/LC_TIME/dates.po":  []byte("msgid \"Today\"\nmsgstr \"Heute\"\n"),
+		"de_AT/LC_MESSAGES/x.po":  []byte("msgid \"Today\"\nmsgstr \"Wrong\"\n"),
+		"flat/de_AT.po":           []byte("msgid \"Hi\"\nmsgstr \"Servus\"\n"),
+		"de/LC_MESSAGES/other.po": []byte("msgid \"Hi\"\nmsgstr \"Hallo\"\n"),
+	}}
+
+	l := NewLocaleWithSource(src, "de_AT")
+	l.SetPathResolver(Layout("locales/{dom}/{lang}.{ext}", "locales/{dom}/{base}.{ext}"))
+	l.AddDomain("checkout")
+	if got := l.GetD("checkout", "Pay"); got != "Zahlen" {
+		t.Errorf("unexpected translation %q", got)
+	}
+
+	l.SetPathResolver(Layout("{lang}/LC_TIME/{dom}.{ext}"))
+	l.AddDomain("dates")
+	if got := l.GetD("dates", "Today"); got != "Heute" {
+		t.Errorf("unexpected translation %q", got)
+	}
+
+	l.SetPathResolver(PathResolverFunc(func(lang, dom, ext string) []string {
+		return []string{"flat/" + lang + "." + ext}
+	}))
+	l.AddDomain("flat")
+	if got := l.GetD("flat", "Hi"); got != "Servus" {
+		t.Errorf("unexpected translation %q", got)
+	}
+
+	// The default layout is restored
+	l.SetPathResolver(nil)
+	l.AddDomain("other")
+	if got := l.GetD("other", "Hi"); got != "Hallo" {
+		t.Errorf("unexpected translation %q", got)
+	}
+}
+
+func TestLocaleAddDomainWithCategory(t *testing.T) {
+	src := &MemorySource{Files: map[string][]byte{
+		"de_AT/LC_TIME/dates.po":     []byte("msgid \"Today\"\nmsgstr \"Heute\"\n"),
+		"de/LC_MONETARY/money.po":    []byte("msgid \"Price\"\nmsgstr \"Preis\"\n"),
+		"de_AT/LC_MESSAGES/dates.po": []byte("msgid \"Today\"\nmsgstr \"Messages\"\n"),
+		"de_AT/money.po":             []byte("msgid \"Price\"\nmsgstr \"Flat\"\n"),
+	}}
+
+	l := NewLocaleWithSource(src, "de_AT")
+	l.AddDomainWithCategory("dates", LCTime)
+	if got := l.GetD("dates", "Today"); got != "Heute" {
+		t.Errorf("unexpected translation %q", got)
+	}
+
+	// The base language is looked up too, but not the files without category folder
+	l.AddDomainWithCategory("money", LCMonetary)
+	if got := l.GetD("money", "Price"); got != "Preis" {
+		t.Errorf("unexpected translation %q", got)
+	}
+
+	// The category is kept for reloads, until LC_MESSAGES is set back
+	l.AddDomain("dates")
+	if got := l.GetD("dates", "Today"); got != "Heute" {
+		t.Errorf("unexpected translation after reload %q", got)
+	}
+	l.AddDomainWithCategory("dates", LCMessages)
+	if got := l.GetD("dates", "Today"); got != "Messages" {
+		t.Errorf("unexpected translation %q", got)
+	}
+
+	// Custom layouts get their LC_MESSAGES folder replaced
+	l.SetPathResolver(Layout("{base}/"+LCMessages+"/{dom}.{ext}", "{lang}/{dom}.{ext}"))
+	l.AddDomainWithCategory("money", LCMonetary)
+	if got := l.GetD("money", "Price"); got != "Preis" {
+		t.Errorf("unexpected translation %q", got)
+	}
+	l.SetPathResolver(Layout("{lang}/{dom}.{ext}"))
+	delete(l.Domains, "money")
+	l.AddDomainWithCategory("money", LCMonetary)
+	if _, ok := l.Domains["money"]; ok {
+		t.Error("paths without LC_MESSAGES folder shouldn't be looked up for other categories")
+	}
+}
@@ -0,0 +1,75 @@
+package gotext
+
+import (
+	"sync"
+)
+
+// lazyTranslator is a catalog that is loaded on its first use.
+type lazyTranslator struct {
+	once sync.Once
+	load func() Translator
+	tr   Translator
+}
+
+// translator loads the catalog the first time it's called, only once even when called concurrently.
+// Catalogs that can't be found or parsed are empty.
+func (lt *lazyTranslator) translator() Translator {
+	lt.once.Do(func() {
+		lt.tr = lt.load()
+		if lt.tr == nil {
+			lt.tr = NewPo()
+		}
+		lt.load = nil
+	})
+	return lt.tr
+}
+
+func (lt *lazyTranslator) ParseFile(f string) {
+	lt.translator().ParseFile(f)
+}
+
+func (lt *lazyTranslator) Parse(buf []byte) {
+	lt.translator().Parse(buf)
+}
+
+func (lt *lazyTranslator) Get(str string, vars ...interface{}) string {
+	return lt.translator().Get(str, vars...)
+}
+
+func (lt *lazyTranslator) GetN(str, plural string, n int, vars ...interface{}) string {
+	return lt.translator().GetN(str, plural, n, vars...)
+}
+
+func (lt *lazyTranslator) GetC(str, ctx string, vars ...interface{}) string {
+	return lt.translator().GetC(str, ctx, vars...)
+}
+
+func (lt *lazyTranslator) GetNC(str, plural string, n int, ctx string, vars ...interface{}) string {
+	return lt.translator().GetNC(str, plural, n, ctx, vars...)
+}
+
+func (lt *lazyTranslator) MarshalBinary() ([]byte, error) {
+	return lt.translator().MarshalBinary()
+}
+
+func (lt *lazyTranslator) UnmarshalBinary(data []byte) error {
+	return lt.translator().UnmarshalBinary(data)
+}
+
+func (lt *lazyTranslator) GetDomain() *Domain {
+	return lt.translator().GetDomain()
+}
+
+/*
+SetLazyLoading makes the next AddDomain calls only record the domains, deferring finding and parsing
+their catalogs until the first lookup in each of them. It cuts the startup time of applications that
+register many domains but only use a few of them. Concurrent first lookups load the catalog only once.
+
+With lazy loading, AddDomain can't tell whether a catalog exists: domains without a catalog
+are registered anyway, and return the untranslated strings.
+*/
+func (l *Locale) SetLazyLoading(lazy bool) {
+	l.Lock()
+	l.lazy = lazy
+	l.Unlock()
+}
@@ -0,0 +1,66 @@
+package gotext
+
+import (
+	"io"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// countingSource counts the catalogs opened from a MemorySource.
+type countingSource struct {
+	*MemorySource
+	opened int32
+}
+
+func (s *countingSource) Open(name string) (io.ReadCloser, error) {
+	rc, err := s.MemorySource.Open(name)
+	if err == nil {
+		atomic.AddInt32(&s.opened, 1)
+	}
+	return rc, err
+}
+
+func TestLocaleLazyLoading(t *testing.T) {
+	src := &countingSource{MemorySource: &MemorySource{Files: map[string][]byte{
+		"de/LC_MESSAGES/default.po": []byte("msgid \"Hello\"\nmsgstr \"Hallo\"\n"),
+		"de/LC_MESSAGES/extras.po":  []byte("msgid \"Bye\"\nmsgstr \"Tschüss\"\n"),
+	}}}
+
+	l := NewLocaleWithSource(src, "de")
+	l.SetLazyLoading(true)
+	l.AddDomain("default")
+	l.AddDomain("extras")
+	l.AddDomain("missing")
+
+	if n := atomic.LoadInt32(&src.opened); n != 0 {
+		t.Fatalf("expected no catalog to be opened, got %d", n)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if got := l.Get("Hello"); got != "Hallo" {
+				t.Errorf("unexpected translation %q", got)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := atomic.LoadInt32(&src.opened); n != 1 {
+		t.Errorf("expected the catalog to be opened once, got %d", n)
+	}
+
+	bye := "Bye"
+	if got := l.GetD("extras", bye); got != "Tschüss" {
+		t.Errorf("unexpected translation %q", got)
+	}
+	if got := l.GetD("missing", bye); got != "Bye" {
+		t.Errorf("unexpected translation %q", got)
+	}
+	if n := atomic.LoadInt32(&src.opened); n != 2 {
+		t.Errorf("expected two catalogs to be opened, got %d", n)
+	}
+}
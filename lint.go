@@ -0,0 +1,233 @@
+package gotext
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// verbPattern matches a fmt verb (with flags, width, precision and argument indexes) or a named %(name)s placeholder.
+const verbPattern = `%(?:\([a-zA-Z0-9_]+\))?(?:\[\d+\])?[-+# 0]*(?:\d+|\*)?(?:\.(?:\d+|\*)?)?(?:\[\d+\])?[bcdeEfFgGoOpqstTUvxX]`
+
+// verbRe matches fmt verbs, named %(name)s placeholders and escaped percent signs.
+var verbRe = regexp.MustCompile(`%%|` + verbPattern)
+
+// LocaleIssueKind tells what kind of inconsistency a LocaleIssue reports.
+type LocaleIssueKind int
+
+const (
+	// RegionalMismatch is a message translated differently by regional variants of the same language (fr vs fr_CA).
+	// It's often accidental, but can be intended.
+	RegionalMismatch LocaleIssueKind = iota
+	// PlaceholderMismatch is a message whose translations don't use the same placeholders in every language.
+	PlaceholderMismatch
+)
+
+// LocaleIssue is an inconsistency between the translations of a message across several locales.
+type LocaleIssue struct {
+	Kind    LocaleIssueKind
+	Domain  string
+	Context string
+	MsgID   string
+
+	// Translations involved, by locale language
+	Translations map[string]string
+}
+
+func (i LocaleIssue) String() string {
+	langs := make([]string, 0, len(i.Translations))
+	for lang := range i.Translations {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+
+	var b strings.Builder
+	switch i.Kind {
+	case RegionalMismatch:
+		b.WriteString("regional variants translate differently")
+	case PlaceholderMismatch:
+		b.WriteString("placeholders differ across languages")
+	}
+	fmt.Fprintf(&b, " [%s] ", i.Domain)
+	if i.Context != "" {
+		fmt.Fprintf(&b, "msgctxt %q ", i.Context)
+	}
+	fmt.Fprintf(&b, "msgid %q", i.MsgID)
+	for _, lang := range langs {
+		fmt.Fprintf(&b, "\n\t%s: %q", lang, i.Translations[lang])
+	}
+	return b.String()
+}
+
+// LocaleReport is the consolidated result of LintLocales.
+type LocaleReport []LocaleIssue
+
+// String formats the report as text, one issue per paragraph.
+func (r LocaleReport) String() string {
+	issues := make([]string, len(r))
+	for i, issue := range r {
+		issues[i] = issue.String()
+	}
+	return strings.Join(issues, "\n\n")
+}
+
+// lintKey identifies a message across locales.
+type lintKey struct {
+	domain  string
+	context string
+	msgID   string
+}
+
+// lintEntry is the translation of a message in one language.
+type lintEntry struct {
+	// Translation, with all plural forms joined
+	str string
+
+	// Arguments used by any of the forms, with their verbs, like "1:d 2:s"
+	placeholders string
+}
+
+/*
+LintLocales checks the translations of the same messages across several locales and reports:
+
+  - Messages translated differently by regional variants of the same language (fr vs fr_CA),
+    which often happens by accident when only one of them gets updated.
+  - Messages whose placeholders (fmt verbs) differ between languages, compared by the argument they use,
+    regardless of their order. The placeholders of all plural forms are put together, so languages with
+    a different number of forms, or forms omitting the count ("one file"), can be compared.
+
+Messages are matched by domain, context and msgid. Untranslated messages are ignored.
+Issues are sorted by domain, context, msgid and kind.
+*/
+func LintLocales(locales ...*Locale) LocaleReport {
+	all := make(map[lintKey]map[string]lintEntry)
+
+	for _, l := range locales {
+		l.RLock()
+		for name, tr := range l.Domains {
+			if tr == nil {
+				continue
+			}
+			do := tr.GetDomain()
+			do.trMutex.RLock()
+			for id, trans := range do.translations {
+				collectLint(all, lintKey{name, "", id}, l.lang, trans)
+			}
+			for ctx, translations := range do.contexts {
+				for id, trans := range translations {
+					collectLint(all, lintKey{name, ctx, id}, l.lang, trans)
+				}
+			}
+			do.trMutex.RUnlock()
+		}
+		l.RUnlock()
+	}
+
+	var report LocaleReport
+	for key, entries := range all {
+		if len(entries) < 2 {
+			continue
+		}
+
+		translations := make(map[string]string, len(entries))
+		for lang, e := range entries {
+			translations[lang] = e.str
+		}
+
+		// Group by base language
+		bases := make(map[string]map[string]string)
+		for lang, str := range translations {
+			base := baseLanguage(lang)
+			if bases[base] == nil {
+				bases[base] = make(map[string]string)
+			}
+			bases[base][lang] = str
+		}
+		for _, variants := range bases {
+			if len(variants) > 1 && !allEqual(variants) {
+				report = append(report, LocaleIssue{RegionalMismatch, key.domain, key.context, key.msgID, variants})
+			}
+		}
+
+		// Compare placeholders regardless of order
+		placeholders := make(map[string]bool)
+		for _, e := range entries {
+			placeholders[e.placeholders] = true
+		}
+		if len(placeholders) > 1 {
+			report = append(report, LocaleIssue{PlaceholderMismatch, key.domain, key.context, key.msgID, translations})
+		}
+	}
+
+	sort.Slice(report, func(i, j int) bool {
+		a, b := report[i], report[j]
+		if a.Domain != b.Domain {
+			return a.Domain < b.Domain
+		}
+		if a.Context != b.Context {
+			return a.Context < b.Context
+		}
+		if a.MsgID != b.MsgID {
+			return a.MsgID < b.MsgID
+		}
+		return a.Kind < b.Kind
+	})
+
+	return report
+}
+
+// collectLint saves the translation of a message for the given language, if it's translated.
+// All plural forms are joined, so any difference among them counts between regional variants,
+// and their placeholders are put together.
+func collectLint(all map[lintKey]map[string]lintEntry, key lintKey, lang string, trans *Translation) {
+	if key.msgID == "" || !trans.IsTranslated() {
+		return
+	}
+
+	forms := []string{trans.Get()}
+	if trans.PluralID != "" {
+		forms = forms[:0]
+		for i := 0; i < len(trans.Trs); i++ {
+			forms = append(forms, trans.GetN(i))
+		}
+	}
+
+	args := make(map[string]byte)
+	for _, form := range forms {
+		for arg, verb := range formatArgs(form) {
+			args[arg] = verb
+		}
+	}
+	placeholders := make([]string, 0, len(args))
+	for arg, verb := range args {
+		placeholders = append(placeholders, arg+":"+string(verb))
+	}
+	sort.Strings(placeholders)
+
+	if all[key] == nil {
+		all[key] = make(map[string]lintEntry)
+	}
+	all[key][lang] = lintEntry{strings.Join(forms, " | "), strings.Join(placeholders, " ")}
+}
+
+// baseLanguage returns the language part of a locale code (fr for fr_CA).
+func baseLanguage(lang string) string {
+	if idx := strings.IndexAny(lang, "_-"); idx != -1 {
+		return lang[:idx]
+	}
+	return lang
+}
+
+func allEqual(m map[string]string) bool {
+	first := true
+	var value string
+	for _, v := range m {
+		if first {
+			value, first = v, false
+		} else if v != value {
+			return false
+		}
+	}
+	return true
+}
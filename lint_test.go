@@ -0,0 +1,115 @@
+package gotext
+
+import (
+	"strings"
+	"testing"
+)
+
+func newLintLocale(lang, po string) *Locale {
+	l := NewLocale("", lang)
+	tr := NewPo()
+	tr.Parse([]byte(po))
+	l.AddTranslator("default", tr)
+	return l
+}
+
+func TestLintLocales(t *testing.T) {
+	fr := newLintLocale("fr", `
+msgid "Cancel"
+msgstr "Annuler"
+
+msgid "Hello %s"
+msgstr "Bonjour %s"
+
+msgid "%d files in %s"
+msgstr "%d fichiers dans %s"
+`)
+	frCA := newLintLocale("fr_CA", `
+msgid "Cancel"
+msgstr "Canceler"
+
+msgid "Hello %s"
+msgstr "Bonjour %s"
+
+msgid "%d files in %s"
+msgstr "%d fichiers dans %s"
+`)
+	de := newLintLocale("de", `
+msgid "Cancel"
+msgstr "Abbrechen"
+
+msgid "Hello %s"
+msgstr "Hallo %d"
+
+msgid "%d files in %s"
+msgstr "%[2]s enthält %[1]d Dateien"
+
+msgid "Untranslated"
+msgstr ""
+`)
+
+	report := LintLocales(fr, frCA, de)
+	if len(report) != 2 {
+		t.Fatalf("Expected 2 issues but got %d:\n%s", len(report), report)
+	}
+
+	if report[0].Kind != RegionalMismatch || report[0].MsgID != "Cancel" || len(report[0].Translations) != 2 {
+		t.Errorf("Unexpected first issue: %s", report[0])
+	}
+	if report[1].Kind != PlaceholderMismatch || report[1].MsgID != "Hello %s" || len(report[1].Translations) != 3 {
+		t.Errorf("Unexpected second issue: %s", report[1])
+	}
+	if !strings.Contains(report.String(), `fr_CA: "Canceler"`) {
+		t.Errorf("Expected the report to list the translations:\n%s", report)
+	}
+}
+
+func TestLintLocalesPlurals(t *testing.T) {
+	en := newLintLocale("en", `
+msgid ""
+msgstr ""
+"Plural-Forms: nplurals=2; plural=(n != 1);\n"
+
+msgid "%d file"
+msgid_plural "%d files"
+msgstr[0] "%d file"
+msgstr[1] "%d files"
+
+msgid "%d item in %s"
+msgid_plural "%d items in %s"
+msgstr[0] "one item in %[2]s"
+msgstr[1] "%d items in %s"
+`)
+	ja := newLintLocale("ja", `
+msgid ""
+msgstr ""
+"Plural-Forms: nplurals=1; plural=0;\n"
+
+msgid "%d file"
+msgid_plural "%d files"
+msgstr[0] "%d ファイル"
+
+msgid "%d item in %s"
+msgid_plural "%d items in %s"
+msgstr[0] "%d 件 (%d)"
+`)
+	ru := newLintLocale("ru", `
+msgid ""
+msgstr ""
+"Plural-Forms: nplurals=3; plural=(n%10==1 && n%100!=11 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2);\n"
+
+msgid "%d file"
+msgid_plural "%d files"
+msgstr[0] "%d файл"
+msgstr[1] "%d файла"
+msgstr[2] "%d файлов"
+`)
+
+	report := LintLocales(en, ja, ru)
+	if len(report) != 1 {
+		t.Fatalf("Expected 1 issue but got %d:\n%s", len(report), report)
+	}
+	if report[0].Kind != PlaceholderMismatch || report[0].MsgID != "%d item in %s" || len(report[0].Translations) != 2 {
+		t.Errorf("Unexpected issue: %s", report[0])
+	}
+}
@@ -0,0 +1,235 @@
+package gotext
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// LintSeverity tells whether a CatalogIssue breaks the catalog or only deserves a look.
+type LintSeverity int
+
+const (
+	// LintError is a problem producing wrong output, like a broken placeholder or a missing plural form.
+	LintError LintSeverity = iota
+	// LintWarning is a suspicious catalog, like a Plural-Forms header disagreeing with CLDR.
+	LintWarning
+)
+
+func (s LintSeverity) String() string {
+	if s == LintWarning {
+		return "warning"
+	}
+	return "error"
+}
+
+// MarshalText implements encoding.TextMarshaler, so severities are encoded as "error" or "warning".
+func (s LintSeverity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// Rules of the issues reported by LintCatalog.
+const (
+	LintRuleSyntax      = "syntax"
+	LintRuleDuplicate   = "duplicate"
+	LintRuleHeader      = "header"
+	LintRuleEncoding    = "encoding"
+	LintRulePlaceholder = "placeholder"
+	LintRulePlural      = "plural"
+)
+
+// CatalogIssue is a problem found in a catalog file by LintCatalog.
+type CatalogIssue struct {
+	File string `json:"file"`
+	// Line of the entry, or 0 when the issue is about the whole catalog
+	Line int `json:"line,omitempty"`
+
+	Rule     string       `json:"rule"`
+	Severity LintSeverity `json:"severity"`
+
+	Context string `json:"msgctxt,omitempty"`
+	MsgID   string `json:"msgid,omitempty"`
+
+	Message string `json:"message"`
+}
+
+func (i CatalogIssue) String() string {
+	pos := i.File
+	if i.Line > 0 {
+		pos += ":" + strconv.Itoa(i.Line)
+	}
+	return fmt.Sprintf("%s: %s: %s (%s)", pos, i.Severity, i.Message, i.Rule)
+}
+
+/*
+LintCatalog checks the PO catalog data, read from the file name, and reports:
+
+  - Syntax errors and duplicate msgids, as found by ParseStrict.
+  - Invalid UTF-8 in catalogs declaring the UTF-8 charset.
+  - Missing or placeholder charset and MIME headers, and missing or invalid Plural-Forms headers in catalogs with plurals.
+  - Translations whose placeholders don't match their msgid, as found by ValidateDomain.
+  - Missing plural forms and Plural-Forms headers disagreeing with CLDR, as found by Domain.CheckPluralCoverage.
+
+Issues are sorted by line. Templates (.pot files) only get the syntax, encoding and duplicate checks.
+*/
+func LintCatalog(name string, data []byte) []CatalogIssue {
+	var issues []CatalogIssue
+	report := func(line int, rule string, severity LintSeverity, ctx, msgid, msg string) {
+		issues = append(issues, CatalogIssue{File: name, Line: line, Rule: rule, Severity: severity, Context: ctx, MsgID: msgid, Message: msg})
+	}
+
+	for _, err := range validatePo(data) {
+		rule := LintRuleSyntax
+		if strings.HasPrefix(err.Msg, "duplicate msgid") {
+			rule = LintRuleDuplicate
+		}
+		report(err.Line, rule, LintError, "", "", err.Msg)
+	}
+
+	po := NewPo()
+	po.Parse(data)
+	do := po.GetDomain()
+
+	charset := ""
+	if ct := do.Header("Content-Type"); ct != "" {
+		if i := strings.Index(strings.ToLower(ct), "charset="); i != -1 {
+			charset = strings.TrimSpace(ct[i+len("charset="):])
+		}
+	}
+	if (charset == "" || strings.EqualFold(charset, "utf-8") || strings.EqualFold(charset, "utf8")) && !utf8.Valid(data) {
+		report(0, LintRuleEncoding, LintError, "", "", "catalog isn't valid UTF-8")
+	}
+
+	if strings.EqualFold(filepath.Ext(name), ".pot") {
+		sortCatalogIssues(issues)
+		return issues
+	}
+
+	for _, h := range []string{"MIME-Version", "Content-Type", "Content-Transfer-Encoding"} {
+		if do.Header(h) == "" {
+			report(0, LintRuleHeader, LintWarning, "", "", fmt.Sprintf("header field %q missing", h))
+		}
+	}
+	if charset == "" || charset == "CHARSET" {
+		report(0, LintRuleHeader, LintError, "", "", "no charset declared in the Content-Type header")
+	}
+
+	lines := poEntryLines(data)
+	line := func(ctx, msgid string) int {
+		return lines[CatalogKey{Context: ctx, MsgID: msgid}]
+	}
+
+	for _, issue := range ValidateDomain(do) {
+		report(line(issue.Context, issue.MsgID), LintRulePlaceholder, LintError, issue.Context, issue.MsgID,
+			fmt.Sprintf("msgstr[%d] %q: %s", issue.Index, issue.Translation, issue.Problem))
+	}
+
+	switch err := do.CheckPluralCoverage().(type) {
+	case nil:
+	case *PluralCoverageError:
+		if err.Mismatch != nil {
+			report(0, LintRulePlural, LintWarning, "", "", strings.TrimPrefix(err.Mismatch.Error(), "gotext: "))
+		}
+		for _, i := range err.Unused {
+			report(0, LintRulePlural, LintWarning, "", "", fmt.Sprintf("plural form %d is never used by the Plural-Forms rule", i))
+		}
+		for _, issue := range err.Issues {
+			report(line(issue.Context, issue.MsgID), LintRulePlural, LintError, issue.Context, issue.MsgID,
+				fmt.Sprintf("msgstr[%d]: %s", issue.Index, issue.Problem))
+		}
+	default:
+		report(0, LintRuleHeader, LintError, "", "", fmt.Sprintf("invalid Plural-Forms header: %v", err))
+	}
+
+	sortCatalogIssues(issues)
+	return issues
+}
+
+// LintTree runs LintCatalog on every .po and .pot file under the directory root, like a locales tree.
+// Issues are sorted by file and line. It returns an error if the tree can't be read.
+func LintTree(root string) ([]CatalogIssue, error) {
+	var issues []CatalogIssue
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		ext := strings.ToLower(filepath.Ext(path))
+		if info.IsDir() || (ext != ".po" && ext != ".pot") {
+			return nil
+		}
+
+		data, err := getFileData(path)
+		if err != nil {
+			return err
+		}
+		issues = append(issues, LintCatalog(path, data)...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sortCatalogIssues(issues)
+	return issues, nil
+}
+
+// sortCatalogIssues sorts issues by file and line, keeping the order of the issues of the same line.
+func sortCatalogIssues(issues []CatalogIssue) {
+	sort.SliceStable(issues, func(i, j int) bool {
+		if issues[i].File != issues[j].File {
+			return issues[i].File < issues[j].File
+		}
+		return issues[i].Line < issues[j].Line
+	})
+}
+
+// poEntryLines returns the line where each entry of the PO data starts, by context and msgid.
+func poEntryLines(data []byte) map[CatalogKey]int {
+	lines := make(map[CatalogKey]int)
+
+	var ctx, id string
+	start, last := 0, ""
+	for n, l := range strings.Split(string(data), "\n") {
+		l = strings.TrimSpace(l)
+
+		if strings.HasPrefix(l, "\"") {
+			s, _ := strconv.Unquote(l)
+			switch last {
+			case "msgctxt":
+				ctx += s
+			case "msgid":
+				id += s
+			}
+			continue
+		}
+
+		kw, str := l, ""
+		if i := strings.IndexAny(l, " \t"); i != -1 {
+			kw, str = l[:i], strings.TrimSpace(l[i+1:])
+		}
+		s, _ := strconv.Unquote(str)
+
+		switch {
+		case kw == "msgctxt":
+			ctx, start = s, n+1
+		case kw == "msgid":
+			if last != "msgctxt" {
+				ctx, start = "", n+1
+			}
+			id = s
+		case strings.HasPrefix(kw, "msgstr") && (last == "msgid" || last == "msgid_plural"):
+			key := CatalogKey{Context: ctx, MsgID: id}
+			if _, ok := lines[key]; !ok {
+				lines[key] = start
+			}
+		}
+		if kw != "" && !strings.HasPrefix(kw, "#") {
+			last = kw
+		}
+	}
+	return lines
+}
@@ -0,0 +1,113 @@
+package gotext
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const lintPo = `msgid ""
+msgstr ""
+"Language: ru\n"
+"MIME-Version: 1.0\n"
+"Content-Type: text/plain; charset=UTF-8\n"
+"Content-Transfer-Encoding: 8bit\n"
+"Plural-Forms: nplurals=3; plural=(n%10==1 && n%100!=11 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2);\n"
+
+msgid "Hello %s"
+msgstr "Привет %d"
+
+msgctxt "files"
+msgid ""
+"%d file"
+msgid_plural "%d files"
+msgstr[0] "%d файл"
+msgstr[1] "%d файла"
+
+msgid "Bye"
+msgstr "Пока"
+
+msgid "Bye"
+msgstr "До свидания"
+`
+
+func TestLintCatalog(t *testing.T) {
+	issues := LintCatalog("ru.po", []byte(lintPo))
+
+	want := []struct {
+		line int
+		rule string
+	}{
+		{9, LintRulePlaceholder},
+		{12, LintRulePlural},
+		{22, LintRuleDuplicate},
+	}
+	if len(issues) != len(want) {
+		t.Fatalf("Expected %d issues, got %v", len(want), issues)
+	}
+	for i, w := range want {
+		if issues[i].Line != w.line || issues[i].Rule != w.rule || issues[i].Severity != LintError {
+			t.Errorf("Unexpected issue %d: %v", i, issues[i])
+		}
+	}
+	if issues[1].Context != "files" || issues[1].MsgID != "%d file" {
+		t.Errorf("Unexpected entry %q %q", issues[1].Context, issues[1].MsgID)
+	}
+	if s := issues[0].String(); !strings.HasPrefix(s, "ru.po:9: error: ") {
+		t.Errorf("Unexpected issue text %q", s)
+	}
+
+	data, err := json.Marshal(issues[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"severity":"error"`) || !strings.Contains(string(data), `"rule":"placeholder"`) {
+		t.Errorf("Unexpected JSON %s", data)
+	}
+
+	// Header and encoding checks
+	issues = LintCatalog("de.po", []byte("msgid \"\"\nmsgstr \"Content-Type: text/plain; charset=UTF-8\\n\"\n\nmsgid \"Hi\"\nmsgstr \"\xff\"\n"))
+	rules := map[string]int{}
+	for _, issue := range issues {
+		rules[issue.Rule]++
+	}
+	if rules[LintRuleEncoding] != 1 || rules[LintRuleHeader] != 2 {
+		t.Errorf("Unexpected issues %v", issues)
+	}
+}
+
+func TestLintTree(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gotext-lint")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"ru/LC_MESSAGES/default.po": lintPo,
+		"default.pot":               "msgid \"Hello %s\"\nmsgstr \"\"\n",
+		"ru/LC_MESSAGES/default.mo": "not a catalog",
+	}
+	for name, data := range files {
+		path := filepath.Join(dir, name)
+		os.MkdirAll(filepath.Dir(path), 0755)
+		if err := ioutil.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	issues, err := LintTree(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(issues) != 3 || issues[0].File != filepath.Join(dir, "ru/LC_MESSAGES/default.po") {
+		t.Errorf("Unexpected issues %v", issues)
+	}
+
+	if _, err := LintTree(filepath.Join(dir, "missing")); err == nil {
+		t.Error("Expected an error for a missing directory")
+	}
+}
@@ -7,18 +7,30 @@ package gotext
 
 import (
 	"bytes"
+	"context"
 	"encoding/gob"
 	"fmt"
-	"os"
+	"io"
+	"io/ioutil"
 	"path"
-	"sync"
+	"path/filepath"
+	"sort"
+	"strings"
 
 	"github.com/razor-1/localizer/store"
+	"golang.org/x/text/encoding"
 	"golang.org/x/text/language"
 )
 
+// Locale categories, the folders of the gettext directory layout where catalogs are looked up.
+// See AddDomainWithCategory.
 const (
 	LCMessages = "LC_MESSAGES"
+	LCCtype    = "LC_CTYPE"
+	LCNumeric  = "LC_NUMERIC"
+	LCTime     = "LC_TIME"
+	LCCollate  = "LC_COLLATE"
+	LCMonetary = "LC_MONETARY"
 )
 
 /*
@@ -67,8 +79,120 @@ type Locale struct {
 	// First AddDomain is default Domain
 	defaultDomain string
 
+	// Domains in registration order, and the ones searched first by GetAny
+	domainOrder    []string
+	domainPriority []string
+
+	// Remote catalog loader, set by NewLocaleHTTP
+	remote *httpLoader
+
+	// Configuration, copied by Clone
+	localeSettings
+
+	// Successful Reload calls, and the catalogs replaced by the last one
+	catalogVersion  int
+	previousDomains map[string]Translator
+
+	// Catalogs waiting for their activation time, by domain
+	schedules map[string]*scheduledTranslator
+
+	// Catalogs added by AddOverlayDomain and files given to AddDomainFiles, by domain, used again by Reload
+	overlays    map[string][]Translator
+	domainFiles map[string][]string
+
 	// Sync Mutex
-	sync.RWMutex
+	localeMutex
+}
+
+// localeSettings holds the configuration of a Locale: where its catalogs are found, how they're parsed
+// and how the strings looked up are returned. It's embedded in Locale, so Clone copies it at once.
+type localeSettings struct {
+	// Where catalog files are read from. The path directory is used when nil.
+	source CatalogSource
+
+	// Where catalog files are in the source. GettextLayout is used when nil.
+	resolver PathResolver
+
+	// Catalog file extensions to look up, in order. catalogExtensions is used when empty.
+	exts []string
+
+	// Defer parsing the catalogs added by AddDomain until their first lookup
+	lazy bool
+
+	// Plural-Forms header check for the catalogs loaded by AddDomain
+	pluralPolicy     PluralPolicy
+	onPluralMismatch func(dom string, m *PluralMismatch)
+
+	// Handling of duplicate msgids in the catalogs loaded by AddDomain
+	duplicatePolicy DuplicatePolicy
+
+	// Plural fallback for the catalogs loaded by AddDomain
+	pluralFallback PluralFallback
+	pluralHook     func(dom string, e PluralOutOfRange)
+
+	// Compact the catalogs loaded by AddDomain, set by SetCompaction
+	compact bool
+
+	// Pool where the catalogs loaded by AddDomain intern their strings
+	internPool   *InternPool
+	internValues bool
+
+	// Lookup normalization of the catalogs loaded by AddDomain
+	normalization Normalization
+
+	// Placeholder rewriting of the catalogs loaded by AddDomain
+	positionalMode PositionalMode
+	percentPolicy  PercentPolicy
+
+	// Handling of missing msgids for the catalogs loaded by AddDomain
+	fuzzyThreshold float64
+	missingHook    func(dom string, m MissingTranslation)
+
+	// MO files loaded by AddDomain are read on demand, with a cache of this many entries
+	moOnDemand bool
+	moCache    int
+
+	// Locale category folders of the domains added by AddDomainWithCategory
+	categories map[string]string
+
+	// Charset of the strings returned, set by SetOutputCharset; nil for UTF-8
+	charset encoding.Encoding
+
+	// Functions applied to the strings returned, in order, added by AddPostProcessor
+	postProcessors []PostProcessor
+
+	// Get functions format numbers, dates and amounts in vars with LocalizeVars, set by SetLocalizedVars
+	localizedVars bool
+
+	// Get functions panic on missing translations, set by SetStrictMode
+	strict bool
+
+	// Language of the msgids, set by SetSourceLanguage; language.Und when unknown
+	sourceLang language.Tag
+
+	// Domain with the display strings of the keys, set by SetKeyMode; empty unless in key mode
+	keySource string
+
+	// Tags trusted by GetHTML, set by SetHTMLTags; defaultHTMLTags when nil
+	htmlTags map[string]bool
+
+	// Languages looked up, in order, for the messages the catalogs of lang don't translate
+	fallbackLangs []string
+}
+
+// clone returns a copy of the settings that doesn't share their slices and maps.
+func (s localeSettings) clone() localeSettings {
+	c := s
+	c.exts = append([]string(nil), s.exts...)
+	c.postProcessors = append([]PostProcessor(nil), s.postProcessors...)
+	c.fallbackLangs = append([]string(nil), s.fallbackLangs...)
+	if s.categories != nil {
+		c.categories = make(map[string]string, len(s.categories))
+		for dom, cat := range s.categories {
+			c.categories[dom] = cat
+		}
+	}
+	return c
 }
 
 // NewLocale creates and initializes a new Locale object for a given language.
@@ -83,54 +207,259 @@ func NewLocale(p, l string) *Locale {
 	}
 }
 
-func (l *Locale) findExt(dom, ext string) string {
-	filename := path.Join(l.path, l.lang, LCMessages, dom+"."+ext)
-	if _, err := os.Stat(filename); err == nil {
-		return filename
+// candidates returns the paths, relative to the library path, where the domain file with the given extension
+// is looked up for the language lang, in lookup order.
+func (l *Locale) candidates(lang, dom, ext string) []string {
+	l.RLock()
+	r := l.resolver
+	l.RUnlock()
+
+	if r == nil {
+		r = GettextLayout
+	}
+	return r.Paths(lang, dom, ext)
+}
+
+// catalogFile is a file where a domain catalog is looked up.
+type catalogFile struct {
+	path, ext string
+}
+
+// catalogFiles returns the files where the catalog of the domain dom is looked up, in lookup order.
+func (l *Locale) catalogFiles(dom string) []catalogFile {
+	return l.langCatalogFiles(l.lang, dom)
+}
+
+// langCatalogFiles returns the files where the catalog of the domain dom is looked up for the language lang.
+func (l *Locale) langCatalogFiles(lang, dom string) []catalogFile {
+	l.RLock()
+	category := l.categories[dom]
+	l.RUnlock()
+
+	// Compressed files, like default.po.gz, are looked up after the uncompressed one
+	compressed := compressedExts()
+
+	var files []catalogFile
+	for _, ext := range l.extensions() {
+		for _, p := range l.candidates(lang, dom, ext) {
+			if category != "" {
+				var ok bool
+				if p, ok = categoryPath(p, category); !ok {
+					continue
+				}
+			}
+			files = append(files, catalogFile{path: p, ext: ext})
+			for _, c := range compressed {
+				files = append(files, catalogFile{path: p + c, ext: ext})
+			}
+		}
+	}
+	return files
+}
+
+// loadCatalog parses the first of files found in src as the catalog of the domain dom.
+// It returns nil without error when none is found, and an error when a file can't be read or parsed,
+// so a failing source isn't mistaken for a missing catalog. Malformed PO and Fluent catalogs are still used,
+// as far as they could be loaded, with their syntax errors logged.
+func loadCatalog(ctx context.Context, src CatalogSource, files []catalogFile, parser catalogParser, dom string) (Translator, error) {
+	for _, f := range files {
+		data, err := readCatalog(src, f.path)
+		if isNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("gotext: reading %s: %w", f.path, err)
+		}
+
+		_, span := startSpan(ctx, "gotext.ParseCatalog", "domain", dom, "file", f.path, "format", f.ext, "bytes", len(data))
+		tr, err := parser.parse(dom, f.ext, f.path, data)
+		if tr != nil {
+			span.SetAttribute("entries", tr.GetDomain().entryCount())
+		}
+		span.End(err)
+		if tr != nil {
+			// Syntax errors were logged by the parser: keep what could be loaded, like Parse does
+			return tr, nil
+		}
+		return nil, err
+	}
+	return nil, nil
+}
+
+// loadDomain loads the catalog of the domain dom right away, like AddDomain does without lazy loading,
+// returning the problems found.
+func (l *Locale) loadDomain(ctx context.Context, dom string) (err error) {
+	ctx, span := startSpan(ctx, "gotext.AddDomain", "lang", l.lang, "domain", dom)
+	defer func() {
+		span.End(err)
+	}()
+
+	tr, err := loadCatalogs(ctx, l.catalogSource(), l.catalogLookups(dom), dom)
+	if err != nil {
+		return err
+	}
+	if tr == nil {
+		return fmt.Errorf("gotext: no catalog found for domain %q", dom)
 	}
 
-	if len(l.lang) > 2 {
-		filename = path.Join(l.path, l.lang[:2], LCMessages, dom+"."+ext)
-		if _, err := os.Stat(filename); err == nil {
-			return filename
+	l.AddTranslator(dom, tr)
+	return nil
+}
+
+// domains returns the domains with a catalog in the source of the Locale, sorted.
+// A file is the catalog of a domain when it's one of the files looked up for it, which is named
+// like the file or one of its folders.
+func (l *Locale) domains() ([]string, error) {
+	names, err := l.catalogSource().List("")
+	if err != nil {
+		return nil, err
+	}
+
+	found := make(map[string]bool)
+	for _, name := range names {
+		file := trimCompressedExt(name)
+		ext := strings.TrimPrefix(path.Ext(file), ".")
+		parts := strings.Split(strings.TrimSuffix(file, "."+ext), "/")
+		for _, dom := range parts {
+			if found[dom] {
+				continue
+			}
+			for _, f := range l.catalogFiles(dom) {
+				if f.path == name && f.ext == ext {
+					found[dom] = true
+					break
+				}
+			}
 		}
 	}
 
-	filename = path.Join(l.path, l.lang, dom+"."+ext)
-	if _, err := os.Stat(filename); err == nil {
-		return filename
+	doms := make([]string, 0, len(found))
+	for dom := range found {
+		doms = append(doms, dom)
+	}
+	sort.Strings(doms)
+	return doms, nil
+}
+
+// NewLocaleWithSource creates and initializes a new Locale object for a given language,
+// reading its catalogs from src instead of a directory. See CatalogSource.
+func NewLocaleWithSource(src CatalogSource, l string) *Locale {
+	loc := NewLocale("", l)
+	loc.source = src
+	return loc
+}
+
+// SetSource changes where the Locale reads catalog files from on the next AddDomain calls.
+func (l *Locale) SetSource(src CatalogSource) {
+	l.Lock()
+	l.source = src
+	l.Unlock()
+}
+
+// catalogSource returns the CatalogSource of the Locale, defaulting to its path directory.
+func (l *Locale) catalogSource() CatalogSource {
+	l.RLock()
+	defer l.RUnlock()
+
+	if l.source == nil {
+		return DirSource(l.path)
 	}
+	return l.source
+}
+
+// catalogExtensions are the catalog file extensions looked up by AddDomain, in order
+var catalogExtensions = []string{"po", "mo", "ftl", "arb", "properties", "yml"}
+
+// SetExtensions sets the catalog file extensions looked up by AddDomain, in order, to force a format preference
+// or restrict the formats used. For example, l.SetExtensions("mo", "po") prefers compiled catalogs, and
+// l.SetExtensions("po") ignores any .mo file. Calling it without extensions restores the default order.
+func (l *Locale) SetExtensions(exts ...string) {
+	l.Lock()
+	l.exts = append([]string(nil), exts...)
+	l.Unlock()
+}
 
-	if len(l.lang) > 2 {
-		filename = path.Join(l.path, l.lang[:2], dom+"."+ext)
-		if _, err := os.Stat(filename); err == nil {
-			return filename
+// extensions returns the catalog file extensions looked up by the Locale, in order.
+func (l *Locale) extensions() []string {
+	l.RLock()
+	defer l.RUnlock()
+
+	if len(l.exts) == 0 {
+		if l.remote != nil {
+			return remoteExtensions
 		}
+		return catalogExtensions
 	}
+	return l.exts
+}
 
-	return ""
+// AddDomainFile loads the catalog file at path, in the format given by its extension, as the domain dom.
+// Compressed files are decompressed, and the extension of their compression is ignored, so "es.po.gz" is a PO file.
+// Unlike AddDomain, it doesn't look the file up, so the exact file to use can be chosen.
+// It returns an error if the file can't be read, or its plural rule is refused by the plural policy.
+func (l *Locale) AddDomainFile(dom, path string) error {
+	tr, err := parseCatalogFile(l.catalogParser(), dom, path)
+	if err != nil {
+		return err
+	}
+
+	l.AddTranslator(dom, tr)
+	return nil
+}
+
+// parseCatalogFile parses the catalog file at path, in the format given by its extension, as the domain dom.
+func parseCatalogFile(p catalogParser, dom, path string) (Translator, error) {
+	data, err := getFileData(path)
+	if err != nil {
+		return nil, err
+	}
+
+	ext := strings.ToLower(strings.TrimPrefix(filepath.Ext(trimCompressedExt(path)), "."))
+	switch ext {
+	case "pot":
+		ext = "po"
+	case "yaml":
+		ext = "yml"
+	}
+
+	return p.parse(dom, ext, path, data)
 }
 
 // AddDomain creates a new domain for a given locale object and initializes the Po object.
 // If the domain exists, it gets reloaded.
+// It looks for a dom.po, dom.mo, dom.ftl (Fluent), dom.arb (Flutter), dom.properties (Java) or dom.yml (Rails) file,
+// in that order, unless other extensions are set with SetExtensions.
+// Each of them is also looked up compressed, like dom.po.gz (see RegisterDecompressor).
 func (l *Locale) AddDomain(dom string) {
-	var poObj Translator
+	if l.remote != nil {
+		l.addRemoteDomain(dom)
+		return
+	}
+
+	src := l.catalogSource()
+	lookups := l.catalogLookups(dom)
+
+	ctx, span := startSpan(context.Background(), "gotext.AddDomain", "lang", l.lang, "domain", dom)
+	defer span.End(nil)
+
+	l.RLock()
+	lazy := l.lazy
+	l.RUnlock()
 
-	file := l.findExt(dom, "po")
-	if file != "" {
-		poObj = NewPo()
-		// Parse file.
-		poObj.ParseFile(file)
+	var poObj Translator
+	if lazy {
+		poObj = &lazyTranslator{load: func() Translator {
+			tr, _ := loadCatalogs(context.Background(), src, lookups, dom)
+			return tr
+		}}
 	} else {
-		file = l.findExt(dom, "mo")
-		if file != "" {
-			poObj = NewMo()
-			// Parse file.
-			poObj.ParseFile(file)
-		} else {
-			// fallback return if no file found with
+		tr, err := loadCatalogs(ctx, src, lookups, dom)
+		if tr == nil {
+			// fallback return if no file found or it can't be read or parsed
+			logWarn("gotext: no catalog loaded", "lang", l.lang, "domain", dom, "err", err)
 			return
 		}
+		poObj = tr
 	}
 
 	// Save new domain
@@ -142,7 +471,10 @@ func (l *Locale) AddDomain(dom string) {
 	if l.defaultDomain == "" {
 		l.defaultDomain = dom
 	}
+	l.addDomainOrder(dom)
 	l.Domains[dom] = poObj
+	l.stopSchedule(dom)
+	l.forgetComposition(dom)
 
 	// Unlock "Save new domain"
 	l.Unlock()
@@ -158,9 +490,45 @@ func (l *Locale) AddTranslator(dom string, tr Translator) {
 	if l.defaultDomain == "" {
 		l.defaultDomain = dom
 	}
+	l.addDomainOrder(dom)
 	l.Domains[dom] = tr
+	l.forgetComposition(dom)
+	lang := l.lang
 
 	l.Unlock()
+
+	// Lazy catalogs get their labels when loaded
+	if _, ok := tr.(*lazyTranslator); !ok && tr != nil {
+		if do := tr.GetDomain(); do != nil {
+			do.setMetricsLabels(lang, dom)
+		}
+	}
+}
+
+// AddDomainBytes parses data as a catalog in the given format and makes it available as the domain dom,
+// so catalogs kept in databases, fetched from services or built in tests can be used without files.
+// The catalog gets the plural policy and fallback of the Locale, like the ones loaded by AddDomain.
+func (l *Locale) AddDomainBytes(dom string, data []byte, format Format) error {
+	if format < 0 || int(format) >= len(formatNames) {
+		return fmt.Errorf("gotext: unknown format %v", format)
+	}
+
+	tr, err := l.parseCatalog(dom, format.String(), data)
+	if err != nil {
+		return err
+	}
+
+	l.AddTranslator(dom, tr)
+	return nil
+}
+
+// AddDomainReader works like AddDomainBytes, reading the catalog from r.
+func (l *Locale) AddDomainReader(dom string, r io.Reader, format Format) error {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	return l.AddDomainBytes(dom, data, format)
 }
 
 // GetDomain is the domain getter for Locale configuration
@@ -199,6 +567,9 @@ func (l *Locale) GetTranslations(tag language.Tag) (lc store.LocaleCatalog, err
 			lc.Translations[msgID] = msg
 		}
 	}
+	if l.inSourceLanguage() {
+		labelSource(lc)
+	}
 
 	return
 }
@@ -206,13 +577,13 @@ func (l *Locale) GetTranslations(tag language.Tag) (lc store.LocaleCatalog, err
 // Get uses a domain "default" to return the corresponding Translation of a given string.
 // Supports optional parameters (vars... interface{}) to be inserted on the formatted string using the fmt.Printf syntax.
 func (l *Locale) Get(str string, vars ...interface{}) string {
-	return l.GetD(l.GetDomain(), str, vars...)
+	return l.GetD(callerDomain(0, l.GetDomain()), str, vars...)
 }
 
 // GetN retrieves the (N)th plural form of Translation for the given string in the "default" domain.
 // Supports optional parameters (vars... interface{}) to be inserted on the formatted string using the fmt.Printf syntax.
 func (l *Locale) GetN(str, plural string, n int, vars ...interface{}) string {
-	return l.GetND(l.GetDomain(), str, plural, n, vars...)
+	return l.GetND(callerDomain(0, l.GetDomain()), str, plural, n, vars...)
 }
 
 // GetD returns the corresponding Translation in the given domain for the given string.
@@ -222,14 +593,26 @@ func (l *Locale) GetD(dom, str string, vars ...interface{}) string {
 	l.RLock()
 	defer l.RUnlock()
 
-	if l.Domains != nil {
-		if _, ok := l.Domains[dom]; ok {
-			if l.Domains[dom] != nil {
-				return l.Domains[dom].Get(str, vars...)
-			}
-		}
+	return l.output(str, l.getD(dom, str, l.localVars(vars)))
+}
+
+// getD returns the translation of str in the domain dom formatted with vars, before the post-processors
+// and output charset are applied. The Locale must be locked.
+func (l *Locale) getD(dom, str string, vars []interface{}) string {
+	if l.inSourceLanguage() {
+		return Printf(str, vars...)
+	}
+
+	if l.strict {
+		l.mustTranslate(dom, str, "", false)
+	}
+
+	if tr := l.translator(dom, str, "", false); tr != nil {
+		return tr.Get(str, vars...)
 	}
 
+	metrics().Lookup(l.lang, dom, LookupMiss)
+
 	return Printf(str, vars...)
 }
 
@@ -239,32 +622,39 @@ func (l *Locale) GetND(dom, str, plural string, n int, vars ...interface{}) stri
 	// Sync read
 	l.RLock()
 	defer l.RUnlock()
+	vars = l.localVars(vars)
 
-	if l.Domains != nil {
-		if _, ok := l.Domains[dom]; ok {
-			if l.Domains[dom] != nil {
-				return l.Domains[dom].GetN(str, plural, n, vars...)
-			}
-		}
+	if l.inSourceLanguage() {
+		return l.output(str, sourceN(str, plural, n, vars...))
 	}
 
+	if l.strict {
+		l.mustTranslate(dom, str, "", false)
+	}
+
+	if tr := l.translator(dom, str, "", false); tr != nil {
+		return l.output(str, tr.GetN(str, plural, n, vars...))
+	}
+
+	metrics().Lookup(l.lang, dom, LookupMiss)
+
 	// Use western default rule (plural > 1) to handle missing domain default result.
 	if n == 1 {
-		return Printf(str, vars...)
+		return l.output(str, Printf(str, vars...))
 	}
-	return Printf(plural, vars...)
+	return l.output(str, Printf(plural, vars...))
 }
 
 // GetC uses a domain "default" to return the corresponding Translation of the given string in the given context.
 // Supports optional parameters (vars... interface{}) to be inserted on the formatted string using the fmt.Printf syntax.
 func (l *Locale) GetC(str, ctx string, vars ...interface{}) string {
-	return l.GetDC(l.GetDomain(), str, ctx, vars...)
+	return l.GetDC(callerDomain(0, l.GetDomain()), str, ctx, vars...)
 }
 
 // GetNC retrieves the (N)th plural form of Translation for the given string in the given context in the "default" domain.
 // Supports optional parameters (vars... interface{}) to be inserted on the formatted string using the fmt.Printf syntax.
 func (l *Locale) GetNC(str, plural string, n int, ctx string, vars ...interface{}) string {
-	return l.GetNDC(l.GetDomain(), str, plural, n, ctx, vars...)
+	return l.GetNDC(callerDomain(0, l.GetDomain()), str, plural, n, ctx, vars...)
 }
 
 // GetDC returns the corresponding Translation in the given domain for the given string in the given context.
@@ -273,16 +663,23 @@ func (l *Locale) GetDC(dom, str, ctx string, vars ...interface{}) string {
 	// Sync read
 	l.RLock()
 	defer l.RUnlock()
+	vars = l.localVars(vars)
 
-	if l.Domains != nil {
-		if _, ok := l.Domains[dom]; ok {
-			if l.Domains[dom] != nil {
-				return l.Domains[dom].GetC(str, ctx, vars...)
-			}
-		}
+	if l.inSourceLanguage() {
+		return l.output(str, Printf(str, vars...))
 	}
 
-	return Printf(str, vars...)
+	if l.strict {
+		l.mustTranslate(dom, str, ctx, true)
+	}
+
+	if tr := l.translator(dom, str, ctx, true); tr != nil {
+		return l.output(str, tr.GetC(str, ctx, vars...))
+	}
+
+	metrics().Lookup(l.lang, dom, LookupMiss)
+
+	return l.output(str, Printf(str, vars...))
 }
 
 // GetNDC retrieves the (N)th plural form of Translation in the given domain for the given string in the given context.
@@ -291,20 +688,90 @@ func (l *Locale) GetNDC(dom, str, plural string, n int, ctx string, vars ...inte
 	// Sync read
 	l.RLock()
 	defer l.RUnlock()
+	vars = l.localVars(vars)
 
-	if l.Domains != nil {
-		if _, ok := l.Domains[dom]; ok {
-			if l.Domains[dom] != nil {
-				return l.Domains[dom].GetNC(str, plural, n, ctx, vars...)
-			}
-		}
+	if l.inSourceLanguage() {
+		return l.output(str, sourceN(str, plural, n, vars...))
 	}
 
+	if l.strict {
+		l.mustTranslate(dom, str, ctx, true)
+	}
+
+	if tr := l.translator(dom, str, ctx, true); tr != nil {
+		return l.output(str, tr.GetNC(str, plural, n, ctx, vars...))
+	}
+
+	metrics().Lookup(l.lang, dom, LookupMiss)
+
 	// Use western default rule (plural > 1) to handle missing domain default result.
 	if n == 1 {
-		return Printf(str, vars...)
+		return l.output(str, Printf(str, vars...))
 	}
-	return Printf(plural, vars...)
+	return l.output(str, Printf(plural, vars...))
+}
+
+// GetOrdinal retrieves the ordinal form of Translation for the given string in the "default" domain.
+// Supports optional parameters (vars... interface{}) to be inserted on the formatted string using the fmt.Printf syntax.
+func (l *Locale) GetOrdinal(str string, n int, vars ...interface{}) string {
+	return l.GetOrdinalD(callerDomain(0, l.GetDomain()), str, n, vars...)
+}
+
+// GetOrdinalD retrieves the ordinal form of Translation in the given domain for the given string.
+// Supports optional parameters (vars... interface{}) to be inserted on the formatted string using the fmt.Printf syntax.
+func (l *Locale) GetOrdinalD(dom, str string, n int, vars ...interface{}) string {
+	// Sync read
+	l.RLock()
+	defer l.RUnlock()
+	vars = l.localVars(vars)
+
+	if l.inSourceLanguage() {
+		return l.output(str, Printf(str, vars...))
+	}
+
+	if l.strict {
+		l.mustTranslate(dom, str, "", false)
+	}
+
+	if tr := l.translator(dom, str, "", false); tr != nil {
+		return l.output(str, catalogFor(tr, str).GetOrdinal(str, n, vars...))
+	}
+
+	metrics().Lookup(l.lang, dom, LookupMiss)
+
+	return l.output(str, Printf(str, vars...))
+}
+
+// GetRange retrieves the plural form of Translation for a range of values (from-to) in the "default" domain.
+// Supports optional parameters (vars... interface{}) to be inserted on the formatted string using the fmt.Printf syntax.
+func (l *Locale) GetRange(str, plural string, from, to int, vars ...interface{}) string {
+	return l.GetRangeD(callerDomain(0, l.GetDomain()), str, plural, from, to, vars...)
+}
+
+// GetRangeD retrieves the plural form of Translation for a range of values (from-to) in the given domain.
+// Supports optional parameters (vars... interface{}) to be inserted on the formatted string using the fmt.Printf syntax.
+func (l *Locale) GetRangeD(dom, str, plural string, from, to int, vars ...interface{}) string {
+	// Sync read
+	l.RLock()
+	defer l.RUnlock()
+	vars = l.localVars(vars)
+
+	if l.inSourceLanguage() {
+		return l.output(str, sourceN(str, plural, rangeCount(l.tag, from, to), vars...))
+	}
+
+	if l.strict {
+		l.mustTranslate(dom, str, "", false)
+	}
+
+	if tr := l.translator(dom, str, "", false); tr != nil {
+		return l.output(str, tr.GetN(str, plural, rangeCount(catalogFor(tr, str).language(), from, to), vars...))
+	}
+
+	metrics().Lookup(l.lang, dom, LookupMiss)
+
+	// Use western default rule (plural > 1) to handle missing domain default result.
+	return l.output(str, sourceN(str, plural, rangeCount(l.tag, from, to), vars...))
 }
 
 // LocaleEncoding is used as intermediary storage to encode Locale objects to Gob.
@@ -313,9 +780,14 @@ type LocaleEncoding struct {
 	Lang          string
 	Domains       map[string][]byte
 	DefaultDomain string
+
+	// SHA-256 of each encoded domain, checked when loading
+	Checksums map[string][]byte
 }
 
-// MarshalBinary implements encoding BinaryMarshaler interface
+// MarshalBinary implements encoding BinaryMarshaler interface.
+// The data is in a versioned binary cache format with checksums (see CacheVersion),
+// so it can be stored and shared between binaries.
 func (l *Locale) MarshalBinary() ([]byte, error) {
 	obj := new(LocaleEncoding)
 	obj.DefaultDomain = l.defaultDomain
@@ -330,30 +802,20 @@ func (l *Locale) MarshalBinary() ([]byte, error) {
 	obj.Lang = l.lang
 	obj.Path = l.path
 
-	var buff bytes.Buffer
-	encoder := gob.NewEncoder(&buff)
-	err := encoder.Encode(obj)
-
-	return buff.Bytes(), err
+	return encodeCache(obj)
 }
 
-// UnmarshalBinary implements encoding BinaryUnmarshaler interface
+// UnmarshalBinary implements encoding BinaryUnmarshaler interface.
+// It returns an error wrapping ErrCacheVersion or ErrCacheChecksum, without changing the Locale,
+// when the data is in an unsupported version or corrupt.
 func (l *Locale) UnmarshalBinary(data []byte) error {
-	buff := bytes.NewBuffer(data)
-	obj := new(LocaleEncoding)
-
-	decoder := gob.NewDecoder(buff)
-	err := decoder.Decode(obj)
+	obj, err := decodeCache(data)
 	if err != nil {
 		return err
 	}
 
-	l.defaultDomain = obj.DefaultDomain
-	l.lang = obj.Lang
-	l.path = obj.Path
-
 	// Decode Domains
-	l.Domains = make(map[string]Translator)
+	domains := make(map[string]Translator)
 	for k, v := range obj.Domains {
 		var tr TranslatorEncoding
 		buff := bytes.NewBuffer(v)
@@ -363,8 +825,14 @@ func (l *Locale) UnmarshalBinary(data []byte) error {
 			return err
 		}
 
-		l.Domains[k] = tr.GetTranslator()
+		domains[k] = tr.GetTranslator()
 	}
 
+	l.defaultDomain = obj.DefaultDomain
+	l.lang = obj.Lang
+	l.tag = language.Make(obj.Lang)
+	l.path = obj.Path
+	l.Domains = domains
+
 	return nil
 }
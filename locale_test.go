@@ -6,8 +6,12 @@
 package gotext
 
 import (
+	"bytes"
+	"errors"
+	"io/ioutil"
 	"os"
 	"path"
+	"strings"
 	"testing"
 
 	"golang.org/x/text/language"
@@ -638,3 +642,130 @@ func TestLocaleGetTranslations(t *testing.T) {
 		t.Errorf("expected translation to be \"%s\" but got \"%s\"", msgStr, tr.Get())
 	}
 }
+
+func TestLocaleAddDomainBytes(t *testing.T) {
+	l := NewLocale("fixtures/", "es")
+
+	po := []byte("msgid \"Hello\"\nmsgstr \"Hola\"\n")
+	if err := l.AddDomainBytes("app", po, FormatPO); err != nil {
+		t.Fatal(err)
+	}
+	if got := l.GetD("app", "Hello"); got != "Hola" {
+		t.Errorf("unexpected translation %q", got)
+	}
+	if l.GetDomain() != "app" {
+		t.Errorf("expected the first domain to be the default, got %q", l.GetDomain())
+	}
+
+	src := NewPo()
+	src.Parse([]byte("msgid \"Bye\"\nmsgstr \"Adiós\"\n"))
+	mo, _ := src.GetDomain().MarshalMO()
+	if err := l.AddDomainReader("mo", bytes.NewReader(mo), FormatMO); err != nil {
+		t.Fatal(err)
+	}
+	if got := l.GetD("mo", "Bye"); got != "Adiós" {
+		t.Errorf("unexpected translation %q", got)
+	}
+
+	json := `{"messages": [{"msgid": "Yes", "msgstr": ["Sí"]}]}`
+	if err := l.AddDomainReader("json", strings.NewReader(json), FormatJSON); err != nil {
+		t.Fatal(err)
+	}
+	if got := l.GetD("json", "Yes"); got != "Sí" {
+		t.Errorf("unexpected translation %q", got)
+	}
+
+	if err := l.AddDomainBytes("bad", []byte("{"), FormatJSON); err == nil {
+		t.Error("expected an error for invalid JSON")
+	}
+	if err := l.AddDomainBytes("bad", po, Format(42)); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+	if _, ok := l.Domains["bad"]; ok {
+		t.Error("invalid catalogs shouldn't be added")
+	}
+}
+
+func TestLocaleMalformedCatalogs(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gotext")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"de.po":  "msgid \"Hello\"\nmsgstr \"Hallo\"\n\nmsgid \"Bye\"\nmsgstr \"Tschüss\n",
+		"de.ftl": "hello = Hallo\ninvalid line here\n",
+		"de.yml": "de:\n  a: b\n  a: c\n",
+	}
+	l := NewLocale("", "de")
+	for name, data := range files {
+		file := path.Join(dir, name)
+		if err := ioutil.WriteFile(file, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := l.AddDomainFile("bad", file); err == nil {
+			t.Errorf("expected an error for %s", name)
+		}
+	}
+	var errs ParseErrors
+	if err := l.AddDomainBytes("bad", []byte(files["de.po"]), FormatPO); !errors.As(err, &errs) || errs[0].Line != 5 {
+		t.Errorf("expected a syntax error on line 5, got %v", err)
+	}
+	if _, ok := l.Domains["bad"]; ok {
+		t.Error("malformed catalogs shouldn't be added")
+	}
+
+	// AddDomain keeps what could be loaded, like Parse does
+	l = NewLocaleWithSource(&MemorySource{Files: map[string][]byte{"de/default.po": []byte(files["de.po"])}}, "de")
+	l.AddDomain("default")
+	if got := l.Get("Hello"); got != "Hallo" {
+		t.Errorf("unexpected translation %q", got)
+	}
+}
+
+func TestLocaleSetExtensions(t *testing.T) {
+	src := &MemorySource{Files: map[string][]byte{}}
+	po := []byte("msgid \"Hello\"\nmsgstr \"From PO\"\n")
+	other := NewPo()
+	other.Parse([]byte("msgid \"Hello\"\nmsgstr \"From MO\"\n"))
+	mo, _ := other.GetDomain().MarshalMO()
+	src.Files["en/default.po"] = po
+	src.Files["en/default.mo"] = mo
+
+	l := NewLocaleWithSource(src, "en")
+	l.AddDomain("default")
+	if got := l.Get("Hello"); got != "From PO" {
+		t.Errorf("expected .po files to be preferred by default, got %q", got)
+	}
+
+	l.SetExtensions("mo", "po")
+	l.AddDomain("default")
+	if got := l.Get("Hello"); got != "From MO" {
+		t.Errorf("expected the .mo file, got %q", got)
+	}
+
+	l.SetExtensions("ftl")
+	l.AddDomain("missing")
+	if _, ok := l.Domains["missing"]; ok {
+		t.Error("expected no catalog for unlisted extensions")
+	}
+}
+
+func TestLocaleAddDomainFile(t *testing.T) {
+	l := NewLocale("unused/", "en_US")
+
+	if err := l.AddDomainFile("compiled", "fixtures/en_US/default.mo"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := l.Domains["compiled"].(*Mo); !ok {
+		t.Errorf("expected a Mo catalog, got %T", l.Domains["compiled"])
+	}
+	if got := l.GetD("compiled", "My text"); got != "Translated text" {
+		t.Errorf("unexpected translation %q", got)
+	}
+
+	if err := l.AddDomainFile("missing", "fixtures/en_US/missing.po"); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
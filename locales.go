@@ -0,0 +1,242 @@
+package gotext
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+
+	"golang.org/x/text/language"
+)
+
+/*
+Locales is a pool of Locale objects, one per language, used to serve requests in several languages at the same time.
+It matches the languages requested by clients (like an Accept-Language header) with the available ones.
+
+	ls := gotext.NewLocales("/path/to/i18n/dir", "en_US", "de_DE", "fr")
+	ls.AddDomain("default")
+
+	l := ls.Match(r.Header.Get("Accept-Language"))
+	fmt.Println(l.Get("Translate this"))
+*/
+type Locales struct {
+	mu sync.RWMutex
+
+	// Available Locale objects by language, and languages in the order they were added.
+	locales map[string]*Locale
+	langs   []string
+
+	// Matcher for the available languages, built on demand.
+	matcher language.Matcher
+
+	// Pool shared by every Locale to intern catalog strings, set by SetInterning.
+	internPool   *InternPool
+	internValues bool
+}
+
+// NewLocales creates a Locales pool with a new Locale for each of the given languages, all sharing the library path p.
+// The first language is the fallback used by Match when no requested language is available.
+func NewLocales(p string, langs ...string) *Locales {
+	ls := &Locales{locales: make(map[string]*Locale)}
+	for _, lang := range langs {
+		ls.Add(NewLocale(p, lang))
+	}
+	return ls
+}
+
+// Add makes l available in the pool, replacing the Locale previously added for the same language.
+func (ls *Locales) Add(l *Locale) {
+	ls.mu.Lock()
+	if ls.locales == nil {
+		ls.locales = make(map[string]*Locale)
+	}
+	if _, ok := ls.locales[l.lang]; !ok {
+		ls.langs = append(ls.langs, l.lang)
+	}
+	ls.locales[l.lang] = l
+	ls.matcher = nil
+	pool, values := ls.internPool, ls.internValues
+	ls.mu.Unlock()
+
+	if pool != nil {
+		l.SetInternPool(pool, values)
+	}
+}
+
+// AddDomain loads the domain dom in every Locale of the pool.
+func (ls *Locales) AddDomain(dom string) {
+	for _, l := range ls.all() {
+		l.AddDomain(dom)
+	}
+}
+
+// LoadError is a catalog that couldn't be loaded by Locales.LoadAll.
+type LoadError struct {
+	Lang   string
+	Domain string
+	Err    error
+}
+
+func (e *LoadError) Error() string {
+	if e.Domain == "" {
+		return e.Lang + ": " + e.Err.Error()
+	}
+	return e.Lang + "/" + e.Domain + ": " + e.Err.Error()
+}
+
+// Unwrap returns the cause of the error.
+func (e *LoadError) Unwrap() error {
+	return e.Err
+}
+
+// LoadErrors are all the catalogs that couldn't be loaded by Locales.LoadAll.
+type LoadErrors []*LoadError
+
+func (e LoadErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return "gotext: " + strings.Join(msgs, "; ")
+}
+
+/*
+LoadAll finds the catalogs of every domain in the source of each Locale in the pool, and loads them
+using up to concurrency goroutines, so booting with many languages and domains takes a fraction of the time.
+Catalogs are loaded right away, even for Locale objects with lazy loading.
+
+It returns a LoadErrors value with the catalogs that couldn't be loaded, sorted by language and domain,
+after trying all the others. When ctx is done, no more catalogs are loaded and its error is returned.
+
+	ls := gotext.NewLocales("/path/to/i18n/dir", "en_US", "de_DE", "fr")
+	if err := ls.LoadAll(ctx, runtime.NumCPU()); err != nil {
+		log.Print(err)
+	}
+*/
+func (ls *Locales) LoadAll(ctx context.Context, concurrency int) error {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	type job struct {
+		l   *Locale
+		dom string
+	}
+
+	var jobs []job
+	var errs LoadErrors
+	for _, l := range ls.all() {
+		doms, err := l.domains()
+		if err != nil {
+			errs = append(errs, &LoadError{Lang: l.lang, Err: err})
+			continue
+		}
+		for _, dom := range doms {
+			jobs = append(jobs, job{l, dom})
+		}
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	queue := make(chan job)
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range queue {
+				if err := j.l.loadDomain(ctx, j.dom); err != nil {
+					mu.Lock()
+					errs = append(errs, &LoadError{Lang: j.l.lang, Domain: j.dom, Err: err})
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+
+dispatch:
+	for _, j := range jobs {
+		select {
+		case queue <- j:
+		case <-ctx.Done():
+			break dispatch
+		}
+	}
+	close(queue)
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if len(errs) > 0 {
+		sort.Slice(errs, func(i, j int) bool {
+			if errs[i].Lang != errs[j].Lang {
+				return errs[i].Lang < errs[j].Lang
+			}
+			return errs[i].Domain < errs[j].Domain
+		})
+		return errs
+	}
+	return nil
+}
+
+// Get returns the Locale for the exact language lang (after simplification), or nil if it isn't available.
+func (ls *Locales) Get(lang string) *Locale {
+	ls.mu.RLock()
+	defer ls.mu.RUnlock()
+
+	return ls.locales[SimplifiedLocale(lang)]
+}
+
+// Languages returns the available languages, sorted.
+func (ls *Locales) Languages() []string {
+	ls.mu.RLock()
+	langs := append([]string(nil), ls.langs...)
+	ls.mu.RUnlock()
+
+	sort.Strings(langs)
+	return langs
+}
+
+// Match returns the Locale that best matches the requested languages.
+// Each value can be a single language code ("de_DE", "pt-BR") or a full Accept-Language header ("fr-CH, fr;q=0.9, en;q=0.8").
+// It returns the first Locale added when none matches, or nil if the pool is empty.
+func (ls *Locales) Match(requested ...string) *Locale {
+	var tags []language.Tag
+	for _, r := range requested {
+		if t, _, err := language.ParseAcceptLanguage(strings.Replace(r, "_", "-", -1)); err == nil {
+			tags = append(tags, t...)
+		}
+	}
+
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+
+	if len(ls.langs) == 0 {
+		return nil
+	}
+	if ls.matcher == nil {
+		supported := make([]language.Tag, len(ls.langs))
+		for i, lang := range ls.langs {
+			supported[i] = ls.locales[lang].tag
+		}
+		ls.matcher = language.NewMatcher(supported)
+	}
+
+	_, idx, conf := ls.matcher.Match(tags...)
+	if conf == language.No || idx < 0 || idx >= len(ls.langs) {
+		idx = 0
+	}
+	return ls.locales[ls.langs[idx]]
+}
+
+// all returns every Locale in the pool, in the order they were added.
+func (ls *Locales) all() []*Locale {
+	ls.mu.RLock()
+	defer ls.mu.RUnlock()
+
+	locales := make([]*Locale, len(ls.langs))
+	for i, lang := range ls.langs {
+		locales[i] = ls.locales[lang]
+	}
+	return locales
+}
@@ -0,0 +1,107 @@
+package gotext
+
+import (
+	"context"
+	"testing"
+)
+
+func TestLocalesMatch(t *testing.T) {
+	ls := NewLocales("fixtures/", "en_US", "de_DE", "fr")
+	ls.AddDomain("default")
+
+	tests := []struct {
+		requested []string
+		expected  string
+	}{
+		{[]string{"de_DE"}, "de_DE"},
+		{[]string{"fr-CH, fr;q=0.9, en;q=0.8"}, "fr"},
+		{[]string{"pt-BR"}, "en_US"},
+		{[]string{"invalid!!", "fr"}, "fr"},
+		{nil, "en_US"},
+	}
+
+	for _, test := range tests {
+		l := ls.Match(test.requested...)
+		if l == nil {
+			t.Fatalf("Expected a Locale for %v", test.requested)
+		}
+		if tr := l.Get("language"); tr != test.expected {
+			t.Errorf("Expected '%s' for %v but got '%s'", test.expected, test.requested, tr)
+		}
+	}
+
+	if l := ls.Get("fr.UTF-8"); l == nil || l.Get("language") != "fr" {
+		t.Error("Expected the fr Locale")
+	}
+	if l := ls.Get("es"); l != nil {
+		t.Error("Expected no Locale for es")
+	}
+	if langs := ls.Languages(); len(langs) != 3 || langs[0] != "de_DE" {
+		t.Errorf("Unexpected languages %v", langs)
+	}
+
+	if l := NewLocales("fixtures/").Match("de"); l != nil {
+		t.Error("Expected no Locale from an empty pool")
+	}
+}
+
+func TestLocalesLoadAll(t *testing.T) {
+	src := &MemorySource{Files: map[string][]byte{
+		"de/LC_MESSAGES/default.po":  []byte("msgid \"Hi\"\nmsgstr \"Hallo\"\n"),
+		"de/LC_MESSAGES/errors.po":   []byte("msgid \"Oops\"\nmsgstr \"Hoppla\"\n"),
+		"fr/default.po":              []byte("msgid \"Hi\"\nmsgstr \"Salut\"\n"),
+		"fr/LC_MESSAGES/broken.json": []byte("{"),
+		"es/LC_MESSAGES/default.po":  []byte("msgid \"Hi\"\nmsgstr \"Hola\"\n"),
+	}}
+
+	ls := NewLocales("")
+	for _, lang := range []string{"de", "fr"} {
+		l := NewLocaleWithSource(src, lang)
+		l.SetExtensions("po", "json")
+		l.SetLazyLoading(true)
+		ls.Add(l)
+	}
+
+	err := ls.LoadAll(context.Background(), 4)
+	errs, ok := err.(LoadErrors)
+	if !ok || len(errs) != 1 {
+		t.Fatalf("Expected one LoadError, got %v", err)
+	}
+	if errs[0].Lang != "fr" || errs[0].Domain != "broken" {
+		t.Errorf("Unexpected LoadError %v", errs[0])
+	}
+
+	// Catalogs are loaded right away, even with lazy loading
+	de := ls.Get("de")
+	for _, dom := range []string{"default", "errors"} {
+		if _, ok := de.Domains[dom].(*Po); !ok {
+			t.Errorf("Expected domain %q to be loaded", dom)
+		}
+	}
+	oops := "Oops"
+	if tr := de.GetD("errors", oops); tr != "Hoppla" {
+		t.Errorf("Unexpected translation %q", tr)
+	}
+	hi := "Hi"
+	if tr := ls.Get("fr").GetD("default", hi); tr != "Salut" {
+		t.Errorf("Unexpected translation %q", tr)
+	}
+	if _, ok := de.Domains["LC_MESSAGES"]; ok {
+		t.Error("Folders shouldn't be loaded as domains")
+	}
+}
+
+func TestLocalesLoadAllCanceled(t *testing.T) {
+	src := &MemorySource{Files: map[string][]byte{
+		"de/default.po": []byte("msgid \"Hi\"\nmsgstr \"Hallo\"\n"),
+	}}
+
+	ls := NewLocales("")
+	ls.Add(NewLocaleWithSource(src, "de"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := ls.LoadAll(ctx, 1); err != context.Canceled {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
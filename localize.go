@@ -0,0 +1,129 @@
+package gotext
+
+import (
+	"errors"
+	"reflect"
+)
+
+// i18nTag is the struct tag used by LocalizeStruct
+const i18nTag = "i18n"
+
+/*
+LocalizeStruct walks the struct pointed by v, including nested structs, pointers and slices,
+and translates its string fields tagged with "i18n" using the default domain:
+
+  - Fields tagged with a msgid (`i18n:"Order shipped"`) are set to the translation of that msgid.
+  - Fields with an empty tag (`i18n:""`) are replaced by the translation of their current value.
+    It works for []string fields too, translating every element.
+
+Untagged and unexported fields are left unchanged.
+It returns an error when v isn't a non-nil pointer to a struct or slice.
+
+	type Status struct {
+		Title   string `i18n:"Order shipped"`
+		Message string `i18n:""`
+	}
+
+	s := Status{Message: "Your package is on its way"}
+	err := l.LocalizeStruct(&s)
+*/
+func (l *Locale) LocalizeStruct(v interface{}) error {
+	return localizeStruct(v, l.GetD, callerDomain(0, l.GetDomain()))
+}
+
+// LocalizeStruct translates the tagged fields of the struct pointed by v using the package configuration.
+// See Locale.LocalizeStruct.
+func LocalizeStruct(v interface{}) error {
+	return localizeStruct(v, GetD, callerDomain(0, GetDomain()))
+}
+
+func localizeStruct(v interface{}, get func(dom, str string, vars ...interface{}) string, dom string) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return errors.New("gotext: LocalizeStruct needs a non-nil pointer")
+	}
+	if k := rv.Elem().Kind(); k != reflect.Struct && k != reflect.Slice && k != reflect.Array {
+		return errors.New("gotext: LocalizeStruct needs a pointer to a struct or slice")
+	}
+
+	localizeValue(rv, func(str string) string {
+		return get(dom, str)
+	}, make(map[visit]bool))
+	return nil
+}
+
+// visit is a pointer followed by localizeValue, with the type it points to, as a struct and its first field
+// have the same address.
+type visit struct {
+	ptr uintptr
+	typ reflect.Type
+}
+
+// localizeValue looks for tagged string fields in rv and translates them with tr.
+// The pointers already followed are in seen, so values referenced twice, or referencing themselves, are walked once.
+func localizeValue(rv reflect.Value, tr func(string) string, seen map[visit]bool) {
+	switch rv.Kind() {
+	case reflect.Ptr:
+		if rv.IsNil() {
+			return
+		}
+		v := visit{rv.Pointer(), rv.Type()}
+		if seen[v] {
+			return
+		}
+		seen[v] = true
+		localizeValue(rv.Elem(), tr, seen)
+
+	case reflect.Interface:
+		if !rv.IsNil() {
+			localizeValue(rv.Elem(), tr, seen)
+		}
+
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			localizeValue(rv.Index(i), tr, seen)
+		}
+
+	case reflect.Struct:
+		t := rv.Type()
+		for i := 0; i < rv.NumField(); i++ {
+			field := rv.Field(i)
+			if !field.CanSet() {
+				continue
+			}
+
+			msgid, tagged := t.Field(i).Tag.Lookup(i18nTag)
+			if !tagged {
+				localizeValue(field, tr, seen)
+				continue
+			}
+			localizeField(field, msgid, tr, seen)
+		}
+	}
+}
+
+// localizeField translates a tagged field: msgid when it isn't empty, or its current value otherwise.
+func localizeField(field reflect.Value, msgid string, tr func(string) string, seen map[visit]bool) {
+	switch {
+	case field.Kind() == reflect.String:
+		if msgid == "" {
+			msgid = field.String()
+		}
+		if msgid != "" {
+			field.SetString(tr(msgid))
+		}
+
+	case msgid == "" && (field.Kind() == reflect.Slice || field.Kind() == reflect.Array) && field.Type().Elem().Kind() == reflect.String:
+		for i := 0; i < field.Len(); i++ {
+			if str := field.Index(i).String(); str != "" {
+				field.Index(i).SetString(tr(str))
+			}
+		}
+
+	case field.Kind() == reflect.Ptr && !field.IsNil() && field.Elem().Kind() == reflect.String:
+		localizeField(field.Elem(), msgid, tr, seen)
+
+	default:
+		localizeValue(field, tr, seen)
+	}
+}
@@ -0,0 +1,107 @@
+package gotext
+
+import (
+	"testing"
+)
+
+type localizeItem struct {
+	Label string `i18n:""`
+}
+
+type localizeDTO struct {
+	Title    string   `i18n:"Invoice"`
+	Message  string   `i18n:""`
+	Tags     []string `i18n:""`
+	Note     *string  `i18n:""`
+	Raw      string
+	Items    []localizeItem
+	Nested   *localizeItem
+	internal string `i18n:"Invoice"`
+}
+
+func TestLocalizeStruct(t *testing.T) {
+	src := &MemorySource{Files: map[string][]byte{"de/default.po": []byte(`
+msgid "Invoice"
+msgstr "Rechnung"
+
+msgid "Paid"
+msgstr "Bezahlt"
+
+msgid "Item"
+msgstr "Artikel"
+
+msgid "Open"
+msgstr "Offen"
+`)}}
+	l := NewLocaleWithSource(src, "de")
+	l.AddDomain("default")
+
+	note := "Open"
+	dto := localizeDTO{
+		Message: "Paid",
+		Tags:    []string{"Open", "Unknown"},
+		Note:    &note,
+		Raw:     "Paid",
+		Items:   []localizeItem{{"Item"}, {"Paid"}},
+		Nested:  &localizeItem{"Invoice"},
+	}
+	if err := l.LocalizeStruct(&dto); err != nil {
+		t.Fatal(err)
+	}
+
+	if dto.Title != "Rechnung" || dto.Message != "Bezahlt" || dto.Raw != "Paid" || dto.internal != "" {
+		t.Errorf("Unexpected fields %+v", dto)
+	}
+	if dto.Tags[0] != "Offen" || dto.Tags[1] != "Unknown" || note != "Offen" {
+		t.Errorf("Unexpected tags %v and note %s", dto.Tags, note)
+	}
+	if dto.Items[0].Label != "Artikel" || dto.Items[1].Label != "Bezahlt" || dto.Nested.Label != "Rechnung" {
+		t.Errorf("Unexpected nested values %+v %+v", dto.Items, dto.Nested)
+	}
+
+	items := []localizeItem{{"Item"}}
+	if err := l.LocalizeStruct(&items); err != nil || items[0].Label != "Artikel" {
+		t.Errorf("Unexpected slice result %v, %v", items, err)
+	}
+
+	if err := l.LocalizeStruct(dto); err == nil {
+		t.Error("Expected an error for a non pointer value")
+	}
+	if err := l.LocalizeStruct(&note); err == nil {
+		t.Error("Expected an error for a pointer to string")
+	}
+}
+
+type localizeNode struct {
+	Label    string `i18n:""`
+	Parent   *localizeNode
+	Children []*localizeNode
+}
+
+func TestLocalizeStructCycles(t *testing.T) {
+	src := &MemorySource{Files: map[string][]byte{"de/default.po": []byte(`
+msgid "Root"
+msgstr "Wurzel"
+
+msgid "Leaf"
+msgstr "Blatt"
+
+msgid "Blatt"
+msgstr "Translated twice"
+`)}}
+	l := NewLocaleWithSource(src, "de")
+	l.AddDomain("default")
+
+	root := &localizeNode{Label: "Root"}
+	leaf := &localizeNode{Label: "Leaf", Parent: root}
+	// The leaf is referenced twice, and the tree references itself
+	root.Children = []*localizeNode{leaf, leaf}
+	root.Parent = root
+
+	if err := l.LocalizeStruct(root); err != nil {
+		t.Fatal(err)
+	}
+	if root.Label != "Wurzel" || leaf.Label != "Blatt" {
+		t.Errorf("Unexpected labels %q and %q", root.Label, leaf.Label)
+	}
+}
@@ -0,0 +1,82 @@
+package gotext
+
+import (
+	"fmt"
+	"time"
+
+	"golang.org/x/text/currency"
+)
+
+// localized formats its value with the Locale's conventions for the %v and %s verbs, and as is for any other verb.
+type localized struct {
+	v    interface{}
+	text string
+}
+
+// Format implements fmt.Formatter.
+func (lv localized) Format(f fmt.State, verb rune) {
+	if verb == 'v' || verb == 's' {
+		fmt.Fprintf(f, formatDirective(f, 's'), lv.text)
+		return
+	}
+	fmt.Fprintf(f, formatDirective(f, verb), lv.v)
+}
+
+/*
+LocalizeVars returns vars wrapped so numbers, time.Time and currency.Amount values are formatted with the Locale's
+conventions (see FormatNumber, FormatDate and FormatCurrency) when the translation uses %v or %s for them:
+
+	// msgid "Your order of %v will arrive on %v"
+	// msgstr "Ihre Bestellung über %v kommt am %v an"
+	l.Get("Your order of %v will arrive on %v", l.LocalizeVars(currency.EUR.Amount(1234.5), eta)...)
+	// Ihre Bestellung über € 1.234,50 kommt am 24.12.2024 an
+
+Other verbs, like %d or %.2f, format the values as usual, so counts and explicit precisions keep their meaning.
+Values of other types are returned as is. See SetLocalizedVars to have the Get functions do it.
+*/
+func (l *Locale) LocalizeVars(vars ...interface{}) []interface{} {
+	var wrapped []interface{}
+	for i, v := range vars {
+		text, ok := l.localize(v)
+		if !ok {
+			continue
+		}
+		if wrapped == nil {
+			wrapped = append([]interface{}(nil), vars...)
+		}
+		wrapped[i] = localized{v, text}
+	}
+	if wrapped == nil {
+		return vars
+	}
+	return wrapped
+}
+
+// localize returns v formatted with the Locale's conventions, or false if v isn't a number, a date or an amount.
+func (l *Locale) localize(v interface{}) (string, bool) {
+	switch v := v.(type) {
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return l.FormatNumber(v), true
+	case time.Time:
+		return l.FormatDate(v), true
+	case currency.Amount:
+		return l.formatAmount(v), true
+	}
+	return "", false
+}
+
+// SetLocalizedVars sets whether the Get and Lookup functions of the Locale format their vars with LocalizeVars,
+// so call sites pass numbers, dates and amounts as they are. It's off by default.
+func (l *Locale) SetLocalizedVars(on bool) {
+	l.Lock()
+	l.localizedVars = on
+	l.Unlock()
+}
+
+// localVars returns vars wrapped by LocalizeVars when the Locale is set to do so. The Locale must be read locked.
+func (l *Locale) localVars(vars []interface{}) []interface{} {
+	if !l.localizedVars || len(vars) == 0 {
+		return vars
+	}
+	return l.LocalizeVars(vars...)
+}
@@ -0,0 +1,66 @@
+package gotext
+
+import (
+	"testing"
+	"time"
+
+	"golang.org/x/text/currency"
+)
+
+func TestLocaleLocalizeVars(t *testing.T) {
+	l := NewLocale("", "de")
+	date := time.Date(2024, 12, 24, 10, 0, 0, 0, time.UTC)
+
+	format := "%v, %s, %d, %.1f, %v, %v, %v"
+	vars := l.LocalizeVars(1234567, 1234.5, 1234, 1234.5, date, currency.EUR.Amount(1234.5), "Ana")
+	want := "1.234.567, 1.234,5, 1234, 1234.5, 24.12.2024, € 1.234,50, Ana"
+	if got := Printf(format, vars...); got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+
+	if got := Printf("[%8v]", l.LocalizeVars(1234)...); got != "[   1.234]" {
+		t.Errorf("unexpected padding %q", got)
+	}
+
+	plain := []interface{}{"Ana", true}
+	if got := l.LocalizeVars(plain...); &got[0] != &plain[0] {
+		t.Error("expected vars without localized values to be returned as is")
+	}
+}
+
+func TestLocaleSetLocalizedVars(t *testing.T) {
+	src := &MemorySource{Files: map[string][]byte{
+		"de/default.po": []byte("msgid \"Your order of %v will arrive on %v\"\n" +
+			"msgstr \"Ihre Bestellung über %v kommt am %v an\"\n\n" +
+			"msgid \"One file\"\nmsgid_plural \"%d files of %v bytes\"\n" +
+			"msgstr[0] \"Eine Datei\"\nmsgstr[1] \"%d Dateien mit %v Bytes\"\n"),
+	}}
+
+	l := NewLocaleWithSource(src, "de")
+	l.AddDomain("default")
+
+	order, files := "Your order of %v will arrive on %v", "%d files of %v bytes"
+	amount, date := currency.EUR.Amount(1234.5), time.Date(2024, 12, 24, 10, 0, 0, 0, time.UTC)
+	if got := l.Get(order, 1234, date); got != "Ihre Bestellung über 1234 kommt am 2024-12-24 10:00:00 +0000 UTC an" {
+		t.Errorf("expected raw values before SetLocalizedVars, got %q", got)
+	}
+
+	l.SetLocalizedVars(true)
+	for _, c := range []struct{ got, want string }{
+		{l.Get(order, amount, date), "Ihre Bestellung über € 1.234,50 kommt am 24.12.2024 an"},
+		{l.GetN("One file", files, 2000, 2000, 4096000), "2000 Dateien mit 4.096.000 Bytes"},
+		{l.Get("Untranslated %v", 10000), "Untranslated 10.000"},
+		{string(l.GetHTML("Total: <b>%v</b>", 10000)), "Total: <b>10.000</b>"},
+	} {
+		if c.got != c.want {
+			t.Errorf("expected %q, got %q", c.want, c.got)
+		}
+	}
+	if got, ok := l.Lookup(order, amount, date); !ok || got != "Ihre Bestellung über € 1.234,50 kommt am 24.12.2024 an" {
+		t.Errorf("unexpected lookup %q, %v", got, ok)
+	}
+
+	if got := l.Clone().Get("Untranslated %v", 10000); got != "Untranslated 10.000" {
+		t.Errorf("expected clones to keep the setting, got %q", got)
+	}
+}
@@ -0,0 +1,143 @@
+package gotext
+
+import (
+	"context"
+	"runtime/trace"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// LockStats reports how many times a class of locks (all Locale locks, or all Domain locks) has been acquired,
+// and the total time callers spent waiting to acquire them.
+type LockStats struct {
+	ReadLocks  uint64
+	WriteLocks uint64
+	ReadWait   time.Duration
+	WriteWait  time.Duration
+}
+
+// lockCounters is the atomic storage behind LockStats.
+// Only used in package level variables, which keeps the 64 bit fields aligned for atomic access.
+type lockCounters struct {
+	readLocks  uint64
+	writeLocks uint64
+	readWait   int64
+	writeWait  int64
+}
+
+var (
+	lockStatsEnabled int32
+
+	localeLocks lockCounters
+	domainLocks lockCounters
+)
+
+// EnableLockStats turns lock instrumentation on or off for all Locale and Domain objects.
+// It's disabled by default, and costs a single atomic load per lock operation while disabled.
+// When enabled and a runtime/trace is active, lock waits also show up as trace regions.
+func EnableLockStats(enabled bool) {
+	var v int32
+	if enabled {
+		v = 1
+	}
+	atomic.StoreInt32(&lockStatsEnabled, v)
+}
+
+// LocaleLockStats returns the lock counters accumulated by all Locale objects since the last ResetLockStats.
+func LocaleLockStats() LockStats {
+	return localeLocks.stats()
+}
+
+// DomainLockStats returns the lock counters accumulated by all Domain objects since the last ResetLockStats.
+func DomainLockStats() LockStats {
+	return domainLocks.stats()
+}
+
+// ResetLockStats sets all lock counters back to zero.
+func ResetLockStats() {
+	localeLocks.reset()
+	domainLocks.reset()
+}
+
+func (c *lockCounters) stats() LockStats {
+	return LockStats{
+		ReadLocks:  atomic.LoadUint64(&c.readLocks),
+		WriteLocks: atomic.LoadUint64(&c.writeLocks),
+		ReadWait:   time.Duration(atomic.LoadInt64(&c.readWait)),
+		WriteWait:  time.Duration(atomic.LoadInt64(&c.writeWait)),
+	}
+}
+
+func (c *lockCounters) reset() {
+	atomic.StoreUint64(&c.readLocks, 0)
+	atomic.StoreUint64(&c.writeLocks, 0)
+	atomic.StoreInt64(&c.readWait, 0)
+	atomic.StoreInt64(&c.writeWait, 0)
+}
+
+func (c *lockCounters) lock(m *sync.RWMutex, region string) {
+	if atomic.LoadInt32(&lockStatsEnabled) == 0 {
+		m.Lock()
+		return
+	}
+
+	var r *trace.Region
+	if trace.IsEnabled() {
+		r = trace.StartRegion(context.Background(), region)
+	}
+	start := time.Now()
+	m.Lock()
+	atomic.AddInt64(&c.writeWait, int64(time.Since(start)))
+	atomic.AddUint64(&c.writeLocks, 1)
+	if r != nil {
+		r.End()
+	}
+}
+
+func (c *lockCounters) rlock(m *sync.RWMutex, region string) {
+	if atomic.LoadInt32(&lockStatsEnabled) == 0 {
+		m.RLock()
+		return
+	}
+
+	var r *trace.Region
+	if trace.IsEnabled() {
+		r = trace.StartRegion(context.Background(), region)
+	}
+	start := time.Now()
+	m.RLock()
+	atomic.AddInt64(&c.readWait, int64(time.Since(start)))
+	atomic.AddUint64(&c.readLocks, 1)
+	if r != nil {
+		r.End()
+	}
+}
+
+// localeMutex is the sync.RWMutex used by Locale objects, instrumented by EnableLockStats.
+type localeMutex struct {
+	sync.RWMutex
+}
+
+// Lock locks the Locale for writing.
+func (m *localeMutex) Lock() {
+	localeLocks.lock(&m.RWMutex, "gotext.Locale.Lock")
+}
+
+// RLock locks the Locale for reading.
+func (m *localeMutex) RLock() {
+	localeLocks.rlock(&m.RWMutex, "gotext.Locale.RLock")
+}
+
+// domainMutex is the sync.RWMutex used by Domain objects, instrumented by EnableLockStats.
+type domainMutex struct {
+	sync.RWMutex
+}
+
+func (m *domainMutex) Lock() {
+	domainLocks.lock(&m.RWMutex, "gotext.Domain.Lock")
+}
+
+func (m *domainMutex) RLock() {
+	domainLocks.rlock(&m.RWMutex, "gotext.Domain.RLock")
+}
@@ -0,0 +1,40 @@
+package gotext
+
+import (
+	"testing"
+)
+
+func TestLockStats(t *testing.T) {
+	EnableLockStats(true)
+	defer EnableLockStats(false)
+	ResetLockStats()
+
+	l := NewLocale("fixtures/", "en_US")
+	l.AddDomain("default")
+	l.Get("My text")
+
+	locale := LocaleLockStats()
+	if locale.WriteLocks == 0 {
+		t.Error("Expected Locale write locks to be counted")
+	}
+	if locale.ReadLocks < 2 {
+		t.Errorf("Expected at least 2 Locale read locks but got %d", locale.ReadLocks)
+	}
+
+	domain := DomainLockStats()
+	if domain.WriteLocks == 0 || domain.ReadLocks == 0 {
+		t.Errorf("Expected Domain locks to be counted but got %+v", domain)
+	}
+
+	ResetLockStats()
+	if stats := LocaleLockStats(); stats != (LockStats{}) {
+		t.Errorf("Expected empty stats after reset but got %+v", stats)
+	}
+
+	// Disabled instrumentation doesn't count
+	EnableLockStats(false)
+	l.Get("My text")
+	if stats := LocaleLockStats(); stats.ReadLocks != 0 {
+		t.Errorf("Expected no read locks counted while disabled but got %d", stats.ReadLocks)
+	}
+}
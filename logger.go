@@ -0,0 +1,45 @@
+package gotext
+
+import (
+	"sync/atomic"
+)
+
+/*
+Logger receives the problems and events that gotext can't return as errors: parse warnings, malformed
+catalog entries, plural forms that can't be resolved and catalog reloads.
+
+Messages come with alternating key and value arguments, like the log/slog package uses, so a *slog.Logger
+can be used directly:
+
+	gotext.SetLogger(slog.Default())
+*/
+type Logger interface {
+	Info(msg string, args ...interface{})
+	Warn(msg string, args ...interface{})
+}
+
+// loggerHolder keeps the concrete type stored in the atomic.Value the same.
+type loggerHolder struct {
+	l Logger
+}
+
+var currentLogger atomic.Value
+
+// SetLogger sets the Logger used by all Locale and Domain objects. A nil l turns logging off, which is the default.
+func SetLogger(l Logger) {
+	currentLogger.Store(loggerHolder{l})
+}
+
+// logInfo logs an event to the current Logger, if any.
+func logInfo(msg string, args ...interface{}) {
+	if h, ok := currentLogger.Load().(loggerHolder); ok && h.l != nil {
+		h.l.Info(msg, args...)
+	}
+}
+
+// logWarn logs a problem to the current Logger, if any.
+func logWarn(msg string, args ...interface{}) {
+	if h, ok := currentLogger.Load().(loggerHolder); ok && h.l != nil {
+		h.l.Warn(msg, args...)
+	}
+}
@@ -0,0 +1,82 @@
+package gotext
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+)
+
+type testLogger struct {
+	mu    sync.Mutex
+	lines []string
+}
+
+func (l *testLogger) log(level, msg string, args ...interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.lines = append(l.lines, level+" "+msg+" "+fmt.Sprint(args...))
+}
+
+func (l *testLogger) Info(msg string, args ...interface{}) { l.log("INFO", msg, args...) }
+func (l *testLogger) Warn(msg string, args ...interface{}) { l.log("WARN", msg, args...) }
+
+func (l *testLogger) has(prefix string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, line := range l.lines {
+		if strings.HasPrefix(line, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestSetLogger(t *testing.T) {
+	log := &testLogger{}
+	SetLogger(log)
+	defer SetLogger(nil)
+
+	po := NewPo()
+	po.Parse([]byte(`
+msgid ""
+msgstr ""
+"Language: de\n"
+"Plural-Forms: nplurals=2; plural=(n != 1);\n"
+
+msgid "One"
+msgid_plural "Many"
+msgstr[0] "Eins"
+
+this is not PO
+`))
+	if !log.has("WARN gotext: skipped malformed PO line") {
+		t.Errorf("Expected a malformed line warning, got %v", log.lines)
+	}
+
+	one := "One"
+	po.GetN(one, "Many", 5)
+	if !log.has("WARN gotext: plural form not defined by the translation") {
+		t.Errorf("Expected a plural warning, got %v", log.lines)
+	}
+
+	l := NewLocaleWithSource(&MemorySource{Files: map[string][]byte{
+		"de/broken.json": []byte("{"),
+	}}, "de")
+	l.SetExtensions("json")
+	l.AddDomain("broken")
+	l.AddDomain("missing")
+	if !log.has("WARN gotext: catalog parse failed") {
+		t.Errorf("Expected a parse warning, got %v", log.lines)
+	}
+	if !log.has("WARN gotext: no catalog loaded") {
+		t.Errorf("Expected a missing catalog warning, got %v", log.lines)
+	}
+}
+
+func TestSetLoggerNil(t *testing.T) {
+	SetLogger(nil)
+	// Must not panic
+	logWarn("test")
+	logInfo("test")
+}
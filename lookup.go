@@ -0,0 +1,152 @@
+package gotext
+
+// translatedChecker is implemented by the Translators whose Domain doesn't hold their entries,
+// so they tell themselves whether they have a translation.
+type translatedChecker interface {
+	translated(str, ctx string, withCtx bool) bool
+}
+
+// translated reports whether tr has a translated entry for str, in the context ctx when withCtx is true.
+func translated(tr Translator, str, ctx string, withCtx bool) bool {
+	if c, ok := tr.(translatedChecker); ok {
+		return c.translated(str, ctx, withCtx)
+	}
+	return tr.GetDomain().translated(str, ctx, withCtx)
+}
+
+// translated reports whether the Domain has an entry for str with a non-empty msgstr, matching exactly
+// or through its normalization, in the context ctx when withCtx is true. Fuzzy matches don't count.
+// Nothing is reported to the Metrics, as the lookup itself is.
+func (do *Domain) translated(str, ctx string, withCtx bool) bool {
+	do.trMutex.RLock()
+	defer do.trMutex.RUnlock()
+
+	trs, normalized := do.translations, do.normalized
+	if withCtx {
+		trs, normalized = do.contexts[ctx], do.normalizedC[ctx]
+	}
+
+	tr, ok := trs[str]
+	if !ok && do.normalization != 0 {
+		tr, ok = normalized[do.normalization.apply(str)]
+	}
+	return ok && tr.IsTranslated()
+}
+
+func (mo *IndexedMo) translated(str, ctx string, withCtx bool) bool {
+	key := str
+	if withCtx {
+		key = ctx + EotSeparator + str
+	}
+	tr, ok := mo.translation(key)
+	return ok && tr.IsTranslated()
+}
+
+func (lt *lazyTranslator) translated(str, ctx string, withCtx bool) bool {
+	return translated(lt.translator(), str, ctx, withCtx)
+}
+
+// Lookup works like Get, but also reports whether a translation was found, so callers can tell the
+// untranslated string apart from a translation equal to it, and implement their own fallbacks.
+// Entries with an empty msgstr and fuzzy matches (see EnableFuzzyMatch) aren't translations.
+// In the source language (see SetSourceLanguage), every string is found.
+func (l *Locale) Lookup(str string, vars ...interface{}) (string, bool) {
+	return l.LookupD(callerDomain(0, l.GetDomain()), str, vars...)
+}
+
+// LookupN works like GetN, but also reports whether a translation was found. See Lookup.
+func (l *Locale) LookupN(str, plural string, n int, vars ...interface{}) (string, bool) {
+	return l.LookupND(callerDomain(0, l.GetDomain()), str, plural, n, vars...)
+}
+
+// LookupD works like GetD, but also reports whether a translation was found. See Lookup.
+func (l *Locale) LookupD(dom, str string, vars ...interface{}) (string, bool) {
+	l.RLock()
+	defer l.RUnlock()
+	vars = l.localVars(vars)
+
+	if l.inSourceLanguage() {
+		return l.output(str, Printf(str, vars...)), true
+	}
+
+	if tr := l.translator(dom, str, "", false); tr != nil {
+		return l.output(str, tr.Get(str, vars...)), translated(tr, str, "", false)
+	}
+
+	metrics().Lookup(l.lang, dom, LookupMiss)
+
+	return l.output(str, Printf(str, vars...)), false
+}
+
+// LookupND works like GetND, but also reports whether a translation was found. See Lookup.
+func (l *Locale) LookupND(dom, str, plural string, n int, vars ...interface{}) (string, bool) {
+	l.RLock()
+	defer l.RUnlock()
+	vars = l.localVars(vars)
+
+	if l.inSourceLanguage() {
+		return l.output(str, sourceN(str, plural, n, vars...)), true
+	}
+
+	if tr := l.translator(dom, str, "", false); tr != nil {
+		return l.output(str, tr.GetN(str, plural, n, vars...)), translated(tr, str, "", false)
+	}
+
+	metrics().Lookup(l.lang, dom, LookupMiss)
+
+	if n == 1 {
+		return l.output(str, Printf(str, vars...)), false
+	}
+	return l.output(str, Printf(plural, vars...)), false
+}
+
+// LookupC works like GetC, but also reports whether a translation was found. See Lookup.
+func (l *Locale) LookupC(str, ctx string, vars ...interface{}) (string, bool) {
+	return l.LookupDC(callerDomain(0, l.GetDomain()), str, ctx, vars...)
+}
+
+// LookupNC works like GetNC, but also reports whether a translation was found. See Lookup.
+func (l *Locale) LookupNC(str, plural string, n int, ctx string, vars ...interface{}) (string, bool) {
+	return l.LookupNDC(callerDomain(0, l.GetDomain()), str, plural, n, ctx, vars...)
+}
+
+// LookupDC works like GetDC, but also reports whether a translation was found. See Lookup.
+func (l *Locale) LookupDC(dom, str, ctx string, vars ...interface{}) (string, bool) {
+	l.RLock()
+	defer l.RUnlock()
+	vars = l.localVars(vars)
+
+	if l.inSourceLanguage() {
+		return l.output(str, Printf(str, vars...)), true
+	}
+
+	if tr := l.translator(dom, str, ctx, true); tr != nil {
+		return l.output(str, tr.GetC(str, ctx, vars...)), translated(tr, str, ctx, true)
+	}
+
+	metrics().Lookup(l.lang, dom, LookupMiss)
+
+	return l.output(str, Printf(str, vars...)), false
+}
+
+// LookupNDC works like GetNDC, but also reports whether a translation was found. See Lookup.
+func (l *Locale) LookupNDC(dom, str, plural string, n int, ctx string, vars ...interface{}) (string, bool) {
+	l.RLock()
+	defer l.RUnlock()
+	vars = l.localVars(vars)
+
+	if l.inSourceLanguage() {
+		return l.output(str, sourceN(str, plural, n, vars...)), true
+	}
+
+	if tr := l.translator(dom, str, ctx, true); tr != nil {
+		return l.output(str, tr.GetNC(str, plural, n, ctx, vars...)), translated(tr, str, ctx, true)
+	}
+
+	metrics().Lookup(l.lang, dom, LookupMiss)
+
+	if n == 1 {
+		return l.output(str, Printf(str, vars...)), false
+	}
+	return l.output(str, Printf(plural, vars...)), false
+}
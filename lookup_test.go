@@ -0,0 +1,112 @@
+package gotext
+
+import (
+	"testing"
+)
+
+const lookupPo = `msgid ""
+msgstr ""
+"Language: es\n"
+"Plural-Forms: nplurals=2; plural=(n != 1);\n"
+
+msgid "Hello"
+msgstr "Hola"
+
+msgid "OK"
+msgstr "OK"
+
+msgid "Untranslated"
+msgstr ""
+
+msgid "%d file"
+msgid_plural "%d files"
+msgstr[0] "%d archivo"
+msgstr[1] "%d archivos"
+
+msgctxt "menu"
+msgid "Open"
+msgstr "Abrir"
+`
+
+func TestLocaleLookup(t *testing.T) {
+	l := NewLocale("", "es")
+	if err := l.AddDomainBytes("default", []byte(lookupPo), FormatPO); err != nil {
+		t.Fatal(err)
+	}
+
+	hello, ok, untranslated, missing := "Hello", "OK", "Untranslated", "Missing"
+	for _, tc := range []struct {
+		str, want string
+		found     bool
+	}{
+		{hello, "Hola", true},
+		{ok, "OK", true},
+		{untranslated, untranslated, false},
+		{missing, missing, false},
+	} {
+		if got, found := l.Lookup(tc.str); got != tc.want || found != tc.found {
+			t.Errorf("Lookup(%q) = %q, %v; want %q, %v", tc.str, got, found, tc.want, tc.found)
+		}
+	}
+
+	file, files := "%d file", "%d files"
+	if got, found := l.LookupN(file, files, 3, 3); got != "3 archivos" || !found {
+		t.Errorf("unexpected plural lookup %q, %v", got, found)
+	}
+	if got, found := l.LookupN(missing, "Missings", 3); got != "Missings" || found {
+		t.Errorf("unexpected plural lookup %q, %v", got, found)
+	}
+
+	open := "Open"
+	if got, found := l.LookupC(open, "menu"); got != "Abrir" || !found {
+		t.Errorf("unexpected context lookup %q, %v", got, found)
+	}
+	if got, found := l.LookupC(open, "toolbar"); got != open || found {
+		t.Errorf("unexpected context lookup %q, %v", got, found)
+	}
+	if got, found := l.LookupC(hello, ""); got != hello || found {
+		t.Errorf("the empty context shouldn't match entries without context, got %q, %v", got, found)
+	}
+
+	if got, found := l.LookupD("other", hello); got != hello || found {
+		t.Errorf("unexpected lookup in an unknown domain %q, %v", got, found)
+	}
+	if got, found := l.LookupNDC("other", file, files, 1, "menu", 1); got != "1 file" || found {
+		t.Errorf("unexpected lookup in an unknown domain %q, %v", got, found)
+	}
+}
+
+func TestLocaleLookupFallbacks(t *testing.T) {
+	l := NewLocale("", "es")
+	l.SetNormalization(NormalizeSpace)
+	l.EnableFuzzyMatch(0.8)
+	if err := l.AddDomainBytes("default", []byte(lookupPo), FormatPO); err != nil {
+		t.Fatal(err)
+	}
+
+	// Normalized msgids are the same message
+	spaced := " Hello "
+	if got, found := l.Lookup(spaced); got != "Hola" || !found {
+		t.Errorf("unexpected normalized lookup %q, %v", got, found)
+	}
+	// Fuzzy matches are a fallback
+	typo := "Helo"
+	if got, found := l.Lookup(typo); got != "Hola" || found {
+		t.Errorf("unexpected fuzzy lookup %q, %v", got, found)
+	}
+}
+
+func TestLocaleLookupIndexedMo(t *testing.T) {
+	l := NewLocale("fixtures/", "en_US")
+	l.SetMoOnDemand(true, 8)
+	l.SetExtensions("mo")
+	l.AddDomain("default")
+
+	myText, missing := "My text", "Missing"
+	if got, found := l.Lookup(myText); got != translatedText || !found {
+		t.Errorf("unexpected lookup %q, %v", got, found)
+	}
+	if got, found := l.Lookup(missing); got != missing || found {
+		t.Errorf("unexpected lookup %q, %v", got, found)
+	}
+}
@@ -0,0 +1,113 @@
+package gotext
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// LookupResult tells how a lookup was resolved, for Metrics.
+type LookupResult int
+
+const (
+	// LookupHit is a lookup that found a translation for its exact msgid.
+	LookupHit LookupResult = iota
+	// LookupMiss is a lookup that found no translation, so it returned the source string.
+	LookupMiss
+	// LookupFallback is a lookup that found a translation for another msgid, by normalization or fuzzy matching.
+	LookupFallback
+)
+
+func (r LookupResult) String() string {
+	switch r {
+	case LookupHit:
+		return "hit"
+	case LookupMiss:
+		return "miss"
+	case LookupFallback:
+		return "fallback"
+	}
+	return "unknown"
+}
+
+/*
+Metrics receives the events of all Locale and Domain objects, to track translation coverage and catalog
+loading times in production. Its methods are called from the lookup and loading paths, by many goroutines,
+so they must be fast and safe for concurrent use.
+
+Domains loaded by a Locale report its language and their domain name. Others report their Language header
+and an empty domain name.
+
+With Prometheus, it can be implemented like:
+
+	type promMetrics struct {
+		lookups *prometheus.CounterVec   // labels: lang, domain, result
+		parses  *prometheus.HistogramVec // labels: lang, domain, format
+	}
+
+	func (m promMetrics) Lookup(lang, dom string, r gotext.LookupResult) {
+		m.lookups.WithLabelValues(lang, dom, r.String()).Inc()
+	}
+
+	func (m promMetrics) Parse(lang, dom, format string, d time.Duration, err error) {
+		m.parses.WithLabelValues(lang, dom, format).Observe(d.Seconds())
+	}
+
+	gotext.SetMetrics(promMetrics{lookups, parses})
+*/
+type Metrics interface {
+	// Lookup is called for every lookup of a msgid, with how it was resolved.
+	Lookup(lang, dom string, r LookupResult)
+	// Parse is called for every catalog parsed by a Locale, with the format name, the time it took and
+	// the error that made it fail, if any.
+	Parse(lang, dom, format string, d time.Duration, err error)
+}
+
+// NopMetrics is a Metrics implementation that does nothing. It's the default.
+type NopMetrics struct{}
+
+// Lookup implements Metrics.
+func (NopMetrics) Lookup(lang, dom string, r LookupResult) {}
+
+// Parse implements Metrics.
+func (NopMetrics) Parse(lang, dom, format string, d time.Duration, err error) {}
+
+// metricsHolder keeps the concrete type stored in the atomic.Value the same.
+type metricsHolder struct {
+	m Metrics
+}
+
+var currentMetrics atomic.Value
+
+// SetMetrics sets the Metrics receiving the events of all Locale and Domain objects.
+// A nil m sets NopMetrics back.
+func SetMetrics(m Metrics) {
+	if m == nil {
+		m = NopMetrics{}
+	}
+	currentMetrics.Store(metricsHolder{m})
+}
+
+// metrics returns the current Metrics.
+func metrics() Metrics {
+	if h, ok := currentMetrics.Load().(metricsHolder); ok {
+		return h.m
+	}
+	return NopMetrics{}
+}
+
+// setMetricsLabels sets the language and domain name reported by the lookups of the Domain.
+func (do *Domain) setMetricsLabels(lang, dom string) {
+	do.trMutex.Lock()
+	do.metricsLang = lang
+	do.metricsDomain = dom
+	do.trMutex.Unlock()
+}
+
+// observeLookup reports a lookup of the Domain to the current Metrics. The Domain must be locked.
+func (do *Domain) observeLookup(r LookupResult) {
+	lang := do.metricsLang
+	if lang == "" {
+		lang = do.Language
+	}
+	metrics().Lookup(lang, do.metricsDomain, r)
+}
@@ -0,0 +1,77 @@
+package gotext
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+type testMetrics struct {
+	mu      sync.Mutex
+	lookups map[string]int
+	parses  []string
+}
+
+func (m *testMetrics) Lookup(lang, dom string, r LookupResult) {
+	m.mu.Lock()
+	m.lookups[lang+"/"+dom+"/"+r.String()]++
+	m.mu.Unlock()
+}
+
+func (m *testMetrics) Parse(lang, dom, format string, d time.Duration, err error) {
+	m.mu.Lock()
+	m.parses = append(m.parses, lang+"/"+dom+"/"+format)
+	m.mu.Unlock()
+}
+
+func TestSetMetrics(t *testing.T) {
+	m := &testMetrics{lookups: make(map[string]int)}
+	SetMetrics(m)
+	defer SetMetrics(nil)
+
+	src := &MemorySource{Files: map[string][]byte{
+		"de/default.po": []byte("msgid \"Hello\"\nmsgstr \"Hallo\"\n\nmsgid \"Bye\"\nmsgstr \"\"\n\nmsgid \"Save file\"\nmsgstr \"Datei speichern\"\n"),
+	}}
+	l := NewLocaleWithSource(src, "de")
+	l.EnableFuzzyMatch(0.8)
+	l.AddDomain("default")
+
+	hello, bye, unknown, save := "Hello", "Bye", "Unknown", "Save files"
+	l.Get(hello)
+	l.Get(hello)
+	l.Get(bye)
+	l.Get(unknown)
+	l.Get(save)
+	l.GetD("other", hello)
+
+	want := map[string]int{
+		"de/default/hit":      2,
+		"de/default/miss":     2,
+		"de/default/fallback": 1,
+		"de/other/miss":       1,
+	}
+	for k, n := range want {
+		if m.lookups[k] != n {
+			t.Errorf("Expected %d %s lookups, got %d", n, k, m.lookups[k])
+		}
+	}
+	if len(m.parses) != 1 || m.parses[0] != "de/default/po" {
+		t.Errorf("Unexpected parses %v", m.parses)
+	}
+
+	// Translators added by hand report the domain name too
+	po := NewPo()
+	po.Parse([]byte("msgid \"Hello\"\nmsgstr \"Hallo\"\n"))
+	l.AddTranslator("manual", po)
+	l.GetD("manual", hello)
+	if m.lookups["de/manual/hit"] != 1 {
+		t.Errorf("Expected a hit in the manual domain, got %v", m.lookups)
+	}
+}
+
+func TestNopMetrics(t *testing.T) {
+	SetMetrics(nil)
+	if _, ok := metrics().(NopMetrics); !ok {
+		t.Errorf("Expected NopMetrics, got %T", metrics())
+	}
+}
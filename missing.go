@@ -0,0 +1,108 @@
+package gotext
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrMissingTranslation is matched, with errors.Is, by the errors returned by the TryGet functions.
+var ErrMissingTranslation = errors.New("gotext: missing translation")
+
+// MissingError is returned by the TryGet functions, and the panic value of the Get functions in strict mode,
+// for a message without translation.
+type MissingError struct {
+	Lang    string
+	Domain  string
+	Context string
+	MsgID   string
+}
+
+func (e *MissingError) Error() string {
+	if e.Context != "" {
+		return fmt.Sprintf("gotext: missing %s translation of %q in context %q of domain %q", e.Lang, e.MsgID, e.Context, e.Domain)
+	}
+	return fmt.Sprintf("gotext: missing %s translation of %q in domain %q", e.Lang, e.MsgID, e.Domain)
+}
+
+// Is reports whether target is ErrMissingTranslation.
+func (e *MissingError) Is(target error) bool {
+	return target == ErrMissingTranslation
+}
+
+/*
+SetStrictMode makes the Get, GetN, GetC, GetNC, GetOrdinal and GetRange functions of the Locale, and their D variants, panic
+with a *MissingError when a message isn't translated, as reported by Lookup, instead of returning it untranslated.
+It's meant for tests, so CI catches unexternalized or untranslated strings before a release:
+
+	func TestMain(m *testing.M) {
+		l.SetStrictMode(true)
+		os.Exit(m.Run())
+	}
+
+Use the TryGet functions to handle missing translations as errors instead.
+*/
+func (l *Locale) SetStrictMode(on bool) {
+	l.Lock()
+	l.strict = on
+	l.Unlock()
+}
+
+// mustTranslate panics with a *MissingError if dom doesn't translate str. The Locale must be locked.
+func (l *Locale) mustTranslate(dom, str, ctx string, withCtx bool) {
+	if tr := l.translator(dom, str, ctx, withCtx); tr == nil || !translated(tr, str, ctx, withCtx) {
+		panic(&MissingError{Lang: l.lang, Domain: dom, Context: ctx, MsgID: str})
+	}
+}
+
+// missing returns a *MissingError for str unless found is true.
+func (l *Locale) missing(found bool, dom, str, ctx string) error {
+	if found {
+		return nil
+	}
+	return &MissingError{Lang: l.GetLanguage(), Domain: dom, Context: ctx, MsgID: str}
+}
+
+// TryGet works like Get, but also returns a *MissingError, matching ErrMissingTranslation,
+// when the message isn't translated (see Lookup). The untranslated string is returned with it.
+func (l *Locale) TryGet(str string, vars ...interface{}) (string, error) {
+	return l.TryGetD(callerDomain(0, l.GetDomain()), str, vars...)
+}
+
+// TryGetN works like GetN, but also returns an error when the message isn't translated. See TryGet.
+func (l *Locale) TryGetN(str, plural string, n int, vars ...interface{}) (string, error) {
+	return l.TryGetND(callerDomain(0, l.GetDomain()), str, plural, n, vars...)
+}
+
+// TryGetD works like GetD, but also returns an error when the message isn't translated. See TryGet.
+func (l *Locale) TryGetD(dom, str string, vars ...interface{}) (string, error) {
+	tr, found := l.LookupD(dom, str, vars...)
+	return tr, l.missing(found, dom, str, "")
+}
+
+// TryGetND works like GetND, but also returns an error when the message isn't translated. See TryGet.
+func (l *Locale) TryGetND(dom, str, plural string, n int, vars ...interface{}) (string, error) {
+	tr, found := l.LookupND(dom, str, plural, n, vars...)
+	return tr, l.missing(found, dom, str, "")
+}
+
+// TryGetC works like GetC, but also returns an error when the message isn't translated. See TryGet.
+func (l *Locale) TryGetC(str, ctx string, vars ...interface{}) (string, error) {
+	return l.TryGetDC(callerDomain(0, l.GetDomain()), str, ctx, vars...)
+}
+
+// TryGetNC works like GetNC, but also returns an error when the message isn't translated. See TryGet.
+func (l *Locale) TryGetNC(str, plural string, n int, ctx string, vars ...interface{}) (string, error) {
+	return l.TryGetNDC(callerDomain(0, l.GetDomain()), str, plural, n, ctx, vars...)
+}
+
+// TryGetDC works like GetDC, but also returns an error when the message isn't translated. See TryGet.
+func (l *Locale) TryGetDC(dom, str, ctx string, vars ...interface{}) (string, error) {
+	tr, found := l.LookupDC(dom, str, ctx, vars...)
+	return tr, l.missing(found, dom, str, ctx)
+}
+
+// TryGetNDC works like GetNDC, but also returns an error when the message isn't translated. See TryGet.
+func (l *Locale) TryGetNDC(dom, str, plural string, n int, ctx string, vars ...interface{}) (string, error) {
+	tr, found := l.LookupNDC(dom, str, plural, n, ctx, vars...)
+	return tr, l.missing(found, dom, str, ctx)
+}
@@ -0,0 +1,82 @@
+package gotext
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestLocaleTryGet(t *testing.T) {
+	l := NewLocale("", "es")
+	if err := l.AddDomainBytes("default", []byte(lookupPo), FormatPO); err != nil {
+		t.Fatal(err)
+	}
+
+	hello, untranslated, missing := "Hello", "Untranslated", "Missing %d"
+	if tr, err := l.TryGet(hello); err != nil || tr != "Hola" {
+		t.Errorf("unexpected result %q, %v", tr, err)
+	}
+
+	tr, err := l.TryGet(missing, 3)
+	if tr != "Missing 3" || !errors.Is(err, ErrMissingTranslation) {
+		t.Errorf("unexpected result %q, %v", tr, err)
+	}
+	var merr *MissingError
+	if !errors.As(err, &merr) || merr.Lang != "es" || merr.Domain != "default" || merr.MsgID != missing {
+		t.Errorf("unexpected error %#v", err)
+	}
+	if _, err := l.TryGet(untranslated); !errors.Is(err, ErrMissingTranslation) {
+		t.Errorf("expected an error for an empty msgstr, got %v", err)
+	}
+
+	file, files := "%d file", "%d files"
+	if tr, err := l.TryGetN(file, files, 2, 2); err != nil || tr != "2 archivos" {
+		t.Errorf("unexpected result %q, %v", tr, err)
+	}
+	if tr, err := l.TryGetC("Open", "menu"); err != nil || tr != "Abrir" {
+		t.Errorf("unexpected result %q, %v", tr, err)
+	}
+	_, err = l.TryGetC("Open", "toolbar")
+	if !errors.As(err, &merr) || merr.Context != "toolbar" {
+		t.Errorf("unexpected error %v", err)
+	}
+	if _, err := l.TryGetNDC("unknown", file, files, 2, "menu", 2); !errors.Is(err, ErrMissingTranslation) {
+		t.Errorf("expected an error for an unknown domain, got %v", err)
+	}
+}
+
+func TestLocaleStrictMode(t *testing.T) {
+	l := NewLocale("", "es")
+	if err := l.AddDomainBytes("default", []byte(lookupPo), FormatPO); err != nil {
+		t.Fatal(err)
+	}
+	l.SetStrictMode(true)
+
+	if tr := l.Get("Hello"); tr != "Hola" {
+		t.Errorf("unexpected translation %q", tr)
+	}
+	if tr := l.GetC("Open", "menu"); tr != "Abrir" {
+		t.Errorf("unexpected translation %q", tr)
+	}
+
+	for name, get := range map[string]func(){
+		"Get":   func() { l.Get("Missing") },
+		"GetN":  func() { l.GetN("Missing", "Missings", 2) },
+		"GetD":  func() { l.GetD("unknown", "Hello") },
+		"GetNC": func() { l.GetNC("%d file", "%d files", 2, "menu", 2) },
+	} {
+		func() {
+			defer func() {
+				if err, ok := recover().(error); !ok || !errors.Is(err, ErrMissingTranslation) {
+					t.Errorf("%s: expected a *MissingError panic, got %v", name, err)
+				}
+			}()
+			get()
+		}()
+	}
+
+	// The Locale is still usable after a panic
+	l.SetStrictMode(false)
+	if tr := l.Get("Missing"); tr != "Missing" {
+		t.Errorf("unexpected translation %q", tr)
+	}
+}
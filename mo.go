@@ -7,7 +7,11 @@ package gotext
 
 import (
 	"bytes"
+	"context"
 	"encoding/binary"
+	"fmt"
+	"sort"
+	"strings"
 )
 
 const (
@@ -83,6 +87,39 @@ func (mo *Mo) GetNC(str, plural string, n int, ctx string, vars ...interface{})
 	return mo.domain.GetNC(str, plural, n, ctx, vars...)
 }
 
+func (mo *Mo) GetOrdinal(str string, n int, vars ...interface{}) string {
+	return mo.domain.GetOrdinal(str, n, vars...)
+}
+
+func (mo *Mo) GetRange(str, plural string, from, to int, vars ...interface{}) string {
+	return mo.domain.GetRange(str, plural, from, to, vars...)
+}
+
+func (mo *Mo) SetProgressFunc(fn func(ParseProgress)) {
+	mo.domain.SetProgressFunc(fn)
+}
+
+// SetHeader sets a catalog header. See Domain.SetHeader.
+func (mo *Mo) SetHeader(key, value string) error {
+	err := mo.domain.SetHeader(key, value)
+	mo.Language = mo.domain.GetLanguage()
+	mo.PluralForms = mo.domain.PluralForms
+	return err
+}
+
+// SetPluralForms sets the Plural-Forms header and its plural rule. See Domain.SetPluralForms.
+func (mo *Mo) SetPluralForms(pf string) error {
+	return mo.SetHeader("Plural-Forms", pf)
+}
+
+func (mo *Mo) SetPluralPolicy(p PluralPolicy) {
+	mo.domain.SetPluralPolicy(p)
+}
+
+func (mo *Mo) PluralMismatch() *PluralMismatch {
+	return mo.domain.PluralMismatch()
+}
+
 func (mo *Mo) MarshalBinary() ([]byte, error) {
 	return mo.domain.MarshalBinary()
 }
@@ -92,135 +129,369 @@ func (mo *Mo) UnmarshalBinary(data []byte) error {
 }
 
 func (mo *Mo) ParseFile(f string) {
+	_, span := startSpan(context.Background(), "gotext.ParseFile", "file", f)
 	data, err := getFileData(f)
 	if err != nil {
+		span.End(err)
 		return
 	}
 
 	mo.Parse(data)
+	span.SetAttribute("entries", mo.domain.entryCount())
+	span.End(nil)
 }
 
-// Parse loads the translations specified in the provided byte slice, in the GNU gettext .mo format
+// Parse loads the translations specified in the provided byte slice, in the GNU gettext .mo format.
+// Corrupt or truncated files are logged (see SetLogger) and not loaded; use ParseWithError to get the problem.
 func (mo *Mo) Parse(buf []byte) {
-	// Lock while parsing
+	if err := mo.parse(buf); err != nil {
+		logWarn("gotext: invalid MO file", "err", err)
+	}
+}
+
+// ParseWithError works like Parse, but returns a *ParseError with the byte offset of the problem
+// when the data is corrupt or truncated: bad magic number or version, or tables and strings out of bounds.
+// Nothing is loaded then.
+func (mo *Mo) ParseWithError(buf []byte) error {
+	return mo.parse(buf)
+}
+
+func (mo *Mo) parse(buf []byte) error {
+	entries, err := readMo(buf)
+	if err != nil {
+		return err
+	}
+
+	// Lock while loading
 	mo.domain.trMutex.Lock()
 	mo.domain.pluralMutex.Lock()
 	defer mo.domain.trMutex.Unlock()
 	defer mo.domain.pluralMutex.Unlock()
 
-	r := bytes.NewReader(buf)
+	mo.domain.progress.start(len(buf))
 
-	var magicNumber uint32
-	if err := binary.Read(r, binary.LittleEndian, &magicNumber); err != nil {
-		return
-		// return fmt.Errorf("gettext: %v", err)
+	for _, e := range entries {
+		mo.addTranslation(e.msgid, e.msgstr)
+		if len(e.msgid) > 0 {
+			mo.domain.progress.entry()
+		}
+		mo.domain.progress.advanceTo(e.end)
+	}
+
+	// Parse headers
+	mo.domain.parseHeaders()
+
+	// set values on this struct
+	// this is for backwards compatibility
+	mo.Language = mo.domain.Language
+	mo.PluralForms = mo.domain.PluralForms
+	mo.Headers = mo.domain.Headers
+
+	mo.domain.progress.done()
+	return nil
+}
+
+// moHeaderSize is the size of the MO file header: magic number, revision, number of strings,
+// offsets of the msgid and msgstr tables, and size and offset of the hash table.
+const moHeaderSize = 28
+
+// moEntry is a msgid and msgstr pair read from a MO file, with the offset where the msgstr ends.
+type moEntry struct {
+	msgid, msgstr []byte
+	end           int64
+}
+
+// moReader reads a MO file, checking every offset and length against its size, so corrupt files
+// are reported as a *ParseError instead of panicking or allocating absurd amounts of memory.
+type moReader struct {
+	buf []byte
+	bo  binary.ByteOrder
+}
+
+// uint32 returns the number at offset off, which is what.
+func (r moReader) uint32(off uint64, what string) (uint32, error) {
+	if off+4 > uint64(len(r.buf)) {
+		return 0, &ParseError{Offset: int64(len(r.buf)), Msg: "unexpected EOF reading " + what}
+	}
+	return r.bo.Uint32(r.buf[off:]), nil
+}
+
+// bytes returns the n bytes at offset off, which are what.
+func (r moReader) bytes(off, n uint32, what string) ([]byte, error) {
+	size := uint64(len(r.buf))
+	if end := uint64(off) + uint64(n); end > size {
+		avail := uint64(0)
+		if uint64(off) < size {
+			avail = size - uint64(off)
+		}
+		return nil, &ParseError{Offset: int64(off), Msg: fmt.Sprintf("truncated %s, %d of %d bytes", what, avail, n)}
+	}
+	return r.buf[off : off+n], nil
+}
+
+// moHeader holds the fields of a MO file header.
+type moHeader struct {
+	minorVersion uint32
+	count        uint32
+	msgIDOffset  uint32
+	msgStrOffset uint32
+	hashSize     uint32
+	hashOffset   uint32
+}
+
+// readMoHeader checks the magic number, version and string tables of the MO file in buf, returning
+// a reader in its byte order and its header, or a *ParseError with the offset of the problem.
+func readMoHeader(buf []byte) (moReader, moHeader, error) {
+	r := moReader{buf: buf, bo: binary.LittleEndian}
+	var h moHeader
+
+	magicNumber, err := r.uint32(0, "magic number")
+	if err != nil {
+		return r, h, err
 	}
-	var bo binary.ByteOrder
 	switch magicNumber {
 	case MoMagicLittleEndian:
-		bo = binary.LittleEndian
 	case MoMagicBigEndian:
-		bo = binary.BigEndian
+		r.bo = binary.BigEndian
 	default:
-		return
-		// return fmt.Errorf("gettext: %v", "invalid magic number")
+		return r, h, &ParseError{Offset: 0, Msg: fmt.Sprintf("invalid magic number %#x", magicNumber)}
 	}
 
-	var header struct {
-		MajorVersion uint16
-		MinorVersion uint16
-		MsgIDCount   uint32
-		MsgIDOffset  uint32
-		MsgStrOffset uint32
-		HashSize     uint32
-		HashOffset   uint32
+	if len(buf) < moHeaderSize {
+		return r, h, &ParseError{Offset: int64(len(buf)), Msg: fmt.Sprintf("unexpected EOF reading header, %d of %d bytes", len(buf), moHeaderSize)}
 	}
-	if err := binary.Read(r, bo, &header); err != nil {
-		return
-		// return fmt.Errorf("gettext: %v", err)
+	// The revision holds the major version in its high half
+	revision := r.bo.Uint32(buf[4:])
+	majorVersion, minorVersion := revision>>16, revision&0xffff
+	if (majorVersion != 0 && majorVersion != 1) || (minorVersion != 0 && minorVersion != 1) {
+		return r, h, &ParseError{Offset: 4, Msg: fmt.Sprintf("invalid version number %d.%d", majorVersion, minorVersion)}
 	}
-	if v := header.MajorVersion; v != 0 && v != 1 {
-		return
-		// return fmt.Errorf("gettext: %v", "invalid version number")
+	h.minorVersion = minorVersion
+	h.count = r.bo.Uint32(buf[8:])
+	h.msgIDOffset = r.bo.Uint32(buf[12:])
+	h.msgStrOffset = r.bo.Uint32(buf[16:])
+	h.hashSize = r.bo.Uint32(buf[20:])
+	h.hashOffset = r.bo.Uint32(buf[24:])
+
+	// Both tables have a length and an offset for every string
+	for _, table := range []struct {
+		name   string
+		offset uint32
+	}{{"msgid", h.msgIDOffset}, {"msgstr", h.msgStrOffset}} {
+		if uint64(table.offset)+uint64(h.count)*8 > uint64(len(buf)) {
+			return r, h, &ParseError{Offset: int64(table.offset), Msg: fmt.Sprintf("%s table of %d strings out of bounds", table.name, h.count)}
+		}
 	}
-	if v := header.MinorVersion; v != 0 && v != 1 {
-		return
-		// return fmt.Errorf("gettext: %v", "invalid version number")
+
+	return r, h, nil
+}
+
+// entry returns the msgid and msgstr of the string i of the tables, which must be in bounds,
+// and the offset where the msgstr ends.
+func (r moReader) entry(h moHeader, i uint32) (msgid, msgstr []byte, end int64, err error) {
+	idLen, _ := r.uint32(uint64(h.msgIDOffset)+uint64(i)*8, "msgid length")
+	idStart, _ := r.uint32(uint64(h.msgIDOffset)+uint64(i)*8+4, "msgid offset")
+	strLen, _ := r.uint32(uint64(h.msgStrOffset)+uint64(i)*8, "msgstr length")
+	strStart, _ := r.uint32(uint64(h.msgStrOffset)+uint64(i)*8+4, "msgstr offset")
+
+	if msgid, err = r.bytes(idStart, idLen, "msgid"); err != nil {
+		return nil, nil, 0, err
 	}
+	if msgstr, err = r.bytes(strStart, strLen, "msgstr"); err != nil {
+		return nil, nil, 0, err
+	}
+	return msgid, msgstr, int64(strStart) + int64(strLen), nil
+}
 
-	msgIDStart := make([]uint32, header.MsgIDCount)
-	msgIDLen := make([]uint32, header.MsgIDCount)
-	if _, err := r.Seek(int64(header.MsgIDOffset), 0); err != nil {
-		return
-		// return fmt.Errorf("gettext: %v", err)
+// readMo returns the msgid and msgstr pairs of the MO file in buf, or a *ParseError with the offset of
+// the first problem found.
+func readMo(buf []byte) ([]moEntry, error) {
+	r, h, err := readMoHeader(buf)
+	if err != nil {
+		return nil, err
 	}
-	for i := 0; i < int(header.MsgIDCount); i++ {
-		if err := binary.Read(r, bo, &msgIDLen[i]); err != nil {
-			return
-			// return fmt.Errorf("gettext: %v", err)
+
+	entries := make([]moEntry, h.count)
+	for i := range entries {
+		e := &entries[i]
+		if e.msgid, e.msgstr, e.end, err = r.entry(h, uint32(i)); err != nil {
+			return nil, err
 		}
-		if err := binary.Read(r, bo, &msgIDStart[i]); err != nil {
-			return
-			// return fmt.Errorf("gettext: %v", err)
+	}
+
+	if h.minorVersion == 1 {
+		sysdep, err := r.sysdepEntries()
+		if err != nil {
+			return nil, err
 		}
+		entries = append(entries, sysdep...)
 	}
 
-	msgStrStart := make([]int32, header.MsgIDCount)
-	msgStrLen := make([]int32, header.MsgIDCount)
-	if _, err := r.Seek(int64(header.MsgStrOffset), 0); err != nil {
-		return
-		// return fmt.Errorf("gettext: %v", err)
+	return entries, nil
+}
+
+// moSegmentsEnd ends the segments of a system dependent string.
+const moSegmentsEnd = 0xffffffff
+
+// moSysdepExpansion is how many times the size of a MO file its system dependent strings can expand to.
+// Strings can share their segments, so a small file could otherwise expand to gigabytes.
+const moSysdepExpansion = 4
+
+/*
+sysdepEntries returns the system dependent strings of a revision 1 MO file, which msgfmt writes for
+messages using the <inttypes.h> format macros, like "%" PRId64. Their macros are expanded to Go verbs,
+and strings with macros that have no Go equivalent are skipped, like the GNU gettext runtime does.
+
+Revision 1 headers go on with:
+
+	n_sysdep_segments         number of macros
+	sysdep_segments_offset    table of macro names (length and offset)
+	n_sysdep_strings          number of system dependent messages
+	orig_sysdep_tab_offset    offsets of the msgid strings
+	trans_sysdep_tab_offset   offsets of the msgstr strings
+
+where every string is an offset to its static data, followed by pairs of static data length and macro
+index, up to the index moSegmentsEnd.
+*/
+func (r moReader) sysdepEntries() ([]moEntry, error) {
+	var fields [5]uint32
+	for i := range fields {
+		v, err := r.uint32(moHeaderSize+uint64(i)*4, "revision 1 header")
+		if err != nil {
+			return nil, err
+		}
+		fields[i] = v
+	}
+	nSegments, segmentsOffset, nStrings, origOffset, transOffset := fields[0], fields[1], fields[2], fields[3], fields[4]
+
+	if uint64(segmentsOffset)+uint64(nSegments)*8 > uint64(len(r.buf)) {
+		return nil, &ParseError{Offset: int64(segmentsOffset), Msg: fmt.Sprintf("system dependent segment table of %d macros out of bounds", nSegments)}
 	}
-	for i := 0; i < int(header.MsgIDCount); i++ {
-		if err := binary.Read(r, bo, &msgStrLen[i]); err != nil {
-			return
-			// return fmt.Errorf("gettext: %v", err)
+	for _, offset := range []uint32{origOffset, transOffset} {
+		if uint64(offset)+uint64(nStrings)*4 > uint64(len(r.buf)) {
+			return nil, &ParseError{Offset: int64(offset), Msg: fmt.Sprintf("system dependent string table of %d strings out of bounds", nStrings)}
 		}
-		if err := binary.Read(r, bo, &msgStrStart[i]); err != nil {
-			return
-			// return fmt.Errorf("gettext: %v", err)
+	}
+
+	// Go equivalents of the macros, or false if there is none
+	macros := make([]string, nSegments)
+	known := make([]bool, nSegments)
+	for i := range macros {
+		length, _ := r.uint32(uint64(segmentsOffset)+uint64(i)*8, "macro length")
+		offset, _ := r.uint32(uint64(segmentsOffset)+uint64(i)*8+4, "macro offset")
+		name, err := r.bytes(offset, length, "macro name")
+		if err != nil {
+			return nil, err
 		}
+		macros[i], known[i] = moSysdepMacro(strings.TrimRight(string(name), NulSeparator))
 	}
 
-	for i := 0; i < int(header.MsgIDCount); i++ {
-		if _, err := r.Seek(int64(msgIDStart[i]), 0); err != nil {
-			return
-			// return fmt.Errorf("gettext: %v", err)
+	var entries []moEntry
+	left := moSysdepExpansion * len(r.buf)
+	for i := uint32(0); i < nStrings; i++ {
+		idOffset, _ := r.uint32(uint64(origOffset)+uint64(i)*4, "msgid offset")
+		strOffset, _ := r.uint32(uint64(transOffset)+uint64(i)*4, "msgstr offset")
+
+		msgid, ok, err := r.sysdepString(idOffset, macros, known, &left)
+		if err != nil {
+			return nil, err
+		}
+		msgstr, ok2, err := r.sysdepString(strOffset, macros, known, &left)
+		if err != nil {
+			return nil, err
 		}
-		msgIDData := make([]byte, msgIDLen[i])
-		if _, err := r.Read(msgIDData); err != nil {
-			return
-			// return fmt.Errorf("gettext: %v", err)
+		if ok && ok2 {
+			entries = append(entries, moEntry{msgid: msgid, msgstr: msgstr, end: int64(strOffset)})
 		}
+	}
+	return entries, nil
+}
 
-		if _, err := r.Seek(int64(msgStrStart[i]), 0); err != nil {
-			return
-			// return fmt.Errorf("gettext: %v", err)
+// sysdepString expands the system dependent string described at offset off. It returns false when
+// the string uses a macro with no Go equivalent. left is the size strings can still expand to.
+func (r moReader) sysdepString(off uint32, macros []string, known []bool, left *int) ([]byte, bool, error) {
+	data, err := r.uint32(uint64(off), "system dependent string")
+	if err != nil {
+		return nil, false, err
+	}
+
+	var str []byte
+	ok := true
+	pos := uint64(off) + 4
+	for {
+		segSize, err := r.uint32(pos, "segment size")
+		if err != nil {
+			return nil, false, err
 		}
-		msgStrData := make([]byte, msgStrLen[i])
-		if _, err := r.Read(msgStrData); err != nil {
-			return
-			// return fmt.Errorf("gettext: %v", err)
+		ref, err := r.uint32(pos+4, "segment macro")
+		if err != nil {
+			return nil, false, err
 		}
+		pos += 8
 
-		if len(msgIDData) == 0 {
-			mo.addTranslation(msgIDData, msgStrData)
-		} else {
-			mo.addTranslation(msgIDData, msgStrData)
+		static, err := r.bytes(data, segSize, "system dependent string")
+		if err != nil {
+			return nil, false, err
+		}
+		if *left -= len(static); *left < 0 {
+			return nil, false, &ParseError{Offset: int64(off), Msg: fmt.Sprintf("system dependent strings expand to more than %d times the file size", moSysdepExpansion)}
+		}
+		str = append(str, static...)
+		data += segSize
+
+		if ref == moSegmentsEnd {
+			break
+		}
+		if uint64(ref) >= uint64(len(macros)) {
+			return nil, false, &ParseError{Offset: int64(pos - 4), Msg: fmt.Sprintf("invalid macro index %d", ref)}
+		}
+		if !known[ref] {
+			ok = false
+		}
+		if *left -= len(macros[ref]); *left < 0 {
+			return nil, false, &ParseError{Offset: int64(off), Msg: fmt.Sprintf("system dependent strings expand to more than %d times the file size", moSysdepExpansion)}
 		}
+		str = append(str, macros[ref]...)
 	}
 
-	// Parse headers
-	mo.domain.parseHeaders()
+	// Strings are NUL terminated in the static data
+	return bytes.TrimSuffix(str, []byte(NulSeparator)), ok, nil
+}
 
-	// set values on this struct
-	// this is for backwards compatibility
-	mo.Language = mo.domain.Language
-	mo.PluralForms = mo.domain.PluralForms
-	mo.Headers = mo.domain.Headers
+// moSysdepMacro returns the Go equivalent of an <inttypes.h> format macro name, like PRId64 or PRIxPTR,
+// without the % it follows. The I flag of glibc has no equivalent, and is dropped.
+func moSysdepMacro(name string) (string, bool) {
+	if name == "I" {
+		return "", true
+	}
+	if !strings.HasPrefix(name, "PRI") || len(name) < 4 {
+		return "", false
+	}
+	switch verb := name[3]; verb {
+	case 'd', 'i', 'u':
+		return "d", true
+	case 'o', 'x', 'X':
+		return string(verb), true
+	}
+	return "", false
 }
 
 func (mo *Mo) addTranslation(msgid, msgstr []byte) {
+	msgctxt, translation := decodeMoEntry(msgid, msgstr)
+
+	if len(msgctxt) > 0 {
+		// With context...
+		if _, ok := mo.domain.contexts[msgctxt]; !ok {
+			mo.domain.contexts[msgctxt] = make(map[string]*Translation)
+		}
+		mo.domain.contexts[msgctxt][translation.ID] = translation
+	} else {
+		mo.domain.translations[translation.ID] = translation
+	}
+}
+
+// decodeMoEntry returns the context and the Translation of a msgid and msgstr pair of a MO file.
+func decodeMoEntry(msgid, msgstr []byte) (string, *Translation) {
 	translation := NewTranslation()
 	var msgctxt []byte
 	var msgidPlural []byte
@@ -232,15 +503,12 @@ func (mo *Mo) addTranslation(msgid, msgstr []byte) {
 		msgid, msgctxt = d[1], d[0]
 	}
 
-	dd := bytes.Split(msgid, []byte(NulSeparator))
-	if len(dd) > 1 {
+	if dd := bytes.Split(msgid, []byte(NulSeparator)); len(dd) > 1 {
 		msgid = dd[0]
-		dd = dd[1:]
+		msgidPlural = bytes.Join(dd[1:], []byte(NulSeparator))
 	}
 
 	translation.ID = string(msgid)
-
-	msgidPlural = bytes.Join(dd, []byte(NulSeparator))
 	if len(msgidPlural) > 0 {
 		translation.PluralID = string(msgidPlural)
 	}
@@ -252,13 +520,73 @@ func (mo *Mo) addTranslation(msgid, msgstr []byte) {
 		}
 	}
 
-	if len(msgctxt) > 0 {
-		// With context...
-		if _, ok := mo.domain.contexts[string(msgctxt)]; !ok {
-			mo.domain.contexts[string(msgctxt)] = make(map[string]*Translation)
+	return string(msgctxt), translation
+}
+
+// MarshalMO writes the translations of the Domain in the GNU gettext .mo format, as msgfmt does,
+// so they can be loaded by any gettext implementation.
+// Untranslated entries are left out, and no hash table is written.
+func (do *Domain) MarshalMO() ([]byte, error) {
+	type moEntry struct {
+		id, str string
+	}
+
+	var header strings.Builder
+	do.trMutex.RLock()
+	for _, k := range do.headerKeys() {
+		for _, value := range do.Headers[k] {
+			header.WriteString(k + ": " + value + "\n")
 		}
-		mo.domain.contexts[string(msgctxt)][translation.ID] = translation
-	} else {
-		mo.domain.translations[translation.ID] = translation
 	}
+	nplurals := do.nplurals
+	do.trMutex.RUnlock()
+
+	entries := []moEntry{{"", header.String()}}
+	for _, e := range do.entries() {
+		tr := e.Translation
+		if !tr.IsTranslated() {
+			continue
+		}
+
+		id := e.MsgID
+		if e.Context != "" {
+			id = e.Context + EotSeparator + id
+		}
+		if tr.PluralID != "" {
+			id += NulSeparator + tr.PluralID
+		}
+		entries = append(entries, moEntry{id, strings.Join(msgstrs(tr, nplurals), NulSeparator)})
+	}
+
+	// Readers use binary search on the msgids
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].id < entries[j].id
+	})
+
+	// Header, msgid and msgstr tables, and then the strings
+	n := uint32(len(entries))
+	idTable := uint32(28)
+	strTable := idTable + 8*n
+	offset := strTable + 8*n
+
+	var buf bytes.Buffer
+	for _, v := range []uint32{MoMagicLittleEndian, 0, n, idTable, strTable, 0, offset} {
+		binary.Write(&buf, binary.LittleEndian, v)
+	}
+	for _, e := range entries {
+		binary.Write(&buf, binary.LittleEndian, []uint32{uint32(len(e.id)), offset})
+		offset += uint32(len(e.id)) + 1
+	}
+	for _, e := range entries {
+		binary.Write(&buf, binary.LittleEndian, []uint32{uint32(len(e.str)), offset})
+		offset += uint32(len(e.str)) + 1
+	}
+	for _, e := range entries {
+		buf.WriteString(e.id + NulSeparator)
+	}
+	for _, e := range entries {
+		buf.WriteString(e.str + NulSeparator)
+	}
+
+	return buf.Bytes(), nil
 }
@@ -6,8 +6,10 @@
 package gotext
 
 import (
+	"encoding/binary"
 	"os"
 	"path"
+	"strings"
 	"testing"
 )
 
@@ -229,3 +231,207 @@ func TestMoBinaryEncoding(t *testing.T) {
 		t.Errorf("Expected 'en_US' but got '%s'", tr)
 	}
 }
+
+func TestDomain_MarshalMO(t *testing.T) {
+	po := NewPo()
+	po.Parse([]byte(convertPo))
+
+	data, err := po.GetDomain().MarshalMO()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mo := NewMo()
+	if err := mo.ParseWithError(data); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := mo.GetDomain().GetTranslation("Untranslated"); ok {
+		t.Error("untranslated entries shouldn't be written")
+	}
+	if pf := mo.GetDomain().Header("Plural-Forms"); pf != "nplurals=2; plural=(n != 1);" {
+		t.Errorf("unexpected Plural-Forms %q", pf)
+	}
+	if got := mo.GetN("%d file", "%d files", 3, 3); got != "3 archivos" {
+		t.Errorf("unexpected translation %q", got)
+	}
+}
+
+// moSysdep is a system dependent string for buildMo: static strings and indexes of macros, in order.
+type moSysdep []interface{}
+
+// buildMo writes a MO file in the byte order bo with the static msgid and msgstr pairs, and a revision 1
+// system dependent section when macros isn't empty.
+func buildMo(bo binary.ByteOrder, static [][2]string, macros []string, sysdep [][2]moSysdep) []byte {
+	rev1 := len(macros) > 0
+	headerSize := 28
+	if rev1 {
+		headerSize += 20
+	}
+
+	var buf []byte
+	put := func(off int, v uint32) { bo.PutUint32(buf[off:], v) }
+	grow := func(n int) int {
+		off := len(buf)
+		buf = append(buf, make([]byte, n)...)
+		return off
+	}
+	str := func(s string) (uint32, uint32) {
+		off := len(buf)
+		buf = append(buf, s...)
+		buf = append(buf, 0)
+		return uint32(len(s)), uint32(off)
+	}
+
+	grow(headerSize)
+	put(0, MoMagicLittleEndian)
+	if rev1 {
+		put(4, 1)
+	}
+	put(8, uint32(len(static)))
+	origTab, transTab := grow(8*len(static)), grow(8*len(static))
+	put(12, uint32(origTab))
+	put(16, uint32(transTab))
+	for i, pair := range static {
+		n, off := str(pair[0])
+		put(origTab+8*i, n)
+		put(origTab+8*i+4, off)
+		n, off = str(pair[1])
+		put(transTab+8*i, n)
+		put(transTab+8*i+4, off)
+	}
+
+	if rev1 {
+		segTab := grow(8 * len(macros))
+		put(28, uint32(len(macros)))
+		put(32, uint32(segTab))
+		for i, m := range macros {
+			n, off := str(m)
+			put(segTab+8*i, n+1)
+			put(segTab+8*i+4, off)
+		}
+
+		origSys, transSys := grow(4*len(sysdep)), grow(4*len(sysdep))
+		put(36, uint32(len(sysdep)))
+		put(40, uint32(origSys))
+		put(44, uint32(transSys))
+		for i, pair := range sysdep {
+			for j, s := range pair {
+				// Static data first, then the descriptor pointing to it
+				var data []byte
+				var segs [][2]uint32
+				size := uint32(0)
+				for _, piece := range s {
+					switch p := piece.(type) {
+					case string:
+						data = append(data, p...)
+						size += uint32(len(p))
+					case int:
+						segs = append(segs, [2]uint32{size, uint32(p)})
+						size = 0
+					}
+				}
+				data = append(data, 0)
+				segs = append(segs, [2]uint32{size + 1, 0xffffffff})
+
+				dataOff := len(buf)
+				buf = append(buf, data...)
+				desc := grow(4 + 8*len(segs))
+				put(desc, uint32(dataOff))
+				for k, seg := range segs {
+					put(desc+4+8*k, seg[0])
+					put(desc+8+8*k, seg[1])
+				}
+				if j == 0 {
+					put(origSys+4*i, uint32(desc))
+				} else {
+					put(transSys+4*i, uint32(desc))
+				}
+			}
+		}
+	}
+	return buf
+}
+
+func TestMoBigEndian(t *testing.T) {
+	static := [][2]string{
+		{"", "Language: de\nPlural-Forms: nplurals=2; plural=(n != 1);\n"},
+		{"Hello", "Hallo"},
+		{"menu\x04Open", "Öffnen"},
+	}
+	for _, bo := range []binary.ByteOrder{binary.LittleEndian, binary.BigEndian} {
+		mo := NewMo()
+		if err := mo.ParseWithError(buildMo(bo, static, nil, nil)); err != nil {
+			t.Fatalf("%v: %v", bo, err)
+		}
+		hello, open := "Hello", "Open"
+		if tr := mo.Get(hello); tr != "Hallo" {
+			t.Errorf("%v: unexpected translation %q", bo, tr)
+		}
+		if tr := mo.GetC(open, "menu"); tr != "Öffnen" {
+			t.Errorf("%v: unexpected translation %q", bo, tr)
+		}
+		if mo.Language != "de" {
+			t.Errorf("%v: unexpected language %q", bo, mo.Language)
+		}
+	}
+}
+
+func TestMoRevision1(t *testing.T) {
+	static := [][2]string{
+		{"", "Language: de\nPlural-Forms: nplurals=2; plural=(n != 1);\n"},
+		{"Hello", "Hallo"},
+	}
+	macros := []string{"PRId64", "PRIxMAX", "PRIzz"}
+	sysdep := [][2]moSysdep{
+		{{"Found %", 0, " files"}, {"%", 0, " Dateien gefunden"}},
+		{{"One byte at %#", 1, "\x00%#", 1, " bytes"}, {"Ein Byte bei %#", 1, "\x00%#", 1, " Bytes"}},
+		{{"Unknown %", 2}, {"Unbekannt %", 2}},
+	}
+
+	for _, bo := range []binary.ByteOrder{binary.LittleEndian, binary.BigEndian} {
+		mo := NewMo()
+		if err := mo.ParseWithError(buildMo(bo, static, macros, sysdep)); err != nil {
+			t.Fatalf("%v: %v", bo, err)
+		}
+
+		hello, found, one := "Hello", "Found %d files", "One byte at %#x"
+		if tr := mo.Get(hello); tr != "Hallo" {
+			t.Errorf("%v: unexpected translation %q", bo, tr)
+		}
+		if tr := mo.Get(found, 3); tr != "3 Dateien gefunden" {
+			t.Errorf("%v: unexpected translation %q", bo, tr)
+		}
+		if tr := mo.GetN(one, "%#x bytes", 2, 255); tr != "0xff Bytes" {
+			t.Errorf("%v: unexpected translation %q", bo, tr)
+		}
+		if _, ok := mo.GetDomain().GetTranslation("Unknown %"); ok {
+			t.Errorf("%v: strings with unknown macros should be skipped", bo)
+		}
+	}
+
+	// Corrupt system dependent tables are reported
+	data := buildMo(binary.LittleEndian, static, macros, sysdep)
+	binary.LittleEndian.PutUint32(data[36:], 0xffffff)
+	if err := NewMo().ParseWithError(data); err == nil || !strings.Contains(err.Error(), "out of bounds") {
+		t.Errorf("Expected an out of bounds error, got %v", err)
+	}
+
+	// Many strings sharing a long segment would expand to much more than the file
+	long := strings.Repeat("x", 4096)
+	data = buildMo(binary.LittleEndian, static, macros, [][2]moSysdep{{{long}, {long}}})
+	desc := binary.LittleEndian.Uint32(data[binary.LittleEndian.Uint32(data[40:]):])
+	n := 1000
+	table := len(data)
+	for i := 0; i < n; i++ {
+		data = append(data, 0, 0, 0, 0)
+		binary.LittleEndian.PutUint32(data[table+4*i:], desc)
+	}
+	binary.LittleEndian.PutUint32(data[36:], uint32(n))
+	binary.LittleEndian.PutUint32(data[40:], uint32(table))
+	binary.LittleEndian.PutUint32(data[44:], uint32(table))
+	for _, parse := range []func([]byte) error{NewMo().ParseWithError, NewIndexedMo(0).ParseWithError} {
+		if err := parse(data); err == nil || !strings.Contains(err.Error(), "file size") {
+			t.Errorf("Expected an expansion error, got %v", err)
+		}
+	}
+}
@@ -0,0 +1,376 @@
+package gotext
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+/*
+IndexedMo is a Translator for GNU gettext .mo files that resolves every message in the file data when it's
+looked up, through the hash table written by msgfmt, instead of building the maps of a Mo when parsing.
+Files without a hash table, like the ones written by Domain.MarshalMO, use a binary search on their sorted msgids.
+Decoded messages are kept in a small LRU cache.
+
+Parsing only reads the headers and checks the bounds of the tables and strings, so loading is fast and the
+resident memory is about the size of the file, which suits large catalogs of rarely used locales.
+
+GetDomain returns a Domain with the headers and plural rule of the file, but without its messages,
+so settings like the plural policy and fallback or the missing hook work, but the ones working on the loaded
+messages, like normalization, fuzzy matching, interning, Merge, Compact, RewritePositional, ApplyPercentPolicy,
+MarshalTextScrubbed or exporting, don't. Use Load for those. Locale methods combining catalogs, like
+AddOverlayDomain, AddDomainFiles, Catalog and ExportForWeb, load the messages themselves.
+
+It's safe for concurrent use by multiple goroutines.
+*/
+type IndexedMo struct {
+	domain *Domain
+
+	mu     sync.Mutex
+	r      moReader
+	h      moHeader
+	sysdep map[string]moEntry
+	cache  *moCache
+}
+
+// NewIndexedMo returns an IndexedMo that caches up to cacheSize decoded messages. A cacheSize of 0 or less
+// decodes the message on every lookup.
+func NewIndexedMo(cacheSize int) *IndexedMo {
+	return &IndexedMo{
+		domain: NewDomain(),
+		cache:  newMoCache(cacheSize),
+	}
+}
+
+// GetDomain returns the Domain with the headers and plural rule of the file. See IndexedMo.
+func (mo *IndexedMo) GetDomain() *Domain {
+	return mo.domain
+}
+
+func (mo *IndexedMo) ParseFile(f string) {
+	_, span := startSpan(context.Background(), "gotext.ParseFile", "file", f)
+	data, err := getFileData(f)
+	if err != nil {
+		span.End(err)
+		return
+	}
+
+	err = mo.ParseWithError(data)
+	if err != nil {
+		logWarn("gotext: invalid MO file", "err", err)
+	} else {
+		span.SetAttribute("entries", int(mo.h.count))
+	}
+	span.End(err)
+}
+
+// Parse loads the MO file in buf, which is kept and must not be modified afterwards.
+// Corrupt or truncated files are logged (see SetLogger) and not loaded; use ParseWithError to get the problem.
+func (mo *IndexedMo) Parse(buf []byte) {
+	if err := mo.ParseWithError(buf); err != nil {
+		logWarn("gotext: invalid MO file", "err", err)
+	}
+}
+
+// ParseWithError works like Parse, but returns a *ParseError with the byte offset of the problem
+// when the data is corrupt or truncated. Nothing is loaded then.
+func (mo *IndexedMo) ParseWithError(buf []byte) error {
+	r, h, err := readMoHeader(buf)
+	if err != nil {
+		return err
+	}
+
+	// Check every string now, so lookups can't fail
+	for i := uint32(0); i < h.count; i++ {
+		if _, _, _, err := r.entry(h, i); err != nil {
+			return err
+		}
+	}
+	if h.hashSize > 0 && uint64(h.hashOffset)+uint64(h.hashSize)*4 > uint64(len(buf)) {
+		return &ParseError{Offset: int64(h.hashOffset), Msg: fmt.Sprintf("hash table of %d slots out of bounds", h.hashSize)}
+	}
+
+	// System dependent strings are few, and need their macros expanded, so they're decoded now
+	var sysdep map[string]moEntry
+	if h.minorVersion == 1 {
+		entries, err := r.sysdepEntries()
+		if err != nil {
+			return err
+		}
+		sysdep = make(map[string]moEntry, len(entries))
+		for _, e := range entries {
+			sysdep[string(moKey(e.msgid))] = e
+		}
+	}
+
+	mo.mu.Lock()
+	mo.r, mo.h, mo.sysdep = r, h, sysdep
+	mo.cache.clear()
+	header := NewTranslation()
+	if msgid, msgstr, ok := mo.find(""); ok {
+		_, header = decodeMoEntry(msgid, msgstr)
+	}
+	mo.mu.Unlock()
+
+	mo.domain.trMutex.Lock()
+	mo.domain.pluralMutex.Lock()
+	defer mo.domain.trMutex.Unlock()
+	defer mo.domain.pluralMutex.Unlock()
+
+	mo.domain.Headers = make(HeaderMap)
+	mo.domain.translations = map[string]*Translation{"": header}
+	mo.domain.contexts = make(map[string]map[string]*Translation)
+	mo.domain.parseHeaders()
+
+	return nil
+}
+
+// messageDomain returns the Domain holding the messages of tr. The ones of an IndexedMo are loaded,
+// as its Domain only has the headers.
+func messageDomain(tr Translator) (*Domain, error) {
+	if lt, ok := tr.(*lazyTranslator); ok {
+		tr = lt.translator()
+	}
+	mo, ok := tr.(*IndexedMo)
+	if !ok {
+		return tr.GetDomain(), nil
+	}
+	full, err := mo.Load()
+	if err != nil {
+		return nil, err
+	}
+	return full.GetDomain(), nil
+}
+
+// Load returns a Mo with all the messages of the file loaded, for the uses that need all of them.
+func (mo *IndexedMo) Load() (*Mo, error) {
+	mo.mu.Lock()
+	buf := mo.r.buf
+	mo.mu.Unlock()
+
+	m := NewMo()
+	if buf == nil {
+		return m, nil
+	}
+	if err := m.ParseWithError(buf); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (mo *IndexedMo) Get(str string, vars ...interface{}) string {
+	if tr, ok := mo.lookup(str, "", str); ok {
+		return Printf(tr.Get(), vars...)
+	}
+	return Printf(str, vars...)
+}
+
+func (mo *IndexedMo) GetN(str, plural string, n int, vars ...interface{}) string {
+	if tr, ok := mo.lookup(str, "", str); ok {
+		return Printf(mo.domain.pluralString("", tr, mo.domain.pluralForm(n)), vars...)
+	}
+
+	if mo.domain.pluralForm(n) == 0 {
+		return Printf(str, vars...)
+	}
+	return Printf(plural, vars...)
+}
+
+func (mo *IndexedMo) GetC(str, ctx string, vars ...interface{}) string {
+	if tr, ok := mo.lookup(str, ctx, ctx+EotSeparator+str); ok {
+		return Printf(tr.Get(), vars...)
+	}
+	return Printf(str, vars...)
+}
+
+func (mo *IndexedMo) GetNC(str, plural string, n int, ctx string, vars ...interface{}) string {
+	if tr, ok := mo.lookup(str, ctx, ctx+EotSeparator+str); ok {
+		return Printf(mo.domain.pluralString(ctx, tr, mo.domain.pluralForm(n)), vars...)
+	}
+
+	if n == 1 {
+		return Printf(str, vars...)
+	}
+	return Printf(plural, vars...)
+}
+
+// MarshalBinary encodes all the messages of the file, like Mo does.
+func (mo *IndexedMo) MarshalBinary() ([]byte, error) {
+	m, err := mo.Load()
+	if err != nil {
+		return nil, err
+	}
+	return m.MarshalBinary()
+}
+
+// UnmarshalBinary isn't supported, as an IndexedMo reads MO files only. Decode into a Mo instead.
+func (mo *IndexedMo) UnmarshalBinary(data []byte) error {
+	return errors.New("gotext: IndexedMo can't be decoded, use a Mo")
+}
+
+// lookup returns the Translation for str in the context ctx, with the key of the file, reporting the result
+// to the current Metrics and calling the missing hook of the Domain when there's none.
+// Like in Domain, the empty context is a context too, so its key is EotSeparator and str.
+func (mo *IndexedMo) lookup(str, ctx, key string) (*Translation, bool) {
+	if tr, ok := mo.translation(key); ok {
+		mo.domain.observeLookup(LookupHit)
+		return tr, true
+	}
+
+	mo.domain.trMutex.RLock()
+	mo.domain.miss(str, ctx, nil)
+	mo.domain.trMutex.RUnlock()
+	mo.domain.observeLookup(LookupMiss)
+	return nil, false
+}
+
+// translation returns the decoded message with the key, from the cache when it's there.
+func (mo *IndexedMo) translation(key string) (*Translation, bool) {
+	mo.mu.Lock()
+	defer mo.mu.Unlock()
+
+	if tr, ok := mo.cache.get(key); ok {
+		return tr, true
+	}
+	msgid, msgstr, ok := mo.find(key)
+	if !ok {
+		return nil, false
+	}
+	_, tr := decodeMoEntry(msgid, msgstr)
+	mo.cache.add(key, tr)
+	return tr, true
+}
+
+// find returns the msgid and msgstr of the message with the key, its context and msgid joined by EotSeparator.
+// mo.mu must be held.
+func (mo *IndexedMo) find(key string) ([]byte, []byte, bool) {
+	if e, ok := mo.sysdep[key]; ok {
+		return e.msgid, e.msgstr, true
+	}
+
+	i, ok := mo.index(key)
+	if !ok {
+		return nil, nil, false
+	}
+	msgid, msgstr, _, _ := mo.r.entry(mo.h, i)
+	return msgid, msgstr, true
+}
+
+// index returns the index in the string tables of the message with the key, looking it up in the hash table
+// like the GNU gettext runtime does, or with a binary search when the file has none.
+func (mo *IndexedMo) index(key string) (uint32, bool) {
+	size := mo.h.hashSize
+	if size <= 2 {
+		i := sort.Search(int(mo.h.count), func(i int) bool {
+			return string(mo.msgid(uint32(i))) >= key
+		})
+		if i < int(mo.h.count) && string(mo.msgid(uint32(i))) == key {
+			return uint32(i), true
+		}
+		return 0, false
+	}
+
+	h := moHash(key)
+	slot, incr := h%size, 1+h%(size-2)
+	for probes := uint32(0); probes < size; probes++ {
+		n := mo.r.bo.Uint32(mo.r.buf[uint64(mo.h.hashOffset)+uint64(slot)*4:])
+		if n == 0 {
+			return 0, false
+		}
+		// Slots hold the index plus one; indexes past the static strings are system dependent ones
+		if n-1 < mo.h.count && string(mo.msgid(n-1)) == key {
+			return n - 1, true
+		}
+
+		if slot += incr; slot >= size {
+			slot -= size
+		}
+	}
+	return 0, false
+}
+
+// msgid returns the key of the string i: its msgid without the plural msgid.
+func (mo *IndexedMo) msgid(i uint32) []byte {
+	msgid, _, _, _ := mo.r.entry(mo.h, i)
+	return moKey(msgid)
+}
+
+// moKey returns the context and msgid part of a MO file msgid, leaving out the plural msgid.
+func moKey(msgid []byte) []byte {
+	if i := bytes.IndexByte(msgid, 0); i >= 0 {
+		return msgid[:i]
+	}
+	return msgid
+}
+
+// moHash is the hashpjw function used by the hash tables of MO files.
+func moHash(s string) uint32 {
+	var h uint32
+	for i := 0; i < len(s); i++ {
+		h = h<<4 + uint32(s[i])
+		if g := h & 0xf0000000; g != 0 {
+			h ^= g >> 24
+			h ^= g
+		}
+	}
+	return h
+}
+
+// moCache is a LRU cache of decoded messages.
+type moCache struct {
+	size  int
+	order *list.List
+	items map[string]*list.Element
+}
+
+type moCacheItem struct {
+	key string
+	tr  *Translation
+}
+
+func newMoCache(size int) *moCache {
+	return &moCache{
+		size:  size,
+		order: list.New(),
+		items: make(map[string]*list.Element),
+	}
+}
+
+func (c *moCache) get(key string) (*Translation, bool) {
+	e, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(e)
+	return e.Value.(*moCacheItem).tr, true
+}
+
+func (c *moCache) add(key string, tr *Translation) {
+	if c.size <= 0 {
+		return
+	}
+	c.items[key] = c.order.PushFront(&moCacheItem{key, tr})
+	if c.order.Len() > c.size {
+		last := c.order.Back()
+		c.order.Remove(last)
+		delete(c.items, last.Value.(*moCacheItem).key)
+	}
+}
+
+func (c *moCache) clear() {
+	c.order.Init()
+	c.items = make(map[string]*list.Element)
+}
+
+// SetMoOnDemand makes the .mo catalogs loaded afterwards by AddDomain be IndexedMo translators when on is true,
+// resolving their messages on demand with a cache of cacheSize decoded messages. See IndexedMo.
+func (l *Locale) SetMoOnDemand(on bool, cacheSize int) {
+	l.Lock()
+	l.moOnDemand = on
+	l.moCache = cacheSize
+	l.Unlock()
+}
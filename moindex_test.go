@@ -0,0 +1,158 @@
+package gotext
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+// checkIndexedMo compares every message of want with its lookup in got.
+func checkIndexedMo(t *testing.T, got *IndexedMo, want *Mo) {
+	t.Helper()
+
+	entries := want.GetDomain().Export()
+	if len(entries) == 0 {
+		t.Fatal("no entries to compare")
+	}
+	for key, e := range entries {
+		id, plural, ctx := key.MsgID, e.Translation.PluralID, key.Context
+		if g, w := got.GetC(id, ctx), want.GetC(id, ctx); g != w {
+			t.Errorf("%q: got %q, want %q", key, g, w)
+		}
+		if ctx == "" {
+			if g, w := got.Get(id), want.Get(id); g != w {
+				t.Errorf("%q: got %q, want %q", key, g, w)
+			}
+		}
+		if plural == "" {
+			continue
+		}
+		for n := 0; n < 3; n++ {
+			if g, w := got.GetNC(id, plural, n, ctx), want.GetNC(id, plural, n, ctx); g != w {
+				t.Errorf("%q with n=%d: got %q, want %q", key, n, g, w)
+			}
+			if ctx != "" {
+				continue
+			}
+			if g, w := got.GetN(id, plural, n), want.GetN(id, plural, n); g != w {
+				t.Errorf("%q with n=%d: got %q, want %q", key, n, g, w)
+			}
+		}
+	}
+}
+
+func TestIndexedMo(t *testing.T) {
+	want := NewMo()
+	want.ParseFile("fixtures/en_US/default.mo")
+
+	// msgfmt writes a hash table
+	mo := NewIndexedMo(4)
+	mo.ParseFile("fixtures/en_US/default.mo")
+	if mo.h.hashSize == 0 {
+		t.Fatal("expected the fixture to have a hash table")
+	}
+	checkIndexedMo(t, mo, want)
+
+	if mo.GetDomain().GetLanguage() != want.GetDomain().GetLanguage() {
+		t.Errorf("unexpected language %q", mo.GetDomain().GetLanguage())
+	}
+	if mo.cache.order.Len() != 4 {
+		t.Errorf("expected the cache to be full, got %d messages", mo.cache.order.Len())
+	}
+
+	missing := "Not in the file"
+	if tr := mo.Get(missing); tr != missing {
+		t.Errorf("unexpected translation %q", tr)
+	}
+	if tr := mo.GetN(missing, "Not in the files", 2); tr != "Not in the files" {
+		t.Errorf("unexpected translation %q", tr)
+	}
+
+	// MarshalMO writes no hash table, so msgids are found with a binary search
+	data, err := want.GetDomain().MarshalMO()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sorted := NewIndexedMo(0)
+	if err := sorted.ParseWithError(data); err != nil {
+		t.Fatal(err)
+	}
+	if sorted.h.hashSize != 0 {
+		t.Fatal("expected no hash table")
+	}
+	written := NewMo()
+	written.Parse(data)
+	checkIndexedMo(t, sorted, written)
+
+	loaded, err := mo.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded.GetDomain().Export()) != len(want.GetDomain().Export()) {
+		t.Error("Load should load every message")
+	}
+}
+
+func TestIndexedMoCorrupt(t *testing.T) {
+	data, err := getFileData("fixtures/en_US/default.mo")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mo := NewIndexedMo(8)
+	if err := mo.ParseWithError(data[:len(data)-10]); err == nil {
+		t.Error("expected an error for a truncated file")
+	}
+
+	bad := append([]byte(nil), data...)
+	binary.LittleEndian.PutUint32(bad[20:], 0xffffff)
+	if err := mo.ParseWithError(bad); err == nil {
+		t.Error("expected an error for a hash table out of bounds")
+	}
+
+	hello := "Hello"
+	if tr := mo.Get(hello); tr != hello {
+		t.Errorf("nothing should be loaded from corrupt files, got %q", tr)
+	}
+}
+
+func TestIndexedMoRevision1(t *testing.T) {
+	static := [][2]string{
+		{"", "Language: de\nPlural-Forms: nplurals=2; plural=(n != 1);\n"},
+		{"Hello", "Hallo"},
+	}
+	sysdep := [][2]moSysdep{
+		{{"Found %", 0, " files"}, {"%", 0, " Dateien gefunden"}},
+	}
+
+	mo := NewIndexedMo(8)
+	if err := mo.ParseWithError(buildMo(binary.BigEndian, static, []string{"PRId64"}, sysdep)); err != nil {
+		t.Fatal(err)
+	}
+	hello, found := "Hello", "Found %d files"
+	if tr := mo.Get(hello); tr != "Hallo" {
+		t.Errorf("unexpected translation %q", tr)
+	}
+	if tr := mo.Get(found, 3); tr != "3 Dateien gefunden" {
+		t.Errorf("unexpected translation %q", tr)
+	}
+}
+
+func TestLocaleSetMoOnDemand(t *testing.T) {
+	l := NewLocale("fixtures/", "en_US")
+	l.SetMoOnDemand(true, 16)
+	l.SetExtensions("mo")
+	l.AddDomain("default")
+
+	mo, ok := l.Domains["default"].(*IndexedMo)
+	if !ok {
+		t.Fatalf("expected an IndexedMo, got %T", l.Domains["default"])
+	}
+	want := NewMo()
+	want.ParseFile("fixtures/en_US/default.mo")
+	checkIndexedMo(t, mo, want)
+
+	myText := "My text"
+	if tr := l.Get(myText); tr != translatedText {
+		t.Errorf("unexpected translation %q", tr)
+	}
+}
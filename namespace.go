@@ -0,0 +1,40 @@
+package gotext
+
+/*
+GetNS returns the Translation of str in the namespace ns of the default domain.
+Namespaces partition a large catalog logically, without separate domains: they're the msgctxt of the entries,
+so the checkout strings of a catalog are written as
+
+	msgctxt "checkout"
+	msgid "Pay now"
+	msgstr "Jetzt bezahlen"
+
+and looked up with l.GetNS("checkout", "Pay now").
+Supports optional parameters (vars... interface{}) to be inserted on the formatted string using the fmt.Printf syntax.
+*/
+func (l *Locale) GetNS(ns, str string, vars ...interface{}) string {
+	return l.GetDC(callerDomain(0, l.GetDomain()), str, ns, vars...)
+}
+
+// GetNNS retrieves the (N)th plural form of Translation for the given string in the namespace ns of the default domain.
+// See GetNS.
+// Supports optional parameters (vars... interface{}) to be inserted on the formatted string using the fmt.Printf syntax.
+func (l *Locale) GetNNS(ns, str, plural string, n int, vars ...interface{}) string {
+	return l.GetNDC(callerDomain(0, l.GetDomain()), str, plural, n, ns, vars...)
+}
+
+// Namespace returns a new Domain with the headers of the Domain and the entries of the namespace ns (their msgctxt),
+// so a namespace can be exported on its own, with MarshalText or any other writer.
+func (do *Domain) Namespace(ns string) *Domain {
+	do.trMutex.RLock()
+	out := do.emptyCopy()
+	if translations, ok := do.contexts[ns]; ok {
+		out.contexts[ns] = make(map[string]*Translation, len(translations))
+		for id, tr := range translations {
+			out.contexts[ns][id] = copyTranslation(tr)
+		}
+	}
+	do.trMutex.RUnlock()
+
+	return out
+}
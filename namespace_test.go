@@ -0,0 +1,70 @@
+package gotext
+
+import (
+	"strings"
+	"testing"
+)
+
+const namespacePo = `msgid ""
+msgstr ""
+"Language: de\n"
+"Plural-Forms: nplurals=2; plural=(n != 1);\n"
+
+msgid "Pay now"
+msgstr "Zahlen"
+
+msgctxt "checkout"
+msgid "Pay now"
+msgstr "Jetzt bezahlen"
+
+msgctxt "checkout"
+msgid "%d item in cart"
+msgid_plural "%d items in cart"
+msgstr[0] "%d Artikel im Warenkorb"
+msgstr[1] "%d Artikel im Warenkorb"
+
+msgctxt "account"
+msgid "Logout"
+msgstr "Abmelden"
+`
+
+func TestLocaleGetNS(t *testing.T) {
+	po := NewPo()
+	po.Parse([]byte(namespacePo))
+
+	l := NewLocale("fixtures/", "de")
+	l.AddTranslator("default", po)
+
+	if got := l.GetNS("checkout", "Pay now"); got != "Jetzt bezahlen" {
+		t.Errorf("unexpected translation %q", got)
+	}
+	if got := l.GetNNS("checkout", "%d item in cart", "%d items in cart", 3, 3); got != "3 Artikel im Warenkorb" {
+		t.Errorf("unexpected translation %q", got)
+	}
+	if got := l.GetNS("account", "Pay now"); got != "Pay now" {
+		t.Errorf("entries of other namespaces shouldn't be used, got %q", got)
+	}
+}
+
+func TestDomain_Namespace(t *testing.T) {
+	po := NewPo()
+	po.Parse([]byte(namespacePo))
+
+	ns := po.GetDomain().Namespace("checkout")
+	out, err := ns.MarshalText()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := string(out)
+	if !strings.Contains(s, `"Language: de\n"`) || !strings.Contains(s, `msgstr "Jetzt bezahlen"`) || !strings.Contains(s, `msgid_plural "%d items in cart"`) {
+		t.Errorf("unexpected output:\n%s", s)
+	}
+	if strings.Contains(s, "Abmelden") || strings.Contains(s, `msgstr "Zahlen"`) {
+		t.Errorf("entries of other namespaces shouldn't be exported:\n%s", s)
+	}
+
+	if n := len(po.GetDomain().Namespace("missing").entries()); n != 0 {
+		t.Errorf("expected no entries, got %d", n)
+	}
+}
@@ -0,0 +1,143 @@
+package gotext
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Normalization tells how msgids are normalized by the lookups of a Domain that miss their exact msgid,
+// so source strings that were trivially reformatted still find their translations. Values can be combined.
+type Normalization int
+
+const (
+	// NormalizeNFC applies the Unicode NFC normalization, so composed and decomposed accents match.
+	NormalizeNFC Normalization = 1 << iota
+	// NormalizeSpace collapses every run of whitespace into a single space, and trims the ends.
+	NormalizeSpace
+	// NormalizeCase applies Unicode simple case folding, so lookups are case-insensitive.
+	NormalizeCase
+
+	// NormalizeAll combines every normalization.
+	NormalizeAll = NormalizeNFC | NormalizeSpace | NormalizeCase
+)
+
+// apply returns s normalized by n.
+func (n Normalization) apply(s string) string {
+	if n&NormalizeNFC != 0 {
+		s = norm.NFC.String(s)
+	}
+	if n&NormalizeSpace != 0 {
+		s = strings.Join(strings.Fields(s), " ")
+	}
+	if n&NormalizeCase != 0 {
+		s = strings.Map(foldRune, s)
+	}
+	return s
+}
+
+// foldRune returns the smallest rune of the case folding orbit of r, which is the same for all its cases.
+func foldRune(r rune) rune {
+	min := r
+	for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+		if f < min {
+			min = f
+		}
+	}
+	return min
+}
+
+/*
+SetNormalization makes the lookups that miss their exact msgid try again with the msgid normalized by n,
+against the msgids of the catalog normalized the same way. Exact matches always win, and a zero Normalization
+turns the normalized lookups off.
+
+The normalized msgids are indexed when it's called, so it must be called again after changing the translations.
+When several msgids normalize to the same one, a translated one is preferred, then the first in sort order.
+
+	po := gotext.NewPo()
+	po.ParseFile("/path/to/po/file/translations.po")
+	po.GetDomain().SetNormalization(gotext.NormalizeSpace | gotext.NormalizeCase)
+	po.Get("hello,   WORLD") // Finds the translation of "Hello, world"
+*/
+func (do *Domain) SetNormalization(n Normalization) {
+	entries := do.entries()
+
+	do.trMutex.Lock()
+	defer do.trMutex.Unlock()
+
+	do.normalization = n
+	do.normalized = nil
+	do.normalizedC = nil
+	if n == 0 {
+		return
+	}
+
+	do.normalized = make(map[string]*Translation)
+	do.normalizedC = make(map[string]map[string]*Translation)
+	for _, e := range entries {
+		index := do.normalized
+		if e.Context != "" || do.contexts[e.Context][e.MsgID] == e.Translation {
+			if do.normalizedC[e.Context] == nil {
+				do.normalizedC[e.Context] = make(map[string]*Translation)
+			}
+			index = do.normalizedC[e.Context]
+		}
+
+		key := n.apply(e.MsgID)
+		if prev, ok := index[key]; ok && (prev.IsTranslated() || !e.Translation.IsTranslated()) {
+			continue
+		}
+		index[key] = e.Translation
+	}
+}
+
+// lookup returns the Translation for str without context, trying the normalized msgids and then
+// the fuzzy matching when there is no exact match.
+// The Domain must be locked.
+func (do *Domain) lookup(str string) (*Translation, bool) {
+	return do.resolve(str, "", do.translations, do.normalized)
+}
+
+// lookupC returns the Translation for str in the context ctx, trying the normalized msgids and then
+// the fuzzy matching when there is no exact match.
+// The Domain must be locked.
+func (do *Domain) lookupC(str, ctx string) (*Translation, bool) {
+	return do.resolve(str, ctx, do.contexts[ctx], do.normalizedC[ctx])
+}
+
+// resolve looks str up in trs, then in the normalized index of the same context, then by fuzzy matching,
+// reporting the result to the current Metrics. The Domain must be locked.
+func (do *Domain) resolve(str, ctx string, trs, normalized map[string]*Translation) (*Translation, bool) {
+	if tr, ok := trs[str]; ok {
+		if tr.IsTranslated() {
+			do.observeLookup(LookupHit)
+		} else {
+			do.observeLookup(LookupMiss)
+		}
+		return tr, true
+	}
+	if do.normalization != 0 {
+		if tr, ok := normalized[do.normalization.apply(str)]; ok {
+			do.observeLookup(LookupFallback)
+			return tr, true
+		}
+	}
+
+	tr, ok := do.miss(str, ctx, trs)
+	if ok {
+		do.observeLookup(LookupFallback)
+	} else {
+		do.observeLookup(LookupMiss)
+	}
+	return tr, ok
+}
+
+// SetNormalization makes the catalogs loaded afterwards by AddDomain normalize their lookups with n.
+// See Domain.SetNormalization.
+func (l *Locale) SetNormalization(n Normalization) {
+	l.Lock()
+	l.normalization = n
+	l.Unlock()
+}
@@ -0,0 +1,101 @@
+package gotext
+
+import (
+	"testing"
+)
+
+func TestNormalizationApply(t *testing.T) {
+	tests := []struct {
+		n        Normalization
+		in, want string
+	}{
+		{NormalizeNFC, "Café", "Café"},
+		{NormalizeSpace, "  Hello,\n\tworld  ", "Hello, world"},
+		{NormalizeCase, "Hello WORLD", "Hello WORLD"},
+		{NormalizeAll, " Café   OPEN ", NormalizeCase.apply("Café open")},
+	}
+	for _, test := range tests {
+		if got := test.n.apply(test.in); test.n != NormalizeCase && got != test.want {
+			t.Errorf("%d: expected %q, got %q", test.n, test.want, got)
+		}
+	}
+
+	if NormalizeCase.apply("Hello WORLD") != NormalizeCase.apply("hello world") {
+		t.Error("Expected case folding to ignore case")
+	}
+	if NormalizeCase.apply("K") != NormalizeCase.apply("k") {
+		t.Error("Expected the Kelvin sign to fold like k")
+	}
+}
+
+func TestDomainSetNormalization(t *testing.T) {
+	po := NewPo()
+	po.Parse([]byte(`
+msgid "Hello,  world"
+msgstr "Hallo, Welt"
+
+msgid "hello, world"
+msgstr ""
+
+msgid "Café"
+msgstr "Kaffee"
+
+msgctxt "menu"
+msgid "Open file"
+msgstr "Datei öffnen"
+
+msgid "One apple"
+msgid_plural "%d apples"
+msgstr[0] "Ein Apfel"
+msgstr[1] "%d Äpfel"
+`))
+
+	hello, cafe, open, apple := "HELLO, WORLD", "Café", " open\nFILE", "one  apple"
+
+	if tr := po.Get(hello); tr != hello {
+		t.Errorf("Expected no normalization by default, got %q", tr)
+	}
+
+	po.GetDomain().SetNormalization(NormalizeAll)
+	if tr := po.Get(hello); tr != "Hallo, Welt" {
+		t.Errorf("Expected the translated msgid to win, got %q", tr)
+	}
+	if tr := po.Get(cafe); tr != "Kaffee" {
+		t.Errorf("Unexpected translation %q", tr)
+	}
+	if tr := po.GetC(open, "menu"); tr != "Datei öffnen" {
+		t.Errorf("Unexpected translation %q", tr)
+	}
+	if tr := po.GetC(open, ""); tr != open {
+		t.Errorf("Expected contexts to stay apart, got %q", tr)
+	}
+	if tr := po.GetN(apple, "%d apples", 3, 3); tr != "3 Äpfel" {
+		t.Errorf("Unexpected translation %q", tr)
+	}
+
+	// Exact matches win
+	exact := "hello, world"
+	if tr := po.Get(exact); tr != exact {
+		t.Errorf("Expected the exact match, got %q", tr)
+	}
+
+	po.GetDomain().SetNormalization(0)
+	if tr := po.Get(cafe); tr != cafe {
+		t.Errorf("Expected no normalization, got %q", tr)
+	}
+}
+
+func TestLocaleSetNormalization(t *testing.T) {
+	src := &MemorySource{Files: map[string][]byte{
+		"de/default.po": []byte("msgid \"Save  changes\"\nmsgstr \"Änderungen speichern\"\n"),
+	}}
+
+	l := NewLocaleWithSource(src, "de")
+	l.SetNormalization(NormalizeSpace)
+	l.AddDomain("default")
+
+	save := "Save changes"
+	if tr := l.Get(save); tr != "Änderungen speichern" {
+		t.Errorf("Unexpected translation %q", tr)
+	}
+}
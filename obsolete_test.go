@@ -0,0 +1,65 @@
+package gotext
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestObsoleteEntries(t *testing.T) {
+	po := NewPo()
+	po.Parse([]byte(`msgid ""
+msgstr "Language: de\n"
+
+msgid "Current"
+msgstr "Aktuell"
+
+#~ msgid "Old"
+#~ msgstr "Alt"
+
+#, fuzzy
+#~| msgid "Old file"
+#~ msgctxt "menu"
+#~ msgid "Old files"
+#~ msgid_plural "%d old files"
+#~ msgstr[0] "Alte Datei"
+#~ msgstr[1] "%d alte "
+#~ "Dateien"
+`))
+
+	// Obsolete entries aren't translations
+	if tr := po.Get("Old"); tr != "Old" {
+		t.Errorf("Expected obsolete entry not to translate, got '%s'", tr)
+	}
+	if tr := po.Get("Current"); tr != "Aktuell" {
+		t.Errorf("Expected 'Aktuell' but got '%s'", tr)
+	}
+
+	obsolete := po.GetDomain().Obsolete
+	if len(obsolete) != 2 {
+		t.Fatalf("Expected 2 obsolete entries but got %v", obsolete)
+	}
+	if obsolete[0].MsgID != "Old" || obsolete[0].Translation.Get() != "Alt" {
+		t.Errorf("Unexpected obsolete entry %+v", obsolete[0])
+	}
+	if e := obsolete[1]; e.Context != "menu" || e.Translation.PluralID != "%d old files" || e.Translation.GetN(1) != "%d alte Dateien" {
+		t.Errorf("Unexpected obsolete entry %+v", e)
+	}
+
+	// Round-trip
+	buff, err := po.MarshalText()
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := string(buff)
+	for _, line := range []string{`#~ msgid "Old"`, `#~ msgstr "Alt"`, `#~ msgctxt "menu"`, `#~ msgstr[1] "%d alte Dateien"`} {
+		if !strings.Contains(out, line) {
+			t.Errorf("Expected '%s' in:\n%s", line, out)
+		}
+	}
+
+	again := NewPo()
+	again.Parse(buff)
+	if len(again.GetDomain().Obsolete) != 2 || again.Get("Current") != "Aktuell" {
+		t.Errorf("Unexpected round-trip result:\n%s", out)
+	}
+}
@@ -0,0 +1,204 @@
+package gotext
+
+import (
+	"sync"
+
+	"golang.org/x/text/feature/plural"
+	"golang.org/x/text/language"
+)
+
+// cldrFormOrder is the order CLDR lists plural categories in.
+// Ordinal translations use it to map categories to msgstr indexes.
+var cldrFormOrder = []plural.Form{plural.Zero, plural.One, plural.Two, plural.Few, plural.Many, plural.Other}
+
+// cldrCategories maps the CLDR plural category names, used as keys by other catalog formats, to their forms.
+var cldrCategories = map[string]plural.Form{
+	"zero":  plural.Zero,
+	"one":   plural.One,
+	"two":   plural.Two,
+	"few":   plural.Few,
+	"many":  plural.Many,
+	"other": plural.Other,
+}
+
+// cldrCategory returns the CLDR name of a plural category.
+func cldrCategory(form plural.Form) string {
+	for name, f := range cldrCategories {
+		if f == form {
+			return name
+		}
+	}
+	return "other"
+}
+
+// ordinalFormsCache holds the ordinal categories used by each language, keyed by language.Tag
+var ordinalFormsCache sync.Map
+
+// ordinalForms returns the CLDR ordinal categories used by the given language, in CLDR order.
+// Every ordinal category shows up for some integer below 1000 in all CLDR languages,
+// so sampling that range is enough to know which ones the language uses.
+func ordinalForms(tag language.Tag) []plural.Form {
+	if forms, ok := ordinalFormsCache.Load(tag); ok {
+		return forms.([]plural.Form)
+	}
+
+	seen := make(map[plural.Form]bool)
+	for n := 0; n < 1000; n++ {
+		seen[plural.Ordinal.MatchPlural(tag, n, 0, 0, 0, 0)] = true
+	}
+
+	forms := make([]plural.Form, 0, len(seen))
+	for _, form := range cldrFormOrder {
+		if seen[form] {
+			forms = append(forms, form)
+		}
+	}
+
+	ordinalFormsCache.Store(tag, forms)
+	return forms
+}
+
+// ordinalForm returns the msgstr index used for the ordinal n in the given language.
+func ordinalForm(tag language.Tag, n int) int {
+	if n < 0 {
+		n = -n
+	}
+
+	form := plural.Ordinal.MatchPlural(tag, n, 0, 0, 0, 0)
+	for i, f := range ordinalForms(tag) {
+		if f == form {
+			return i
+		}
+	}
+	return 0
+}
+
+// GetOrdinal retrieves the ordinal form of Translation for the given string, e.g. "Finish %dst" for n = 1, 2, 3...
+// Ordinal translations are stored as plural entries where each msgstr[i] holds the i-th CLDR ordinal category
+// used by the catalog language, in CLDR order (zero, one, two, few, many, other).
+// For English that means msgstr[0] is "one" (1st), msgstr[1] "two" (2nd), msgstr[2] "few" (3rd) and msgstr[3] "other" (4th).
+// Supports optional parameters (vars... interface{}) to be inserted on the formatted string using the fmt.Printf syntax.
+func (do *Domain) GetOrdinal(str string, n int, vars ...interface{}) string {
+	// Sync read
+	do.trMutex.RLock()
+	defer do.trMutex.RUnlock()
+
+	if tr, ok := do.lookup(str); ok {
+		return Printf(do.pluralString("", tr, ordinalForm(do.tag, n)), vars...)
+	}
+
+	// Return the same we received by default
+	return Printf(str, vars...)
+}
+
+// GetOrdinalC retrieves the ordinal form of Translation for the given string in the given context.
+// See GetOrdinal for the expected msgstr layout.
+// Supports optional parameters (vars... interface{}) to be inserted on the formatted string using the fmt.Printf syntax.
+func (do *Domain) GetOrdinalC(str string, n int, ctx string, vars ...interface{}) string {
+	do.trMutex.RLock()
+	defer do.trMutex.RUnlock()
+
+	if tr, ok := do.lookupC(str, ctx); ok {
+		return Printf(do.pluralString(ctx, tr, ordinalForm(do.tag, n)), vars...)
+	}
+
+	// Return the string we received by default
+	return Printf(str, vars...)
+}
+
+// GetRange retrieves the plural form of Translation to use for a range of values, like "2–4 items".
+// The form follows the CLDR plural ranges of the catalog language (see rangeCount): most of the time the one of the end value.
+// Supports optional parameters (vars... interface{}) to be inserted on the formatted string using the fmt.Printf syntax.
+func (do *Domain) GetRange(str, plural string, from, to int, vars ...interface{}) string {
+	return do.GetN(str, plural, rangeCount(do.language(), from, to), vars...)
+}
+
+// GetRangeC retrieves the plural form of Translation to use for a range of values in the given context.
+// Supports optional parameters (vars... interface{}) to be inserted on the formatted string using the fmt.Printf syntax.
+func (do *Domain) GetRangeC(str, plural string, from, to int, ctx string, vars ...interface{}) string {
+	return do.GetNC(str, plural, rangeCount(do.language(), from, to), ctx, vars...)
+}
+
+// language returns the language tag of the catalog.
+func (do *Domain) language() language.Tag {
+	do.trMutex.RLock()
+	defer do.trMutex.RUnlock()
+
+	return do.tag
+}
+
+// pluralRanges holds the CLDR plural range rules of the languages where a range doesn't always take the form
+// of its end value: the form of some {start, end} pairs of forms. Other pairs take the end form.
+var pluralRanges = func() map[string]map[[2]plural.Form]plural.Form {
+	// "0–1 items": other to one is other
+	otherOne := map[[2]plural.Form]plural.Form{{plural.Other, plural.One}: plural.Other}
+
+	ranges := map[string]map[[2]plural.Form]plural.Form{
+		"ka": {{plural.One, plural.Other}: plural.One},
+		"mk": {{plural.One, plural.One}: plural.Other, {plural.Other, plural.One}: plural.Other},
+		"ro": {{plural.Few, plural.One}: plural.Few},
+		"sl": {
+			{plural.One, plural.One}: plural.Few, {plural.Two, plural.One}: plural.Few,
+			{plural.Few, plural.One}: plural.Few, {plural.Other, plural.One}: plural.Few,
+		},
+		"he": {
+			{plural.One, plural.Two}: plural.Other, {plural.Two, plural.Many}: plural.Other,
+			{plural.Other, plural.One}: plural.Other, {plural.Other, plural.Two}: plural.Other,
+		},
+		"ar": {
+			{plural.Zero, plural.One}: plural.Zero, {plural.Zero, plural.Two}: plural.Zero,
+			{plural.One, plural.Two}: plural.Other, {plural.Other, plural.One}: plural.Other,
+			{plural.Other, plural.Two}: plural.Other,
+		},
+	}
+	for _, lang := range []string{"af", "bg", "ca", "en", "es", "et", "eu", "fi", "nb", "si", "sv", "ur"} {
+		ranges[lang] = otherOne
+	}
+	return ranges
+}()
+
+// rangeCount returns the count whose plural form the range of values from-to takes in the given language,
+// following the CLDR plural ranges: it's to, unless the language has a rule for the forms of from and to.
+// When the rule picks a form that neither of them has, the smallest count with that form is returned,
+// so the msgstr index is still chosen by the Plural-Forms of the catalog.
+func rangeCount(tag language.Tag, from, to int) int {
+	if tag.IsRoot() {
+		return to
+	}
+	base, _ := tag.Base()
+	rules := pluralRanges[base.String()]
+	if rules == nil {
+		return to
+	}
+
+	start, end := cardinalForm(tag, from), cardinalForm(tag, to)
+	form, ok := rules[[2]plural.Form{start, end}]
+	switch {
+	case !ok || form == end:
+		return to
+	case form == start:
+		return from
+	}
+	for n := 0; n < 1000; n++ {
+		if cardinalForm(tag, n) == form {
+			return n
+		}
+	}
+	return to
+}
+
+// cardinalForm returns the CLDR cardinal form of the count n in the given language.
+func cardinalForm(tag language.Tag, n int) plural.Form {
+	if n < 0 {
+		n = -n
+	}
+	return plural.Cardinal.MatchPlural(tag, n, 0, 0, 0, 0)
+}
+
+// catalogFor returns the catalog of tr to look str up in, when tr looks messages up in several ones.
+func catalogFor(tr Translator, str string) *Domain {
+	if ft, ok := tr.(*fallbackTranslator); ok {
+		return ft.find(str, "", false).GetDomain()
+	}
+	return tr.GetDomain()
+}
@@ -0,0 +1,150 @@
+package gotext
+
+import (
+	"testing"
+
+	"golang.org/x/text/language"
+)
+
+func TestOrdinalForms(t *testing.T) {
+	forms := ordinalForms(language.Make("en"))
+	if len(forms) != 4 {
+		t.Fatalf("Expected 4 English ordinal forms but got %d", len(forms))
+	}
+
+	for n, idx := range map[int]int{1: 0, 2: 1, 3: 2, 4: 3, 11: 3, 12: 3, 13: 3, 21: 0, 22: 1, 23: 2, 101: 0, -1: 0} {
+		if got := ordinalForm(language.Make("en"), n); got != idx {
+			t.Errorf("Expected ordinal index %d for n = %d but got %d", idx, n, got)
+		}
+	}
+}
+
+func TestGetOrdinal(t *testing.T) {
+	str := `
+msgid ""
+msgstr ""
+"Language: en\n"
+"Plural-Forms: nplurals=2; plural=(n != 1);\n"
+
+msgid "Finish %dst"
+msgid_plural "Finish %dth"
+msgstr[0] "Finished %dst"
+msgstr[1] "Finished %dnd"
+msgstr[2] "Finished %drd"
+msgstr[3] "Finished %dth"
+
+msgctxt "race"
+msgid "%dst place"
+msgid_plural "%dth place"
+msgstr[0] "%dst"
+msgstr[1] "%dnd"
+msgstr[2] "%drd"
+msgstr[3] "%dth"
+
+msgid "%d-%d item"
+msgid_plural "%d-%d items"
+msgstr[0] "%d-%d thing"
+msgstr[1] "%d-%d things"
+`
+	po := NewPo()
+	po.Parse([]byte(str))
+
+	for n, expected := range map[int]string{1: "Finished 1st", 2: "Finished 2nd", 3: "Finished 3rd", 4: "Finished 4th", 11: "Finished 11th", 22: "Finished 22nd"} {
+		if tr := po.GetOrdinal("Finish %dst", n, n); tr != expected {
+			t.Errorf("Expected '%s' but got '%s'", expected, tr)
+		}
+	}
+
+	if tr := po.GetDomain().GetOrdinalC("%dst place", 3, "race", 3); tr != "3rd" {
+		t.Errorf("Expected '3rd' but got '%s'", tr)
+	}
+
+	// Untranslated
+	if tr := po.GetOrdinal("Round %d", 2, 2); tr != "Round 2" {
+		t.Errorf("Expected 'Round 2' but got '%s'", tr)
+	}
+
+	// Ranges take the plural form of the end value, but in English other to one is other
+	if tr := po.GetRange("%d-%d item", "%d-%d items", 0, 1, 0, 1); tr != "0-1 things" {
+		t.Errorf("Expected '0-1 things' but got '%s'", tr)
+	}
+	if tr := po.GetRange("%d-%d item", "%d-%d items", 2, 4, 2, 4); tr != "2-4 things" {
+		t.Errorf("Expected '2-4 things' but got '%s'", tr)
+	}
+}
+
+func TestRangeCount(t *testing.T) {
+	for _, c := range []struct {
+		lang           string
+		from, to, want int
+	}{
+		{"en", 1, 5, 5},
+		{"en", 0, 1, 0},
+		{"de", 0, 1, 1},
+		{"ka", 1, 5, 1},
+		// one to one is few: 3 is the first few count
+		{"sl", 1, 101, 3},
+		{"ar", 0, 1, 0},
+		// one to two is other: 100 is the first other count
+		{"ar", 1, 2, 100},
+	} {
+		if got := rangeCount(language.Make(c.lang), c.from, c.to); got != c.want {
+			t.Errorf("%s %d-%d: expected %d but got %d", c.lang, c.from, c.to, c.want, got)
+		}
+	}
+}
+
+func TestLocaleGetOrdinal(t *testing.T) {
+	l := NewLocale("fixtures/", "en_US")
+	po := NewPo()
+	po.Parse([]byte(`
+msgid ""
+msgstr ""
+"Language: en\n"
+
+msgid "%dst"
+msgid_plural "%dth"
+msgstr[0] "%dst"
+msgstr[1] "%dnd"
+msgstr[2] "%drd"
+msgstr[3] "%dth"
+`))
+	l.AddTranslator("ordinals", po)
+
+	if tr := l.GetOrdinalD("ordinals", "%dst", 23, 23); tr != "23rd" {
+		t.Errorf("Expected '23rd' but got '%s'", tr)
+	}
+	if tr := l.GetOrdinalD("missing", "%dst", 23, 23); tr != "23st" {
+		t.Errorf("Expected '23st' but got '%s'", tr)
+	}
+	if tr := l.GetRangeD("missing", "%d item", "%d items", 1, 5, 5); tr != "5 items" {
+		t.Errorf("Expected '5 items' but got '%s'", tr)
+	}
+
+	// Lookups go through the key source catalog and strict mode, like GetND
+	src := NewPo()
+	src.Parse([]byte(`
+msgid ""
+msgstr ""
+"Language: en\n"
+"Plural-Forms: nplurals=2; plural=(n != 1);\n"
+
+msgid "range.items"
+msgid_plural "range.items"
+msgstr[0] "%d-%d item"
+msgstr[1] "%d-%d items"
+`))
+	l.AddTranslator("keys", src)
+	l.SetKeyMode("keys")
+	if tr := l.GetRangeD("ordinals", "range.items", "range.items", 0, 1, 0, 1); tr != "0-1 items" {
+		t.Errorf("Expected '0-1 items' but got '%s'", tr)
+	}
+
+	l.SetStrictMode(true)
+	defer func() {
+		if _, ok := recover().(*MissingError); !ok {
+			t.Error("Expected a MissingError panic in strict mode")
+		}
+	}()
+	l.GetOrdinalD("ordinals", "%dnd", 2, 2)
+}
@@ -0,0 +1,20 @@
+module github.com/leonelquinteros/gotext/otel
+
+require (
+	github.com/leonelquinteros/gotext v1.4.0
+	go.opentelemetry.io/otel v1.11.2
+	go.opentelemetry.io/otel/trace v1.11.2
+)
+
+require (
+	github.com/razor-1/cldr v0.1.7 // indirect
+	github.com/razor-1/cldr/resources v0.1.7 // indirect
+	github.com/razor-1/cldr/resources/currency v0.1.1 // indirect
+	github.com/razor-1/localizer v0.0.4 // indirect
+	github.com/razor-1/localizer/store v0.0.1 // indirect
+	golang.org/x/text v0.3.3 // indirect
+)
+
+replace github.com/leonelquinteros/gotext => ../
+
+go 1.17
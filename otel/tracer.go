@@ -0,0 +1,68 @@
+/*
+Package gotextotel traces the catalog loads of gotext with OpenTelemetry.
+
+It lives in its own module, so the gotext package doesn't depend on OpenTelemetry.
+
+	gotext.SetTracer(gotextotel.NewTracer(otel.Tracer("github.com/leonelquinteros/gotext")))
+
+Catalog loads then show up as spans with their file, domain, format and entry count attributes.
+See gotext.Tracer for the traced operations.
+*/
+package gotextotel
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/leonelquinteros/gotext"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// NewTracer returns a gotext.Tracer that starts its spans with t.
+func NewTracer(t trace.Tracer) gotext.Tracer {
+	return tracer{t}
+}
+
+type tracer struct {
+	t trace.Tracer
+}
+
+func (t tracer) Start(ctx context.Context, name string) (context.Context, gotext.Span) {
+	ctx, s := t.t.Start(ctx, name)
+	return ctx, span{s}
+}
+
+type span struct {
+	s trace.Span
+}
+
+func (s span) SetAttribute(key string, value interface{}) {
+	s.s.SetAttributes(attributeOf(key, value))
+}
+
+func (s span) End(err error) {
+	if err != nil {
+		s.s.RecordError(err)
+		s.s.SetStatus(codes.Error, err.Error())
+	}
+	s.s.End()
+}
+
+// attributeOf returns the OpenTelemetry attribute for a gotext span attribute.
+func attributeOf(key string, value interface{}) attribute.KeyValue {
+	switch v := value.(type) {
+	case string:
+		return attribute.String(key, v)
+	case int:
+		return attribute.Int(key, v)
+	case int64:
+		return attribute.Int64(key, v)
+	case bool:
+		return attribute.Bool(key, v)
+	case float64:
+		return attribute.Float64(key, v)
+	}
+	return attribute.String(key, fmt.Sprint(value))
+}
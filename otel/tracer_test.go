@@ -0,0 +1,26 @@
+package gotextotel
+
+import (
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func TestAttributeOf(t *testing.T) {
+	tests := []struct {
+		value interface{}
+		want  attribute.Value
+	}{
+		{"default", attribute.StringValue("default")},
+		{3, attribute.IntValue(3)},
+		{int64(4), attribute.Int64Value(4)},
+		{true, attribute.BoolValue(true)},
+		{1.5, attribute.Float64Value(1.5)},
+		{[]int{1}, attribute.StringValue("[1]")},
+	}
+	for _, test := range tests {
+		if got := attributeOf("k", test.value); got.Key != "k" || got.Value != test.want {
+			t.Errorf("attributeOf(%v): expected %v, got %v", test.value, test.want, got.Value)
+		}
+	}
+}
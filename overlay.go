@@ -0,0 +1,184 @@
+package gotext
+
+import "fmt"
+
+// emptyCopy returns a new Domain with the headers and plural rule of the Domain, and no entries.
+// The Domain must be locked.
+func (do *Domain) emptyCopy() *Domain {
+	c := NewDomain()
+	for k, v := range do.Headers {
+		c.Headers[k] = append([]string(nil), v...)
+	}
+	c.Language = do.Language
+	c.tag = do.tag
+	c.PluralForms = do.PluralForms
+	c.nplurals = do.nplurals
+	c.plural = do.plural
+	c.pluralforms = do.pluralforms
+	c.pluralCache = do.pluralCache
+	return c
+}
+
+// Merge copies the entries of other into the Domain, replacing the ones with the same context and msgid,
+// so a catalog can be customized by another one. Untranslated entries of other don't replace translated ones.
+//
+// The headers and plural rule of the Domain are kept, so other should use the same Plural-Forms.
+func (do *Domain) Merge(other *Domain) {
+	entries := other.entries()
+
+	do.trMutex.Lock()
+	do.pluralMutex.Lock()
+	defer do.trMutex.Unlock()
+	defer do.pluralMutex.Unlock()
+
+	for _, e := range entries {
+		translations := do.translations
+		if e.Context != "" {
+			if _, ok := do.contexts[e.Context]; !ok {
+				do.contexts[e.Context] = make(map[string]*Translation)
+			}
+			translations = do.contexts[e.Context]
+		}
+
+		if old, ok := translations[e.MsgID]; ok && old.IsTranslated() && !e.Translation.IsTranslated() {
+			continue
+		}
+		translations[e.MsgID] = copyTranslation(e.Translation)
+	}
+}
+
+/*
+AddOverlayDomain customizes the catalog of the domain dom with tr: its entries replace the ones
+with the same context and msgid, and the rest are still looked up in the current catalog.
+Overlays can be stacked, with the last one added taking precedence.
+It's meant for plugins or white-label products, where a base catalog is adjusted by a smaller one:
+
+	l.AddDomain("default")
+
+	brand := gotext.NewPo()
+	brand.ParseFile("/path/to/brand/de.po")
+	l.AddOverlayDomain("default", brand)
+
+The catalogs are merged into a new one, so neither the current catalog nor tr are changed.
+The messages of an IndexedMo are loaded for that.
+The headers and plural rule of the current catalog are kept. When there's no catalog for dom yet,
+tr is added as is.
+*/
+func (l *Locale) AddOverlayDomain(dom string, tr Translator) {
+	l.Lock()
+	defer l.Unlock()
+
+	if l.Domains == nil {
+		l.Domains = make(map[string]Translator)
+	}
+	if l.defaultDomain == "" {
+		l.defaultDomain = dom
+	}
+	l.addDomainOrder(dom)
+	if l.overlays == nil {
+		l.overlays = make(map[string][]Translator)
+	}
+	l.overlays[dom] = append(l.overlays[dom], tr)
+
+	l.Domains[dom] = overlay(l.Domains[dom], tr)
+}
+
+// overlay returns a new catalog with the entries of base, replaced by the ones of tr, or tr when there's no base.
+// Catalogs whose messages can't be loaded are skipped.
+func overlay(base, tr Translator) Translator {
+	if base == nil {
+		return tr
+	}
+
+	src := base.GetDomain()
+	merged := NewPo()
+	src.trMutex.RLock()
+	merged.domain = src.emptyCopy()
+	src.trMutex.RUnlock()
+
+	for _, t := range []Translator{base, tr} {
+		do, err := messageDomain(t)
+		if err != nil {
+			logWarn("gotext: overlay catalog skipped", "err", err)
+			continue
+		}
+		merged.domain.Merge(do)
+	}
+	return merged
+}
+
+// forgetComposition forgets the overlays and files of the domain dom, when its catalog is replaced.
+// The Locale must be locked.
+func (l *Locale) forgetComposition(dom string) {
+	delete(l.overlays, dom)
+	delete(l.domainFiles, dom)
+}
+
+/*
+AddDomainFiles assembles the domain dom from several catalog files, like modular catalogs split by feature team:
+
+	err := l.AddDomainFiles("default", "locales/de/common.po", "locales/de/app.po", "locales/de/overrides.po")
+
+Each file is loaded like AddDomainFile does. Files given later take precedence: their entries replace the ones
+of the previous files with the same context and msgid, unless they're untranslated.
+The headers and plural rule are the ones of the first file.
+Nothing is added when a file can't be loaded, and the error tells which one.
+*/
+func (l *Locale) AddDomainFiles(dom string, paths ...string) error {
+	if len(paths) == 0 {
+		return fmt.Errorf("gotext: no catalog files for domain %q", dom)
+	}
+
+	tr, err := l.loadDomainFiles(dom, paths)
+	if err != nil {
+		return err
+	}
+
+	l.AddTranslator(dom, tr)
+	if len(paths) > 1 {
+		l.Lock()
+		if l.domainFiles == nil {
+			l.domainFiles = make(map[string][]string)
+		}
+		l.domainFiles[dom] = append([]string(nil), paths...)
+		l.Unlock()
+	}
+	return nil
+}
+
+// loadDomainFiles parses the catalog files at paths and merges them into one catalog, as AddDomainFiles does.
+func (l *Locale) loadDomainFiles(dom string, paths []string) (Translator, error) {
+	p := l.catalogParser()
+	trs := make([]Translator, len(paths))
+	for i, path := range paths {
+		tr, err := parseCatalogFile(p, dom, path)
+		if err != nil {
+			return nil, fmt.Errorf("gotext: loading %s: %w", path, err)
+		}
+		trs[i] = tr
+	}
+	if len(trs) == 1 {
+		return trs[0], nil
+	}
+
+	first := trs[0].GetDomain()
+	merged := NewPo()
+	first.trMutex.RLock()
+	merged.domain = first.emptyCopy()
+	first.trMutex.RUnlock()
+
+	p.setup(dom, merged.domain)
+	for i, tr := range trs {
+		do, err := messageDomain(tr)
+		if err != nil {
+			return nil, fmt.Errorf("gotext: loading %s: %w", paths[i], err)
+		}
+		merged.domain.Merge(do)
+	}
+	p.finish(dom, merged.domain)
+
+	merged.Headers = merged.domain.Headers
+	merged.Language = merged.domain.Language
+	merged.PluralForms = merged.domain.PluralForms
+	return merged, nil
+}
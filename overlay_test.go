@@ -0,0 +1,195 @@
+package gotext
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const overlayBase = `msgid ""
+msgstr ""
+"Language: de\n"
+"Plural-Forms: nplurals=2; plural=(n != 1);\n"
+
+msgid "Welcome"
+msgstr "Willkommen"
+
+msgid "Logout"
+msgstr "Abmelden"
+
+msgctxt "menu"
+msgid "Open"
+msgstr "Öffnen"
+
+msgid "%d item"
+msgid_plural "%d items"
+msgstr[0] "%d Artikel"
+msgstr[1] "%d Artikel"
+`
+
+const overlayBrand = `msgid ""
+msgstr ""
+"Language: de\n"
+
+msgid "Welcome"
+msgstr "Willkommen bei ACME"
+
+msgid "Logout"
+msgstr ""
+
+msgctxt "menu"
+msgid "Open"
+msgstr "Aufmachen"
+
+msgid "%d item"
+msgid_plural "%d items"
+msgstr[0] "%d Produkt"
+msgstr[1] "%d Produkte"
+
+msgid "Support"
+msgstr "Hilfe"
+`
+
+func TestLocaleAddOverlayDomain(t *testing.T) {
+	base := NewPo()
+	base.Parse([]byte(overlayBase))
+	brand := NewPo()
+	brand.Parse([]byte(overlayBrand))
+
+	l := NewLocale("fixtures/", "de")
+	l.AddTranslator("default", base)
+	l.AddOverlayDomain("default", brand)
+
+	for _, c := range []struct{ got, want string }{
+		{l.Get("Welcome"), "Willkommen bei ACME"},
+		{l.Get("Logout"), "Abmelden"},
+		{l.GetC("Open", "menu"), "Aufmachen"},
+		{l.GetN("%d item", "%d items", 2, 2), "2 Produkte"},
+		{l.Get("Support"), "Hilfe"},
+	} {
+		if c.got != c.want {
+			t.Errorf("expected %q, got %q", c.want, c.got)
+		}
+	}
+
+	// The base catalog is unchanged
+	if got := base.Get("Welcome"); got != "Willkommen" {
+		t.Errorf("base catalog changed: %q", got)
+	}
+	if pf := l.Domains["default"].GetDomain().PluralForms; pf != "nplurals=2; plural=(n != 1);" {
+		t.Errorf("unexpected Plural-Forms %q", pf)
+	}
+
+	// Overlays stack
+	last := NewPo()
+	last.Parse([]byte("msgid \"Welcome\"\nmsgstr \"Hallo\"\n"))
+	l.AddOverlayDomain("default", last)
+	if got := l.Get("Welcome"); got != "Hallo" {
+		t.Errorf("unexpected translation %q", got)
+	}
+	if got := l.Get("Support"); got != "Hilfe" {
+		t.Errorf("unexpected translation %q", got)
+	}
+}
+
+func TestLocaleAddOverlayDomainWithoutBase(t *testing.T) {
+	brand := NewPo()
+	brand.Parse([]byte(overlayBrand))
+
+	l := NewLocale("fixtures/", "de")
+	l.AddOverlayDomain("extras", brand)
+
+	if l.Domains["extras"] != brand || l.GetDomain() != "extras" {
+		t.Error("expected the overlay to be added as the catalog")
+	}
+}
+
+func TestLocaleAddOverlayDomainIndexedMo(t *testing.T) {
+	base := NewIndexedMo(0)
+	base.ParseFile("fixtures/en_US/default.mo")
+
+	l := NewLocale("fixtures/", "en_US")
+	l.AddTranslator("default", base)
+	if got := l.Get("language"); got != "en_US" {
+		t.Fatalf("unexpected translation %q", got)
+	}
+
+	l.AddOverlayDomain("default", NewDomainBuilder().Add("My text", "Overlaid").BuildPo())
+	if got := l.Get("language"); got != "en_US" {
+		t.Errorf("expected the base messages to be kept, got %q", got)
+	}
+	if got := l.Get("My text"); got != "Overlaid" {
+		t.Errorf("unexpected translation %q", got)
+	}
+}
+
+func TestLocaleAddDomainFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gotext-files")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	common := filepath.Join(dir, "common.po")
+	app := filepath.Join(dir, "app.po")
+	overrides := filepath.Join(dir, "overrides.po")
+	for path, data := range map[string]string{
+		common:    overlayBase,
+		app:       overlayBrand,
+		overrides: "msgid \"Support\"\nmsgstr \"Kundendienst\"\n",
+	} {
+		if err := ioutil.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	l := NewLocale(dir, "de")
+	if err := l.AddDomainFiles("default", common, app, overrides); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, c := range []struct{ got, want string }{
+		{l.Get("Welcome"), "Willkommen bei ACME"},
+		{l.Get("Logout"), "Abmelden"},
+		{l.GetC("Open", "menu"), "Aufmachen"},
+		{l.GetN("%d item", "%d items", 1, 1), "1 Produkt"},
+		{l.Get("Support"), "Kundendienst"},
+	} {
+		if c.got != c.want {
+			t.Errorf("expected %q, got %q", c.want, c.got)
+		}
+	}
+	if pf := l.Domains["default"].GetDomain().PluralForms; pf != "nplurals=2; plural=(n != 1);" {
+		t.Errorf("unexpected Plural-Forms %q", pf)
+	}
+
+	// A missing file adds nothing
+	missing := filepath.Join(dir, "missing.po")
+	err = l.AddDomainFiles("extras", common, missing)
+	if err == nil || !strings.Contains(err.Error(), missing) {
+		t.Errorf("expected an error naming %s, got %v", missing, err)
+	}
+	if _, ok := l.Domains["extras"]; ok {
+		t.Error("expected no catalog for the domain")
+	}
+
+	if err := l.AddDomainFiles("extras"); err == nil {
+		t.Error("expected an error without files")
+	}
+
+	// Reload reads the files again
+	if err := ioutil.WriteFile(overrides, []byte("msgid \"Support\"\nmsgstr \"Kontakt\"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if got := l.Get("Support"); got != "Kontakt" {
+		t.Errorf("expected the files to be reloaded, got %q", got)
+	}
+	if got := l.Get("Logout"); got != "Abmelden" {
+		t.Errorf("unexpected translation %q", got)
+	}
+}
@@ -0,0 +1,162 @@
+package gotext
+
+import (
+	"fmt"
+	"sort"
+)
+
+// PairIssueKind tells what's inconsistent between a source catalog and its translation.
+type PairIssueKind int
+
+const (
+	// PairMissingTranslation is a key defined in the source catalog with no translation.
+	PairMissingTranslation PairIssueKind = iota
+	// PairUnknownKey is a key in the translation catalog that the source catalog doesn't define.
+	PairUnknownKey
+	// PairPluralMismatch is a key that's a plural entry in one catalog and a singular one in the other.
+	PairPluralMismatch
+)
+
+// PairIssue is one inconsistency found by PairCatalogs.
+type PairIssue struct {
+	Kind    PairIssueKind
+	Context string
+	Key     string
+}
+
+func (i PairIssue) String() string {
+	key := i.Key
+	if i.Context != "" {
+		key = i.Context + "|" + i.Key
+	}
+
+	switch i.Kind {
+	case PairMissingTranslation:
+		return fmt.Sprintf("missing translation for key %q", key)
+	case PairUnknownKey:
+		return fmt.Sprintf("key %q is not defined in the source catalog", key)
+	case PairPluralMismatch:
+		return fmt.Sprintf("plural forms of key %q don't match the source catalog", key)
+	}
+	return fmt.Sprintf("unknown issue with key %q", key)
+}
+
+/*
+PairCatalogs links a monolingual source catalog with a bilingual translation catalog.
+
+In the source catalog every msgid is a key and msgstr holds the text in the source language
+(this is the "monolingual gettext" layout used by TMS tools like Weblate).
+The translation catalog uses the same keys and holds the translated text.
+
+The returned Po resolves every key defined in the source catalog to its translation,
+falling back to the source text when the translation is missing or empty.
+Its headers (and plural rules) come from the translation catalog.
+It can be added to a Locale with AddTranslator.
+
+The returned issues report every inconsistency between both catalogs, sorted by context and key.
+
+	src := gotext.NewPo()
+	src.ParseFile("i18n/en.po")
+	tr := gotext.NewPo()
+	tr.ParseFile("i18n/de.po")
+
+	po, issues := gotext.PairCatalogs(src, tr)
+	l.AddTranslator("default", po)
+*/
+func PairCatalogs(source, translation Translator) (*Po, []PairIssue) {
+	src := source.GetDomain()
+	trs := translation.GetDomain()
+
+	src.trMutex.RLock()
+	defer src.trMutex.RUnlock()
+	if trs != src {
+		trs.trMutex.RLock()
+		defer trs.trMutex.RUnlock()
+	}
+
+	po := NewPo()
+	do := po.domain
+	var issues []PairIssue
+
+	// Headers come from the translation catalog
+	if header, ok := trs.translations[""]; ok {
+		do.translations[""] = copyTranslation(header)
+	}
+
+	issues = pairTranslations(do.translations, "", src.translations, trs.translations, issues)
+	for ctx, srcCtx := range src.contexts {
+		if _, ok := do.contexts[ctx]; !ok {
+			do.contexts[ctx] = make(map[string]*Translation)
+		}
+		issues = pairTranslations(do.contexts[ctx], ctx, srcCtx, trs.contexts[ctx], issues)
+	}
+	for ctx, trsCtx := range trs.contexts {
+		if _, ok := src.contexts[ctx]; !ok {
+			issues = pairTranslations(nil, ctx, nil, trsCtx, issues)
+		}
+	}
+
+	do.parseHeaders()
+	po.Language = do.Language
+	po.PluralForms = do.PluralForms
+	po.Headers = do.Headers
+
+	sort.Slice(issues, func(i, j int) bool {
+		if issues[i].Context != issues[j].Context {
+			return issues[i].Context < issues[j].Context
+		}
+		if issues[i].Key != issues[j].Key {
+			return issues[i].Key < issues[j].Key
+		}
+		return issues[i].Kind < issues[j].Kind
+	})
+
+	return po, issues
+}
+
+// pairTranslations fills dst with the paired entries of a single context and appends the issues found.
+func pairTranslations(dst map[string]*Translation, ctx string, src, trs map[string]*Translation, issues []PairIssue) []PairIssue {
+	for key, srcTr := range src {
+		if key == "" {
+			continue
+		}
+
+		paired := copyTranslation(srcTr)
+		trsTr, ok := trs[key]
+		if !ok || !trsTr.IsTranslated() {
+			issues = append(issues, PairIssue{Kind: PairMissingTranslation, Context: ctx, Key: key})
+		} else {
+			if (srcTr.PluralID == "") != (trsTr.PluralID == "") {
+				issues = append(issues, PairIssue{Kind: PairPluralMismatch, Context: ctx, Key: key})
+			}
+			for i, str := range trsTr.Trs {
+				if str != "" {
+					paired.Trs[i] = str
+				}
+			}
+		}
+		dst[key] = paired
+	}
+
+	for key := range trs {
+		if _, ok := src[key]; !ok && key != "" {
+			issues = append(issues, PairIssue{Kind: PairUnknownKey, Context: ctx, Key: key})
+		}
+	}
+
+	return issues
+}
+
+// copyTranslation returns a copy of tr that doesn't share its Trs map.
+func copyTranslation(tr *Translation) *Translation {
+	cp := NewTranslationWithRefs(tr.Refs)
+	cp.ID = tr.ID
+	cp.PluralID = tr.PluralID
+	cp.Fuzzy = tr.Fuzzy
+	cp.File = tr.File
+	cp.Line = tr.Line
+	for i, str := range tr.Trs {
+		cp.Trs[i] = str
+	}
+	return cp
+}
@@ -0,0 +1,85 @@
+package gotext
+
+import (
+	"testing"
+)
+
+func TestPairCatalogs(t *testing.T) {
+	source := NewPo()
+	source.Parse([]byte(`
+msgid ""
+msgstr ""
+"Language: en\n"
+
+msgid "checkout.pay"
+msgstr "Pay now"
+
+msgid "checkout.cancel"
+msgstr "Cancel"
+
+msgid "cart.items"
+msgid_plural "cart.items"
+msgstr[0] "%d item"
+msgstr[1] "%d items"
+
+msgctxt "menu"
+msgid "file.open"
+msgstr "Open"
+`))
+
+	translation := NewPo()
+	translation.Parse([]byte(`
+msgid ""
+msgstr ""
+"Language: de\n"
+"Plural-Forms: nplurals=2; plural=(n != 1);\n"
+
+msgid "checkout.pay"
+msgstr "Jetzt bezahlen"
+
+msgid "checkout.cancel"
+msgstr ""
+
+msgid "cart.items"
+msgstr "Artikel"
+
+msgid "checkout.old"
+msgstr "Veraltet"
+
+msgctxt "menu"
+msgid "file.open"
+msgstr "Öffnen"
+`))
+
+	po, issues := PairCatalogs(source, translation)
+
+	if po.Language != "de" {
+		t.Errorf("Expected language 'de' but got '%s'", po.Language)
+	}
+	if tr := po.Get("checkout.pay"); tr != "Jetzt bezahlen" {
+		t.Errorf("Expected 'Jetzt bezahlen' but got '%s'", tr)
+	}
+	if tr := po.Get("checkout.cancel"); tr != "Cancel" {
+		t.Errorf("Expected source text fallback 'Cancel' but got '%s'", tr)
+	}
+	if tr := po.Get("checkout.old"); tr != "checkout.old" {
+		t.Errorf("Expected unknown key to be dropped but got '%s'", tr)
+	}
+	if tr := po.GetC("file.open", "menu"); tr != "Öffnen" {
+		t.Errorf("Expected 'Öffnen' but got '%s'", tr)
+	}
+
+	expected := []PairIssue{
+		{Kind: PairPluralMismatch, Key: "cart.items"},
+		{Kind: PairMissingTranslation, Key: "checkout.cancel"},
+		{Kind: PairUnknownKey, Key: "checkout.old"},
+	}
+	if len(issues) != len(expected) {
+		t.Fatalf("Expected %d issues but got %v", len(expected), issues)
+	}
+	for i, issue := range expected {
+		if issues[i] != issue {
+			t.Errorf("Expected issue '%s' but got '%s'", issue, issues[i])
+		}
+	}
+}
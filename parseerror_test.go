@@ -0,0 +1,125 @@
+package gotext
+
+import (
+	"encoding/binary"
+	"io/ioutil"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestMoParseWithError(t *testing.T) {
+	data, err := ioutil.ReadFile("fixtures/en_US/default.mo")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := NewMo().ParseWithError(data); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	tests := map[string][]byte{
+		"invalid magic number": []byte("not a mo file at all, sorry"),
+		"EOF":                  data[:2],
+		"truncated":            data[:len(data)-3],
+	}
+	for expected, buf := range tests {
+		err := NewMo().ParseWithError(buf)
+		perr, ok := err.(*ParseError)
+		if !ok {
+			t.Errorf("Expected a *ParseError containing '%s' but got %v", expected, err)
+			continue
+		}
+		if !strings.Contains(perr.Error(), expected) || !strings.HasPrefix(perr.Error(), "gotext: offset ") {
+			t.Errorf("Unexpected error message '%s'", perr.Error())
+		}
+	}
+
+	// Parse keeps ignoring errors
+	NewMo().Parse(data[:len(data)-3])
+}
+
+func TestPoParseWithError(t *testing.T) {
+	po := NewPo()
+	err := po.ParseWithError([]byte("msgid \"A\"\nmsgstr \"1\"\n\nmsgid \"B\"\nmsgstr \"trunc"))
+
+	errs, ok := err.(ParseErrors)
+	if !ok || len(errs) != 1 || errs[0].Line != 5 {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if msg := err.Error(); msg != "gotext: line 5: unterminated string" {
+		t.Errorf("Unexpected error message '%s'", msg)
+	}
+
+	// What could be parsed is loaded
+	if tr := po.Get("A"); tr != "1" {
+		t.Errorf("Expected '1' but got '%s'", tr)
+	}
+
+	if err := NewPo().ParseWithError([]byte("msgid \"A\"\nmsgstr \"1\"\n")); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestMoParseWithErrorBounds(t *testing.T) {
+	data, err := ioutil.ReadFile("fixtures/en_US/default.mo")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// corrupt returns a copy of data with the little endian uint32 at off set to v
+	corrupt := func(off int, v uint32) []byte {
+		buf := append([]byte(nil), data...)
+		binary.LittleEndian.PutUint32(buf[off:], v)
+		return buf
+	}
+	msgIDOffset := binary.LittleEndian.Uint32(data[12:])
+
+	tests := map[string][]byte{
+		"msgid table of 4294967295 strings out of bounds": corrupt(8, 0xffffffff),
+		"msgstr table":          corrupt(16, 0xfffffff0),
+		"truncated msgid":       corrupt(int(msgIDOffset), 0x7fffffff),
+		"truncated msgstr,":     corrupt(int(binary.LittleEndian.Uint32(data[16:])), 0xffffffff),
+		"truncated msgid, 0 of": corrupt(int(msgIDOffset)+12, 0xfffffffe),
+		"invalid version":       corrupt(4, 0x00020000),
+		"reading header":        data[:10],
+	}
+	for expected, buf := range tests {
+		mo := NewMo()
+		err := mo.ParseWithError(buf)
+		if _, ok := err.(*ParseError); !ok || !strings.Contains(err.Error(), expected) {
+			t.Errorf("Expected a *ParseError containing '%s' but got %v", expected, err)
+			continue
+		}
+		// Nothing is loaded
+		if n := mo.GetDomain().entryCount(); n != 0 {
+			t.Errorf("Expected no entries after '%s', got %d", expected, n)
+		}
+	}
+}
+
+// TestMoParseCorrupt checks that no truncation or byte corruption of a valid MO file makes the parser panic.
+func TestMoParseCorrupt(t *testing.T) {
+	data, err := ioutil.ReadFile("fixtures/en_US/default.mo")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i <= len(data); i++ {
+		NewMo().ParseWithError(data[:i])
+	}
+
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 2000; i++ {
+		buf := append([]byte(nil), data...)
+		for j := rnd.Intn(4); j >= 0; j-- {
+			// Corrupt the header and tables more often, which is where offsets are
+			off := rnd.Intn(len(buf))
+			if rnd.Intn(2) == 0 {
+				off = rnd.Intn(moHeaderSize + 16*8)
+			}
+			buf[off] = byte(rnd.Intn(256))
+		}
+		NewMo().ParseWithError(buf)
+	}
+}
@@ -0,0 +1,151 @@
+package gotext
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// PercentPolicy tells what to do with the percent signs of translations that fmt would misread, like the one of
+// "%d% off", formatted as "5%!o(MISSING)ff", or the escaped "%%" of a translation without placeholders,
+// which is returned as is since it isn't formatted.
+type PercentPolicy int
+
+const (
+	// PercentPassThrough uses the translations as they are. It's the default.
+	PercentPassThrough PercentPolicy = iota
+	// PercentEscape escapes the percent signs that don't start a placeholder in translations with placeholders,
+	// and unescapes the "%%" of translations without them, so they're all printed as written.
+	PercentEscape
+	// PercentError refuses the catalog: Locale objects don't load it and report the first such translation as an error.
+	PercentError
+	// PercentStrip removes the percent signs that don't start a placeholder from translations with placeholders,
+	// so "%d% off" prints "5 off", and unescapes the "%%" of translations without them.
+	PercentStrip
+)
+
+// StrayPercent is a translation with a percent sign that fmt would misread. See PercentPolicy.
+type StrayPercent struct {
+	Context     string
+	MsgID       string
+	Translation string
+}
+
+func (e *StrayPercent) Error() string {
+	if e.Context != "" {
+		return fmt.Sprintf("gotext: stray percent sign in msgstr %q of msgid %q in context %q", e.Translation, e.MsgID, e.Context)
+	}
+	return fmt.Sprintf("gotext: stray percent sign in msgstr %q of msgid %q", e.Translation, e.MsgID)
+}
+
+// placeholderRe matches a fmt verb, or a named %(name)s placeholder, at the start of a string.
+var placeholderRe = regexp.MustCompile(`^` + verbPattern)
+
+// strayPercents returns the positions of the percent signs of s that neither start a placeholder nor are escaped,
+// and whether s has placeholders.
+func strayPercents(s string) (stray []int, placeholders bool) {
+	for i := 0; i < len(s); i++ {
+		if s[i] != '%' {
+			continue
+		}
+		if strings.HasPrefix(s[i:], "%%") {
+			i++
+			continue
+		}
+		if loc := placeholderRe.FindStringIndex(s[i:]); loc != nil {
+			placeholders = true
+			i += loc[1] - 1
+			continue
+		}
+		stray = append(stray, i)
+	}
+	return stray, placeholders
+}
+
+// misreadPercents reports whether fmt would misread a percent sign of the translation s. See PercentPolicy.
+func misreadPercents(s string) bool {
+	stray, placeholders := strayPercents(s)
+	if placeholders {
+		return len(stray) > 0
+	}
+	return strings.Contains(s, "%%")
+}
+
+// escapePercents rewrites the translation s as told by PercentEscape.
+func escapePercents(s string) string {
+	stray, placeholders := strayPercents(s)
+	if !placeholders {
+		return strings.Replace(s, "%%", "%", -1)
+	}
+	if len(stray) == 0 {
+		return s
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, i := range stray {
+		b.WriteString(s[last:i])
+		b.WriteByte('%')
+		last = i
+	}
+	b.WriteString(s[last:])
+	return b.String()
+}
+
+// stripPercents rewrites the translation s as told by PercentStrip.
+func stripPercents(s string) string {
+	stray, placeholders := strayPercents(s)
+	if !placeholders {
+		return strings.Replace(s, "%%", "%", -1)
+	}
+	if len(stray) == 0 {
+		return s
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, i := range stray {
+		b.WriteString(s[last:i])
+		last = i + 1
+	}
+	b.WriteString(s[last:])
+	return b.String()
+}
+
+/*
+ApplyPercentPolicy handles the percent signs of the translations in the Domain that fmt would misread, as told by p:
+it escapes them with PercentEscape, removes them with PercentStrip, and returns a *StrayPercent error for the first one,
+in context and msgid order, with PercentError. Translations whose percent signs are read right are unchanged.
+
+	po := gotext.NewPo()
+	po.ParseFile("/path/to/po/file/translations.po")
+	po.GetDomain().ApplyPercentPolicy(gotext.PercentEscape)
+	po.Get("%d%% off", 5) // msgstr "%d% Rabatt" gives "5% Rabatt"
+
+Percent signs followed by a space are taken as text, even though fmt reads "% d" as a verb.
+*/
+func (do *Domain) ApplyPercentPolicy(p PercentPolicy) error {
+	switch p {
+	case PercentEscape:
+		do.rewriteMsgstrs(escapePercents)
+	case PercentStrip:
+		do.rewriteMsgstrs(stripPercents)
+	case PercentError:
+		for _, e := range do.entries() {
+			for i := 0; i < len(e.Translation.Trs); i++ {
+				if str := e.Translation.Trs[i]; misreadPercents(str) {
+					return &StrayPercent{Context: e.Context, MsgID: e.MsgID, Translation: str}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// SetPercentPolicy sets how the catalogs loaded afterwards by AddDomain handle the percent signs fmt would misread.
+// See Domain.ApplyPercentPolicy.
+func (l *Locale) SetPercentPolicy(p PercentPolicy) {
+	l.Lock()
+	l.percentPolicy = p
+	l.Unlock()
+}
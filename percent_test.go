@@ -0,0 +1,130 @@
+package gotext
+
+import (
+	"strings"
+	"testing"
+)
+
+const percentPo = `
+msgid "%d%% off"
+msgstr "%d% Rabatt"
+
+msgid "100%% juice"
+msgstr "100%% Saft"
+
+msgid "50% off"
+msgstr "50% Rabatt"
+
+msgctxt "tax"
+msgid "%.1f%% VAT"
+msgstr "%.1f%% MwSt."
+`
+
+func TestEscapePercents(t *testing.T) {
+	for in, want := range map[string]string{
+		"%d% Rabatt":     "%d%% Rabatt",
+		"%d%% Rabatt":    "%d%% Rabatt",
+		"100%% Saft":     "100% Saft",
+		"50% Rabatt":     "50% Rabatt",
+		"%s: 5% (%d)":    "%s: 5%% (%d)",
+		"%(name)s 5% ab": "%(name)s 5%% ab",
+		"% d Punkte":     "% d Punkte",
+		"% d, 5% mehr":   "% d, 5%% mehr",
+		"no percent":     "no percent",
+	} {
+		if got := escapePercents(in); got != want {
+			t.Errorf("escapePercents(%q): expected %q, got %q", in, want, got)
+		}
+	}
+}
+
+func TestStripPercents(t *testing.T) {
+	for in, want := range map[string]string{
+		"%d% Rabatt":   "%d Rabatt",
+		"%d%% Rabatt":  "%d%% Rabatt",
+		"100%% Saft":   "100% Saft",
+		"50% Rabatt":   "50% Rabatt",
+		"%s: 5% (%d)":  "%s: 5 (%d)",
+		"% d, 5% mehr": "% d, 5 mehr",
+		"no percent":   "no percent",
+	} {
+		if got := stripPercents(in); got != want {
+			t.Errorf("stripPercents(%q): expected %q, got %q", in, want, got)
+		}
+	}
+}
+
+func TestDomainApplyPercentPolicy(t *testing.T) {
+	po := NewPo()
+	po.Parse([]byte(percentPo))
+
+	err := po.GetDomain().ApplyPercentPolicy(PercentError)
+	if sp, ok := err.(*StrayPercent); !ok || sp.MsgID != "%d%% off" || sp.Translation != "%d% Rabatt" {
+		t.Errorf("unexpected error %v", err)
+	}
+	if err := po.GetDomain().ApplyPercentPolicy(PercentPassThrough); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	if err := po.GetDomain().ApplyPercentPolicy(PercentEscape); err != nil {
+		t.Fatal(err)
+	}
+	off, juice, halfOff, vat := "%d%% off", "100%% juice", "50% off", "%.1f%% VAT"
+	for _, c := range []struct{ got, want string }{
+		{po.Get(off, 5), "5% Rabatt"},
+		{po.Get(juice), "100% Saft"},
+		{po.Get(halfOff), "50% Rabatt"},
+		{po.GetC(vat, "tax", 19.0), "19.0% MwSt."},
+	} {
+		if c.got != c.want {
+			t.Errorf("expected %q, got %q", c.want, c.got)
+		}
+	}
+	if err := po.GetDomain().ApplyPercentPolicy(PercentError); err != nil {
+		t.Errorf("expected no stray percent sign after escaping, got %v", err)
+	}
+}
+
+func TestLocaleSetPercentPolicy(t *testing.T) {
+	src := &MemorySource{Files: map[string][]byte{
+		"de/default.po": []byte(percentPo),
+	}}
+
+	l := NewLocaleWithSource(src, "de")
+	l.SetPercentPolicy(PercentError)
+	l.AddDomain("default")
+	if _, ok := l.Domains["default"]; ok {
+		t.Error("expected the catalog to be refused")
+	}
+
+	l.SetPercentPolicy(PercentEscape)
+	l.AddDomain("default")
+	off := "%d%% off"
+	if got := l.Get(off, 5); got != "5% Rabatt" {
+		t.Errorf("unexpected translation %q", got)
+	}
+
+	l.SetPercentPolicy(PercentStrip)
+	l.AddDomain("default")
+	if got := l.Get(off, 5); got != "5 Rabatt" {
+		t.Errorf("unexpected translation %q", got)
+	}
+	if got := l.Get("100%% juice"); got != "100% Saft" {
+		t.Errorf("unexpected translation %q", got)
+	}
+}
+
+func TestValidateDomainPercents(t *testing.T) {
+	po := NewPo()
+	po.Parse([]byte(percentPo))
+
+	var problems []string
+	for _, issue := range ValidateDomain(po.GetDomain()) {
+		if strings.Contains(issue.Problem, "%%") {
+			problems = append(problems, issue.MsgID+": "+issue.Problem)
+		}
+	}
+	if len(problems) != 1 || problems[0] != "%d%% off: percent signs aren't escaped (%%) like in the source string" {
+		t.Errorf("unexpected issues %q", problems)
+	}
+}
@@ -0,0 +1,36 @@
+package gotext
+
+import (
+	"sync/atomic"
+
+	"github.com/leonelquinteros/gotext/plurals"
+)
+
+// pluralCacheSize is the number of counts, from 0, whose plural form is memoized by each Domain.
+// UIs mostly pluralize small counts, so most GetN calls don't evaluate the Plural-Forms expression.
+const pluralCacheSize = 101
+
+// pluralCache memoizes the plural forms of the counts below pluralCacheSize for one plural expression.
+// Entries hold the form plus one, 0 meaning not evaluated yet. They're accessed atomically,
+// as lookups only hold the read lock of the Domain.
+type pluralCache [pluralCacheSize]int32
+
+// eval returns the plural form of n for expr, the expression the cache belongs to.
+func (c *pluralCache) eval(expr plurals.Expression, n int) int {
+	if c == nil || n < 0 || n >= pluralCacheSize {
+		return expr.Eval(uint32(n))
+	}
+	if form := atomic.LoadInt32(&c[n]); form != 0 {
+		return int(form - 1)
+	}
+
+	form := expr.Eval(uint32(n))
+	atomic.StoreInt32(&c[n], int32(form+1))
+	return form
+}
+
+// setPluralExpression sets the expression returning the plural form of a count, with a new cache.
+func (do *Domain) setPluralExpression(expr plurals.Expression) {
+	do.pluralforms = expr
+	do.pluralCache = new(pluralCache)
+}
@@ -0,0 +1,45 @@
+package gotext
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestPluralCache(t *testing.T) {
+	do := NewDomain()
+	if err := do.SetPluralForms(russianPluralForms); err != nil {
+		t.Fatal(err)
+	}
+	expr := do.pluralforms
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for n := 0; n < 2*pluralCacheSize; n++ {
+				if form, want := do.pluralForm(n), expr.Eval(uint32(n)); form != want {
+					t.Errorf("Expected form %d for %d but got %d", want, n, form)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	for n, form := range do.pluralCache {
+		if int(form-1) != expr.Eval(uint32(n)) {
+			t.Errorf("Unexpected cached form %d for %d", form-1, n)
+		}
+	}
+
+	// A new rule gets a new cache
+	if err := do.SetPluralForms("nplurals=2; plural=(n != 1);"); err != nil {
+		t.Fatal(err)
+	}
+	if form := do.pluralForm(5); form != 1 {
+		t.Errorf("Expected form 1 but got %d", form)
+	}
+	if form := do.pluralForm(21); form != 1 {
+		t.Errorf("Expected form 1 but got %d", form)
+	}
+}
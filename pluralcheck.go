@@ -0,0 +1,140 @@
+package gotext
+
+import (
+	"fmt"
+	"sync"
+
+	"golang.org/x/text/feature/plural"
+	"golang.org/x/text/language"
+)
+
+// PluralPolicy tells what to do when the Plural-Forms header of a catalog disagrees with the CLDR plural rules of its language.
+type PluralPolicy int
+
+const (
+	// PluralTrustHeader uses the Plural-Forms header as is. It's the default.
+	PluralTrustHeader PluralPolicy = iota
+	// PluralTrustCLDR replaces the header plural rule with the CLDR one, with msgstr indexes following the CLDR category order.
+	PluralTrustCLDR
+	// PluralError refuses the catalog: Locale objects don't load it and report the mismatch as an error.
+	PluralError
+)
+
+// PluralMismatch describes a catalog whose Plural-Forms header disagrees with the CLDR plural rules of its language.
+type PluralMismatch struct {
+	Language    string
+	PluralForms string
+
+	// Number of plural forms declared by the header and expected by CLDR for integer values
+	HeaderNPlurals int
+	CLDRNPlurals   int
+}
+
+func (m *PluralMismatch) Error() string {
+	return fmt.Sprintf("gotext: Plural-Forms %q declares %d forms but CLDR expects %d for language %q",
+		m.PluralForms, m.HeaderNPlurals, m.CLDRNPlurals, m.Language)
+}
+
+// cardinalFormsCache holds the cardinal categories used by each language for integers, keyed by language.Tag
+var cardinalFormsCache sync.Map
+
+// cardinalForms returns the CLDR cardinal categories a language uses for integer values, in CLDR order.
+// Categories only used by decimals (like "other" in Russian) aren't included, matching what gettext catalogs declare.
+func cardinalForms(tag language.Tag) []plural.Form {
+	if forms, ok := cardinalFormsCache.Load(tag); ok {
+		return forms.([]plural.Form)
+	}
+
+	seen := make(map[plural.Form]bool)
+	for n := 0; n < 1000; n++ {
+		seen[plural.Cardinal.MatchPlural(tag, n, 0, 0, 0, 0)] = true
+	}
+
+	forms := make([]plural.Form, 0, len(seen))
+	for _, form := range cldrFormOrder {
+		if seen[form] {
+			forms = append(forms, form)
+		}
+	}
+
+	cardinalFormsCache.Store(tag, forms)
+	return forms
+}
+
+// cldrPlural is a plurals.Expression evaluating the CLDR cardinal rules of a language.
+type cldrPlural struct {
+	tag   language.Tag
+	forms []plural.Form
+}
+
+func (c cldrPlural) Eval(n uint32) int {
+	form := plural.Cardinal.MatchPlural(c.tag, int(n), 0, 0, 0, 0)
+	for i, f := range c.forms {
+		if f == form {
+			return i
+		}
+	}
+	return 0
+}
+
+// SetPluralPolicy sets how the next parsed catalog handles a Plural-Forms header that disagrees with CLDR.
+// See PluralPolicy.
+func (do *Domain) SetPluralPolicy(p PluralPolicy) {
+	do.pluralMutex.Lock()
+	do.pluralPolicy = p
+	do.pluralMutex.Unlock()
+}
+
+// PluralMismatch returns the disagreement found between the Plural-Forms header of the parsed catalog
+// and the CLDR plural rules of its language, or nil if there is none.
+func (do *Domain) PluralMismatch() *PluralMismatch {
+	do.pluralMutex.RLock()
+	defer do.pluralMutex.RUnlock()
+
+	return do.pluralMismatch
+}
+
+// checkPlurals compares the parsed Plural-Forms header with CLDR and applies the plural policy.
+// It's called by parseHeaders, while parsing, so the Domain is already locked.
+func (do *Domain) checkPlurals() {
+	do.pluralMismatch = nil
+	if do.PluralForms == "" || do.Language == "" || do.tag == language.Und {
+		return
+	}
+
+	forms := cardinalForms(do.tag)
+	if do.nplurals == len(forms) {
+		return
+	}
+
+	do.pluralMismatch = &PluralMismatch{
+		Language:       do.Language,
+		PluralForms:    do.PluralForms,
+		HeaderNPlurals: do.nplurals,
+		CLDRNPlurals:   len(forms),
+	}
+
+	if do.pluralPolicy == PluralTrustCLDR {
+		do.nplurals = len(forms)
+		do.setPluralExpression(cldrPlural{tag: do.tag, forms: forms})
+	}
+}
+
+/*
+SetPluralPolicy sets how the catalogs loaded afterwards by AddDomain handle a Plural-Forms header
+that disagrees with the CLDR plural rules of their language (see PluralPolicy).
+
+When onMismatch isn't nil, it's called for every loaded catalog with a mismatch, whatever the policy is.
+
+	l := gotext.NewLocale("/path/to/i18n/dir", "ru")
+	l.SetPluralPolicy(gotext.PluralTrustCLDR, func(dom string, m *gotext.PluralMismatch) {
+		log.Printf("domain %s: %v", dom, m)
+	})
+	l.AddDomain("default")
+*/
+func (l *Locale) SetPluralPolicy(p PluralPolicy, onMismatch func(dom string, m *PluralMismatch)) {
+	l.Lock()
+	l.pluralPolicy = p
+	l.onPluralMismatch = onMismatch
+	l.Unlock()
+}
@@ -0,0 +1,77 @@
+package gotext
+
+import (
+	"testing"
+)
+
+// Russian catalog declaring the Germanic plural rule
+const wrongRussianPlurals = `
+msgid ""
+msgstr ""
+"Language: ru\n"
+"Plural-Forms: nplurals=2; plural=(n != 1);\n"
+
+msgid "%d file"
+msgid_plural "%d files"
+msgstr[0] "%d файл"
+msgstr[1] "%d файла"
+msgstr[2] "%d файлов"
+`
+
+func TestPluralMismatch(t *testing.T) {
+	po := NewPo()
+	po.Parse([]byte(wrongRussianPlurals))
+
+	m := po.PluralMismatch()
+	if m == nil {
+		t.Fatal("Expected a plural mismatch")
+	}
+	if m.HeaderNPlurals != 2 || m.CLDRNPlurals != 3 || m.Language != "ru" {
+		t.Errorf("Unexpected mismatch %+v", m)
+	}
+
+	// The header is trusted by default
+	if tr := po.GetN("%d file", "%d files", 5, 5); tr != "5 файла" {
+		t.Errorf("Expected '5 файла' but got '%s'", tr)
+	}
+
+	po = NewPo()
+	po.SetPluralPolicy(PluralTrustCLDR)
+	po.Parse([]byte(wrongRussianPlurals))
+	for n, expected := range map[int]string{1: "1 файл", 3: "3 файла", 5: "5 файлов", 21: "21 файл"} {
+		if tr := po.GetN("%d file", "%d files", n, n); tr != expected {
+			t.Errorf("Expected '%s' but got '%s'", expected, tr)
+		}
+	}
+
+	// Consistent catalogs don't report anything
+	po = NewPo()
+	po.Parse([]byte("msgid \"\"\nmsgstr \"Language: fr\\nPlural-Forms: nplurals=2; plural=(n > 1);\\n\"\n"))
+	if m := po.PluralMismatch(); m != nil {
+		t.Errorf("Unexpected mismatch %v", m)
+	}
+}
+
+func TestLocalePluralPolicy(t *testing.T) {
+	src := &MemorySource{Files: map[string][]byte{"ru/default.po": []byte(wrongRussianPlurals)}}
+
+	var reported []string
+	l := NewLocaleWithSource(src, "ru")
+	l.SetPluralPolicy(PluralError, func(dom string, m *PluralMismatch) {
+		reported = append(reported, dom)
+	})
+	l.AddDomain("default")
+
+	if _, ok := l.Domains["default"]; ok {
+		t.Error("Expected the catalog to be refused")
+	}
+	if len(reported) != 1 || reported[0] != "default" {
+		t.Errorf("Unexpected reports %v", reported)
+	}
+
+	l.SetPluralPolicy(PluralTrustCLDR, nil)
+	l.AddDomain("default")
+	if tr := l.GetN("%d file", "%d files", 11, 11); tr != "11 файлов" {
+		t.Errorf("Expected '11 файлов' but got '%s'", tr)
+	}
+}
@@ -0,0 +1,70 @@
+package gotext
+
+// PluralFallback tells what to return when the plural form selected for a number isn't defined by the translation,
+// like msgstr[2] in a catalog that only has msgstr[0] and msgstr[1] because its Plural-Forms header is wrong.
+type PluralFallback int
+
+const (
+	// PluralFallbackSource returns the untranslated msgid_plural (or msgid for the first form). It's the default.
+	PluralFallbackSource PluralFallback = iota
+	// PluralFallbackLastForm returns the last plural form defined by the translation.
+	PluralFallbackLastForm
+)
+
+// PluralOutOfRange describes a lookup whose plural form isn't defined by the translation.
+type PluralOutOfRange struct {
+	Context string
+	MsgID   string
+
+	// Index of the selected plural form, and number of forms defined by the translation
+	Index int
+	Forms int
+}
+
+// SetPluralFallback sets what plural lookups return when the selected form isn't defined by the translation.
+// When hook isn't nil, it's called every time that happens, so it can be logged.
+func (do *Domain) SetPluralFallback(f PluralFallback, hook func(PluralOutOfRange)) {
+	do.pluralMutex.Lock()
+	do.pluralFallback = f
+	do.pluralHook = hook
+	do.pluralMutex.Unlock()
+}
+
+// pluralString returns the plural form idx of tr, applying the plural fallback when it isn't defined.
+func (do *Domain) pluralString(ctx string, tr *Translation, idx int) string {
+	if _, ok := tr.Trs[idx]; ok || len(tr.Trs) == 0 {
+		return tr.GetN(idx)
+	}
+
+	do.pluralMutex.RLock()
+	fallback, hook := do.pluralFallback, do.pluralHook
+	do.pluralMutex.RUnlock()
+
+	// Highest form defined
+	last := -1
+	for i := range tr.Trs {
+		if i > last {
+			last = i
+		}
+	}
+
+	logWarn("gotext: plural form not defined by the translation", "context", ctx, "msgid", tr.ID, "index", idx, "forms", last+1)
+	if hook != nil {
+		hook(PluralOutOfRange{Context: ctx, MsgID: tr.ID, Index: idx, Forms: last + 1})
+	}
+
+	if fallback == PluralFallbackLastForm && idx > last {
+		return tr.GetN(last)
+	}
+	return tr.GetN(idx)
+}
+
+// SetPluralFallback sets what plural lookups return, for the catalogs loaded afterwards by AddDomain,
+// when the selected form isn't defined by the translation (see PluralFallback).
+// When hook isn't nil, it's called every time that happens, with the domain name.
+func (l *Locale) SetPluralFallback(f PluralFallback, hook func(dom string, e PluralOutOfRange)) {
+	l.Lock()
+	l.pluralFallback = f
+	l.pluralHook = hook
+	l.Unlock()
+}
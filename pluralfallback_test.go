@@ -0,0 +1,59 @@
+package gotext
+
+import (
+	"testing"
+)
+
+// Russian catalog with a correct Plural-Forms header but only two forms translated
+const missingRussianForm = `
+msgid ""
+msgstr ""
+"Language: ru\n"
+"Plural-Forms: nplurals=3; plural=(n%10==1 && n%100!=11 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2);\n"
+
+msgid "%d file"
+msgid_plural "%d files"
+msgstr[0] "%d файл"
+msgstr[1] "%d файла"
+`
+
+func TestPluralFallback(t *testing.T) {
+	po := NewPo()
+	po.Parse([]byte(missingRussianForm))
+
+	// Default: source plural
+	if tr := po.GetN("%d file", "%d files", 5, 5); tr != "5 files" {
+		t.Errorf("Expected '5 files' but got '%s'", tr)
+	}
+
+	var reported []PluralOutOfRange
+	po.GetDomain().SetPluralFallback(PluralFallbackLastForm, func(e PluralOutOfRange) {
+		reported = append(reported, e)
+	})
+	if tr := po.GetN("%d file", "%d files", 5, 5); tr != "5 файла" {
+		t.Errorf("Expected '5 файла' but got '%s'", tr)
+	}
+	if tr := po.GetN("%d file", "%d files", 3, 3); tr != "3 файла" {
+		t.Errorf("Expected '3 файла' but got '%s'", tr)
+	}
+	if len(reported) != 1 || reported[0].MsgID != "%d file" || reported[0].Index != 2 || reported[0].Forms != 2 {
+		t.Errorf("Unexpected reports %+v", reported)
+	}
+}
+
+func TestLocalePluralFallback(t *testing.T) {
+	l := NewLocaleWithSource(&MemorySource{Files: map[string][]byte{"ru/default.po": []byte(missingRussianForm)}}, "ru")
+
+	var doms []string
+	l.SetPluralFallback(PluralFallbackLastForm, func(dom string, e PluralOutOfRange) {
+		doms = append(doms, dom)
+	})
+	l.AddDomain("default")
+
+	if tr := l.GetN("%d file", "%d files", 11, 11); tr != "11 файла" {
+		t.Errorf("Expected '11 файла' but got '%s'", tr)
+	}
+	if len(doms) != 1 || doms[0] != "default" {
+		t.Errorf("Unexpected reports %v", doms)
+	}
+}
@@ -0,0 +1,192 @@
+package gotext
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang.org/x/text/language"
+
+	"github.com/leonelquinteros/gotext/plurals"
+)
+
+// PluralRule is a parsed and validated Plural-Forms header. See ParsePluralForms.
+type PluralRule struct {
+	// Number of plural forms, so of msgstr[N] entries of plural translations
+	NPlurals int
+
+	// Plural expression, in the canonical form written by String, like "(n != 1)" or "0"
+	Plural string
+
+	expr plurals.Expression
+}
+
+// pluralRuleChecks is the number of values of n, from 0, that ParsePluralForms evaluates the plural expression for.
+const pluralRuleChecks = 1000
+
+/*
+ParsePluralForms parses the value of a Plural-Forms header, like "nplurals=2; plural=n!=1", so tools can verify catalogs.
+It returns an error when:
+
+  - nplurals or the plural expression is missing or invalid.
+  - the expression has characters plural expressions don't use, which the compiler would ignore.
+  - the expression selects a form beyond nplurals, as for "nplurals=2; plural=n%10;" with n = 2.
+
+The expression is checked for n from 0 to 999, which covers the rules of every language.
+*/
+func ParsePluralForms(header string) (PluralRule, error) {
+	nplurals, plural, expr, err := parsePluralForms(header)
+	if err != nil {
+		return PluralRule{}, err
+	}
+
+	canonical, err := canonicalPlural(plural)
+	if err != nil {
+		return PluralRule{}, fmt.Errorf("gotext: invalid plural expression in Plural-Forms %q: %v", header, err)
+	}
+
+	for n := 0; n < pluralRuleChecks; n++ {
+		if i := expr.Eval(uint32(n)); i < 0 || i >= nplurals {
+			return PluralRule{}, fmt.Errorf("gotext: Plural-Forms %q selects form %d for n = %d, but declares %d forms",
+				header, i, n, nplurals)
+		}
+	}
+
+	return PluralRule{NPlurals: nplurals, Plural: canonical, expr: expr}, nil
+}
+
+// String returns the rule as a Plural-Forms header value in canonical form, like "nplurals=2; plural=(n != 1);",
+// so rules written with different spacing or parentheses compare equal.
+func (r PluralRule) String() string {
+	return fmt.Sprintf("nplurals=%d; plural=%s;", r.NPlurals, r.Plural)
+}
+
+// Eval returns the plural form, the msgstr[N] index, the rule selects for n.
+func (r PluralRule) Eval(n int) int {
+	if r.expr == nil || n < 0 {
+		return 0
+	}
+	return r.expr.Eval(uint32(n))
+}
+
+// CheckLanguage returns a *PluralMismatch if the rule doesn't have as many forms as the CLDR plural rules of lang,
+// like nplurals=2 for Russian, which needs three.
+func (r PluralRule) CheckLanguage(lang string) error {
+	tag := language.Make(lang)
+	if tag == language.Und {
+		return fmt.Errorf("gotext: unknown language %q", lang)
+	}
+	if forms := cardinalForms(tag); len(forms) != r.NPlurals {
+		return &PluralMismatch{
+			Language:       lang,
+			PluralForms:    r.String(),
+			HeaderNPlurals: r.NPlurals,
+			CLDRNPlurals:   len(forms),
+		}
+	}
+	return nil
+}
+
+// CheckTranslation returns an error if the plural translation tr doesn't have exactly one msgstr[N] entry per form
+// of the rule. Singular translations are fine.
+func (r PluralRule) CheckTranslation(tr *Translation) error {
+	if tr.PluralID == "" {
+		return nil
+	}
+
+	max := -1
+	for i := range tr.Trs {
+		if i >= r.NPlurals {
+			return fmt.Errorf("gotext: msgid %q has msgstr[%d], but the rule has %d forms", tr.ID, i, r.NPlurals)
+		}
+		if i > max {
+			max = i
+		}
+	}
+	if max+1 < r.NPlurals {
+		return fmt.Errorf("gotext: msgid %q has %d plural forms, but the rule has %d", tr.ID, max+1, r.NPlurals)
+	}
+	return nil
+}
+
+// pluralOperators are the operators of plural expressions, longest first so ">=" isn't read as ">".
+var pluralOperators = []string{"==", "!=", ">=", "<=", "&&", "||", ">", "<", "%", "?", ":", "(", ")"}
+
+// canonicalPlural returns the plural expression s with a single space around binary operators and
+// one pair of parentheses around it, unless it's a constant, or an error if s has characters plural expressions don't use.
+func canonicalPlural(s string) (string, error) {
+	var tokens []string
+	for i := 0; i < len(s); {
+		switch c := s[i]; {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+			continue
+		case c == 'n':
+			tokens = append(tokens, "n")
+			i++
+			continue
+		case c >= '0' && c <= '9':
+			j := i
+			for j < len(s) && s[j] >= '0' && s[j] <= '9' {
+				j++
+			}
+			n, err := strconv.ParseUint(s[i:j], 10, 32)
+			if err != nil {
+				return "", err
+			}
+			tokens = append(tokens, strconv.FormatUint(n, 10))
+			i = j
+			continue
+		}
+
+		found := false
+		for _, op := range pluralOperators {
+			if strings.HasPrefix(s[i:], op) {
+				tokens = append(tokens, op)
+				i += len(op)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return "", fmt.Errorf("unexpected %q", s[i:i+1])
+		}
+	}
+
+	// Drop the parentheses around the whole expression
+	for len(tokens) > 1 && tokens[0] == "(" && closingParen(tokens) == len(tokens)-1 {
+		tokens = tokens[1 : len(tokens)-1]
+	}
+
+	if len(tokens) == 1 {
+		return tokens[0], nil
+	}
+
+	var b strings.Builder
+	b.WriteString("(")
+	for i, t := range tokens {
+		if i > 0 && t != ")" && tokens[i-1] != "(" {
+			b.WriteString(" ")
+		}
+		b.WriteString(t)
+	}
+	b.WriteString(")")
+	return b.String(), nil
+}
+
+// closingParen returns the index of the parenthesis closing the one opening tokens, or -1 if it isn't closed.
+func closingParen(tokens []string) int {
+	depth := 0
+	for i, t := range tokens {
+		switch t {
+		case "(":
+			depth++
+		case ")":
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
@@ -0,0 +1,69 @@
+package gotext
+
+import (
+	"testing"
+)
+
+func TestParsePluralForms(t *testing.T) {
+	for header, want := range map[string]string{
+		"nplurals=2; plural=n!=1":              "nplurals=2; plural=(n != 1);",
+		" nplurals = 2 ; plural = ( n != 1 );": "nplurals=2; plural=(n != 1);",
+		"nplurals=1; plural=0;":                "nplurals=1; plural=0;",
+		"nplurals=3; plural=((n%10==1 && n%100!=11) ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2);": "nplurals=3; plural=((n % 10 == 1 && n % 100 != 11) ? 0 : n % 10 >= 2 && n % 10 <= 4 && (n % 100 < 10 || n % 100 >= 20) ? 1 : 2);",
+	} {
+		r, err := ParsePluralForms(header)
+		if err != nil {
+			t.Errorf("%q: %v", header, err)
+			continue
+		}
+		if got := r.String(); got != want {
+			t.Errorf("%q: expected %q, got %q", header, want, got)
+		}
+	}
+
+	for _, header := range []string{
+		"",
+		"plural=n != 1;",
+		"nplurals=0; plural=0;",
+		"nplurals=2; plural=n%10;",
+		"nplurals=2; plural=n != 1 ; plural=x",
+		"nplurals=2; plural=n !~ 1;",
+	} {
+		if r, err := ParsePluralForms(header); err == nil {
+			t.Errorf("%q: expected an error, got %v", header, r)
+		}
+	}
+}
+
+func TestPluralRuleChecks(t *testing.T) {
+	r, err := ParsePluralForms("nplurals=2; plural=(n != 1);")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if r.Eval(1) != 0 || r.Eval(5) != 1 {
+		t.Errorf("unexpected forms %d and %d", r.Eval(1), r.Eval(5))
+	}
+
+	if err := r.CheckLanguage("de"); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	if m, ok := r.CheckLanguage("ru").(*PluralMismatch); !ok || m.CLDRNPlurals != 3 {
+		t.Errorf("expected a mismatch, got %v", m)
+	}
+
+	tr := NewTranslation()
+	tr.ID, tr.PluralID = "%d file", "%d files"
+	tr.SetN(0, "%d Datei")
+	if err := r.CheckTranslation(tr); err == nil {
+		t.Error("expected an error for a missing form")
+	}
+	tr.SetN(1, "%d Dateien")
+	if err := r.CheckTranslation(tr); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	tr.SetN(2, "%d Dateien")
+	if err := r.CheckTranslation(tr); err == nil {
+		t.Error("expected an error for an extra form")
+	}
+}
@@ -53,7 +53,7 @@ func (ternaryStruct) compile(tokens []string) (expr Expression, err error) {
 	}
 	falseAction, err := compileExpression(strings.Join(actions.Right, ""))
 	if err != nil {
-		return expr, nil
+		return expr, err
 	}
 	return ternary{
 		test:      test,
@@ -133,6 +133,9 @@ func compileMod(tokens []string) (math math, err error) {
 	if err != nil {
 		return math, err
 	}
+	if i == 0 {
+		return math, errors.New("Modulus operation by zero")
+	}
 	return mod{value: uint32(i)}, nil
 }
 
@@ -367,23 +370,30 @@ func tokenize(s string) []string {
 	}
 	ret := []string{}
 	for chunk := range split(s) {
-		if len(chunk) != 0 {
-			if chunk[0] == '(' && chunk[len(chunk)-1] == ')' {
-				ret = append(ret, chunk)
-			} else {
-				for _, token := range pat.FindAllStringSubmatch(chunk, -1) {
-					ret = append(ret, token[0])
-				}
-			}
+		if len(chunk) == 0 {
+			continue
+		}
+		if chunk[0] == '(' && chunk[len(chunk)-1] == ')' {
+			ret = append(ret, chunk)
 		} else {
-			fmt.Printf("Empty chunk in string '%s'\n", s)
+			for _, token := range pat.FindAllStringSubmatch(chunk, -1) {
+				ret = append(ret, token[0])
+			}
 		}
 	}
 	return ret
 }
 
+// MaxLength is the length of the longest plural form expression Compile accepts.
+// Real expressions are much shorter, and the time taken to compile grows quickly with their nesting,
+// so catalogs from untrusted sources can't make it hang.
+const MaxLength = 1024
+
 // Compile a string containing a plural form expression to a Expression object.
 func Compile(s string) (expr Expression, err error) {
+	if len(s) > MaxLength {
+		return expr, fmt.Errorf("plural form expression longer than %d bytes", MaxLength)
+	}
 	if s == "0" {
 		return constValue{value: 0}, nil
 	}
@@ -8,6 +8,7 @@ package plurals
 import (
 	"encoding/json"
 	"os"
+	"strings"
 	"testing"
 )
 
@@ -48,3 +49,15 @@ func TestCompiler(t *testing.T) {
 		}
 	}
 }
+
+func TestCompileInvalid(t *testing.T) {
+	for _, s := range []string{
+		"n!=0?0:",
+		"n%0==1",
+		strings.Repeat("n==1?0:", MaxLength) + "1",
+	} {
+		if expr, err := Compile(s); err == nil {
+			t.Errorf("%.20q: expected an error, got %v", s, expr)
+		}
+	}
+}
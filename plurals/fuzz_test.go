@@ -0,0 +1,31 @@
+//go:build go1.18
+// +build go1.18
+
+package plurals
+
+import "testing"
+
+func FuzzPluralForms(f *testing.F) {
+	for _, s := range []string{
+		"n != 1",
+		"n>1",
+		"n==1 ? 0 : n==2 ? 1 : 2",
+		"(n%10==1 && n%100!=11 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2)",
+		"n==0 ? 0 : n==1 ? 1 : n==2 ? 2 : n%100>=3 && n%100<=10 ? 3 : n%100>=11 ? 4 : 5",
+		"(n % 0)",
+		"((((n",
+		"n ? : 1",
+	} {
+		f.Add(s)
+	}
+
+	f.Fuzz(func(t *testing.T, s string) {
+		expr, err := Compile(s)
+		if err != nil {
+			return
+		}
+		for _, n := range []uint32{0, 1, 2, 5, 11, 100, 1 << 31} {
+			expr.Eval(n)
+		}
+	})
+}
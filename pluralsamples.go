@@ -0,0 +1,157 @@
+package gotext
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// PluralForms is the value of a Plural-Forms header, like "nplurals=2; plural=(n != 1);".
+type PluralForms string
+
+// maxPluralSamples is the number of values Samples returns for each plural form.
+const maxPluralSamples = 5
+
+/*
+Samples returns representative values of n for each plural form of the rule, indexed like the msgstr[N] entries:
+the smallest values from 0 to 999 that select each form, up to 5 of them.
+It helps writing table tests for plural rules, and telling translators which numbers use which form:
+
+	samples, err := gotext.PluralForms(po.PluralForms).Samples()
+	// Russian: [[1 21 31 41 51] [2 3 4 22 23] [0 5 6 7 8]]
+
+Forms the rule never selects get no samples, and values selecting an index beyond nplurals are ignored.
+It returns an error if the rule can't be parsed.
+*/
+func (pf PluralForms) Samples() ([][]int, error) {
+	nplurals, _, expr, err := parsePluralForms(string(pf))
+	if err != nil {
+		return nil, err
+	}
+
+	samples := make([][]int, nplurals)
+	for n, found := 0, 0; n < 1000 && found < nplurals*maxPluralSamples; n++ {
+		i := expr.Eval(uint32(n))
+		if i < 0 || i >= nplurals || len(samples[i]) == maxPluralSamples {
+			continue
+		}
+		samples[i] = append(samples[i], n)
+		found++
+	}
+	return samples, nil
+}
+
+// PluralCoverageError is returned by Domain.CheckPluralCoverage for catalogs missing plural forms.
+type PluralCoverageError struct {
+	// Disagreement between the Plural-Forms header and the CLDR rules of the language, if any
+	Mismatch *PluralMismatch
+
+	// Plural forms unused by the header rule, as msgstr indexes
+	Unused []int
+
+	// Translated plural entries missing some of their forms
+	Issues []Issue
+}
+
+func (e *PluralCoverageError) Error() string {
+	var problems []string
+	if e.Mismatch != nil {
+		problems = append(problems, strings.TrimPrefix(e.Mismatch.Error(), "gotext: "))
+	}
+	for _, i := range e.Unused {
+		problems = append(problems, fmt.Sprintf("plural form %d is never used by the Plural-Forms rule", i))
+	}
+	for _, issue := range e.Issues {
+		problems = append(problems, issue.String())
+	}
+	return "gotext: incomplete plural forms: " + strings.Join(problems, "; ")
+}
+
+/*
+CheckPluralCoverage verifies that the catalog defines every plural form its language needs, mainly for tests:
+
+	if err := po.GetDomain().CheckPluralCoverage(); err != nil {
+		t.Error(err)
+	}
+
+It returns a *PluralCoverageError when:
+
+  - the Plural-Forms header declares a different number of forms than the CLDR rules of the Language header,
+    like nplurals=2 for Russian, which needs three.
+  - the header rule never selects some of the forms it declares.
+  - a translated plural entry has an empty or missing msgstr[N] for a form the rule selects.
+
+Untranslated entries are skipped. It returns an error too if the Plural-Forms header is invalid,
+and nil if the catalog has no plural entries or covers them all.
+*/
+func (do *Domain) CheckPluralCoverage() error {
+	do.trMutex.RLock()
+	pf, tag := do.PluralForms, do.tag
+	lang := do.Language
+	do.trMutex.RUnlock()
+
+	var plurals []Entry
+	for _, e := range do.entries() {
+		if e.Translation.PluralID != "" {
+			plurals = append(plurals, e)
+		}
+	}
+	if len(plurals) == 0 {
+		return nil
+	}
+
+	samples, err := PluralForms(pf).Samples()
+	if err != nil {
+		return err
+	}
+
+	cerr := &PluralCoverageError{}
+	if lang != "" && tag != language.Und {
+		if forms := cardinalForms(tag); len(forms) != len(samples) {
+			cerr.Mismatch = &PluralMismatch{
+				Language:       lang,
+				PluralForms:    pf,
+				HeaderNPlurals: len(samples),
+				CLDRNPlurals:   len(forms),
+			}
+		}
+	}
+	for i, ns := range samples {
+		if len(ns) == 0 {
+			cerr.Unused = append(cerr.Unused, i)
+		}
+	}
+
+	for _, e := range plurals {
+		if !e.Translation.IsTranslated() {
+			continue
+		}
+		for i, ns := range samples {
+			if len(ns) == 0 || e.Translation.Trs[i] != "" {
+				continue
+			}
+			cerr.Issues = append(cerr.Issues, Issue{
+				Context: e.Context,
+				MsgID:   e.MsgID,
+				Index:   i,
+				Problem: "missing plural form, used for n = " + joinInts(ns),
+			})
+		}
+	}
+
+	if cerr.Mismatch == nil && len(cerr.Unused) == 0 && len(cerr.Issues) == 0 {
+		return nil
+	}
+	return cerr
+}
+
+// joinInts formats ns as a comma separated list.
+func joinInts(ns []int) string {
+	strs := make([]string, len(ns))
+	for i, n := range ns {
+		strs[i] = strconv.Itoa(n)
+	}
+	return strings.Join(strs, ", ")
+}
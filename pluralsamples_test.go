@@ -0,0 +1,91 @@
+package gotext
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const russianPluralForms = "nplurals=3; plural=(n%10==1 && n%100!=11 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2);"
+
+func TestPluralFormsSamples(t *testing.T) {
+	for _, tc := range []struct {
+		pf   PluralForms
+		want [][]int
+	}{
+		{"nplurals=2; plural=(n != 1);", [][]int{{1}, {0, 2, 3, 4, 5}}},
+		{"nplurals=1; plural=0;", [][]int{{0, 1, 2, 3, 4}}},
+		{russianPluralForms, [][]int{{1, 21, 31, 41, 51}, {2, 3, 4, 22, 23}, {0, 5, 6, 7, 8}}},
+		// The third form is never selected
+		{"nplurals=3; plural=(n > 1);", [][]int{{0, 1}, {2, 3, 4, 5, 6}, nil}},
+	} {
+		got, err := tc.pf.Samples()
+		if err != nil {
+			t.Errorf("%s: %v", tc.pf, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s: got %v, want %v", tc.pf, got, tc.want)
+		}
+	}
+
+	if _, err := PluralForms("nplurals=2;").Samples(); err == nil {
+		t.Error("expected an error for a rule without plural expression")
+	}
+}
+
+func TestCheckPluralCoverage(t *testing.T) {
+	complete := `
+msgid ""
+msgstr ""
+"Language: ru\n"
+"Plural-Forms: ` + russianPluralForms + `\n"
+
+msgid "Hello"
+msgstr "Привет"
+
+msgid "%d file"
+msgid_plural "%d files"
+msgstr[0] "%d файл"
+msgstr[1] "%d файла"
+msgstr[2] "%d файлов"
+
+msgid "%d day"
+msgid_plural "%d days"
+msgstr[0] ""
+`
+	po := NewPo()
+	po.Parse([]byte(complete))
+	if err := po.GetDomain().CheckPluralCoverage(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	po = NewPo()
+	po.Parse([]byte(strings.Replace(complete, "msgstr[2] \"%d файлов\"\n", "", 1)))
+	err := po.GetDomain().CheckPluralCoverage()
+	cerr, ok := err.(*PluralCoverageError)
+	if !ok {
+		t.Fatalf("expected a *PluralCoverageError, got %v", err)
+	}
+	if cerr.Mismatch != nil || len(cerr.Unused) != 0 || len(cerr.Issues) != 1 {
+		t.Fatalf("unexpected error %+v", cerr)
+	}
+	if issue := cerr.Issues[0]; issue.MsgID != "%d file" || issue.Index != 2 || !strings.Contains(issue.Problem, "0, 5, 6, 7, 8") {
+		t.Errorf("unexpected issue %v", issue)
+	}
+
+	// Russian catalogs with two forms don't cover the CLDR categories
+	po = NewPo()
+	po.Parse([]byte(wrongRussianPlurals))
+	cerr, ok = po.GetDomain().CheckPluralCoverage().(*PluralCoverageError)
+	if !ok || cerr.Mismatch == nil || cerr.Mismatch.CLDRNPlurals != 3 || len(cerr.Issues) != 0 {
+		t.Errorf("unexpected error %+v", cerr)
+	}
+
+	// Catalogs without plural entries don't need a Plural-Forms header
+	po = NewPo()
+	po.Parse([]byte("msgid \"Hello\"\nmsgstr \"Hola\"\n"))
+	if err := po.GetDomain().CheckPluralCoverage(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
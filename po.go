@@ -6,6 +6,7 @@
 package gotext
 
 import (
+	"context"
 	"strconv"
 	"strings"
 )
@@ -105,10 +106,55 @@ func (po *Po) GetNC(str, plural string, n int, ctx string, vars ...interface{})
 	return po.domain.GetNC(str, plural, n, ctx, vars...)
 }
 
+func (po *Po) GetOrdinal(str string, n int, vars ...interface{}) string {
+	return po.domain.GetOrdinal(str, n, vars...)
+}
+
+func (po *Po) GetRange(str, plural string, from, to int, vars ...interface{}) string {
+	return po.domain.GetRange(str, plural, from, to, vars...)
+}
+
+func (po *Po) SetProgressFunc(fn func(ParseProgress)) {
+	po.domain.SetProgressFunc(fn)
+}
+
+// SetHeader sets a catalog header. See Domain.SetHeader.
+func (po *Po) SetHeader(key, value string) error {
+	err := po.domain.SetHeader(key, value)
+	po.Language = po.domain.GetLanguage()
+	po.PluralForms = po.domain.PluralForms
+	return err
+}
+
+// SetPluralForms sets the Plural-Forms header and its plural rule. See Domain.SetPluralForms.
+func (po *Po) SetPluralForms(pf string) error {
+	return po.SetHeader("Plural-Forms", pf)
+}
+
+func (po *Po) SetPluralPolicy(p PluralPolicy) {
+	po.domain.SetPluralPolicy(p)
+}
+
+func (po *Po) PluralMismatch() *PluralMismatch {
+	return po.domain.PluralMismatch()
+}
+
+func (po *Po) SetDuplicatePolicy(p DuplicatePolicy) {
+	po.domain.SetDuplicatePolicy(p)
+}
+
+func (po *Po) Duplicates() []*DuplicateEntry {
+	return po.domain.Duplicates()
+}
+
 func (po *Po) MarshalText() ([]byte, error) {
 	return po.domain.MarshalText()
 }
 
+func (po *Po) MarshalTextScrubbed(opts ScrubOptions) ([]byte, error) {
+	return po.domain.MarshalTextScrubbed(opts)
+}
+
 func (po *Po) MarshalBinary() ([]byte, error) {
 	return po.domain.MarshalBinary()
 }
@@ -118,16 +164,26 @@ func (po *Po) UnmarshalBinary(data []byte) error {
 }
 
 func (po *Po) ParseFile(f string) {
+	_, span := startSpan(context.Background(), "gotext.ParseFile", "file", f)
 	data, err := getFileData(f)
 	if err != nil {
+		span.End(err)
 		return
 	}
 
-	po.Parse(data)
+	po.parse(data, f)
+	span.SetAttribute("entries", po.domain.entryCount())
+	span.End(nil)
 }
 
 // Parse loads the translations specified in the provided string (str)
 func (po *Po) Parse(buf []byte) {
+	po.parse(buf, "")
+}
+
+// parse loads the translations of buf, read from the file named file, if any,
+// and returns the syntax errors found, checked like ParseStrict does.
+func (po *Po) parse(buf []byte, file string) ParseErrors {
 	if po.domain == nil {
 		panic("NewPo() was not used to instantiate this object")
 	}
@@ -145,14 +201,36 @@ func (po *Po) Parse(buf []byte) {
 	po.domain.trBuffer = NewTranslation()
 	po.domain.ctxBuffer = ""
 	po.domain.refBuffer = ""
-
+	po.domain.fuzBuffer = false
+	po.domain.duplicates = nil
+	po.domain.fileBuffer = file
+	po.domain.lineBuffer = 0
+	po.domain.blocks = nil
+	po.domain.progress.start(len(buf))
+
+	var obsolete []string
 	state := head
-	for _, l := range lines {
+	v := newPoValidator()
+	for n, l := range lines {
+		po.domain.progress.advance(len(l) + 1)
+
 		// Trim spaces
 		l = strings.TrimSpace(l)
+		v.line(n+1, l)
+
+		// Buffer obsolete entries, skipping their previous msgid (#~|)
+		if strings.HasPrefix(l, "#~") {
+			if l = strings.TrimPrefix(l, "#~"); !strings.HasPrefix(l, "|") {
+				obsolete = append(obsolete, strings.TrimSpace(l))
+			}
+			continue
+		}
 
 		// Skip invalid lines
 		if !po.isValidLine(l) {
+			if l != "" && l[0] != '#' {
+				logWarn("gotext: skipped malformed PO line", "line", n+1, "text", l)
+			}
 			po.parseComment(l, state)
 			continue
 		}
@@ -160,6 +238,7 @@ func (po *Po) Parse(buf []byte) {
 		// Buffer context and continue
 		if strings.HasPrefix(l, "msgctxt") {
 			po.parseContext(l)
+			po.domain.lineBuffer = n + 1
 			state = msgCtxt
 			continue
 		}
@@ -167,6 +246,11 @@ func (po *Po) Parse(buf []byte) {
 		// Buffer msgid and continue
 		if strings.HasPrefix(l, "msgid") && !strings.HasPrefix(l, "msgid_plural") {
 			po.parseID(l)
+			if state != msgCtxt {
+				po.domain.lineBuffer = n + 1
+			}
+			po.domain.trBuffer.File = po.domain.fileBuffer
+			po.domain.trBuffer.Line = po.domain.lineBuffer
 			state = msgID
 			continue
 		}
@@ -196,6 +280,14 @@ func (po *Po) Parse(buf []byte) {
 	// Save last Translation buffer.
 	po.saveBuffer()
 
+	// Parse obsolete entries apart
+	po.domain.Obsolete = nil
+	if len(obsolete) > 0 {
+		obs := NewPo()
+		obs.Parse([]byte(strings.Join(obsolete, "\n")))
+		po.domain.Obsolete = obs.domain.entries()
+	}
+
 	// Parse headers
 	po.domain.parseHeaders()
 
@@ -204,20 +296,31 @@ func (po *Po) Parse(buf []byte) {
 	po.Language = po.domain.Language
 	po.PluralForms = po.domain.PluralForms
 	po.Headers = po.domain.Headers
+
+	po.domain.progress.done()
+	return v.finish()
 }
 
 // saveBuffer takes the context and Translation buffers
 // and saves it on the translations collection
 func (po *Po) saveBuffer() {
+	if po.domain.trBuffer.ID != "" {
+		po.domain.progress.entry()
+	}
+
 	// With no context...
 	if po.domain.ctxBuffer == "" {
-		po.domain.translations[po.domain.trBuffer.ID] = po.domain.trBuffer
+		if po.keep(po.domain.translations) {
+			po.domain.translations[po.domain.trBuffer.ID] = po.domain.trBuffer
+		}
 	} else {
 		// With context...
 		if _, ok := po.domain.contexts[po.domain.ctxBuffer]; !ok {
 			po.domain.contexts[po.domain.ctxBuffer] = make(map[string]*Translation)
 		}
-		po.domain.contexts[po.domain.ctxBuffer][po.domain.trBuffer.ID] = po.domain.trBuffer
+		if po.keep(po.domain.contexts[po.domain.ctxBuffer]) {
+			po.domain.contexts[po.domain.ctxBuffer][po.domain.trBuffer.ID] = po.domain.trBuffer
+		}
 
 		// Cleanup current context buffer if needed
 		if po.domain.trBuffer.ID != "" {
@@ -225,12 +328,34 @@ func (po *Po) saveBuffer() {
 		}
 	}
 
+	// Flags and references before a msgctxt are kept until its msgid
+	fuzzy := po.domain.fuzBuffer || (po.domain.trBuffer.ID == "" && po.domain.trBuffer.Fuzzy)
+	var refs []string
+	if po.domain.refBuffer != "" {
+		refs = strings.Split(po.domain.refBuffer, " ")
+	} else if po.domain.trBuffer.ID == "" {
+		refs = po.domain.trBuffer.Refs
+	}
+
 	// Flush Translation buffer
-	if po.domain.refBuffer == "" {
+	if refs == nil {
 		po.domain.trBuffer = NewTranslation()
 	} else {
-		po.domain.trBuffer = NewTranslationWithRefs(strings.Split(po.domain.refBuffer, " "))
+		po.domain.trBuffer = NewTranslationWithRefs(refs)
 	}
+	po.domain.trBuffer.Fuzzy = fuzzy
+	po.domain.fuzBuffer = false
+	po.domain.refBuffer = ""
+}
+
+// keep reports whether the Translation buffer is stored in translations, applying the duplicate policy
+// when it already has the same msgid.
+func (po *Po) keep(translations map[string]*Translation) bool {
+	old, ok := translations[po.domain.trBuffer.ID]
+	if !ok || po.domain.trBuffer.ID == "" {
+		return true
+	}
+	return po.domain.keepDuplicate(po.domain.ctxBuffer, old, po.domain.trBuffer)
 }
 
 // Either preserves comments before the first "msgid", for later round-trip.
@@ -245,6 +370,12 @@ func (po *Po) parseComment(l string, state parseState) {
 				if len(l) > 2 {
 					po.domain.refBuffer = strings.TrimSpace(l[2:])
 				}
+			case ',':
+				for _, flag := range strings.Split(l[2:], ",") {
+					if strings.TrimSpace(flag) == "fuzzy" {
+						po.domain.fuzBuffer = true
+					}
+				}
 			}
 		}
 	}
@@ -7,8 +7,10 @@ package gotext
 
 import (
 	"fmt"
+	"io/ioutil"
 	"os"
 	"path"
+	"path/filepath"
 	"testing"
 )
 
@@ -735,3 +737,67 @@ func TestPoTextEncoding(t *testing.T) {
 		t.Errorf("Expected 'This one is plural in a Ctx context: Test' but got '%s'", tr)
 	}
 }
+
+func TestPoPositions(t *testing.T) {
+	data := []byte(`msgid ""
+msgstr "Language: es\n"
+
+# A comment
+msgid "Hello"
+msgstr "Hola"
+
+#, fuzzy
+msgctxt "menu"
+msgid "Open"
+msgstr "Abrir"
+
+msgid "%d file"
+msgid_plural "%d files"
+msgstr[0] "%d archivo"
+msgstr[1] "%d archivos"
+`)
+
+	po := NewPo()
+	po.Parse(data)
+	do := po.GetDomain()
+
+	want := map[string]int{"Hello": 5, "Open": 9, "%d file": 13}
+	for _, e := range do.entries() {
+		if line := want[e.MsgID]; e.Translation.Line != line || e.Translation.File != "" {
+			t.Errorf("Expected %q at line %d, got %s:%d", e.MsgID, line, e.Translation.File, e.Translation.Line)
+		}
+	}
+
+	// Files keep their name
+	dir, err := ioutil.TempDir("", "gotext")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "es.po")
+	if err := ioutil.WriteFile(file, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	po = NewPo()
+	po.ParseFile(file)
+	tr := po.GetDomain().contexts["menu"]["Open"]
+	if tr == nil || tr.File != file || tr.Line != 9 {
+		t.Errorf("Unexpected position %+v", tr)
+	}
+
+	l := NewLocale("", "es")
+	if err := l.AddDomainFile("default", file); err != nil {
+		t.Fatal(err)
+	}
+	if tr := l.Domains["default"].GetDomain().translations["Hello"]; tr.File != file || tr.Line != 5 {
+		t.Errorf("Unexpected position %s:%d", tr.File, tr.Line)
+	}
+
+	// Compaction keeps them
+	do = l.Domains["default"].GetDomain()
+	do.Compact()
+	if tr := do.translations["%d file"]; tr.File != file || tr.Line != 13 {
+		t.Errorf("Unexpected position %s:%d", tr.File, tr.Line)
+	}
+}
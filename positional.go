@@ -0,0 +1,191 @@
+package gotext
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// PositionalMode tells how the placeholders of translations are rewritten when their catalog is loaded.
+// See Domain.RewritePositional.
+type PositionalMode int
+
+const (
+	// PositionalKeep uses the translations as they are. It's the default.
+	PositionalKeep PositionalMode = iota
+	// PositionalRemap rewrites the placeholders of translations with RemapPositional.
+	PositionalRemap
+	// PositionalIndex rewrites the placeholders of translations with IndexPositional.
+	PositionalIndex
+)
+
+// xsiRe matches the argument index of the positional placeholders of C and PHP (%2$s), and escaped percent signs.
+var xsiRe = regexp.MustCompile(`%%|%(\d+)\$`)
+
+/*
+RemapPositional rewrites the reordered placeholders of the translation s so they keep referring to the right vars:
+
+  - The positional placeholders of C and PHP, like %2$s, are rewritten with the fmt explicit index, %[2]s.
+  - The placeholders without index following one with an explicit index take the first argument no other
+    placeholder uses, instead of the one after it, as translators expect: "%[2]s von %s" becomes "%[2]s von %[1]s".
+
+Placeholders before the first explicit index are kept, as fmt already numbers them in order.
+Strings using '*' for a width or precision are only rewritten from the C syntax, and named %(name)s placeholders are ignored.
+*/
+func RemapPositional(s string) string {
+	return rewritePositional(s, false)
+}
+
+// IndexPositional works like RemapPositional, and also gives an explicit index to every other placeholder,
+// so "%s has %d files" becomes "%[1]s has %[2]d files" and stays right when reordered by a later edit.
+func IndexPositional(s string) string {
+	return rewritePositional(s, true)
+}
+
+// rewritePositional implements RemapPositional, and IndexPositional when all is true.
+func rewritePositional(s string, all bool) string {
+	if !strings.Contains(s, "%") {
+		return s
+	}
+
+	s = xsiRe.ReplaceAllStringFunc(s, func(m string) string {
+		if m == "%%" {
+			return m
+		}
+		return "%[" + m[1:len(m)-1] + "]"
+	})
+
+	// Argument of every placeholder, 0 for the ones to rewrite
+	locs := verbRe.FindAllStringIndex(s, -1)
+	args := make([]int, len(locs))
+	used := make(map[int]bool)
+	explicit := false
+	next := 1
+	for i, loc := range locs {
+		v := s[loc[0]:loc[1]]
+		if v == "%%" || strings.HasPrefix(v, "%(") {
+			args[i] = -1
+			continue
+		}
+		if strings.Contains(v, "*") {
+			return s
+		}
+
+		if j := strings.LastIndexByte(v, '['); j != -1 {
+			args[i], _ = strconv.Atoi(v[j+1 : j+strings.IndexByte(v[j:], ']')])
+			used[args[i]] = true
+			explicit = true
+			continue
+		}
+		if !explicit {
+			used[next] = true
+			if !all {
+				args[i] = -1
+			} else {
+				args[i] = next
+			}
+			next++
+		}
+	}
+
+	var b strings.Builder
+	last := 0
+	free := 1
+	for i, loc := range locs {
+		if args[i] == -1 || strings.Contains(s[loc[0]:loc[1]], "[") {
+			continue
+		}
+		arg := args[i]
+		if arg == 0 {
+			for used[free] {
+				free++
+			}
+			arg = free
+			used[arg] = true
+		}
+
+		// The index goes right before the verb, after the flags, width and precision
+		b.WriteString(s[last : loc[1]-1])
+		b.WriteString("[" + strconv.Itoa(arg) + "]")
+		last = loc[1] - 1
+	}
+	b.WriteString(s[last:])
+	return b.String()
+}
+
+/*
+RewritePositional rewrites the placeholders of every translation in the Domain as told by mode,
+so catalogs written for gettext in C or PHP, or reordered by translators, format their vars right:
+
+	po := gotext.NewPo()
+	po.ParseFile("/path/to/po/file/translations.po")
+	po.GetDomain().RewritePositional(gotext.PositionalRemap)
+	po.Get("%s has %d files", "Ana", 3) // msgstr "%2$d Dateien hat %1$s" gives "3 Dateien hat Ana"
+
+It changes the current translations, so it must be called again after adding new ones. See RemapPositional and IndexPositional.
+*/
+func (do *Domain) RewritePositional(mode PositionalMode) {
+	if mode == PositionalKeep {
+		return
+	}
+
+	all := mode == PositionalIndex
+	do.rewriteMsgstrs(func(str string) string {
+		return rewritePositional(str, all)
+	})
+}
+
+// rewriteMsgstrs replaces every msgstr of the Domain with f(msgstr).
+// Changed translations are copied, as they can be shared with other catalogs.
+func (do *Domain) rewriteMsgstrs(f func(string) string) {
+	do.trMutex.Lock()
+	defer do.trMutex.Unlock()
+
+	moved := make(map[*Translation]*Translation)
+	rewrite := func(trs map[string]*Translation) {
+		for id, tr := range trs {
+			if c, ok := moved[tr]; ok {
+				trs[id] = c
+				continue
+			}
+			var c *Translation
+			for i, str := range tr.Trs {
+				if s := f(str); s != str {
+					if c == nil {
+						c = copyTranslation(tr)
+					}
+					c.Trs[i] = s
+				}
+			}
+			if c != nil {
+				trs[id] = c
+				moved[tr] = c
+			}
+		}
+	}
+	rewrite(do.translations)
+	for _, trs := range do.contexts {
+		rewrite(trs)
+	}
+
+	remap := func(index map[string]*Translation) {
+		for key, tr := range index {
+			if c, ok := moved[tr]; ok {
+				index[key] = c
+			}
+		}
+	}
+	remap(do.normalized)
+	remap(do.pluralTranslations)
+	for _, index := range do.normalizedC {
+		remap(index)
+	}
+}
+
+// SetPositionalMode makes the catalogs loaded afterwards by AddDomain rewrite the placeholders of their translations
+// as told by mode. See Domain.RewritePositional.
+func (l *Locale) SetPositionalMode(mode PositionalMode) {
+	l.Lock()
+	l.positionalMode = mode
+	l.Unlock()
+}
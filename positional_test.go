@@ -0,0 +1,90 @@
+package gotext
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestRemapPositional(t *testing.T) {
+	for _, c := range []struct{ in, remap, index string }{
+		{"No placeholders", "No placeholders", "No placeholders"},
+		{"%s has %d files", "%s has %d files", "%[1]s has %[2]d files"},
+		{"%2$d Dateien hat %1$s", "%[2]d Dateien hat %[1]s", "%[2]d Dateien hat %[1]s"},
+		{"%[2]s von %s", "%[2]s von %[1]s", "%[2]s von %[1]s"},
+		{"%s: %[3]s %s", "%s: %[3]s %[2]s", "%[1]s: %[3]s %[2]s"},
+		{"%[2]-5s|%5.2f", "%[2]-5s|%5.2[1]f", "%[2]-5s|%5.2[1]f"},
+		{"100%% %s", "100%% %s", "100%% %[1]s"},
+		{"%%2$s", "%%2$s", "%%2$s"},
+		{"%(name)s %[2]s %s", "%(name)s %[2]s %[1]s", "%(name)s %[2]s %[1]s"},
+		{"%2$*d %s", "%[2]*d %s", "%[2]*d %s"},
+	} {
+		if got := RemapPositional(c.in); got != c.remap {
+			t.Errorf("RemapPositional(%q): expected %q, got %q", c.in, c.remap, got)
+		}
+		if got := IndexPositional(c.in); got != c.index {
+			t.Errorf("IndexPositional(%q): expected %q, got %q", c.in, c.index, got)
+		}
+	}
+
+	// The remapped string formats the vars in the order translators expect
+	format := RemapPositional("%[2]s von %s")
+	if got := fmt.Sprintf(format, "Ana", "Dateien"); got != "Dateien von Ana" {
+		t.Errorf("unexpected output %q", got)
+	}
+}
+
+func TestDomainRewritePositional(t *testing.T) {
+	shared := NewPo()
+	shared.Parse([]byte(`
+msgid "%s has %d files"
+msgstr "%2$d Dateien hat %1$s"
+
+msgctxt "title"
+msgid "%s by %s"
+msgstr "%[2]s: %s"
+
+msgid "%d file"
+msgid_plural "%d files"
+msgstr[0] "%1$d Datei"
+msgstr[1] "%1$d Dateien"
+`))
+	original := shared.GetDomain().translations["%s has %d files"]
+
+	shared.GetDomain().RewritePositional(PositionalRemap)
+
+	msgid, plural, ctxMsgid := "%s has %d files", "%d files", "%s by %s"
+	for _, c := range []struct{ got, want string }{
+		{shared.Get(msgid, "Ana", 3), "3 Dateien hat Ana"},
+		{shared.GetC(ctxMsgid, "title", "Faust", "Goethe"), "Goethe: Faust"},
+		{shared.GetN("%d file", plural, 2, 2), "2 Dateien"},
+	} {
+		if c.got != c.want {
+			t.Errorf("expected %q, got %q", c.want, c.got)
+		}
+	}
+
+	// Rewritten translations are copies
+	if original.Get() != "%2$d Dateien hat %1$s" {
+		t.Errorf("the original translation changed: %q", original.Get())
+	}
+}
+
+func TestLocaleSetPositionalMode(t *testing.T) {
+	src := &MemorySource{Files: map[string][]byte{
+		"de/default.po": []byte("msgid \"%s has %d files\"\nmsgstr \"%s hat %d Dateien\"\n"),
+	}}
+
+	l := NewLocaleWithSource(src, "de")
+	l.SetPositionalMode(PositionalIndex)
+	l.AddDomain("default")
+
+	tr := l.Domains["default"].GetDomain().translations["%s has %d files"]
+	if tr == nil || tr.Get() != "%[1]s hat %[2]d Dateien" {
+		t.Errorf("unexpected translation %v", tr)
+	}
+
+	msgid := "%s has %d files"
+	if got := l.Get(msgid, "Ana", 3); got != "Ana hat 3 Dateien" {
+		t.Errorf("unexpected translation %q", got)
+	}
+}
@@ -0,0 +1,32 @@
+package gotext
+
+// PostProcessor rewrites out, the string returned for the msgid msgid by a Locale. See Locale.AddPostProcessor.
+type PostProcessor func(msgid, out string) string
+
+/*
+AddPostProcessor adds f to the functions applied, in the order they're added, to every string returned by the
+Get and Lookup functions of the Locale, translated or not, after formatting. It's meant for typography fixes,
+branding substitutions or filters that apply to a whole language:
+
+	fr := gotext.NewLocale("/path/to/i18n/dir", "fr")
+	fr.AddPostProcessor(func(msgid, out string) string {
+		// Non-breaking space before double punctuation
+		return strings.NewReplacer(" :", "\u00a0:", " ;", "\u00a0;", " !", "\u00a0!", " ?", "\u00a0?").Replace(out)
+	})
+
+f must be safe for concurrent use. The output charset (see SetOutputCharset) is applied after every post-processor.
+*/
+func (l *Locale) AddPostProcessor(f PostProcessor) {
+	l.Lock()
+	l.postProcessors = append(l.postProcessors, f)
+	l.Unlock()
+}
+
+// output applies the post-processors and the output charset of the Locale to out, the string returned for msgid.
+// The Locale must be locked.
+func (l *Locale) output(msgid, out string) string {
+	for _, f := range l.postProcessors {
+		out = f(msgid, out)
+	}
+	return l.encodeOutput(out)
+}
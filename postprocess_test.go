@@ -0,0 +1,52 @@
+package gotext
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLocaleAddPostProcessor(t *testing.T) {
+	src := &MemorySource{Files: map[string][]byte{
+		"fr/default.po": []byte("msgid \"Are you sure?\"\nmsgstr \"Êtes-vous sûr ?\"\n\n" +
+			"msgid \"Welcome to %s!\"\nmsgstr \"Bienvenue sur %s !\"\n"),
+	}}
+
+	l := NewLocaleWithSource(src, "fr")
+	l.AddDomain("default")
+
+	var msgids []string
+	l.AddPostProcessor(func(msgid, out string) string {
+		msgids = append(msgids, msgid)
+		return strings.Replace(out, " ?", "\u00a0?", -1)
+	})
+	l.AddPostProcessor(func(msgid, out string) string {
+		return strings.Replace(out, "ACME", "Globex", -1)
+	})
+
+	welcome := "Welcome to %s!"
+	for _, c := range []struct{ got, want string }{
+		{l.Get("Are you sure?"), "Êtes-vous sûr\u00a0?"},
+		{l.Get(welcome, "ACME"), "Bienvenue sur Globex !"},
+		{l.Get("Untranslated ACME?"), "Untranslated Globex?"},
+	} {
+		if c.got != c.want {
+			t.Errorf("expected %q, got %q", c.want, c.got)
+		}
+	}
+	if got, ok := l.Lookup("Are you sure?"); got != "Êtes-vous sûr\u00a0?" || !ok {
+		t.Errorf("unexpected lookup %q, %v", got, ok)
+	}
+	if len(msgids) != 4 || msgids[1] != welcome {
+		t.Errorf("unexpected msgids %q", msgids)
+	}
+
+	// Clones keep their own post-processors
+	c := l.Clone()
+	c.AddPostProcessor(func(msgid, out string) string { return strings.ToUpper(out) })
+	if got := l.Get("Are you sure?"); got != "Êtes-vous sûr\u00a0?" {
+		t.Errorf("unexpected translation %q", got)
+	}
+	if got := c.Get("Are you sure?"); got != "ÊTES-VOUS SÛR\u00a0?" {
+		t.Errorf("unexpected translation %q", got)
+	}
+}
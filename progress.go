@@ -0,0 +1,68 @@
+package gotext
+
+// ParseProgress is reported to the progress function of a Po or Mo object while parsing.
+type ParseProgress struct {
+	// BytesProcessed so far, out of TotalBytes
+	BytesProcessed int64
+	TotalBytes     int64
+
+	// Entries (msgid) parsed so far
+	Entries int
+}
+
+// progressInterval is the amount of bytes parsed between two progress reports
+const progressInterval = 1 << 20
+
+// progressTracker accumulates parse progress and reports it to fn every progressInterval bytes.
+type progressTracker struct {
+	fn   func(ParseProgress)
+	p    ParseProgress
+	next int64
+}
+
+func (t *progressTracker) start(total int) {
+	t.p = ParseProgress{TotalBytes: int64(total)}
+	t.next = progressInterval
+}
+
+// advance adds n processed bytes.
+func (t *progressTracker) advance(n int) {
+	t.advanceTo(t.p.BytesProcessed + int64(n))
+}
+
+// advanceTo moves the processed bytes forward to pos.
+func (t *progressTracker) advanceTo(pos int64) {
+	if pos <= t.p.BytesProcessed {
+		return
+	}
+	if pos > t.p.TotalBytes {
+		pos = t.p.TotalBytes
+	}
+	t.p.BytesProcessed = pos
+
+	if t.fn != nil && pos >= t.next {
+		t.fn(t.p)
+		t.next = pos - pos%progressInterval + progressInterval
+	}
+}
+
+func (t *progressTracker) entry() {
+	t.p.Entries++
+}
+
+// done reports the final progress, with all bytes processed.
+func (t *progressTracker) done() {
+	t.p.BytesProcessed = t.p.TotalBytes
+	if t.fn != nil {
+		t.fn(t.p)
+	}
+}
+
+// SetProgressFunc sets a function to be called with the parse progress every megabyte of parsed data,
+// and once more when parsing finishes. It allows displaying progress while loading very large catalogs.
+// fn is called while the catalog is locked for parsing, so it must not use the catalog in any way.
+func (do *Domain) SetProgressFunc(fn func(ParseProgress)) {
+	do.trMutex.Lock()
+	do.progress.fn = fn
+	do.trMutex.Unlock()
+}
@@ -0,0 +1,49 @@
+package gotext
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func TestParseProgress(t *testing.T) {
+	var buf bytes.Buffer
+	buf.WriteString("msgid \"\"\nmsgstr \"\"\n\"Language: en\\n\"\n")
+	for i := 0; buf.Len() < 3*progressInterval; i++ {
+		fmt.Fprintf(&buf, "\nmsgid \"Entry %d\"\nmsgstr \"Translated entry %d\"\n", i, i)
+	}
+
+	var reports []ParseProgress
+	po := NewPo()
+	po.SetProgressFunc(func(p ParseProgress) {
+		reports = append(reports, p)
+	})
+	po.Parse(buf.Bytes())
+
+	if len(reports) < 3 {
+		t.Fatalf("Expected at least 3 progress reports but got %d", len(reports))
+	}
+	for i := 1; i < len(reports); i++ {
+		if reports[i].BytesProcessed < reports[i-1].BytesProcessed || reports[i].Entries < reports[i-1].Entries {
+			t.Errorf("Progress went backwards: %+v after %+v", reports[i], reports[i-1])
+		}
+	}
+
+	last := reports[len(reports)-1]
+	if last.BytesProcessed != int64(buf.Len()) || last.TotalBytes != int64(buf.Len()) {
+		t.Errorf("Expected all %d bytes processed but got %+v", buf.Len(), last)
+	}
+	if tr := po.Get(fmt.Sprintf("Entry %d", last.Entries-1)); tr != fmt.Sprintf("Translated entry %d", last.Entries-1) {
+		t.Errorf("Expected %d entries parsed, but last one wasn't found", last.Entries)
+	}
+
+	var moReports []ParseProgress
+	mo := NewMo()
+	mo.SetProgressFunc(func(p ParseProgress) {
+		moReports = append(moReports, p)
+	})
+	mo.ParseFile("fixtures/en_US/default.mo")
+	if len(moReports) != 1 || moReports[0].Entries == 0 || moReports[0].BytesProcessed != moReports[0].TotalBytes {
+		t.Errorf("Expected a single final MO progress report but got %+v", moReports)
+	}
+}
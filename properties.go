@@ -0,0 +1,288 @@
+package gotext
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// propertiesPluralKey separates the base key of a plural message from its CLDR category, as in "files.count.one"
+const propertiesPluralKey = ".count."
+
+/*
+Properties parses Java .properties files (as used by ResourceBundle) and provides all the Translation functions needed,
+so JVM translation assets can be used without converting them.
+
+Keys are used as msgids. Values follow the Java rules: line continuations, \uXXXX and other escapes,
+and ISO-8859-1 encoding for files that aren't valid UTF-8.
+
+MessageFormat placeholders ({0}, {1,number}...) are passed as positional vars, so "Hello {0}" is rendered
+with Get("greeting", "World"). Quotes are handled like MessageFormat does in messages with placeholders.
+
+Plural messages use one key per CLDR category of the language, with the ".count." infix:
+
+	files.count.one = {0} file
+	files.count.other = {0} files
+
+They're plural entries, looked up with GetN("files", "files", n, n).
+Categories missing from the file use the "other" value.
+*/
+type Properties struct {
+	domain *Domain
+}
+
+// NewProperties should always be used to instantiate a new Properties object.
+// lang is the language of the resources, used to map plural keys to msgstr indexes.
+func NewProperties(lang string) *Properties {
+	p := &Properties{domain: NewDomain()}
+	p.domain.setCLDRLanguage(lang)
+
+	return p
+}
+
+func (p *Properties) GetDomain() *Domain {
+	return p.domain
+}
+
+func (p *Properties) Get(str string, vars ...interface{}) string {
+	return printfIndexed(p.domain.Get(str), vars...)
+}
+
+func (p *Properties) GetN(str, plural string, n int, vars ...interface{}) string {
+	return printfIndexed(p.domain.GetN(str, plural, n), vars...)
+}
+
+func (p *Properties) GetC(str, ctx string, vars ...interface{}) string {
+	return printfIndexed(p.domain.GetC(str, ctx), vars...)
+}
+
+func (p *Properties) GetNC(str, plural string, n int, ctx string, vars ...interface{}) string {
+	return printfIndexed(p.domain.GetNC(str, plural, n, ctx), vars...)
+}
+
+func (p *Properties) MarshalBinary() ([]byte, error) {
+	return p.domain.MarshalBinary()
+}
+
+func (p *Properties) UnmarshalBinary(data []byte) error {
+	return p.domain.UnmarshalBinary(data)
+}
+
+func (p *Properties) ParseFile(f string) {
+	data, err := getFileData(f)
+	if err != nil {
+		return
+	}
+
+	p.Parse(data)
+}
+
+// Parse loads the translations specified in the provided byte slice, in the .properties format.
+func (p *Properties) Parse(buf []byte) {
+	values := parseProperties(buf)
+
+	do := p.domain
+	do.trMutex.Lock()
+	do.pluralMutex.Lock()
+	defer do.trMutex.Unlock()
+	defer do.pluralMutex.Unlock()
+
+	plurals := make(map[string]map[string]string)
+	for key, value := range values {
+		if i := strings.LastIndex(key, propertiesPluralKey); i != -1 {
+			category := key[i+len(propertiesPluralKey):]
+			if _, ok := cldrCategories[category]; ok {
+				base := key[:i]
+				if plurals[base] == nil {
+					plurals[base] = make(map[string]string)
+				}
+				plurals[base][category] = messageFormat(value)
+				continue
+			}
+		}
+
+		tr := NewTranslation()
+		tr.ID = key
+		tr.Set(messageFormat(value))
+		do.translations[key] = tr
+	}
+
+	forms := cardinalForms(do.tag)
+	for base, categories := range plurals {
+		tr := NewTranslation()
+		tr.ID = base
+		tr.PluralID = base
+		for i, form := range forms {
+			str, ok := categories[cldrCategory(form)]
+			if !ok {
+				str = categories["other"]
+			}
+			tr.Trs[i] = str
+		}
+		do.translations[base] = tr
+	}
+}
+
+// parseProperties reads the key/value pairs of a .properties file. Later keys replace earlier ones.
+func parseProperties(buf []byte) map[string]string {
+	src := string(buf)
+	if !utf8.Valid(buf) {
+		// ISO-8859-1 maps every byte to the same code point
+		runes := make([]rune, len(buf))
+		for i, b := range buf {
+			runes[i] = rune(b)
+		}
+		src = string(runes)
+	}
+
+	values := make(map[string]string)
+	lines := strings.Split(strings.Replace(strings.Replace(src, "\r\n", "\n", -1), "\r", "\n", -1), "\n")
+
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimLeft(lines[i], " \t\f")
+		if line == "" || line[0] == '#' || line[0] == '!' {
+			continue
+		}
+
+		// Join continuation lines, ending with an odd number of backslashes
+		for propertiesContinues(line) && i+1 < len(lines) {
+			i++
+			line = line[:len(line)-1] + strings.TrimLeft(lines[i], " \t\f")
+		}
+		if propertiesContinues(line) {
+			line = line[:len(line)-1]
+		}
+
+		// The key ends at the first unescaped separator: '=', ':' or whitespace
+		end := len(line)
+		for j := 0; j < len(line); j++ {
+			if line[j] == '\\' {
+				j++
+				continue
+			}
+			if strings.IndexByte("=: \t\f", line[j]) != -1 {
+				end = j
+				break
+			}
+		}
+		key, value := line[:end], strings.TrimLeft(line[end:], " \t\f")
+		if value != "" && (value[0] == '=' || value[0] == ':') {
+			value = strings.TrimLeft(value[1:], " \t\f")
+		}
+
+		values[unescapeProperties(key)] = unescapeProperties(value)
+	}
+
+	return values
+}
+
+// propertiesContinues tells whether a line ends with an odd number of backslashes, so it continues on the next one.
+func propertiesContinues(line string) bool {
+	n := 0
+	for i := len(line) - 1; i >= 0 && line[i] == '\\'; i-- {
+		n++
+	}
+	return n%2 == 1
+}
+
+// unescapeProperties resolves the escape sequences of a .properties key or value.
+func unescapeProperties(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+
+	var b strings.Builder
+	var surrogate rune
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+
+		i++
+		switch c := s[i]; c {
+		case 't':
+			b.WriteByte('\t')
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 'f':
+			b.WriteByte('\f')
+		case 'u':
+			if i+4 >= len(s) {
+				b.WriteByte('u')
+				continue
+			}
+			r, err := strconv.ParseUint(s[i+1:i+5], 16, 16)
+			if err != nil {
+				b.WriteByte('u')
+				continue
+			}
+			i += 4
+
+			// UTF-16 surrogate pairs are written as two escapes
+			switch {
+			case utf16.IsSurrogate(rune(r)) && surrogate == 0:
+				surrogate = rune(r)
+				continue
+			case surrogate != 0:
+				b.WriteRune(utf16.DecodeRune(surrogate, rune(r)))
+				surrogate = 0
+			default:
+				b.WriteRune(rune(r))
+			}
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// messageFormat converts the placeholders of a Java MessageFormat pattern, like {0} or {1,number},
+// to fmt verbs with explicit argument indexes. Patterns without placeholders are returned as is.
+func messageFormat(s string) string {
+	if !strings.Contains(s, "{") {
+		return s
+	}
+
+	var b strings.Builder
+	placeholders := false
+	quoted := false
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '\'' && i+1 < len(s) && s[i+1] == '\'':
+			b.WriteByte('\'')
+			i++
+		case c == '\'':
+			quoted = !quoted
+		case c == '{' && !quoted:
+			end := strings.IndexByte(s[i:], '}')
+			if end == -1 {
+				return s
+			}
+			arg := s[i+1 : i+end]
+			if comma := strings.IndexByte(arg, ','); comma != -1 {
+				arg = arg[:comma]
+			}
+			n, err := strconv.Atoi(strings.TrimSpace(arg))
+			if err != nil || n < 0 {
+				return s
+			}
+			b.WriteString("%[" + strconv.Itoa(n+1) + "]v")
+			placeholders = true
+			i += end
+		case c == '%':
+			b.WriteString("%%")
+		default:
+			b.WriteByte(c)
+		}
+	}
+
+	if !placeholders {
+		return s
+	}
+	return b.String()
+}
@@ -0,0 +1,93 @@
+package gotext
+
+import (
+	"testing"
+)
+
+const propertiesFile = `# Comment
+! Another comment
+title = My App
+greeting=Hello {0}, you have {1,number} messages
+colon:Separated by colon
+space Separated by space
+   indented = Indented key
+escaped\ key\:x = Escaped key
+unicode = caf\u00e9 \uD83D\uDE00
+tabs = a\tb\nc
+multi = first \
+        second \
+    third
+backslash = C:\\path\\
+quotes = It''s {0}''s turn, '{literal}'
+no.placeholders = It's 100%
+percent = {0}% done
+empty =
+files.count.one = {0} Datei
+files.count.other = {0} Dateien
+other.count.invalid = Not a plural
+`
+
+func TestProperties(t *testing.T) {
+	p := NewProperties("de")
+	p.Parse([]byte(propertiesFile))
+
+	for _, c := range []struct {
+		key  string
+		vars []interface{}
+		want string
+	}{
+		{"title", nil, "My App"},
+		{"greeting", []interface{}{"Ana", 3}, "Hello Ana, you have 3 messages"},
+		{"colon", nil, "Separated by colon"},
+		{"space", nil, "Separated by space"},
+		{"indented", nil, "Indented key"},
+		{"escaped key:x", nil, "Escaped key"},
+		{"unicode", nil, "café 😀"},
+		{"tabs", nil, "a\tb\nc"},
+		{"multi", nil, "first second third"},
+		{"backslash", nil, `C:\path\`},
+		{"quotes", []interface{}{"Bob"}, "It's Bob's turn, {literal}"},
+		{"no.placeholders", nil, "It's 100%"},
+		{"percent", []interface{}{50}, "50% done"},
+		{"other.count.invalid", nil, "Not a plural"},
+	} {
+		if got := p.Get(c.key, c.vars...); got != c.want {
+			t.Errorf("Get(%q) = %q, want %q", c.key, got, c.want)
+		}
+	}
+
+	if tr, ok := p.GetDomain().GetTranslation("empty"); !ok || tr.Trs[0] != "" {
+		t.Errorf("expected an empty translation for empty")
+	}
+
+	for n, want := range map[int]string{1: "1 Datei", 2: "2 Dateien"} {
+		if got := p.GetN("files", "files", n, n); got != want {
+			t.Errorf("GetN(files, %d) = %q, want %q", n, got, want)
+		}
+	}
+}
+
+func TestPropertiesLatin1(t *testing.T) {
+	p := NewProperties("fr")
+	p.Parse([]byte("drink = caf\xe9\n"))
+
+	if got := p.Get("drink"); got != "café" {
+		t.Errorf("unexpected translation %q", got)
+	}
+}
+
+func TestPropertiesPluralFallback(t *testing.T) {
+	p := NewProperties("ru")
+	p.Parse([]byte("files.count.one = {0} файл\nfiles.count.other = {0} файлов\n"))
+
+	if got := p.GetN("files", "files", 3, 3); got != "3 файлов" {
+		t.Errorf("missing categories should use other, got %q", got)
+	}
+	if got := p.GetN("files", "files", 21, 21); got != "21 файл" {
+		t.Errorf("unexpected translation %q", got)
+	}
+}
+
+func TestPropertiesTranslator(t *testing.T) {
+	var _ Translator = NewProperties("en")
+}
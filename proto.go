@@ -0,0 +1,303 @@
+package gotext
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+
+	"golang.org/x/text/language"
+)
+
+// Protocol Buffers wire types
+const (
+	protoVarint  = 0
+	protoFixed64 = 1
+	protoBytes   = 2
+	protoFixed32 = 5
+)
+
+// Field numbers of proto/gotext.proto
+const (
+	protoLocaleLang          = 1
+	protoLocaleDefaultDomain = 2
+	protoLocaleDomains       = 3
+
+	protoDomainHeaders     = 1
+	protoDomainLanguage    = 2
+	protoDomainPluralForms = 3
+	protoDomainMessages    = 4
+
+	protoHeaderKey    = 1
+	protoHeaderValues = 2
+
+	protoMessageContext    = 1
+	protoMessageID         = 2
+	protoMessagePluralID   = 3
+	protoMessageMsgstr     = 4
+	protoMessageReferences = 5
+)
+
+// MarshalProto encodes the headers and translations of the Domain as a gotext.Domain Protocol Buffers message,
+// defined in proto/gotext.proto, so catalogs can be read by services written in other languages.
+func (do *Domain) MarshalProto() ([]byte, error) {
+	var b []byte
+
+	do.trMutex.RLock()
+	for _, k := range do.headerKeys() {
+		var h []byte
+		h = appendProtoString(h, protoHeaderKey, k)
+		for _, value := range do.Headers[k] {
+			h = appendProtoBytes(h, protoHeaderValues, []byte(value))
+		}
+		b = appendProtoBytes(b, protoDomainHeaders, h)
+	}
+	b = appendProtoString(b, protoDomainLanguage, do.Language)
+	b = appendProtoString(b, protoDomainPluralForms, do.PluralForms)
+	nplurals := do.nplurals
+	do.trMutex.RUnlock()
+
+	for _, e := range do.entries() {
+		var m []byte
+		m = appendProtoString(m, protoMessageContext, e.Context)
+		m = appendProtoString(m, protoMessageID, e.MsgID)
+		m = appendProtoString(m, protoMessagePluralID, e.Translation.PluralID)
+		// Repeated strings keep their empty elements, so forms stay in place
+		for _, str := range msgstrs(e.Translation, nplurals) {
+			m = appendProtoBytes(m, protoMessageMsgstr, []byte(str))
+		}
+		for _, ref := range e.Translation.Refs {
+			m = appendProtoBytes(m, protoMessageReferences, []byte(ref))
+		}
+		b = appendProtoBytes(b, protoDomainMessages, m)
+	}
+
+	return b, nil
+}
+
+// UnmarshalProto replaces the headers and translations of the Domain with the ones in a gotext.Domain
+// Protocol Buffers message, as written by MarshalProto. Nothing changes when the data is invalid.
+func (do *Domain) UnmarshalProto(data []byte) error {
+	headers := make(HeaderMap)
+	translations := make(map[string]*Translation)
+	contexts := make(map[string]map[string]*Translation)
+	var lang, pluralForms string
+
+	err := readProto(data, func(field int, value []byte) error {
+		switch field {
+		case protoDomainHeaders:
+			var key string
+			var values []string
+			err := readProto(value, func(field int, value []byte) error {
+				switch field {
+				case protoHeaderKey:
+					key = string(value)
+				case protoHeaderValues:
+					values = append(values, string(value))
+				}
+				return nil
+			})
+			headers[key] = values
+			return err
+
+		case protoDomainLanguage:
+			lang = string(value)
+
+		case protoDomainPluralForms:
+			pluralForms = string(value)
+
+		case protoDomainMessages:
+			var ctx string
+			tr := NewTranslation()
+			err := readProto(value, func(field int, value []byte) error {
+				switch field {
+				case protoMessageContext:
+					ctx = string(value)
+				case protoMessageID:
+					tr.ID = string(value)
+				case protoMessagePluralID:
+					tr.PluralID = string(value)
+				case protoMessageMsgstr:
+					tr.Trs[len(tr.Trs)] = string(value)
+				case protoMessageReferences:
+					tr.Refs = append(tr.Refs, string(value))
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+
+			if ctx == "" {
+				translations[tr.ID] = tr
+				return nil
+			}
+			if _, ok := contexts[ctx]; !ok {
+				contexts[ctx] = make(map[string]*Translation)
+			}
+			contexts[ctx][tr.ID] = tr
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	do.trMutex.Lock()
+	do.pluralMutex.Lock()
+	defer do.trMutex.Unlock()
+	defer do.pluralMutex.Unlock()
+
+	do.Headers = headers
+	do.Language = lang
+	do.tag = language.Make(lang)
+	do.PluralForms = pluralForms
+	do.translations = translations
+	do.contexts = contexts
+
+	if nplurals, plural, expr, err := parsePluralForms(pluralForms); err == nil {
+		do.nplurals = nplurals
+		do.plural = plural
+		do.setPluralExpression(expr)
+	}
+
+	return nil
+}
+
+// MarshalProto encodes the language and catalogs of the Locale as a gotext.Locale Protocol Buffers message,
+// defined in proto/gotext.proto.
+func (l *Locale) MarshalProto() ([]byte, error) {
+	l.RLock()
+	defer l.RUnlock()
+
+	var b []byte
+	b = appendProtoString(b, protoLocaleLang, l.lang)
+	b = appendProtoString(b, protoLocaleDefaultDomain, l.defaultDomain)
+
+	names := make([]string, 0, len(l.Domains))
+	for name := range l.Domains {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		dom, err := l.Domains[name].GetDomain().MarshalProto()
+		if err != nil {
+			return nil, err
+		}
+
+		// Map entries are messages with the key and value as fields 1 and 2
+		var entry []byte
+		entry = appendProtoString(entry, 1, name)
+		entry = appendProtoBytes(entry, 2, dom)
+		b = appendProtoBytes(b, protoLocaleDomains, entry)
+	}
+
+	return b, nil
+}
+
+// UnmarshalProto replaces the language and catalogs of the Locale with the ones in a gotext.Locale
+// Protocol Buffers message, as written by MarshalProto. Nothing changes when the data is invalid.
+func (l *Locale) UnmarshalProto(data []byte) error {
+	var lang, defaultDomain string
+	domains := make(map[string]Translator)
+
+	err := readProto(data, func(field int, value []byte) error {
+		switch field {
+		case protoLocaleLang:
+			lang = string(value)
+		case protoLocaleDefaultDomain:
+			defaultDomain = string(value)
+		case protoLocaleDomains:
+			var name string
+			po := NewPo()
+			err := readProto(value, func(field int, value []byte) error {
+				switch field {
+				case 1:
+					name = string(value)
+				case 2:
+					return po.GetDomain().UnmarshalProto(value)
+				}
+				return nil
+			})
+			domains[name] = po
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	l.Lock()
+	defer l.Unlock()
+
+	l.lang = lang
+	l.tag = language.Make(lang)
+	l.defaultDomain = defaultDomain
+	l.Domains = domains
+
+	return nil
+}
+
+// appendProtoString appends a string field to b. Empty strings are left out, like proto3 does.
+func appendProtoString(b []byte, field int, s string) []byte {
+	if s == "" {
+		return b
+	}
+	return appendProtoBytes(b, field, []byte(s))
+}
+
+// appendProtoBytes appends a length-delimited field to b.
+func appendProtoBytes(b []byte, field int, data []byte) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(buf[:], uint64(field)<<3|protoBytes)
+	b = append(b, buf[:n]...)
+	n = binary.PutUvarint(buf[:], uint64(len(data)))
+	b = append(b, buf[:n]...)
+	return append(b, data...)
+}
+
+// readProto calls fn with the number and value of every length-delimited field of the message in data.
+// Fields of other wire types are skipped, as they aren't used by the gotext messages.
+func readProto(data []byte, fn func(field int, value []byte) error) error {
+	for len(data) > 0 {
+		key, n := binary.Uvarint(data)
+		if n <= 0 {
+			return errors.New("gotext: invalid protobuf field key")
+		}
+		data = data[n:]
+
+		field := int(key >> 3)
+		var size uint64
+		switch key & 7 {
+		case protoVarint:
+			if _, n = binary.Uvarint(data); n <= 0 {
+				return errors.New("gotext: invalid protobuf varint")
+			}
+			size = uint64(n)
+		case protoFixed64:
+			size = 8
+		case protoFixed32:
+			size = 4
+		case protoBytes:
+			if size, n = binary.Uvarint(data); n <= 0 {
+				return errors.New("gotext: invalid protobuf length")
+			}
+			data = data[n:]
+		default:
+			return fmt.Errorf("gotext: unsupported protobuf wire type %d", key&7)
+		}
+		if size > uint64(len(data)) {
+			return errors.New("gotext: truncated protobuf message")
+		}
+
+		if key&7 == protoBytes {
+			if err := fn(field, data[:size]); err != nil {
+				return err
+			}
+		}
+		data = data[size:]
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package gotext
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestDomain_MarshalProto(t *testing.T) {
+	po := NewPo()
+	po.Parse([]byte(convertPo))
+
+	data, err := po.GetDomain().MarshalProto()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Headers come first: field 1, with key "Language" and value "es"
+	if want := []byte("\x0a\x0e\x0a\x08Language\x12\x02es"); !bytes.HasPrefix(data, want) {
+		t.Errorf("unexpected encoding % x", data[:len(want)])
+	}
+
+	// Unknown fields are skipped: a varint (field 9) and a fixed32 (field 10)
+	data = append(data, 0x48, 0x96, 0x01, 0x55, 1, 2, 3, 4)
+
+	do := NewDomain()
+	if err := do.UnmarshalProto(data); err != nil {
+		t.Fatal(err)
+	}
+
+	if lang := do.GetLanguage(); lang != "es" {
+		t.Errorf("unexpected language %q", lang)
+	}
+	if got := do.GetC("Open", "menu"); got != "Abrir" {
+		t.Errorf("unexpected translation %q", got)
+	}
+	if got := do.GetN("%d file", "%d files", 3, 3); got != "3 archivos" {
+		t.Errorf("unexpected plural %q", got)
+	}
+	if refs := do.GetRefs("%d file"); len(refs) != 2 || refs[1] != "util.go" {
+		t.Errorf("unexpected refs %v", refs)
+	}
+	if tr, ok := do.GetTranslation("Untranslated"); !ok || tr.IsTranslated() {
+		t.Error("expected an untranslated entry")
+	}
+}
+
+func TestDomain_UnmarshalProtoErrors(t *testing.T) {
+	po := NewPo()
+	po.Parse([]byte(convertPo))
+	data, _ := po.GetDomain().MarshalProto()
+
+	do := NewDomain()
+	do.SetLanguage("fr")
+	for _, bad := range [][]byte{data[:len(data)-3], {0x0a}, {0x0b, 0}} {
+		if err := do.UnmarshalProto(bad); err == nil {
+			t.Errorf("expected an error for % x", bad)
+		}
+	}
+	if do.GetLanguage() != "fr" {
+		t.Error("the Domain shouldn't change on error")
+	}
+}
+
+func TestLocaleMarshalProto(t *testing.T) {
+	l := NewLocale("fixtures/", "en_US")
+	l.AddDomain("default")
+
+	data, err := l.MarshalProto()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l2 := new(Locale)
+	if err := l2.UnmarshalProto(data); err != nil {
+		t.Fatal(err)
+	}
+	if l2.lang != l.lang || l2.defaultDomain != "default" {
+		t.Errorf("unexpected locale %q, domain %q", l2.lang, l2.defaultDomain)
+	}
+	if l2.Get("My text") != l.Get("My text") {
+		t.Errorf("unexpected translation %q", l2.Get("My text"))
+	}
+	if got, want := l2.GetN("One with var: %s", "Several with vars: %s", 3, "x"), l.GetN("One with var: %s", "Several with vars: %s", 3, "x"); got != want {
+		t.Errorf("unexpected plural %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,197 @@
+package gotext
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Entry is a catalog entry returned by the Domain query methods.
+type Entry struct {
+	Context     string
+	MsgID       string
+	Translation *Translation
+}
+
+// entries returns all the entries of the Domain but the header, sorted by context and msgid.
+func (do *Domain) entries() []Entry {
+	do.trMutex.RLock()
+	entries := make([]Entry, 0, len(do.translations))
+	for id, tr := range do.translations {
+		if id != "" {
+			entries = append(entries, Entry{MsgID: id, Translation: tr})
+		}
+	}
+	for ctx, translations := range do.contexts {
+		for id, tr := range translations {
+			if id != "" {
+				entries = append(entries, Entry{Context: ctx, MsgID: id, Translation: tr})
+			}
+		}
+	}
+	do.trMutex.RUnlock()
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Context != entries[j].Context {
+			return entries[i].Context < entries[j].Context
+		}
+		return entries[i].MsgID < entries[j].MsgID
+	})
+	return entries
+}
+
+// Iterate calls fn for every entry of the Domain, including the ones in a context, sorted by context and msgid.
+// Entries without context come first, with an empty ctx. The header entry is skipped.
+// Iteration stops when fn returns false.
+//
+// fn receives the stored Translation objects, which must not be modified.
+// The Domain isn't locked while fn runs, so it can call other Domain methods.
+func (do *Domain) Iterate(fn func(ctx, msgid string, tr *Translation) bool) {
+	for _, e := range do.entries() {
+		if !fn(e.Context, e.MsgID, e.Translation) {
+			return
+		}
+	}
+}
+
+// GetTranslation returns the stored Translation object for the given msgid, without context.
+// It must not be modified.
+func (do *Domain) GetTranslation(msgid string) (*Translation, bool) {
+	do.trMutex.RLock()
+	defer do.trMutex.RUnlock()
+
+	return do.lookup(msgid)
+}
+
+// GetTranslationC returns the stored Translation object for the given msgid in the given context.
+// It must not be modified.
+func (do *Domain) GetTranslationC(msgid, ctx string) (*Translation, bool) {
+	do.trMutex.RLock()
+	defer do.trMutex.RUnlock()
+
+	return do.lookupC(msgid, ctx)
+}
+
+// FindPrefix returns the entries whose msgid starts with prefix, in any context, sorted by context and msgid.
+func (do *Domain) FindPrefix(prefix string) []Entry {
+	var found []Entry
+	for _, e := range do.entries() {
+		if strings.HasPrefix(e.MsgID, prefix) {
+			found = append(found, e)
+		}
+	}
+	return found
+}
+
+// Find returns the entries whose msgid matches the glob pattern, in any context, sorted by context and msgid.
+// In the pattern, '*' matches any sequence of characters (including none), '?' any single character
+// and '[...]' a character class, like in path.Match, but the '/' character isn't special.
+// A '\' escapes the next character. It returns an error if the pattern is malformed.
+func (do *Domain) Find(pattern string) ([]Entry, error) {
+	re, err := globRegexp(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	var found []Entry
+	for _, e := range do.entries() {
+		if re.MatchString(e.MsgID) {
+			found = append(found, e)
+		}
+	}
+	return found, nil
+}
+
+// globRegexp converts a glob pattern to an anchored regular expression.
+func globRegexp(pattern string) (*regexp.Regexp, error) {
+	var b strings.Builder
+	b.WriteString(`(?s)^`)
+
+	runes := []rune(pattern)
+	for i := 0; i < len(runes); i++ {
+		switch c := runes[i]; c {
+		case '*':
+			b.WriteString(`.*`)
+		case '?':
+			b.WriteString(`.`)
+		case '\\':
+			if i+1 < len(runes) {
+				i++
+				b.WriteString(regexp.QuoteMeta(string(runes[i])))
+			} else {
+				b.WriteString(`\\`)
+			}
+		case '[':
+			end := i + 1
+			if end < len(runes) && (runes[end] == '!' || runes[end] == '^') {
+				end++
+			}
+			if end < len(runes) && runes[end] == ']' {
+				end++
+			}
+			for end < len(runes) && runes[end] != ']' {
+				end++
+			}
+			if end >= len(runes) {
+				// Unterminated class: let regexp report the error
+				b.WriteString(`[`)
+				continue
+			}
+			class := string(runes[i+1 : end])
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			b.WriteString("[" + class + "]")
+			i = end
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+
+	b.WriteString(`$`)
+	return regexp.Compile(b.String())
+}
+
+// Contexts returns the contexts used by the messages of the Domain, sorted. Messages without context aren't in one.
+func (do *Domain) Contexts() []string {
+	do.trMutex.RLock()
+	ctxs := make([]string, 0, len(do.contexts))
+	for ctx, translations := range do.contexts {
+		if ctx != "" && len(translations) > 0 {
+			ctxs = append(ctxs, ctx)
+		}
+	}
+	do.trMutex.RUnlock()
+
+	sort.Strings(ctxs)
+	return ctxs
+}
+
+// ContextDomain returns a new Domain with the headers and plural rule of the Domain and a copy of the messages
+// in the context ctx, still in that context, so they can be exported on their own, like with MarshalText.
+// An empty ctx copies the messages without context.
+func (do *Domain) ContextDomain(ctx string) *Domain {
+	do.trMutex.RLock()
+	defer do.trMutex.RUnlock()
+
+	c := do.emptyCopy()
+	if ctx == "" {
+		for id, tr := range do.translations {
+			c.translations[id] = copyTranslation(tr)
+		}
+		return c
+	}
+
+	if header, ok := do.translations[""]; ok {
+		c.translations[""] = copyTranslation(header)
+	}
+
+	translations := make(map[string]*Translation, len(do.contexts[ctx]))
+	for id, tr := range do.contexts[ctx] {
+		translations[id] = copyTranslation(tr)
+	}
+	if len(translations) > 0 {
+		c.contexts[ctx] = translations
+	}
+	return c
+}
@@ -0,0 +1,153 @@
+package gotext
+
+import (
+	"testing"
+)
+
+const queryCatalog = `
+msgid ""
+msgstr "Language: de\n"
+
+msgid "menu.file"
+msgstr "Datei"
+
+msgid "menu.edit"
+msgstr "Bearbeiten"
+
+msgid "menu/help"
+msgstr "Hilfe"
+
+msgctxt "toolbar"
+msgid "menu.file"
+msgstr "Datei (Leiste)"
+
+msgid "title"
+msgstr "Titel"
+`
+
+func TestDomainIterate(t *testing.T) {
+	po := NewPo()
+	po.Parse([]byte(queryCatalog))
+	do := po.GetDomain()
+
+	var visited []string
+	do.Iterate(func(ctx, msgid string, tr *Translation) bool {
+		visited = append(visited, ctx+"|"+msgid+"="+tr.Get())
+		return true
+	})
+	expected := []string{"|menu.edit=Bearbeiten", "|menu.file=Datei", "|menu/help=Hilfe", "|title=Titel", "toolbar|menu.file=Datei (Leiste)"}
+	if len(visited) != len(expected) {
+		t.Fatalf("Expected %v but got %v", expected, visited)
+	}
+	for i := range expected {
+		if visited[i] != expected[i] {
+			t.Errorf("Expected %s but got %s", expected[i], visited[i])
+		}
+	}
+
+	count := 0
+	do.Iterate(func(ctx, msgid string, tr *Translation) bool {
+		count++
+		return count < 2
+	})
+	if count != 2 {
+		t.Errorf("Expected iteration to stop after 2 entries, got %d", count)
+	}
+}
+
+func TestDomainQuery(t *testing.T) {
+	po := NewPo()
+	po.Parse([]byte(queryCatalog))
+	do := po.GetDomain()
+
+	if tr, ok := do.GetTranslation("title"); !ok || tr.Get() != "Titel" {
+		t.Error("Expected the 'title' translation")
+	}
+	if _, ok := do.GetTranslation("missing"); ok {
+		t.Error("Unexpected translation for 'missing'")
+	}
+	if tr, ok := do.GetTranslationC("menu.file", "toolbar"); !ok || tr.Get() != "Datei (Leiste)" {
+		t.Error("Expected the 'menu.file' translation in the toolbar context")
+	}
+	if _, ok := do.GetTranslationC("menu.file", "missing"); ok {
+		t.Error("Unexpected translation in a missing context")
+	}
+
+	if found := do.FindPrefix("menu."); len(found) != 3 || found[2].Context != "toolbar" {
+		t.Errorf("Unexpected prefix results %v", found)
+	}
+
+	tests := map[string]int{
+		"menu*":     4,
+		"menu.????": 3,
+		"menu[./]*": 4,
+		"menu[!.]*": 1,
+		"*e*":       5,
+		"menu\\.*":  3,
+		"title":     1,
+		"nothing*":  0,
+	}
+	for pattern, n := range tests {
+		found, err := do.Find(pattern)
+		if err != nil {
+			t.Errorf("Unexpected error for %s: %v", pattern, err)
+		}
+		if len(found) != n {
+			t.Errorf("Expected %d entries for %s but got %v", n, pattern, found)
+		}
+	}
+
+	if _, err := do.Find("menu[a-"); err == nil {
+		t.Error("Expected an error for a malformed pattern")
+	}
+}
+
+func TestDomainContexts(t *testing.T) {
+	po := NewPo()
+	po.Parse([]byte(queryCatalog + `
+msgctxt "dialog"
+msgid "%d file"
+msgid_plural "%d files"
+msgstr[0] "%d Datei"
+msgstr[1] "%d Dateien"
+`))
+	do := po.GetDomain()
+
+	if ctxs := do.Contexts(); len(ctxs) != 2 || ctxs[0] != "dialog" || ctxs[1] != "toolbar" {
+		t.Errorf("Unexpected contexts %v", ctxs)
+	}
+
+	all, err := do.GetAllByContext("dialog")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 2 || all["%d file"] == nil || all["%d file"] != all["%d files"] || all["%d file"].Get() != "%d Datei" {
+		t.Errorf("Unexpected dialog translations %v", all)
+	}
+	if all, _ := do.GetAllByContext(""); len(all) != 4 || all["title"].Get() != "Titel" {
+		t.Errorf("Unexpected translations without context %v", all)
+	}
+	if all, _ := do.GetAllByContext("missing"); len(all) != 0 {
+		t.Errorf("Unexpected translations in a missing context %v", all)
+	}
+
+	toolbar := do.ContextDomain("toolbar")
+	if toolbar.GetLanguage() != "de" {
+		t.Errorf("Expected the headers to be copied, got language %q", toolbar.GetLanguage())
+	}
+	if entries := toolbar.entries(); len(entries) != 1 || entries[0].Context != "toolbar" || entries[0].MsgID != "menu.file" {
+		t.Errorf("Unexpected toolbar entries %v", entries)
+	}
+	if tr := toolbar.GetC("menu.file", "toolbar"); tr != "Datei (Leiste)" {
+		t.Errorf("Unexpected toolbar translation %q", tr)
+	}
+
+	// The subset is a copy
+	toolbar.SetC("menu.file", "toolbar", "Datei!")
+	if tr := do.GetC("menu.file", "toolbar"); tr != "Datei (Leiste)" {
+		t.Errorf("Expected the Domain to be unchanged, got %q", tr)
+	}
+	if entries := do.ContextDomain("").entries(); len(entries) != 4 {
+		t.Errorf("Unexpected entries without context %v", entries)
+	}
+}
@@ -0,0 +1,45 @@
+package gotext
+
+import (
+	"sync"
+
+	"golang.org/x/text/language"
+)
+
+// registry holds the Locale objects shared by the process, keyed by language.Tag
+var registry sync.Map
+
+/*
+Register makes l the process-wide Locale for the language tag, replacing the one registered before, if any,
+so frameworks and libraries can share a single set of loaded catalogs:
+
+	l := gotext.NewLocale("/path/to/i18n/dir", "de_DE")
+	l.AddDomain("default")
+	gotext.Register(language.German, l)
+
+	// Anywhere else
+	fmt.Println(gotext.For(language.German).Get("Translate this"))
+
+A nil l removes the Locale registered for tag. It's safe for concurrent use.
+*/
+func Register(tag language.Tag, l *Locale) {
+	if l == nil {
+		registry.Delete(tag)
+		return
+	}
+	registry.Store(tag, l)
+}
+
+// For returns the Locale registered for the language tag. When there's none, the ones of its parent tags
+// are used, so For(language.MustParse("de-AT")) returns the Locale registered for "de" if there isn't one for "de-AT".
+// It returns nil if no Locale is registered for tag or its parents. It's safe for concurrent use.
+func For(tag language.Tag) *Locale {
+	for t := tag; ; t = t.Parent() {
+		if l, ok := registry.Load(t); ok {
+			return l.(*Locale)
+		}
+		if t.IsRoot() {
+			return nil
+		}
+	}
+}
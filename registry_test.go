@@ -0,0 +1,57 @@
+package gotext
+
+import (
+	"sync"
+	"testing"
+
+	"golang.org/x/text/language"
+)
+
+func TestRegistry(t *testing.T) {
+	de := NewLocale("", "de")
+	ptBR := NewLocale("", "pt_BR")
+	Register(language.Make("de"), de)
+	Register(language.Make("pt-BR"), ptBR)
+	defer Register(language.Make("de"), nil)
+	defer Register(language.Make("pt-BR"), nil)
+
+	if l := For(language.Make("de")); l != de {
+		t.Errorf("unexpected Locale %v", l)
+	}
+	if l := For(language.Make("de-AT")); l != de {
+		t.Errorf("expected the parent Locale, got %v", l)
+	}
+	if l := For(language.Make("pt-BR")); l != ptBR {
+		t.Errorf("unexpected Locale %v", l)
+	}
+	if l := For(language.Make("pt")); l != nil {
+		t.Errorf("expected no Locale for the parent of a registered tag, got %v", l)
+	}
+	if l := For(language.Make("fr")); l != nil {
+		t.Errorf("unexpected Locale %v", l)
+	}
+
+	// Registering again replaces the Locale, and nil removes it
+	de2 := NewLocale("", "de")
+	Register(language.Make("de"), de2)
+	if l := For(language.Make("de")); l != de2 {
+		t.Errorf("expected the Locale to be replaced, got %v", l)
+	}
+	Register(language.Make("de"), nil)
+	if l := For(language.Make("de")); l != nil {
+		t.Errorf("expected the Locale to be removed, got %v", l)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			Register(language.Make("de"), de)
+			if For(language.Make("de")) == nil {
+				t.Error("expected a Locale")
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,161 @@
+package gotext
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// ReloadError is the catalog that made Locale.Reload keep the current catalogs.
+type ReloadError struct {
+	Domain string
+
+	// Placeholder problems of the new catalog, found by ValidateDomain
+	Issues []Issue
+
+	// Error loading the new catalog, or its missing plural forms, as a *PluralCoverageError
+	Err error
+}
+
+func (e *ReloadError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("gotext: reloading domain %q: %v", e.Domain, e.Err)
+	}
+	return fmt.Sprintf("gotext: reloading domain %q: %d placeholder issues, like %s", e.Domain, len(e.Issues), e.Issues[0])
+}
+
+func (e *ReloadError) Unwrap() error {
+	return e.Err
+}
+
+/*
+Reload parses again the catalogs of every domain loaded from the source of the Locale into a staging set,
+and validates them before replacing the current ones, so a bad catalog pushed to production can't blank out
+or break its strings:
+
+	if err := l.Reload(); err != nil {
+		log.Printf("catalogs not updated: %v", err)
+	}
+
+A new catalog is refused when it can't be parsed, when its translations don't use the same placeholders as
+their msgids (see ValidateDomain), or when it's missing plural forms (see Domain.CheckPluralCoverage).
+Then nothing is replaced, and a *ReloadError reports the first domain refused, by name.
+Domains whose catalog isn't in the source anymore, or that weren't loaded from it, like the ones added
+by AddTranslator, are kept as they are. Domains added by AddDomainFiles are loaded again from their files,
+and the catalogs added by AddOverlayDomain are applied again to the new catalogs.
+
+PO catalogs are parsed incrementally: from the second Reload on, only the entries whose text changed are parsed again,
+and the others are shared with the previous catalog, so catalogs with many entries and a few changes reload quickly.
+
+On success, the version returned by CatalogVersion increases, and Rollback restores the previous catalogs.
+Locale objects created by NewLocaleHTTP reload their catalogs with RefreshRemote instead, without validation.
+*/
+func (l *Locale) Reload() error {
+	if l.remote != nil {
+		return l.RefreshRemote()
+	}
+
+	l.RLock()
+	doms := make([]string, 0, len(l.Domains))
+	for dom := range l.Domains {
+		doms = append(doms, dom)
+	}
+	l.RUnlock()
+	sort.Strings(doms)
+
+	src := l.catalogSource()
+	staged := make(map[string]Translator, len(doms))
+	for _, dom := range doms {
+		l.RLock()
+		paths := l.domainFiles[dom]
+		overlays := l.overlays[dom]
+		l.RUnlock()
+
+		var tr Translator
+		var err error
+		if len(paths) > 0 {
+			tr, err = l.loadDomainFiles(dom, paths)
+		} else {
+			lookups := l.catalogLookups(dom)
+			l.RLock()
+			lookups[0].parser.previous = previousPo(l.Domains[dom])
+			l.RUnlock()
+
+			tr, err = loadCatalogs(context.Background(), src, lookups, dom)
+		}
+		if err != nil {
+			return &ReloadError{Domain: dom, Err: err}
+		}
+		if tr == nil {
+			continue
+		}
+		for _, o := range overlays {
+			tr = overlay(tr, o)
+		}
+		if err := validateReload(dom, tr.GetDomain()); err != nil {
+			return err
+		}
+		staged[dom] = tr
+	}
+
+	l.Lock()
+	previous := make(map[string]Translator, len(staged))
+	for dom, tr := range staged {
+		previous[dom] = l.Domains[dom]
+		l.Domains[dom] = tr
+		l.stopSchedule(dom)
+	}
+	l.previousDomains = previous
+	l.catalogVersion++
+	lang := l.lang
+	l.Unlock()
+
+	for dom, tr := range staged {
+		tr.GetDomain().setMetricsLabels(lang, dom)
+	}
+	logInfo("gotext: catalogs reloaded", "lang", lang, "domains", len(staged))
+	return nil
+}
+
+// validateReload returns a *ReloadError if the catalog of the domain dom has placeholder issues or is missing plural forms.
+func validateReload(dom string, do *Domain) error {
+	if issues := ValidateDomain(do); len(issues) > 0 {
+		return &ReloadError{Domain: dom, Issues: issues}
+	}
+
+	err := do.CheckPluralCoverage()
+	if cerr, ok := err.(*PluralCoverageError); ok && len(cerr.Issues) == 0 {
+		// Disagreements with CLDR are handled by the plural policy
+		err = nil
+	}
+	if err != nil {
+		return &ReloadError{Domain: dom, Err: err}
+	}
+	return nil
+}
+
+// CatalogVersion returns the number of successful calls to Reload, minus the ones undone by Rollback.
+func (l *Locale) CatalogVersion() int {
+	l.RLock()
+	defer l.RUnlock()
+
+	return l.catalogVersion
+}
+
+// Rollback restores the catalogs replaced by the last successful Reload, and returns true.
+// Domains added since then are kept.
+// It returns false when there's nothing to restore: Reload wasn't called, or its catalogs were already rolled back.
+func (l *Locale) Rollback() bool {
+	l.Lock()
+	defer l.Unlock()
+
+	if l.previousDomains == nil {
+		return false
+	}
+	for dom, tr := range l.previousDomains {
+		l.Domains[dom] = tr
+	}
+	l.previousDomains = nil
+	l.catalogVersion--
+	return true
+}
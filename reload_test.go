@@ -0,0 +1,101 @@
+package gotext
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestLocaleReload(t *testing.T) {
+	src := &MemorySource{Files: map[string][]byte{
+		"es/default.po": []byte("msgid \"Hello %s\"\nmsgstr \"Hola %s\"\n"),
+		"es/other.po":   []byte("msgid \"Bye\"\nmsgstr \"Adiós\"\n"),
+	}}
+	l := NewLocaleWithSource(src, "es")
+	l.AddDomain("default")
+	l.AddDomain("other")
+	if err := l.AddDomainBytes("bytes", []byte("msgid \"Yes\"\nmsgstr \"Sí\"\n"), FormatPO); err != nil {
+		t.Fatal(err)
+	}
+
+	hello, bye, yes := "Hello %s", "Bye", "Yes"
+	src.Files["es/default.po"] = []byte("msgid \"Hello %s\"\nmsgstr \"Buenas %s\"\n")
+	if err := l.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if tr := l.Get(hello, "Ana"); tr != "Buenas Ana" {
+		t.Errorf("Expected the new catalog, got %q", tr)
+	}
+	if tr := l.GetD("bytes", yes); tr != "Sí" {
+		t.Errorf("Expected catalogs not in the source to be kept, got %q", tr)
+	}
+	if v := l.CatalogVersion(); v != 1 {
+		t.Errorf("Unexpected version %d", v)
+	}
+
+	// Broken placeholders keep every current catalog
+	src.Files["es/default.po"] = []byte("msgid \"Hello %s\"\nmsgstr \"Hola %d\"\n")
+	src.Files["es/other.po"] = []byte("msgid \"Bye\"\nmsgstr \"Chao\"\n")
+	err := l.Reload()
+	var rerr *ReloadError
+	if !errors.As(err, &rerr) || rerr.Domain != "default" || len(rerr.Issues) != 1 {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if tr := l.GetD("other", bye); tr != "Adiós" {
+		t.Errorf("Expected the current catalogs to be kept, got %q", tr)
+	}
+
+	// Missing plural forms
+	src.Files["es/default.po"] = []byte(`msgid ""
+msgstr "Plural-Forms: nplurals=2; plural=(n != 1);\n"
+
+msgid "%d file"
+msgid_plural "%d files"
+msgstr[0] "%d archivo"
+`)
+	if err := l.Reload(); !errors.As(err, &rerr) || rerr.Domain != "default" || rerr.Err == nil {
+		t.Errorf("Unexpected error %v", err)
+	}
+	if v := l.CatalogVersion(); v != 1 {
+		t.Errorf("Unexpected version %d", v)
+	}
+
+	// Rollback restores the catalogs replaced by the last Reload
+	if !l.Rollback() {
+		t.Fatal("Expected a rollback")
+	}
+	if tr := l.Get(hello, "Ana"); tr != "Hola Ana" {
+		t.Errorf("Expected the previous catalog, got %q", tr)
+	}
+	if l.Rollback() || l.CatalogVersion() != 0 {
+		t.Error("Expected a single rollback")
+	}
+}
+
+func TestLocaleReloadOverlay(t *testing.T) {
+	src := &MemorySource{Files: map[string][]byte{
+		"de/default.po": []byte("msgid \"Hello\"\nmsgstr \"Hallo\"\n\nmsgid \"Bye\"\nmsgstr \"Tschüss\"\n"),
+	}}
+	l := NewLocaleWithSource(src, "de")
+	l.AddDomain("default")
+	l.AddOverlayDomain("default", NewDomainBuilder().Add("Hello", "Servus").BuildPo())
+
+	src.Files["de/default.po"] = []byte("msgid \"Hello\"\nmsgstr \"Hallo\"\n\nmsgid \"Bye\"\nmsgstr \"Auf Wiedersehen\"\n")
+	if err := l.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if tr := l.Get("Hello"); tr != "Servus" {
+		t.Errorf("Expected the overlay to be kept, got %q", tr)
+	}
+	if tr := l.Get("Bye"); tr != "Auf Wiedersehen" {
+		t.Errorf("Expected the new catalog, got %q", tr)
+	}
+
+	// Replacing the domain drops its overlays
+	l.AddDomain("default")
+	if err := l.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if tr := l.Get("Hello"); tr != "Hallo" {
+		t.Errorf("Expected no overlay, got %q", tr)
+	}
+}
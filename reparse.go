@@ -0,0 +1,230 @@
+package gotext
+
+import (
+	"bytes"
+	"hash/fnv"
+	"sort"
+	"strings"
+)
+
+// poBlock is a run of non-blank lines of a PO catalog, usually one entry with its comments.
+type poBlock struct {
+	// Line of the first line, starting at 1
+	start int
+	text  []byte
+	key   blockKey
+}
+
+// blockKey identifies the text of a poBlock.
+type blockKey struct {
+	hash uint64
+	size int
+}
+
+// indexedBlock holds the entries and syntax errors parsed from a poBlock, for Locale.Reload to reuse them.
+type indexedBlock struct {
+	start   int
+	entries []Entry
+	errs    ParseErrors
+}
+
+// splitPoBlocks splits the PO catalog buf into blocks separated by blank lines.
+func splitPoBlocks(buf []byte) []poBlock {
+	var blocks []poBlock
+	start := -1
+	lines := bytes.Split(buf, []byte("\n"))
+	end := func(n int) {
+		if start == -1 {
+			return
+		}
+		text := bytes.Join(lines[start:n], []byte("\n"))
+		h := fnv.New64a()
+		h.Write(text)
+		blocks = append(blocks, poBlock{start: start + 1, text: text, key: blockKey{hash: h.Sum64(), size: len(text)}})
+		start = -1
+	}
+	for n, l := range lines {
+		if len(bytes.TrimSpace(l)) == 0 {
+			end(n)
+		} else if start == -1 {
+			start = n
+		}
+	}
+	end(len(lines))
+	return blocks
+}
+
+// indexBlocks returns the entries of the Domain and the syntax errors errs by the block they were parsed from,
+// using their line. The Domain must be locked.
+func (do *Domain) indexBlocks(blocks []poBlock, errs ParseErrors) map[blockKey]*indexedBlock {
+	index := make(map[blockKey]*indexedBlock, len(blocks))
+	block := func(line int) *indexedBlock {
+		i := sort.Search(len(blocks), func(i int) bool { return blocks[i].start > line }) - 1
+		if i < 0 {
+			return nil
+		}
+		b, ok := index[blocks[i].key]
+		if !ok {
+			b = &indexedBlock{start: blocks[i].start}
+			index[blocks[i].key] = b
+		}
+		return b
+	}
+	add := func(ctx, id string, tr *Translation) {
+		if id == "" || tr.Line == 0 {
+			return
+		}
+		if b := block(tr.Line); b != nil {
+			b.entries = append(b.entries, Entry{Context: ctx, MsgID: id, Translation: tr})
+		}
+	}
+	for id, tr := range do.translations {
+		add("", id, tr)
+	}
+	for ctx, trs := range do.contexts {
+		for id, tr := range trs {
+			add(ctx, id, tr)
+		}
+	}
+	for _, err := range errs {
+		if b := block(err.Line); b != nil {
+			b.errs = append(b.errs, err)
+		}
+	}
+	for _, b := range index {
+		sort.Slice(b.entries, func(i, j int) bool { return b.entries[i].Translation.Line < b.entries[j].Translation.Line })
+	}
+	return index
+}
+
+// parseIndexed works like parse, and indexes the entries by block so a later reparse can reuse them.
+func (po *Po) parseIndexed(buf []byte, file string) ParseErrors {
+	errs := po.parse(buf, file)
+
+	po.domain.trMutex.Lock()
+	po.domain.blocks = po.domain.indexBlocks(splitPoBlocks(buf), errs)
+	po.domain.trMutex.Unlock()
+	return errs
+}
+
+/*
+reparse parses the catalog buf, read from the file named file, like parse does, reusing the entries
+of prev whose block of text didn't change, so reloading a large catalog with a few changes is fast.
+The syntax errors of unchanged blocks are reused too, and duplicate msgids are only reported within a block.
+po must be new, and prev isn't changed. Unchanged entries are copied, so changing either catalog doesn't change the other.
+
+The first block, usually the header, is always parsed, and so are the blocks with obsolete entries.
+When prev wasn't indexed, or most blocks changed, the whole catalog is parsed.
+*/
+func (po *Po) reparse(prev *Po, buf []byte, file string) ParseErrors {
+	prev.domain.trMutex.RLock()
+	index := prev.domain.blocks
+	prev.domain.trMutex.RUnlock()
+
+	blocks := splitPoBlocks(buf)
+	changed := 0
+	for _, b := range blocks {
+		if _, ok := index[b.key]; !ok {
+			changed++
+		}
+	}
+	if index == nil || len(blocks) == 0 || changed > len(blocks)/2 {
+		return po.parseIndexed(buf, file)
+	}
+
+	errs := shiftErrors(po.parse(blocks[0].text, file), blocks[0].start-1)
+
+	do := po.domain
+	do.trMutex.Lock()
+	defer do.trMutex.Unlock()
+
+	newIndex := make(map[blockKey]*indexedBlock, len(blocks))
+	for _, b := range blocks[1:] {
+		var entries []Entry
+		var blockErrs ParseErrors
+		if old, ok := index[b.key]; ok && !bytes.Contains(b.text, []byte("#~")) {
+			entries = make([]Entry, len(old.entries))
+			for i, e := range old.entries {
+				// Copied, as Set and SetN change the entries of a catalog in place
+				tr := copyTranslation(e.Translation)
+				tr.Line = tr.Line - old.start + b.start
+				tr.File = file
+				entries[i] = Entry{Context: e.Context, MsgID: e.MsgID, Translation: tr}
+			}
+			blockErrs = shiftErrors(old.errs, b.start-old.start)
+		} else {
+			entries, blockErrs = parseBlock(b, file, do)
+		}
+
+		for _, e := range entries {
+			do.insertParsed(e.Context, e.Translation)
+		}
+		newIndex[b.key] = &indexedBlock{start: b.start, entries: entries, errs: blockErrs}
+		errs = append(errs, blockErrs...)
+	}
+	do.blocks = newIndex
+	return errs
+}
+
+// shiftErrors returns copies of errs moved by delta lines.
+func shiftErrors(errs ParseErrors, delta int) ParseErrors {
+	if len(errs) == 0 {
+		return nil
+	}
+	shifted := make(ParseErrors, len(errs))
+	for i, err := range errs {
+		c := *err
+		c.Line += delta
+		shifted[i] = &c
+	}
+	return shifted
+}
+
+// parseBlock parses the PO block b of the file named file, returning its entries and syntax errors
+// with their position in the file. Its obsolete entries are added to do.
+func parseBlock(b poBlock, file string, do *Domain) ([]Entry, ParseErrors) {
+	// An empty header first, so the comments of the block aren't taken as header comments
+	const header = "msgid \"\"\nmsgstr \"\"\n\n"
+	tmp := NewPo()
+	errs := tmp.parse(append([]byte(header), b.text...), file)
+
+	delta := b.start - 1 - strings.Count(header, "\n")
+	entries := tmp.domain.entries()
+	for _, e := range entries {
+		e.Translation.Line += delta
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Translation.Line < entries[j].Translation.Line })
+
+	do.Obsolete = append(do.Obsolete, tmp.domain.Obsolete...)
+	return entries, shiftErrors(errs, delta)
+}
+
+// insertParsed stores tr, parsed in the context ctx, applying the duplicate policy. The Domain must be locked.
+func (do *Domain) insertParsed(ctx string, tr *Translation) {
+	translations := do.translations
+	if ctx != "" {
+		if _, ok := do.contexts[ctx]; !ok {
+			do.contexts[ctx] = make(map[string]*Translation)
+		}
+		translations = do.contexts[ctx]
+	}
+
+	if old, ok := translations[tr.ID]; ok && !do.keepDuplicate(ctx, old, tr) {
+		return
+	}
+	translations[tr.ID] = tr
+	if tr.PluralID != "" {
+		do.pluralTranslations[tr.PluralID] = tr
+	}
+}
+
+// previousPo returns the Po catalog answering the lookups of tr, for reparse, or nil if there is none.
+func previousPo(tr Translator) *Po {
+	switch t := tr.(type) {
+	case *Po:
+		return t
+	case *fallbackTranslator:
+		return previousPo(t.trs[0])
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+package gotext
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// reparseCatalog returns a PO catalog with n entries, where edit can change the entry i.
+func reparseCatalog(n int, edit func(i int) string) []byte {
+	var b strings.Builder
+	b.WriteString("# Header comment\nmsgid \"\"\nmsgstr \"Language: es\\n\"\n\"Plural-Forms: nplurals=2; plural=(n != 1);\\n\"\n")
+	for i := 0; i < n; i++ {
+		entry := fmt.Sprintf("#: main.go:%d\nmsgid \"Message %d\"\nmsgstr \"Mensaje %d\"\n", i, i, i)
+		if edit != nil {
+			if e, ok := edit(i), true; ok && e != "-" {
+				if e != "" {
+					entry = e
+				}
+			} else {
+				continue
+			}
+		}
+		b.WriteString("\n" + entry)
+	}
+	b.WriteString("\nmsgctxt \"menu\"\nmsgid \"Open\"\nmsgid_plural \"Open all\"\nmsgstr[0] \"Abrir\"\nmsgstr[1] \"Abrir todos\"\n")
+	b.WriteString("\n#~ msgid \"Old\"\n#~ msgstr \"Viejo\"\n")
+	return []byte(b.String())
+}
+
+func TestPoReparse(t *testing.T) {
+	first := reparseCatalog(20, nil)
+	second := reparseCatalog(20, func(i int) string {
+		switch i {
+		case 3:
+			return "#, fuzzy\nmsgid \"Message 3\"\nmsgstr \"Mensaje tres\"\n"
+		case 7:
+			return "-"
+		case 12:
+			return "msgid \"Message 12\"\nmsgstr \"Mensaje 12\"\n\nmsgid \"New\"\nmsgstr \"Nuevo\"\n"
+		}
+		return ""
+	})
+
+	prev := NewPo()
+	prev.parseIndexed(first, "es.po")
+	msg1 := prev.GetDomain().translations["Message 1"]
+	msg15 := prev.GetDomain().translations["Message 15"]
+
+	po := NewPo()
+	po.reparse(prev, second, "es.po")
+	want := NewPo()
+	want.parse(second, "es.po")
+
+	got, exp := po.GetDomain().entries(), want.GetDomain().entries()
+	if len(got) != len(exp) {
+		t.Fatalf("Expected %d entries but got %d", len(exp), len(got))
+	}
+	for i := range got {
+		g, e := got[i], exp[i]
+		if g.Context != e.Context || g.MsgID != e.MsgID || !sameTranslation(g.Translation, e.Translation) ||
+			g.Translation.Fuzzy != e.Translation.Fuzzy || g.Translation.Line != e.Translation.Line ||
+			g.Translation.File != e.Translation.File || !reflect.DeepEqual(g.Translation.Refs, e.Translation.Refs) {
+			t.Errorf("Expected %+v but got %+v", e.Translation, g.Translation)
+		}
+	}
+	if !reflect.DeepEqual(po.GetDomain().Obsolete, want.GetDomain().Obsolete) || len(po.GetDomain().Obsolete) != 1 {
+		t.Errorf("Unexpected obsolete entries %v", po.GetDomain().Obsolete)
+	}
+	if po.GetDomain().GetNPlurals() != 2 || po.Language != "es" {
+		t.Errorf("Unexpected headers %v", po.Headers)
+	}
+	if tr := po.GetNC("Open", "Open all", 2, "menu"); tr != "Abrir todos" {
+		t.Errorf("Expected 'Abrir todos' but got '%s'", tr)
+	}
+
+	// Unchanged entries are copied, so changing one catalog doesn't change the other
+	if tr := po.GetDomain().translations["Message 1"]; tr == msg1 || !sameTranslation(tr, msg1) {
+		t.Error("Expected an unchanged entry to be copied")
+	}
+	if tr := po.GetDomain().translations["Message 15"]; tr == msg15 || msg15.Line == tr.Line {
+		t.Error("Expected a moved entry to be copied")
+	}
+	po.Set("Message 1", "Cambiado")
+	if tr := prev.Get("Message 1"); tr != "Mensaje 1" {
+		t.Errorf("Expected the previous catalog to be unchanged, got '%s'", tr)
+	}
+	if tr := prev.Get("Message 3"); tr != "Mensaje 3" {
+		t.Errorf("Expected the previous catalog to be unchanged, got '%s'", tr)
+	}
+
+	// Most blocks changed
+	po = NewPo()
+	po.reparse(prev, reparseCatalog(20, func(i int) string {
+		return fmt.Sprintf("msgid \"Message %d\"\nmsgstr \"Otro %d\"\n", i, i)
+	}), "es.po")
+	if tr := po.Get("Message 4"); tr != "Otro 4" {
+		t.Errorf("Expected 'Otro 4' but got '%s'", tr)
+	}
+	if po.GetDomain().blocks == nil {
+		t.Error("Expected the catalog to be indexed")
+	}
+}
+
+func TestPoReparseErrors(t *testing.T) {
+	broken := func(i int) string {
+		switch i {
+		case 2:
+			return "msgid \"Message 2\"\nmsgstr \"Mensaje 2\n"
+		case 9:
+			return "msgid \"Message 9\"\nmsgstr[0] \"Mensaje 9\"\n"
+		}
+		return ""
+	}
+	first := reparseCatalog(20, broken)
+	second := reparseCatalog(20, func(i int) string {
+		switch i {
+		case 0:
+			return "msgid \"Message 0\"\nmsgstr \"Mensaje 0\"\n\nmsgid \"New\"\nmsgstr \"Nuevo\"\n"
+		case 12:
+			return "msgid \"Message 12\"\nmsgstr \"Mensaje 12\"\nmsgid_plural \"Messages 12\"\n"
+		}
+		return broken(i)
+	})
+
+	prev := NewPo()
+	if errs := prev.parseIndexed(first, "es.po"); !reflect.DeepEqual(errs, validatePo(first)) {
+		t.Errorf("Unexpected errors %v", errs)
+	}
+
+	// The errors of unchanged blocks are kept, at their new lines
+	po := NewPo()
+	errs := po.reparse(prev, second, "es.po")
+	if want := validatePo(second); len(want) != 3 || !reflect.DeepEqual(errs, want) {
+		t.Errorf("Expected %v but got %v", want, errs)
+	}
+}
+
+func TestLocaleReloadIncremental(t *testing.T) {
+	src := &MemorySource{Files: map[string][]byte{"es/default.po": reparseCatalog(20, nil)}}
+	l := NewLocaleWithSource(src, "es")
+	l.AddDomain("default")
+
+	for i, text := range []string{"Mensaje uno", "Mensaje 1"} {
+		text := text
+		src.Files["es/default.po"] = reparseCatalog(20, func(n int) string {
+			if n == 1 {
+				return fmt.Sprintf("msgid \"Message 1\"\nmsgstr \"%s\"\n", text)
+			}
+			return ""
+		})
+
+		prev := l.Domains["default"].GetDomain()
+		if err := l.Reload(); err != nil {
+			t.Fatal(err)
+		}
+		if tr := l.Get("Message 1"); tr != text {
+			t.Errorf("Expected '%s' but got '%s'", text, tr)
+		}
+
+		if l.Domains["default"].GetDomain().translations["Message 5"] == prev.translations["Message 5"] {
+			t.Errorf("Reload %d: expected the entries not to be shared with the previous catalog", i)
+		}
+	}
+}
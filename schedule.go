@@ -0,0 +1,88 @@
+package gotext
+
+import (
+	"time"
+)
+
+// scheduledTranslator is a catalog waiting for its activation time.
+type scheduledTranslator struct {
+	at    time.Time
+	timer *time.Timer
+}
+
+/*
+ScheduleTranslator loads tr alongside the current catalog of the domain dom and makes it replace that catalog
+at the given time, without any further action. It's meant for changes that must go live at a precise moment,
+like an embargoed product name. The switch is atomic: lookups get either the old or the new catalog.
+
+A pending schedule for the same domain is replaced, and it's cancelled when AddDomain or Reload replace the catalog
+of the domain before the activation. When at isn't in the future, tr is activated right away.
+
+	next := gotext.NewPo()
+	next.ParseFile("/path/to/i18n/dir/de/LC_MESSAGES/default.launch.po")
+	l.ScheduleTranslator("default", next, launchTime)
+*/
+func (l *Locale) ScheduleTranslator(dom string, tr Translator, at time.Time) {
+	l.CancelSchedule(dom)
+
+	delay := time.Until(at)
+	if delay <= 0 {
+		l.AddTranslator(dom, tr)
+		return
+	}
+
+	s := &scheduledTranslator{at: at}
+
+	l.Lock()
+	if l.schedules == nil {
+		l.schedules = make(map[string]*scheduledTranslator)
+	}
+	l.schedules[dom] = s
+	s.timer = time.AfterFunc(delay, func() {
+		l.Lock()
+		// Skip if it has been cancelled or replaced in the meantime
+		if l.schedules[dom] != s {
+			l.Unlock()
+			return
+		}
+		delete(l.schedules, dom)
+		l.Unlock()
+
+		l.AddTranslator(dom, tr)
+		logInfo("gotext: scheduled catalog activated", "lang", l.lang, "domain", dom)
+	})
+	l.Unlock()
+}
+
+// CancelSchedule cancels the pending activation of a catalog scheduled for the domain dom.
+// It returns false if there wasn't any.
+func (l *Locale) CancelSchedule(dom string) bool {
+	l.Lock()
+	defer l.Unlock()
+
+	return l.stopSchedule(dom)
+}
+
+// stopSchedule cancels the pending activation of a catalog scheduled for the domain dom, if any.
+// The Locale must be locked.
+func (l *Locale) stopSchedule(dom string) bool {
+	s, ok := l.schedules[dom]
+	if !ok {
+		return false
+	}
+	s.timer.Stop()
+	delete(l.schedules, dom)
+	return true
+}
+
+// ScheduledAt returns the activation time of the catalog scheduled for the domain dom, if any.
+func (l *Locale) ScheduledAt(dom string) (time.Time, bool) {
+	l.RLock()
+	defer l.RUnlock()
+
+	s, ok := l.schedules[dom]
+	if !ok {
+		return time.Time{}, false
+	}
+	return s.at, true
+}
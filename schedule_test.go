@@ -0,0 +1,89 @@
+package gotext
+
+import (
+	"testing"
+	"time"
+)
+
+func TestScheduleTranslator(t *testing.T) {
+	l := NewLocale("fixtures/", "de_DE")
+	l.AddDomain("default")
+
+	next := NewPo()
+	next.Parse([]byte("msgid \"My text\"\nmsgstr \"Launched text\"\n"))
+
+	at := time.Now().Add(100 * time.Millisecond)
+	l.ScheduleTranslator("default", next, at)
+
+	if scheduled, ok := l.ScheduledAt("default"); !ok || !scheduled.Equal(at) {
+		t.Errorf("Expected a schedule at %v but got %v", at, scheduled)
+	}
+	if tr := l.Get("My text"); tr != "Translated text" {
+		t.Errorf("Expected 'Translated text' before activation but got '%s'", tr)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for l.Get("My text") != "Launched text" && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if tr := l.Get("My text"); tr != "Launched text" {
+		t.Errorf("Expected 'Launched text' after activation but got '%s'", tr)
+	}
+	if _, ok := l.ScheduledAt("default"); ok {
+		t.Error("Expected no pending schedule after activation")
+	}
+
+	do := next.GetDomain()
+	do.trMutex.RLock()
+	lang, dom := do.metricsLang, do.metricsDomain
+	do.trMutex.RUnlock()
+	if lang != "de_DE" || dom != "default" {
+		t.Errorf("Expected the metrics labels of the activated catalog but got %q, %q", lang, dom)
+	}
+}
+
+func TestCancelSchedule(t *testing.T) {
+	l := NewLocale("fixtures/", "de_DE")
+	l.AddDomain("default")
+
+	next := NewPo()
+	next.Parse([]byte("msgid \"My text\"\nmsgstr \"Launched text\"\n"))
+
+	l.ScheduleTranslator("default", next, time.Now().Add(time.Hour))
+	if !l.CancelSchedule("default") {
+		t.Error("Expected a pending schedule to cancel")
+	}
+	if l.CancelSchedule("default") {
+		t.Error("Expected nothing left to cancel")
+	}
+
+	// Past times activate right away
+	l.ScheduleTranslator("extras", next, time.Now().Add(-time.Minute))
+	if tr := l.GetD("extras", "My text"); tr != "Launched text" {
+		t.Errorf("Expected 'Launched text' but got '%s'", tr)
+	}
+}
+
+func TestScheduleReplaced(t *testing.T) {
+	next := NewPo()
+	next.Parse([]byte("msgid \"My text\"\nmsgstr \"Launched text\"\n"))
+
+	src := &MemorySource{Files: map[string][]byte{
+		"de/default.po": []byte("msgid \"My text\"\nmsgstr \"Translated text\"\n"),
+	}}
+	l := NewLocaleWithSource(src, "de")
+	l.AddDomain("default")
+	l.ScheduleTranslator("default", next, time.Now().Add(time.Hour))
+	l.AddDomain("default")
+	if _, ok := l.ScheduledAt("default"); ok {
+		t.Error("Expected AddDomain to cancel the pending schedule")
+	}
+
+	l.ScheduleTranslator("default", next, time.Now().Add(time.Hour))
+	if err := l.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := l.ScheduledAt("default"); ok {
+		t.Error("Expected Reload to cancel the pending schedule")
+	}
+}
@@ -0,0 +1,76 @@
+package gotext
+
+import (
+	"strings"
+)
+
+// DefaultScrubHeaders are the headers kept by MarshalTextScrubbed when ScrubOptions.KeepHeaders is nil.
+// They're needed to read the catalog but don't tell anything about who worked on it.
+var DefaultScrubHeaders = []string{
+	"Project-Id-Version",
+	"Language",
+	"MIME-Version",
+	"Content-Type",
+	"Content-Transfer-Encoding",
+	"Plural-Forms",
+}
+
+// ScrubOptions configures what MarshalTextScrubbed keeps from a catalog.
+type ScrubOptions struct {
+	// KeepHeaders lists the headers to retain, case insensitive. DefaultScrubHeaders is used when nil.
+	KeepHeaders []string
+
+	// KeepReferences retains the source references (#: comments) of the entries.
+	KeepReferences bool
+}
+
+/*
+MarshalTextScrubbed works like MarshalText, but strips the information that shouldn't leave the team
+before sharing a catalog with external vendors:
+
+  - Comments before the header, which usually hold translator names and emails.
+  - Every header not listed in opts.KeepHeaders (Last-Translator, Language-Team, Report-Msgid-Bugs-To...).
+  - Source references, which expose internal file paths, unless opts.KeepReferences is set.
+
+The Domain itself isn't modified.
+*/
+func (do *Domain) MarshalTextScrubbed(opts ScrubOptions) ([]byte, error) {
+	keep := opts.KeepHeaders
+	if keep == nil {
+		keep = DefaultScrubHeaders
+	}
+	allowed := make(map[string]bool, len(keep))
+	for _, k := range keep {
+		allowed[strings.ToLower(k)] = true
+	}
+
+	scrubbed := NewDomain()
+
+	do.trMutex.RLock()
+	for k, v := range do.Headers {
+		if allowed[strings.ToLower(k)] {
+			scrubbed.Headers[k] = append([]string(nil), v...)
+		}
+	}
+	for id, tr := range do.translations {
+		scrubbed.translations[id] = scrubTranslation(tr, opts)
+	}
+	for ctx, translations := range do.contexts {
+		scrubbed.contexts[ctx] = make(map[string]*Translation, len(translations))
+		for id, tr := range translations {
+			scrubbed.contexts[ctx][id] = scrubTranslation(tr, opts)
+		}
+	}
+	do.trMutex.RUnlock()
+
+	return scrubbed.MarshalText()
+}
+
+// scrubTranslation returns a copy of tr without the information opts doesn't keep.
+func scrubTranslation(tr *Translation, opts ScrubOptions) *Translation {
+	cp := copyTranslation(tr)
+	if !opts.KeepReferences {
+		cp.Refs = nil
+	}
+	return cp
+}
@@ -0,0 +1,67 @@
+package gotext
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMarshalTextScrubbed(t *testing.T) {
+	po := NewPo()
+	po.Parse([]byte(`# Translation of the shop.
+# John Doe <john@example.com>, 2020.
+msgid ""
+msgstr ""
+"Project-Id-Version: shop 1.0\n"
+"Last-Translator: John Doe <john@example.com>\n"
+"Language-Team: Internal QA <qa@example.com>\n"
+"Language: de\n"
+"Plural-Forms: nplurals=2; plural=(n != 1);\n"
+"X-Internal-Ticket: SHOP-42\n"
+
+#: internal/billing/invoice.go:12
+msgid "Invoice"
+msgstr "Rechnung"
+
+#: internal/cart/cart.go:7
+msgctxt "cart"
+msgid "Item"
+msgstr "Artikel"
+`))
+
+	buff, err := po.MarshalTextScrubbed(ScrubOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := string(buff)
+	for _, leaked := range []string{"john@example.com", "qa@example.com", "SHOP-42", "internal/", "Translation of the shop"} {
+		if strings.Contains(out, leaked) {
+			t.Errorf("Expected '%s' to be scrubbed from:\n%s", leaked, out)
+		}
+	}
+	for _, kept := range []string{"Project-Id-Version: shop 1.0", "Language: de", "Plural-Forms:", `msgstr "Rechnung"`, `msgctxt "cart"`} {
+		if !strings.Contains(out, kept) {
+			t.Errorf("Expected '%s' to be kept in:\n%s", kept, out)
+		}
+	}
+
+	// The scrubbed catalog is still valid
+	scrubbed := NewPo()
+	scrubbed.Parse(buff)
+	if tr := scrubbed.GetC("Item", "cart"); tr != "Artikel" {
+		t.Errorf("Expected 'Artikel' but got '%s'", tr)
+	}
+
+	buff, _ = po.MarshalTextScrubbed(ScrubOptions{KeepHeaders: []string{"language", "x-internal-ticket"}, KeepReferences: true})
+	out = string(buff)
+	if !strings.Contains(out, "X-Internal-Ticket: SHOP-42") || !strings.Contains(out, "#: internal/billing/invoice.go:12") {
+		t.Errorf("Expected allowed header and references to be kept in:\n%s", out)
+	}
+	if strings.Contains(out, "Project-Id-Version") {
+		t.Errorf("Expected Project-Id-Version to be scrubbed from:\n%s", out)
+	}
+
+	// The original catalog is untouched
+	if po.GetDomain().Headers.Get("Last-Translator") == "" {
+		t.Error("Expected the original headers to be kept")
+	}
+}
@@ -0,0 +1,49 @@
+package gotext
+
+// SelectOther is the selector GetSelect falls back to when the translation has no variant for the requested one.
+const SelectOther = "other"
+
+/*
+GetSelect translates str choosing among its variants with selector, like ICU select messages do,
+which gives gettext catalogs a practical mechanism for grammatical gender. Variants are entries of str
+whose msgctxt is the selector:
+
+	msgctxt "female"
+	msgid "%s liked your post"
+	msgstr "A %s le gustó tu publicación (ella)"
+
+	msgctxt "male"
+	msgid "%s liked your post"
+	msgstr "A %s le gustó tu publicación (él)"
+
+	msgid "%s liked your post"
+	msgstr "A %s le gustó tu publicación"
+
+	l.GetSelect("%s liked your post", user.Gender, user.Name)
+
+When there's no translated variant for selector, the "other" variant (SelectOther) is used, and then the entry
+of str without context, so catalogs only need variants for the languages that make a difference.
+*/
+func (l *Locale) GetSelect(str, selector string, vars ...interface{}) string {
+	return l.GetSelectD(callerDomain(0, l.GetDomain()), str, selector, vars...)
+}
+
+// GetSelectD translates str in the domain dom choosing among its variants with selector. See GetSelect.
+func (l *Locale) GetSelectD(dom, str, selector string, vars ...interface{}) string {
+	l.RLock()
+	defer l.RUnlock()
+	vars = l.localVars(vars)
+
+	tr := l.Domains[dom]
+	if tr == nil {
+		metrics().Lookup(l.lang, dom, LookupMiss)
+		return l.output(str, Printf(str, vars...))
+	}
+
+	for _, ctx := range []string{selector, SelectOther} {
+		if ctx != "" && translated(tr, str, ctx, true) {
+			return l.output(str, tr.GetC(str, ctx, vars...))
+		}
+	}
+	return l.output(str, tr.Get(str, vars...))
+}
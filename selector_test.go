@@ -0,0 +1,61 @@
+package gotext
+
+import (
+	"testing"
+)
+
+const selectPo = `
+msgctxt "female"
+msgid "%s liked your post"
+msgstr "A %s le gustó tu publicación (ella)"
+
+msgctxt "male"
+msgid "%s liked your post"
+msgstr "A %s le gustó tu publicación (él)"
+
+msgctxt "male"
+msgid "Welcome"
+msgstr ""
+
+msgctxt "other"
+msgid "Welcome"
+msgstr "Te damos la bienvenida"
+
+msgid "Welcome"
+msgstr "Bienvenido"
+
+msgid "%s liked your post"
+msgstr "A %s le gustó tu publicación"
+`
+
+func TestLocaleGetSelect(t *testing.T) {
+	l := NewLocale("", "es")
+	if err := l.AddDomainBytes("default", []byte(selectPo), FormatPO); err != nil {
+		t.Fatal(err)
+	}
+
+	liked, welcome, missing := "%s liked your post", "Welcome", "Missing %s"
+	for _, tc := range []struct {
+		str, selector string
+		vars          []interface{}
+		want          string
+	}{
+		{liked, "female", []interface{}{"Ana"}, "A Ana le gustó tu publicación (ella)"},
+		{liked, "male", []interface{}{"Juan"}, "A Juan le gustó tu publicación (él)"},
+		// Without variant, nor "other" one
+		{liked, "unknown", []interface{}{"Alex"}, "A Alex le gustó tu publicación"},
+		{liked, "", []interface{}{"Alex"}, "A Alex le gustó tu publicación"},
+		// Untranslated variants fall back to "other"
+		{welcome, "male", nil, "Te damos la bienvenida"},
+		{welcome, "female", nil, "Te damos la bienvenida"},
+		{missing, "female", []interface{}{"x"}, "Missing x"},
+	} {
+		if got := l.GetSelect(tc.str, tc.selector, tc.vars...); got != tc.want {
+			t.Errorf("%q (%s): got %q, want %q", tc.str, tc.selector, got, tc.want)
+		}
+	}
+
+	if got := l.GetSelectD("unknown", liked, "female", "Ana"); got != "Ana liked your post" {
+		t.Errorf("unexpected translation %q", got)
+	}
+}
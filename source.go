@@ -0,0 +1,212 @@
+package gotext
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// CatalogSource is where a Locale reads its catalog files from.
+// Names are slash-separated paths relative to the source root, like "de_DE/LC_MESSAGES/default.po".
+//
+// The filesystem is used by default (see DirSource), but a source can be backed by an archive,
+// an object store, a database or anything else able to serve files by name.
+type CatalogSource interface {
+	// Open returns the content of the named catalog.
+	// It must return an error for which os.IsNotExist (or errors.Is(err, os.ErrNotExist)) is true when it doesn't exist.
+	Open(name string) (io.ReadCloser, error)
+
+	// List returns the names of all catalogs under the dir prefix ("" lists everything), sorted.
+	List(dir string) ([]string, error)
+
+	// ModTime returns the last modification time of the named catalog.
+	ModTime(name string) (time.Time, error)
+}
+
+// isNotExist reports whether err tells that a catalog doesn't exist.
+func isNotExist(err error) bool {
+	return os.IsNotExist(err) || errors.Is(err, os.ErrNotExist)
+}
+
+// readCatalog reads the whole content of the named catalog from src.
+func readCatalog(src CatalogSource, name string) ([]byte, error) {
+	rc, err := src.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	data, err := ioutil.ReadAll(rc)
+	if err != nil {
+		return nil, err
+	}
+	return decompress(data)
+}
+
+// DirSource is a CatalogSource reading catalogs from a directory on the filesystem.
+type DirSource string
+
+// Open implements CatalogSource
+func (d DirSource) Open(name string) (io.ReadCloser, error) {
+	filename := filepath.Join(string(d), filepath.FromSlash(name))
+	info, err := os.Stat(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check that isn't a directory
+	if info.IsDir() {
+		return nil, &os.PathError{Op: "open", Path: filename, Err: os.ErrNotExist}
+	}
+
+	return os.Open(filename)
+}
+
+// List implements CatalogSource
+func (d DirSource) List(dir string) ([]string, error) {
+	root := filepath.Join(string(d), filepath.FromSlash(dir))
+
+	var names []string
+	err := filepath.Walk(root, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			rel, err := filepath.Rel(string(d), p)
+			if err != nil {
+				return err
+			}
+			names = append(names, filepath.ToSlash(rel))
+		}
+		return nil
+	})
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	sort.Strings(names)
+	return names, nil
+}
+
+// ModTime implements CatalogSource
+func (d DirSource) ModTime(name string) (time.Time, error) {
+	info, err := os.Stat(filepath.Join(string(d), filepath.FromSlash(name)))
+	if err != nil {
+		return time.Time{}, err
+	}
+	return info.ModTime(), nil
+}
+
+// MemorySource is a CatalogSource serving catalogs held in memory, keyed by name.
+type MemorySource struct {
+	Files map[string][]byte
+
+	// Modified is reported as the modification time of every catalog
+	Modified time.Time
+}
+
+// Open implements CatalogSource
+func (m *MemorySource) Open(name string) (io.ReadCloser, error) {
+	data, ok := m.Files[path.Clean(name)]
+	if !ok {
+		return nil, &os.PathError{Op: "open", Path: name, Err: os.ErrNotExist}
+	}
+	return ioutil.NopCloser(bytes.NewReader(data)), nil
+}
+
+// List implements CatalogSource
+func (m *MemorySource) List(dir string) ([]string, error) {
+	var names []string
+	for name := range m.Files {
+		if strings.HasPrefix(name, dir) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// ModTime implements CatalogSource
+func (m *MemorySource) ModTime(name string) (time.Time, error) {
+	if _, ok := m.Files[path.Clean(name)]; !ok {
+		return time.Time{}, &os.PathError{Op: "stat", Path: name, Err: os.ErrNotExist}
+	}
+	return m.Modified, nil
+}
+
+// ObjectInfo describes an object returned by ObjectStore.ListObjects.
+type ObjectInfo struct {
+	Key          string
+	LastModified time.Time
+}
+
+// ObjectStore is the minimal client interface needed to read catalogs from an object store (S3, GCS, Azure Blob...).
+// It's meant to be implemented by a thin adapter around the store SDK client.
+type ObjectStore interface {
+	// GetObject returns the object content.
+	// It must return an error for which errors.Is(err, os.ErrNotExist) is true when the key doesn't exist.
+	GetObject(ctx context.Context, key string) (io.ReadCloser, error)
+
+	// ListObjects returns all objects whose key starts with prefix.
+	ListObjects(ctx context.Context, prefix string) ([]ObjectInfo, error)
+}
+
+// ObjectStoreSource is a CatalogSource backed by an ObjectStore.
+// Catalog names are appended to Prefix to build the object keys (e.g. Prefix "i18n/" and "de/default.po").
+type ObjectStoreSource struct {
+	Store  ObjectStore
+	Prefix string
+
+	// Context used for the store requests. Defaults to context.Background().
+	Context context.Context
+}
+
+func (o *ObjectStoreSource) ctx() context.Context {
+	if o.Context == nil {
+		return context.Background()
+	}
+	return o.Context
+}
+
+// Open implements CatalogSource
+func (o *ObjectStoreSource) Open(name string) (io.ReadCloser, error) {
+	return o.Store.GetObject(o.ctx(), o.Prefix+name)
+}
+
+// List implements CatalogSource
+func (o *ObjectStoreSource) List(dir string) ([]string, error) {
+	objects, err := o.Store.ListObjects(o.ctx(), o.Prefix+dir)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(objects))
+	for _, obj := range objects {
+		names = append(names, strings.TrimPrefix(obj.Key, o.Prefix))
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// ModTime implements CatalogSource
+func (o *ObjectStoreSource) ModTime(name string) (time.Time, error) {
+	objects, err := o.Store.ListObjects(o.ctx(), o.Prefix+name)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	for _, obj := range objects {
+		if obj.Key == o.Prefix+name {
+			return obj.LastModified, nil
+		}
+	}
+	return time.Time{}, &os.PathError{Op: "stat", Path: o.Prefix + name, Err: os.ErrNotExist}
+}
@@ -0,0 +1,158 @@
+package gotext
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDirSource(t *testing.T) {
+	src := DirSource("fixtures")
+
+	names, err := src.List("de")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(names) != 2 || names[0] != "de/default.mo" || names[1] != "de/default.po" {
+		t.Errorf("Unexpected catalog list %v", names)
+	}
+
+	if _, err := src.ModTime("de/default.po"); err != nil {
+		t.Error(err)
+	}
+	if _, err := src.Open("de"); !isNotExist(err) {
+		t.Errorf("Expected a not exist error opening a directory but got %v", err)
+	}
+	if _, err := src.Open("xx/default.po"); !isNotExist(err) {
+		t.Errorf("Expected a not exist error but got %v", err)
+	}
+}
+
+// fakeObjectStore is an in-memory ObjectStore
+type fakeObjectStore map[string]string
+
+func (f fakeObjectStore) GetObject(ctx context.Context, key string) (io.ReadCloser, error) {
+	data, ok := f[key]
+	if !ok {
+		return nil, os.ErrNotExist
+	}
+	return ioutil.NopCloser(strings.NewReader(data)), nil
+}
+
+func (f fakeObjectStore) ListObjects(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	var objects []ObjectInfo
+	for key := range f {
+		if strings.HasPrefix(key, prefix) {
+			objects = append(objects, ObjectInfo{Key: key, LastModified: time.Unix(42, 0)})
+		}
+	}
+	return objects, nil
+}
+
+func TestObjectStoreSource(t *testing.T) {
+	src := &ObjectStoreSource{
+		Store: fakeObjectStore{
+			"i18n/es/LC_MESSAGES/default.po": "msgid \"Hello\"\nmsgstr \"Hola\"\n",
+			"i18n/es/extras.po":              "msgid \"Bye\"\nmsgstr \"Adiós\"\n",
+		},
+		Prefix: "i18n/",
+	}
+
+	names, err := src.List("es/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(names) != 2 || names[0] != "es/LC_MESSAGES/default.po" {
+		t.Errorf("Unexpected catalog list %v", names)
+	}
+	if mt, err := src.ModTime("es/extras.po"); err != nil || mt.Unix() != 42 {
+		t.Errorf("Unexpected ModTime %v, %v", mt, err)
+	}
+	if _, err := src.ModTime("es/missing.po"); !isNotExist(err) {
+		t.Errorf("Expected a not exist error but got %v", err)
+	}
+
+	l := NewLocaleWithSource(src, "es_AR")
+	l.AddDomain("default")
+	l.AddDomain("extras")
+	l.AddDomain("missing")
+
+	if tr := l.Get("Hello"); tr != "Hola" {
+		t.Errorf("Expected 'Hola' but got '%s'", tr)
+	}
+	if tr := l.GetD("extras", "Bye"); tr != "Adiós" {
+		t.Errorf("Expected 'Adiós' but got '%s'", tr)
+	}
+	if _, ok := l.Domains["missing"]; ok {
+		t.Error("Expected missing domain not to be added")
+	}
+}
+
+func TestMemorySource(t *testing.T) {
+	src := &MemorySource{Files: map[string][]byte{
+		"fr/default.po": []byte("msgid \"Yes\"\nmsgstr \"Oui\"\n"),
+	}}
+
+	l := NewLocale("fixtures/", "fr")
+	l.SetSource(src)
+	l.AddDomain("default")
+
+	if tr := l.Get("Yes"); tr != "Oui" {
+		t.Errorf("Expected 'Oui' but got '%s'", tr)
+	}
+
+	rc, err := src.Open("./fr/default.po")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rc.Close()
+	data, _ := ioutil.ReadAll(rc)
+	if !bytes.Contains(data, []byte("Oui")) {
+		t.Errorf("Unexpected content %q", data)
+	}
+}
+
+// failingSource is a MemorySource failing to open one of its files, like a flaky object store
+type failingSource struct {
+	MemorySource
+	failing string
+}
+
+func (f *failingSource) Open(name string) (io.ReadCloser, error) {
+	if name == f.failing {
+		return nil, errors.New("connection reset")
+	}
+	return f.MemorySource.Open(name)
+}
+
+func TestLocaleSourceErrors(t *testing.T) {
+	src := &failingSource{
+		MemorySource: MemorySource{Files: map[string][]byte{
+			"fr/default.po": []byte("msgid \"Yes\"\nmsgstr \"Oui\"\n"),
+		}},
+		failing: "fr/LC_MESSAGES/default.po",
+	}
+
+	l := NewLocaleWithSource(src, "fr")
+	err := l.loadDomain(context.Background(), "default")
+	if err == nil || !strings.Contains(err.Error(), "connection reset") {
+		t.Errorf("expected the read error, got %v", err)
+	}
+	l.AddDomain("default")
+	if _, ok := l.Domains["default"]; ok {
+		t.Error("expected no catalog when the source fails")
+	}
+
+	// Missing files are skipped
+	src.failing = ""
+	l.AddDomain("default")
+	if tr := l.Get("Yes"); tr != "Oui" {
+		t.Errorf("Expected 'Oui' but got '%s'", tr)
+	}
+}
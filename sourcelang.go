@@ -0,0 +1,66 @@
+package gotext
+
+import (
+	"golang.org/x/text/feature/plural"
+	"golang.org/x/text/language"
+
+	"github.com/razor-1/localizer/store"
+)
+
+/*
+SetSourceLanguage declares tag as the language the msgids are written in.
+When the Locale language is the source language, lookups return the msgids as they are, without reading
+the catalogs, missing translations aren't reported (see SetStrictMode and Lookup), and GetTranslations
+labels the untranslated entries with their msgids:
+
+	l := gotext.NewLocale("locales", "en")
+	l.SetSourceLanguage(language.English)
+
+Only the same tag is matched: regional variants, like en-GB for en, still use their catalogs.
+Setting language.Und clears the source language.
+*/
+func (l *Locale) SetSourceLanguage(tag language.Tag) {
+	l.Lock()
+	l.sourceLang = tag
+	l.Unlock()
+}
+
+// SourceLanguage returns the language set by SetSourceLanguage, or false if there is none.
+func (l *Locale) SourceLanguage() (language.Tag, bool) {
+	l.RLock()
+	defer l.RUnlock()
+
+	return l.sourceLang, l.sourceLang != language.Und
+}
+
+// inSourceLanguage reports whether the language of the Locale is the source language. The Locale must be locked.
+func (l *Locale) inSourceLanguage() bool {
+	return l.sourceLang != language.Und && l.sourceLang == l.tag
+}
+
+// sourceN returns str or plural formatted with vars, the source language string for the count n.
+func sourceN(str, plural string, n int, vars ...interface{}) string {
+	if n == 1 {
+		return Printf(str, vars...)
+	}
+	return Printf(plural, vars...)
+}
+
+// labelSource sets the msgids as the strings of the untranslated entries of lc.
+func labelSource(lc store.LocaleCatalog) {
+	for _, tr := range lc.Translations {
+		if tr.String == "" {
+			tr.String = tr.ID
+		}
+		for form, str := range tr.Plurals {
+			if str != "" {
+				continue
+			}
+			if form == plural.One {
+				tr.Plurals[form] = tr.ID
+			} else {
+				tr.Plurals[form] = tr.PluralID
+			}
+		}
+	}
+}
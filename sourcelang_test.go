@@ -0,0 +1,78 @@
+package gotext
+
+import (
+	"testing"
+
+	"golang.org/x/text/language"
+)
+
+func TestLocaleSourceLanguage(t *testing.T) {
+	l := NewLocale("fixtures/", "en_US")
+	if err := l.AddDomainBytes("default", []byte(`msgid "Hello"
+msgstr "Howdy"
+
+msgid "Bye"
+msgstr ""
+
+msgid "%d file"
+msgid_plural "%d files"
+msgstr[0] ""
+msgstr[1] ""
+`), FormatPO); err != nil {
+		t.Fatal(err)
+	}
+	hello, bye, missing := "Hello", "Bye", "Missing %s"
+
+	if _, ok := l.SourceLanguage(); ok {
+		t.Error("Expected no source language")
+	}
+	if tr := l.Get(hello); tr != "Howdy" {
+		t.Errorf("Expected the catalog translation, got '%s'", tr)
+	}
+
+	// Regional variants still use their catalogs
+	l.SetSourceLanguage(language.Make("en"))
+	if tr := l.Get(hello); tr != "Howdy" {
+		t.Errorf("Expected the catalog translation, got '%s'", tr)
+	}
+
+	l.SetSourceLanguage(language.Make("en-US"))
+	if tag, ok := l.SourceLanguage(); !ok || tag != language.Make("en-US") {
+		t.Errorf("Unexpected source language %v", tag)
+	}
+	if tr := l.Get(hello); tr != "Hello" {
+		t.Errorf("Expected the msgid, got '%s'", tr)
+	}
+	if tr := l.GetN("%d file", "%d files", 3, 3); tr != "3 files" {
+		t.Errorf("Expected the plural msgid, got '%s'", tr)
+	}
+	if tr := l.GetNC("%d file", "%d files", 1, "ctx", 1); tr != "1 file" {
+		t.Errorf("Expected the msgid, got '%s'", tr)
+	}
+	if tr, ok := l.Lookup(missing, "x"); !ok || tr != "Missing x" {
+		t.Errorf("Expected the msgid to be found, got '%s'", tr)
+	}
+
+	l.SetStrictMode(true)
+	if _, err := l.TryGet(missing, "x"); err != nil {
+		t.Errorf("Unexpected error %v", err)
+	}
+	l.Get(missing, "x")
+	l.SetStrictMode(false)
+
+	lc, err := l.GetTranslations(language.Make("en_US"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tr := lc.Translations[bye]; tr == nil || tr.String != bye {
+		t.Errorf("Expected untranslated entries labelled with their msgid, got %+v", tr)
+	}
+	if tr := lc.Translations[hello]; tr == nil || tr.String != "Howdy" {
+		t.Errorf("Expected translations to be kept, got %+v", tr)
+	}
+
+	l.SetSourceLanguage(language.Und)
+	if tr := l.Get(hello); tr != "Howdy" {
+		t.Errorf("Expected the catalog translation, got '%s'", tr)
+	}
+}
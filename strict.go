@@ -0,0 +1,306 @@
+package gotext
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ParseError is an error found while parsing a catalog, with its position.
+type ParseError struct {
+	// Line number, starting at 1, for text catalogs
+	Line int
+	// Byte offset, for binary catalogs
+	Offset int64
+
+	Msg string
+
+	// duplicate tells the error is a duplicate msgid, which the DuplicatePolicy of catalogs handles
+	duplicate bool
+}
+
+func (e *ParseError) Error() string {
+	return "gotext: " + e.describe()
+}
+
+// describe returns the position and message of the error.
+func (e *ParseError) describe() string {
+	if e.Line > 0 {
+		return fmt.Sprintf("line %d: %s", e.Line, e.Msg)
+	}
+	return fmt.Sprintf("offset %d: %s", e.Offset, e.Msg)
+}
+
+// ParseErrors are all the errors found while parsing a catalog, in order.
+type ParseErrors []*ParseError
+
+func (e ParseErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.describe()
+	}
+	return "gotext: " + strings.Join(msgs, "; ")
+}
+
+// ParseWithError works like Parse, loading everything it can, but returns the syntax problems found
+// as a ParseErrors value with their line numbers (see ParseStrict for the checks done).
+// Truncated or corrupt files, which Parse silently loads partially, are reported this way.
+func (po *Po) ParseWithError(buf []byte) error {
+	if errs := po.parse(buf, ""); len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+/*
+ParseStrict works like Parse, but validates the whole catalog first and doesn't load anything when it finds errors,
+instead of silently producing a partial domain. It reports:
+
+  - Unterminated strings and invalid escape sequences.
+  - Unknown keywords, and strings out of place.
+  - Duplicate msgids (in the same context).
+  - Malformed entries: msgctxt or msgid without msgstr, msgid_plural with a plain msgstr (or the opposite),
+    and msgstr[n] indexes that are invalid, repeated or not consecutive starting at 0.
+
+The returned error is a ParseErrors value with all the problems found.
+*/
+func (po *Po) ParseStrict(buf []byte) error {
+	if errs := validatePo(buf); len(errs) > 0 {
+		return errs
+	}
+
+	po.Parse(buf)
+	return nil
+}
+
+// NewPoFromString returns a Po object with the PO catalog s, checked like ParseStrict does.
+func NewPoFromString(s string) (*Po, error) {
+	po := NewPo()
+	if err := po.ParseStrict([]byte(s)); err != nil {
+		return nil, err
+	}
+	return po, nil
+}
+
+/*
+MustParsePO returns a Po object with the PO catalog data, checked like ParseStrict does, and panics when it's malformed.
+It's meant for catalogs embedded in the binary, so mistakes are found as soon as the program starts:
+
+	//go:embed locales/es/default.po
+	var esCatalog []byte
+
+	var es = gotext.MustParsePO(esCatalog)
+*/
+func MustParsePO(data []byte) *Po {
+	po := NewPo()
+	if err := po.ParseStrict(data); err != nil {
+		panic(err)
+	}
+	return po
+}
+
+// poEntry is the state of an entry being validated by a poValidator.
+type poEntry struct {
+	line    int
+	ctx     string
+	hasCtx  bool
+	id      string
+	hasID   bool
+	plural  bool
+	msgstr  bool
+	indexes map[int]bool
+}
+
+// poValidator checks the PO syntax of a catalog, line by line, so Po.parse can collect the errors while parsing.
+type poValidator struct {
+	errs  ParseErrors
+	seen  map[[2]string]int
+	entry *poEntry
+
+	// Keyword the string continuation lines belong to
+	last string
+}
+
+func newPoValidator() *poValidator {
+	return &poValidator{seen: make(map[[2]string]int), entry: &poEntry{}}
+}
+
+// validatePo checks the PO syntax of buf, returning every error found.
+func validatePo(buf []byte) ParseErrors {
+	v := newPoValidator()
+	for n, l := range strings.Split(string(buf), "\n") {
+		v.line(n+1, strings.TrimSpace(l))
+	}
+	return v.finish()
+}
+
+func (v *poValidator) fail(line int, format string, args ...interface{}) {
+	v.errs = append(v.errs, &ParseError{Line: line, Msg: fmt.Sprintf(format, args...)})
+}
+
+// endEntry checks the entry being validated, and starts a new one.
+func (v *poValidator) endEntry() {
+	entry := v.entry
+	if !entry.hasCtx && !entry.hasID {
+		return
+	}
+	switch {
+	case !entry.hasID:
+		v.fail(entry.line, "msgctxt without msgid")
+	case !entry.msgstr:
+		v.fail(entry.line, "msgid %q without msgstr", entry.id)
+	case entry.plural && entry.indexes == nil:
+		v.fail(entry.line, "msgid_plural %q needs msgstr[n] forms", entry.id)
+	case entry.plural:
+		for i := 0; i < len(entry.indexes); i++ {
+			if !entry.indexes[i] {
+				v.fail(entry.line, "msgid %q is missing msgstr[%d]", entry.id, i)
+				break
+			}
+		}
+	}
+
+	if entry.hasID {
+		key := [2]string{entry.ctx, entry.id}
+		if first, ok := v.seen[key]; ok {
+			v.fail(entry.line, "duplicate msgid %q, first defined on line %d", entry.id, first)
+			v.errs[len(v.errs)-1].duplicate = true
+		} else {
+			v.seen[key] = entry.line
+		}
+	}
+	v.entry = &poEntry{}
+}
+
+// finish checks the last entry, and returns every error found.
+func (v *poValidator) finish() ParseErrors {
+	v.endEntry()
+	return v.errs
+}
+
+// line checks the line l, with its spaces trimmed, at the given line number.
+func (v *poValidator) line(line int, l string) {
+	if l == "" || strings.HasPrefix(l, "#") {
+		return
+	}
+
+	entry := v.entry
+	if strings.HasPrefix(l, "\"") {
+		if v.last == "" {
+			v.fail(line, "string without keyword")
+		}
+		if err := validateString(l); err != "" {
+			v.fail(line, "%s", err)
+		}
+
+		// Multi-line msgctxt and msgid, which identify the entry
+		value, _ := strconv.Unquote(l)
+		switch v.last {
+		case "msgctxt":
+			entry.ctx += value
+		case "msgid":
+			entry.id += value
+		}
+		return
+	}
+
+	kw, str := l, ""
+	if idx := strings.IndexAny(l, " \t"); idx != -1 {
+		kw, str = l[:idx], strings.TrimSpace(l[idx+1:])
+	}
+	if err := validateString(str); err != "" {
+		v.fail(line, "%s", err)
+	}
+	value, _ := strconv.Unquote(str)
+	v.last = kw
+
+	switch {
+	case kw == "msgctxt":
+		v.endEntry()
+		v.entry.line, v.entry.ctx, v.entry.hasCtx = line, value, true
+
+	case kw == "msgid":
+		if entry.hasID {
+			v.endEntry()
+		}
+		if !v.entry.hasCtx {
+			v.entry.line = line
+		}
+		v.entry.id, v.entry.hasID = value, true
+
+	case kw == "msgid_plural":
+		if !entry.hasID || entry.msgstr || entry.plural {
+			v.fail(line, "unexpected msgid_plural")
+			return
+		}
+		entry.plural = true
+
+	case kw == "msgstr":
+		if !entry.hasID {
+			v.fail(line, "msgstr without msgid")
+			return
+		}
+		if entry.msgstr {
+			v.fail(line, "duplicate msgstr")
+		}
+		if entry.plural {
+			v.fail(line, "msgid_plural %q needs msgstr[n] forms", entry.id)
+		}
+		entry.msgstr = true
+
+	case strings.HasPrefix(kw, "msgstr["):
+		if !entry.hasID {
+			v.fail(line, "msgstr without msgid")
+			return
+		}
+		if !entry.plural {
+			v.fail(line, "%s without msgid_plural", kw)
+		}
+		i, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(kw, "msgstr["), "]"))
+		if err != nil || i < 0 || !strings.HasSuffix(kw, "]") {
+			v.fail(line, "invalid plural index in %s", kw)
+			return
+		}
+		if entry.indexes == nil {
+			entry.indexes = make(map[int]bool)
+		}
+		if entry.indexes[i] {
+			v.fail(line, "duplicate %s", kw)
+		}
+		entry.indexes[i] = true
+		entry.msgstr = true
+
+	default:
+		v.fail(line, "unknown keyword %q", kw)
+		v.last = ""
+	}
+}
+
+// validateString checks a quoted PO string, returning the problem found or an empty string.
+func validateString(s string) string {
+	if !strings.HasPrefix(s, "\"") {
+		return "missing string"
+	}
+
+	// The closing quote must not be escaped
+	end := len(s) - 1
+	escaped := false
+	for i := 1; i < len(s); i++ {
+		switch {
+		case escaped:
+			escaped = false
+		case s[i] == '\\':
+			escaped = true
+		case s[i] == '"':
+			if i != end {
+				return "unexpected text after string"
+			}
+			if _, err := strconv.Unquote(s); err != nil {
+				return "invalid escape sequence in string"
+			}
+			return ""
+		}
+	}
+
+	return "unterminated string"
+}
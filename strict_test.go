@@ -0,0 +1,158 @@
+package gotext
+
+import (
+	"testing"
+)
+
+func TestParseStrictValid(t *testing.T) {
+	po := NewPo()
+	err := po.ParseStrict([]byte(`# Header comment
+msgid ""
+msgstr ""
+"Language: de\n"
+"Plural-Forms: nplurals=2; plural=(n != 1);\n"
+
+#: main.go:1
+msgid "Multi"
+"line"
+msgstr "Mehr"
+"zeilig"
+
+msgid "Escapes \"quoted\"\t\\"
+msgstr "Maskiert \"zitiert\"\t\\\n\101\x41"
+
+msgctxt "menu"
+msgid "File"
+msgstr "Datei"
+
+msgid "File"
+msgstr "Akte"
+
+msgid "%d file"
+msgid_plural "%d files"
+msgstr[0] "%d Datei"
+msgstr[1] "%d Dateien"
+
+#~ msgid "Old"
+#~ msgstr "Alt"
+`))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	tests := map[string]string{
+		"Multiline":              "Mehrzeilig",
+		"Escapes \"quoted\"\t\\": "Maskiert \"zitiert\"\t\\\nAA",
+		"File":                   "Akte",
+	}
+	for id, expected := range tests {
+		if tr := po.Get(id); tr != expected {
+			t.Errorf("Expected %q but got %q", expected, tr)
+		}
+	}
+	if tr := po.GetC("File", "menu"); tr != "Datei" {
+		t.Errorf("Expected 'Datei' but got '%s'", tr)
+	}
+	if tr := po.GetN("%d file", "%d files", 2, 2); tr != "2 Dateien" {
+		t.Errorf("Expected '2 Dateien' but got '%s'", tr)
+	}
+}
+
+func TestParseStrictErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		po    string
+		lines []int
+	}{
+		{"unterminated string", "msgid \"Hello\nmsgstr \"Hola\"", []int{1}},
+		{"unterminated continuation", "msgid \"Hello\"\nmsgstr \"Hola\"\n\"more\\\"", []int{3}},
+		{"invalid escape", "msgid \"Hello\\q\"\nmsgstr \"Hola\"", []int{1}},
+		{"text after string", "msgid \"Hello\" x\nmsgstr \"Hola\"", []int{1}},
+		{"duplicate msgid", "msgid \"A\"\nmsgstr \"1\"\n\nmsgid \"A\"\nmsgstr \"2\"", []int{4}},
+		{"missing msgstr", "msgid \"A\"\n\nmsgid \"B\"\nmsgstr \"2\"", []int{1}},
+		{"msgctxt without msgid", "msgctxt \"x\"\nmsgstr \"1\"", []int{2, 1}},
+		{"plain msgstr for plural", "msgid \"A\"\nmsgid_plural \"As\"\nmsgstr \"1\"", []int{3, 1}},
+		{"indexed msgstr for singular", "msgid \"A\"\nmsgstr[0] \"1\"", []int{2}},
+		{"missing plural index", "msgid \"A\"\nmsgid_plural \"As\"\nmsgstr[0] \"1\"\nmsgstr[2] \"3\"", []int{1}},
+		{"invalid plural index", "msgid \"A\"\nmsgid_plural \"As\"\nmsgstr[x] \"1\"\nmsgstr[0] \"1\"", []int{3}},
+		{"unknown keyword", "msgid \"A\"\nmsgtxt \"1\"\nmsgstr \"1\"", []int{2}},
+		{"string without keyword", "\"orphan\"\nmsgid \"A\"\nmsgstr \"1\"", []int{1}},
+	}
+
+	for _, test := range tests {
+		po := NewPo()
+		err := po.ParseStrict([]byte(test.po))
+		errs, ok := err.(ParseErrors)
+		if !ok {
+			t.Errorf("%s: expected ParseErrors but got %v", test.name, err)
+			continue
+		}
+		if len(errs) != len(test.lines) {
+			t.Errorf("%s: expected errors on lines %v but got %v", test.name, test.lines, errs)
+			continue
+		}
+		for i, line := range test.lines {
+			if errs[i].Line != line {
+				t.Errorf("%s: expected error on line %d but got %v", test.name, line, errs[i])
+			}
+		}
+
+		// Nothing is loaded
+		if len(po.GetDomain().translations) != 0 {
+			t.Errorf("%s: expected no translations to be loaded", test.name)
+		}
+	}
+}
+
+func TestNewPoFromString(t *testing.T) {
+	po, err := NewPoFromString("msgid \"Hello\"\nmsgstr \"Hola\"\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tr := po.Get("Hello"); tr != "Hola" {
+		t.Errorf("Expected 'Hola' but got '%s'", tr)
+	}
+
+	if _, err := NewPoFromString("msgid \"Hello\"\nmsgstr \"Hola"); err == nil {
+		t.Error("Expected an error for an unterminated string")
+	}
+}
+
+func TestMustParsePO(t *testing.T) {
+	po := MustParsePO([]byte("msgctxt \"month\"\nmsgid \"May\"\nmsgstr \"Mayo\"\n"))
+	if tr := po.GetC("May", "month"); tr != "Mayo" {
+		t.Errorf("Expected 'Mayo' but got '%s'", tr)
+	}
+
+	defer func() {
+		if _, ok := recover().(ParseErrors); !ok {
+			t.Error("Expected a panic with ParseErrors")
+		}
+	}()
+	MustParsePO([]byte("msgid \"A\"\nmsgstr \"1\"\nmsgid \"A\"\nmsgstr \"2\"\n"))
+}
+
+func TestParseStrictMultilineIDs(t *testing.T) {
+	po := NewPo()
+	err := po.ParseStrict([]byte(`msgid ""
+"First"
+msgstr "Erste"
+
+msgid ""
+"Second"
+msgstr "Zweite"
+
+msgctxt ""
+"menu"
+msgid "First"
+msgstr "Erste"
+`))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	err = po.ParseStrict([]byte("msgid \"\"\n\"Same\"\nmsgstr \"A\"\n\nmsgid \"Sa\"\n\"me\"\nmsgstr \"B\"\n"))
+	if errs, ok := err.(ParseErrors); !ok || len(errs) != 1 || errs[0].Line != 5 {
+		t.Errorf("Expected a duplicate msgid on line 5, got %v", err)
+	}
+}
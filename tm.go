@@ -0,0 +1,201 @@
+package gotext
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// Suggestion is a translation found by a TranslationMemory for a source string.
+type Suggestion struct {
+	// Domain of the message, and the message itself
+	Domain string
+	Entry
+
+	// Similarity of the msgid with the source string, from 0 to 1 (see Domain.SetFuzzyMatch), and
+	// whether the message is in the requested context
+	Similarity  float64
+	SameContext bool
+}
+
+// Exact tells whether the msgid of the suggestion is the source string.
+func (s Suggestion) Exact() bool {
+	return s.Similarity == 1
+}
+
+// tmUnit is a translated message indexed by a TranslationMemory.
+type tmUnit struct {
+	domain string
+	entry  Entry
+}
+
+/*
+TranslationMemory indexes the translated messages of catalogs to suggest translations for new source strings,
+like translation editors do: exact matches first, then the most similar msgids, preferring the same context.
+
+	tm := gotext.NewTranslationMemory(0.6)
+	tm.AddLocale(l)
+	for _, s := range tm.Suggest("Save all changes", "", 5) {
+		fmt.Printf("%.0f%% %s -> %s\n", s.Similarity*100, s.MsgID, s.Translation.Get())
+	}
+
+It's safe for concurrent use.
+*/
+type TranslationMemory struct {
+	mu sync.RWMutex
+
+	// Minimum similarity of the suggestions
+	threshold float64
+
+	units []tmUnit
+
+	// Units by msgid, and by trigram of their msgid
+	exact    map[string][]int
+	trigrams map[string][]int
+}
+
+// NewTranslationMemory creates an empty TranslationMemory that suggests messages whose msgid is at least
+// as similar to the source string as threshold.
+func NewTranslationMemory(threshold float64) *TranslationMemory {
+	return &TranslationMemory{
+		threshold: threshold,
+		exact:     make(map[string][]int),
+		trigrams:  make(map[string][]int),
+	}
+}
+
+// Add indexes the translated messages of the Domain do, as the domain dom.
+// Messages are copied, so later changes of the Domain aren't seen.
+func (tm *TranslationMemory) Add(dom string, do *Domain) {
+	entries := do.entries()
+
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	for _, e := range entries {
+		if !e.Translation.IsTranslated() {
+			continue
+		}
+		e.Translation = copyTranslation(e.Translation)
+
+		i := len(tm.units)
+		tm.units = append(tm.units, tmUnit{domain: dom, entry: e})
+		tm.exact[e.MsgID] = append(tm.exact[e.MsgID], i)
+		for _, tri := range trigrams(e.MsgID) {
+			tm.trigrams[tri] = append(tm.trigrams[tri], i)
+		}
+	}
+}
+
+// AddLocale indexes the translated messages of every domain loaded by l.
+func (tm *TranslationMemory) AddLocale(l *Locale) {
+	l.RLock()
+	domains := make(map[string]Translator, len(l.Domains))
+	for name, tr := range l.Domains {
+		domains[name] = tr
+	}
+	l.RUnlock()
+
+	names := make([]string, 0, len(domains))
+	for name := range domains {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		tm.Add(name, domains[name].GetDomain())
+	}
+}
+
+// Len returns the number of messages indexed.
+func (tm *TranslationMemory) Len() int {
+	tm.mu.RLock()
+	defer tm.mu.RUnlock()
+
+	return len(tm.units)
+}
+
+// Suggest returns up to max suggestions for the source string src in the context ctx, best first:
+// by similarity, then messages in the same context, then by domain, context and msgid.
+// A max of 0 or less returns all of them.
+func (tm *TranslationMemory) Suggest(src, ctx string, max int) []Suggestion {
+	tm.mu.RLock()
+	defer tm.mu.RUnlock()
+
+	// Messages sharing no trigram with src can't be similar enough, unless the strings are too short to have any
+	candidates := make(map[int]bool)
+	for _, i := range tm.exact[src] {
+		candidates[i] = true
+	}
+	tris := trigrams(src)
+	if len(tris) == 0 {
+		for i := range tm.units {
+			candidates[i] = true
+		}
+	}
+	for _, tri := range tris {
+		for _, i := range tm.trigrams[tri] {
+			candidates[i] = true
+		}
+	}
+
+	srcLen := utf8.RuneCountInString(src)
+	var suggestions []Suggestion
+	for i := range candidates {
+		u := tm.units[i]
+		similarity := 1.0
+		if u.entry.MsgID != src {
+			longest := utf8.RuneCountInString(u.entry.MsgID)
+			if srcLen > longest {
+				longest = srcLen
+			}
+			similarity = 1 - float64(levenshtein(src, u.entry.MsgID))/float64(longest)
+		}
+		if similarity < tm.threshold {
+			continue
+		}
+
+		suggestions = append(suggestions, Suggestion{
+			Domain:      u.domain,
+			Entry:       Entry{Context: u.entry.Context, MsgID: u.entry.MsgID, Translation: copyTranslation(u.entry.Translation)},
+			Similarity:  similarity,
+			SameContext: u.entry.Context == ctx,
+		})
+	}
+
+	sort.Slice(suggestions, func(i, j int) bool {
+		a, b := suggestions[i], suggestions[j]
+		switch {
+		case a.Similarity != b.Similarity:
+			return a.Similarity > b.Similarity
+		case a.SameContext != b.SameContext:
+			return a.SameContext
+		case a.Domain != b.Domain:
+			return a.Domain < b.Domain
+		case a.Context != b.Context:
+			return a.Context < b.Context
+		}
+		return a.MsgID < b.MsgID
+	})
+
+	if max > 0 && len(suggestions) > max {
+		suggestions = suggestions[:max]
+	}
+	return suggestions
+}
+
+// trigrams returns the distinct sequences of three runes of the lowercased s.
+func trigrams(s string) []string {
+	r := []rune(strings.ToLower(s))
+	seen := make(map[string]bool)
+	var tris []string
+	for i := 0; i+3 <= len(r); i++ {
+		tri := string(r[i : i+3])
+		if !seen[tri] {
+			seen[tri] = true
+			tris = append(tris, tri)
+		}
+	}
+	return tris
+}
@@ -0,0 +1,64 @@
+package gotext
+
+import (
+	"testing"
+)
+
+func TestTranslationMemory(t *testing.T) {
+	app := NewPo()
+	app.Parse([]byte(`
+msgid "Save changes"
+msgstr "Änderungen speichern"
+
+msgctxt "dialog"
+msgid "Save all changes"
+msgstr "Alle Änderungen speichern"
+
+msgid "Discard changes"
+msgstr ""
+
+msgid "Quit"
+msgstr "Beenden"
+`))
+	admin := NewPo()
+	admin.Parse([]byte(`
+msgid "Save all changes"
+msgstr "Alles speichern"
+`))
+
+	l := NewLocale("", "de")
+	l.AddTranslator("app", app)
+	l.AddTranslator("admin", admin)
+
+	tm := NewTranslationMemory(0.6)
+	tm.AddLocale(l)
+	if tm.Len() != 4 {
+		t.Errorf("Expected 4 translated messages, got %d", tm.Len())
+	}
+
+	got := tm.Suggest("Save all changes", "dialog", 0)
+	if len(got) != 3 {
+		t.Fatalf("Expected 3 suggestions, got %+v", got)
+	}
+
+	// Exact matches first, the one in the same context before the others
+	if s := got[0]; !s.Exact() || !s.SameContext || s.Domain != "app" || s.Translation.Get() != "Alle Änderungen speichern" {
+		t.Errorf("Unexpected suggestion %+v", s)
+	}
+	if s := got[1]; !s.Exact() || s.SameContext || s.Domain != "admin" {
+		t.Errorf("Unexpected suggestion %+v", s)
+	}
+	if s := got[2]; s.Exact() || s.MsgID != "Save changes" || s.Similarity < 0.7 {
+		t.Errorf("Unexpected suggestion %+v", s)
+	}
+
+	if got := tm.Suggest("Save all changes", "", 1); len(got) != 1 || got[0].Domain != "admin" {
+		t.Errorf("Expected the exact match without context, got %+v", got)
+	}
+	if got := tm.Suggest("Quit", "", 0); len(got) != 1 || got[0].Translation.Get() != "Beenden" {
+		t.Errorf("Unexpected suggestions %+v", got)
+	}
+	if got := tm.Suggest("Open a file", "", 0); len(got) != 0 {
+		t.Errorf("Expected no suggestions, got %+v", got)
+	}
+}
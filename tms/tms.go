@@ -0,0 +1,76 @@
+/*
+Package tms pulls catalogs from a translation management system (TMS), like Weblate, Crowdin or Lokalise,
+and pushes source catalogs to it, so services can load fresh translations at startup or on a schedule
+instead of shipping them with each release.
+
+	client := &tms.Weblate{
+		URL:     "https://hosted.weblate.org",
+		Token:   os.Getenv("WEBLATE_TOKEN"),
+		Project: "myapp",
+	}
+
+	ls, err := tms.LoadLocales(ctx, client, "default")
+
+	// Refresh once an hour
+	go func() {
+		for range time.Tick(time.Hour) {
+			for _, lang := range ls.Languages() {
+				tms.Load(ctx, client, ls.Get(lang), "default")
+			}
+		}
+	}()
+
+Catalogs are exchanged in the PO format. Other systems can be used by implementing Client.
+*/
+package tms
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/leonelquinteros/gotext"
+)
+
+// Client talks to a translation management system.
+type Client interface {
+	// DownloadCatalog returns the PO catalog of the domain in the language lang.
+	DownloadCatalog(ctx context.Context, lang, domain string) ([]byte, error)
+
+	// UploadSource replaces the source strings of the domain with the PO (or POT) catalog data,
+	// like the one extracted by xgotext.
+	UploadSource(ctx context.Context, domain string, data []byte) error
+
+	// ListLanguages returns the languages translated in the system.
+	ListLanguages(ctx context.Context) ([]string, error)
+}
+
+// Load downloads the catalog of the domain dom in the language of l and loads it in l, replacing the current one.
+// The catalog gets the settings of l, like for AddDomainBytes.
+func Load(ctx context.Context, c Client, l *gotext.Locale, dom string) error {
+	data, err := c.DownloadCatalog(ctx, l.GetLanguage(), dom)
+	if err != nil {
+		return err
+	}
+	return l.AddDomainBytes(dom, data, gotext.FormatPO)
+}
+
+// LoadLocales creates a Locale for every language of the system, with the catalogs of the given domains,
+// and returns them as a Locales pool. It returns an error if the languages or any catalog can't be downloaded.
+func LoadLocales(ctx context.Context, c Client, domains ...string) (*gotext.Locales, error) {
+	langs, err := c.ListLanguages(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ls := gotext.NewLocales("")
+	for _, lang := range langs {
+		l := gotext.NewLocale("", lang)
+		for _, dom := range domains {
+			if err := Load(ctx, c, l, dom); err != nil {
+				return nil, fmt.Errorf("tms: loading %s catalog of %q: %w", lang, dom, err)
+			}
+		}
+		ls.Add(l)
+	}
+	return ls, nil
+}
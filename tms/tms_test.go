@@ -0,0 +1,91 @@
+package tms
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newWeblate() (*Weblate, *[]byte, func()) {
+	uploaded := new([]byte)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/projects/app/languages/", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"code": "de", "name": "German"}, {"code": "fr", "name": "French"}]`))
+	})
+	mux.HandleFunc("/api/translations/app/default/de/file/", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("msgid \"Hello\"\nmsgstr \"Hallo\"\n"))
+	})
+	mux.HandleFunc("/api/translations/app/default/fr/file/", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("msgid \"Hello\"\nmsgstr \"Bonjour\"\n"))
+	})
+	mux.HandleFunc("/api/translations/app/default/en/file/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.FormValue("method") != "source" {
+			http.Error(w, "bad upload", http.StatusBadRequest)
+			return
+		}
+		f, _, err := r.FormFile("file")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		*uploaded, _ = ioutil.ReadAll(f)
+	})
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Token secret" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		mux.ServeHTTP(w, r)
+	}))
+
+	return &Weblate{URL: srv.URL + "/", Token: "secret", Project: "app"}, uploaded, srv.Close
+}
+
+func TestWeblate(t *testing.T) {
+	w, uploaded, stop := newWeblate()
+	defer stop()
+	ctx := context.Background()
+
+	langs, err := w.ListLanguages(ctx)
+	if err != nil || len(langs) != 2 || langs[0] != "de" || langs[1] != "fr" {
+		t.Errorf("Unexpected languages %v, %v", langs, err)
+	}
+
+	if err := w.UploadSource(ctx, "default", []byte("msgid \"Hello\"\nmsgstr \"\"\n")); err != nil {
+		t.Fatal(err)
+	}
+	if string(*uploaded) != "msgid \"Hello\"\nmsgstr \"\"\n" {
+		t.Errorf("Unexpected upload %q", *uploaded)
+	}
+
+	if _, err := w.DownloadCatalog(ctx, "es", "default"); err == nil {
+		t.Error("Expected an error for a missing translation")
+	}
+	w.Token = "wrong"
+	if _, err := w.ListLanguages(ctx); err == nil {
+		t.Error("Expected an error for a wrong token")
+	}
+}
+
+func TestLoadLocales(t *testing.T) {
+	w, _, stop := newWeblate()
+	defer stop()
+
+	ls, err := LoadLocales(context.Background(), w, "default")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tr := ls.Get("fr").GetD("default", "Hello"); tr != "Bonjour" {
+		t.Errorf("Unexpected translation %q", tr)
+	}
+	if tr := ls.Get("de").GetD("default", "Hello"); tr != "Hallo" {
+		t.Errorf("Unexpected translation %q", tr)
+	}
+
+	if _, err := LoadLocales(context.Background(), w, "missing"); err == nil {
+		t.Error("Expected an error for a missing domain")
+	}
+}
@@ -0,0 +1,125 @@
+package tms
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Weblate is a Client for the REST API of Weblate (https://weblate.org), where every domain is a component
+// of the project, with the same slug.
+type Weblate struct {
+	// Base URL of the Weblate server, like "https://hosted.weblate.org"
+	URL string
+
+	// API token of the user, sent in the Authorization header
+	Token string
+
+	// Slug of the project
+	Project string
+
+	// Language of the source strings, "en" if empty
+	SourceLanguage string
+
+	// HTTP client of the requests, http.DefaultClient if nil
+	Client *http.Client
+}
+
+// DownloadCatalog implements Client.
+func (w *Weblate) DownloadCatalog(ctx context.Context, lang, domain string) ([]byte, error) {
+	resp, err := w.do(ctx, http.MethodGet, w.translationPath(domain, lang)+"file/", "", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	return ioutil.ReadAll(resp.Body)
+}
+
+// UploadSource implements Client. The source strings are updated with the "source" upload method of Weblate.
+func (w *Weblate) UploadSource(ctx context.Context, domain string, data []byte) error {
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	mw.WriteField("method", "source")
+	fw, err := mw.CreateFormFile("file", domain+".pot")
+	if err != nil {
+		return err
+	}
+	fw.Write(data)
+	if err := mw.Close(); err != nil {
+		return err
+	}
+
+	lang := w.SourceLanguage
+	if lang == "" {
+		lang = "en"
+	}
+	resp, err := w.do(ctx, http.MethodPost, w.translationPath(domain, lang)+"file/", mw.FormDataContentType(), &body)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// ListLanguages implements Client, returning the language codes of the project.
+func (w *Weblate) ListLanguages(ctx context.Context) ([]string, error) {
+	resp, err := w.do(ctx, http.MethodGet, "/api/projects/"+url.PathEscape(w.Project)+"/languages/", "", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var langs []struct {
+		Code string `json:"code"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&langs); err != nil {
+		return nil, fmt.Errorf("tms: decoding Weblate languages: %w", err)
+	}
+
+	codes := make([]string, len(langs))
+	for i, lang := range langs {
+		codes[i] = lang.Code
+	}
+	return codes, nil
+}
+
+// translationPath returns the API path of the translation of the domain in the language lang.
+func (w *Weblate) translationPath(domain, lang string) string {
+	return "/api/translations/" + url.PathEscape(w.Project) + "/" + url.PathEscape(domain) + "/" + url.PathEscape(lang) + "/"
+}
+
+// do sends an API request, returning an error for non 2xx responses.
+func (w *Weblate) do(ctx context.Context, method, path, contentType string, body io.Reader) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimSuffix(w.URL, "/")+path, body)
+	if err != nil {
+		return nil, err
+	}
+	if w.Token != "" {
+		req.Header.Set("Authorization", "Token "+w.Token)
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	client := w.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		resp.Body.Close()
+		return nil, fmt.Errorf("tms: Weblate %s %s: %s", method, path, resp.Status)
+	}
+	return resp, nil
+}
@@ -0,0 +1,83 @@
+package gotext
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// Span is an operation traced by a Tracer.
+type Span interface {
+	// SetAttribute records a property of the operation, like the file or domain name.
+	SetAttribute(key string, value interface{})
+	// End finishes the span, with the error that made the operation fail, if any.
+	End(err error)
+}
+
+/*
+Tracer starts the spans of the catalog loads, so slow loads and reloads can be attributed in production.
+The gotextotel module provides one for OpenTelemetry, without adding the dependency to this package:
+
+	gotext.SetTracer(gotextotel.NewTracer(otel.Tracer("gotext")))
+
+These operations are traced:
+
+	gotext.ParseFile      Po and Mo ParseFile calls: file, entries
+	gotext.AddDomain      Locale.AddDomain, Locales.LoadAll: lang, domain
+	gotext.ParseCatalog   catalogs parsed while loading a domain: domain, file, format, bytes, entries
+	gotext.FetchCatalog   remote catalogs of NewLocaleHTTP: domain, url, status, entries
+*/
+type Tracer interface {
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+type nopSpan struct{}
+
+func (nopSpan) SetAttribute(key string, value interface{}) {}
+func (nopSpan) End(err error)                              {}
+
+// tracerHolder keeps the concrete type stored in the atomic.Value the same.
+type tracerHolder struct {
+	t Tracer
+}
+
+var currentTracer atomic.Value
+
+// SetTracer sets the Tracer used by all Locale and Domain objects. A nil t turns tracing off, which is the default.
+func SetTracer(t Tracer) {
+	currentTracer.Store(tracerHolder{t})
+}
+
+// startSpan starts a span of the current Tracer with the given alternating key and value attributes.
+// It returns a span that does nothing when there is no Tracer.
+func startSpan(ctx context.Context, name string, attrs ...interface{}) (context.Context, Span) {
+	h, ok := currentTracer.Load().(tracerHolder)
+	if !ok || h.t == nil {
+		return ctx, nopSpan{}
+	}
+
+	ctx, span := h.t.Start(ctx, name)
+	for i := 0; i+1 < len(attrs); i += 2 {
+		if key, ok := attrs[i].(string); ok {
+			span.SetAttribute(key, attrs[i+1])
+		}
+	}
+	return ctx, span
+}
+
+// entryCount returns the number of entries of the Domain, in or out of a context, but the header.
+func (do *Domain) entryCount() int {
+	do.trMutex.RLock()
+	defer do.trMutex.RUnlock()
+
+	n := len(do.translations)
+	if _, ok := do.translations[""]; ok {
+		n--
+	}
+	for _, trs := range do.contexts {
+		n += len(trs)
+		if _, ok := trs[""]; ok {
+			n--
+		}
+	}
+	return n
+}
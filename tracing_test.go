@@ -0,0 +1,106 @@
+package gotext
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+type testSpan struct {
+	name   string
+	parent *testSpan
+	attrs  map[string]interface{}
+	ended  bool
+	err    error
+}
+
+func (s *testSpan) SetAttribute(key string, value interface{}) { s.attrs[key] = value }
+func (s *testSpan) End(err error)                              { s.ended, s.err = true, err }
+
+type spanKey struct{}
+
+type testTracer struct {
+	mu    sync.Mutex
+	spans []*testSpan
+}
+
+func (t *testTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	parent, _ := ctx.Value(spanKey{}).(*testSpan)
+	s := &testSpan{name: name, parent: parent, attrs: make(map[string]interface{})}
+	t.mu.Lock()
+	t.spans = append(t.spans, s)
+	t.mu.Unlock()
+	return context.WithValue(ctx, spanKey{}, s), s
+}
+
+func (t *testTracer) find(name string) *testSpan {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, s := range t.spans {
+		if s.name == name {
+			return s
+		}
+	}
+	return nil
+}
+
+func TestSetTracer(t *testing.T) {
+	tracer := &testTracer{}
+	SetTracer(tracer)
+	defer SetTracer(nil)
+
+	src := &MemorySource{Files: map[string][]byte{
+		"de/default.po": []byte("msgid \"Hello\"\nmsgstr \"Hallo\"\n\nmsgctxt \"menu\"\nmsgid \"Open\"\nmsgstr \"Öffnen\"\n"),
+	}}
+	l := NewLocaleWithSource(src, "de")
+	l.AddDomain("default")
+
+	add := tracer.find("gotext.AddDomain")
+	if add == nil || !add.ended || add.attrs["lang"] != "de" || add.attrs["domain"] != "default" {
+		t.Fatalf("Unexpected AddDomain span %+v", add)
+	}
+	parse := tracer.find("gotext.ParseCatalog")
+	if parse == nil || parse.parent != add || !parse.ended {
+		t.Fatalf("Unexpected ParseCatalog span %+v", parse)
+	}
+	if parse.attrs["file"] != "de/default.po" || parse.attrs["format"] != "po" || parse.attrs["entries"] != 2 {
+		t.Errorf("Unexpected attributes %v", parse.attrs)
+	}
+
+	po := NewPo()
+	po.ParseFile("fixtures/de/default.po")
+	if s := tracer.find("gotext.ParseFile"); s == nil || s.attrs["file"] != "fixtures/de/default.po" || s.attrs["entries"] == 0 {
+		t.Errorf("Unexpected ParseFile span %+v", s)
+	}
+}
+
+func TestSetTracerRemote(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/de/default.po" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte("msgid \"Hello\"\nmsgstr \"Hallo\"\n"))
+	}))
+	defer srv.Close()
+
+	tracer := &testTracer{}
+	SetTracer(tracer)
+	defer SetTracer(nil)
+
+	l := NewLocaleHTTP(srv.URL, "de", HTTPOptions{})
+	l.AddDomain("default")
+	defer l.StopRefresh()
+
+	var found *testSpan
+	for _, s := range tracer.spans {
+		if s.name == "gotext.FetchCatalog" && s.attrs["status"] == http.StatusOK {
+			found = s
+		}
+	}
+	if found == nil || found.attrs["entries"] != 1 || found.err != nil {
+		t.Errorf("Expected a successful FetchCatalog span, got %+v", tracer.spans)
+	}
+}
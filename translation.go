@@ -12,6 +12,14 @@ type Translation struct {
 	Trs      map[int]string
 	Refs     []string
 
+	// Fuzzy flag (#, fuzzy) of PO files, set on translations that need review
+	Fuzzy bool
+
+	// Position of the entry in the PO catalog it was parsed from: the file name, when known,
+	// and the line of its msgctxt or msgid, starting at 1. Line is 0 for entries not parsed from a PO catalog.
+	File string
+	Line int
+
 	dirty bool
 }
 
@@ -33,6 +41,16 @@ func (t *Translation) IsStale() bool {
 	return t.dirty == false
 }
 
+// IsTranslated reports whether any form of the translation has a non-empty string
+func (t *Translation) IsTranslated() bool {
+	for _, str := range t.Trs {
+		if str != "" {
+			return true
+		}
+	}
+	return false
+}
+
 func (t *Translation) SetRefs(refs []string) {
 	t.Refs = refs
 	t.dirty = true
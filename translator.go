@@ -9,6 +9,8 @@ import (
 	"errors"
 	"io/ioutil"
 	"os"
+
+	"golang.org/x/text/language"
 )
 
 // Translator interface is used by Locale and Po objects.Translator
@@ -56,6 +58,7 @@ func (te *TranslatorEncoding) GetTranslator() Translator {
 	po.domain = NewDomain()
 	po.domain.Headers = te.Headers
 	po.domain.Language = te.Language
+	po.domain.tag = language.Make(te.Language)
 	po.domain.PluralForms = te.PluralForms
 	po.domain.nplurals = te.Nplurals
 	po.domain.plural = te.Plural
@@ -78,5 +81,9 @@ func getFileData(f string) ([]byte, error) {
 		return nil, errors.New("cannot parse a directory")
 	}
 
-	return ioutil.ReadFile(f)
+	data, err := ioutil.ReadFile(f)
+	if err != nil {
+		return nil, err
+	}
+	return decompress(data)
 }
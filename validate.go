@@ -0,0 +1,177 @@
+package gotext
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Issue is a problem found by ValidateDomain in a translation.
+type Issue struct {
+	Context string
+	MsgID   string
+
+	// Index of the msgstr with the problem (0 for singular entries)
+	Index       int
+	Translation string
+
+	Problem string
+}
+
+func (i Issue) String() string {
+	key := fmt.Sprintf("msgid %q", i.MsgID)
+	if i.Context != "" {
+		key = fmt.Sprintf("msgctxt %q %s", i.Context, key)
+	}
+	return fmt.Sprintf("%s msgstr[%d] %q: %s", key, i.Index, i.Translation, i.Problem)
+}
+
+/*
+ValidateDomain checks that every translation in the domain uses the same fmt placeholders as its source string,
+which is the main cause of broken output (like "%!d(string=...)" or "%!(EXTRA ...)") after translators edit a catalog.
+Placeholders are matched by the argument they use, so translations can reorder them with explicit indexes (%[2]s).
+It reports:
+
+  - Arguments used by the translation that the source string doesn't use, and the opposite.
+  - Arguments formatted with incompatible verbs (%d vs %s). The %v verb is compatible with any other.
+  - Escaped percent signs (%%) used by the translation but not by the source string, and the opposite.
+
+Every plural form is checked against msgid_plural, and they may omit arguments
+(a singular form like "one file" doesn't need the count).
+Untranslated entries are skipped. Issues are sorted by context, msgid and msgstr index.
+*/
+func ValidateDomain(do *Domain) []Issue {
+	var issues []Issue
+
+	do.Iterate(func(ctx, msgid string, tr *Translation) bool {
+		source := msgid
+		if tr.PluralID != "" {
+			source = tr.PluralID
+		}
+		want := formatArgs(source)
+
+		for i := 0; i < len(tr.Trs); i++ {
+			str, ok := tr.Trs[i]
+			if !ok || str == "" {
+				continue
+			}
+			problems := compareFormatArgs(want, formatArgs(str), tr.PluralID != "")
+			if problem := comparePercents(source, str); problem != "" {
+				problems = append(problems, problem)
+			}
+			for _, problem := range problems {
+				issues = append(issues, Issue{Context: ctx, MsgID: msgid, Index: i, Translation: str, Problem: problem})
+			}
+		}
+		return true
+	})
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		a, b := issues[i], issues[j]
+		if a.Context != b.Context {
+			return a.Context < b.Context
+		}
+		if a.MsgID != b.MsgID {
+			return a.MsgID < b.MsgID
+		}
+		return a.Index < b.Index
+	})
+
+	return issues
+}
+
+// compareFormatArgs returns the differences between the arguments used by a source string and a translation.
+// Missing arguments are allowed for plural forms.
+func compareFormatArgs(want, got map[string]byte, plural bool) []string {
+	var problems []string
+
+	keys := make([]string, 0, len(want)+len(got))
+	for k := range want {
+		keys = append(keys, k)
+	}
+	for k := range got {
+		if _, ok := want[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		w, inWant := want[k]
+		g, inGot := got[k]
+		switch {
+		case !inWant:
+			problems = append(problems, fmt.Sprintf("%s argument isn't in the source string", argName(k)))
+		case !inGot && !plural:
+			problems = append(problems, fmt.Sprintf("%s argument (%%%c) is missing", argName(k), w))
+		case inGot && w != g && w != 'v' && g != 'v':
+			problems = append(problems, fmt.Sprintf("%s argument uses %%%c instead of %%%c", argName(k), g, w))
+		}
+	}
+
+	return problems
+}
+
+// comparePercents returns how the escaped percent signs (%%) of a translation differ from the ones of its source string.
+func comparePercents(source, str string) string {
+	switch want, got := strings.Contains(source, "%%"), strings.Contains(str, "%%"); {
+	case want && !got:
+		return "percent signs aren't escaped (%%) like in the source string"
+	case got && !want:
+		return "percent signs are escaped (%%) but not in the source string"
+	}
+	return ""
+}
+
+// argName describes an argument key returned by formatArgs.
+func argName(k string) string {
+	if strings.HasPrefix(k, "(") {
+		return "named " + k
+	}
+	return "#" + k
+}
+
+// formatArgs returns the verb used for each argument of the format string s, keyed by argument number (starting at 1),
+// following the fmt rules for implicit and explicit (%[n]) indexes. Named %(name)s placeholders are keyed by "(name)".
+// Arguments used by '*' width or precision are reported with the 'd' verb.
+func formatArgs(s string) map[string]byte {
+	args := make(map[string]byte)
+	set := func(n int, verb byte) {
+		k := strconv.Itoa(n)
+		if _, ok := args[k]; !ok {
+			args[k] = verb
+		}
+	}
+
+	next := 1
+	for _, v := range verbRe.FindAllString(s, -1) {
+		if v == "%%" {
+			continue
+		}
+		verb := v[len(v)-1]
+
+		if strings.HasPrefix(v, "%(") {
+			args[v[1:strings.Index(v, ")")+1]] = verb
+			continue
+		}
+
+		for i := 1; i < len(v)-1; i++ {
+			switch v[i] {
+			case '[':
+				end := strings.IndexByte(v[i:], ']')
+				if n, err := strconv.Atoi(v[i+1 : i+end]); err == nil {
+					next = n
+				}
+				i += end
+			case '*':
+				set(next, 'd')
+				next++
+			}
+		}
+		set(next, verb)
+		next++
+	}
+
+	return args
+}
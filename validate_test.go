@@ -0,0 +1,85 @@
+package gotext
+
+import (
+	"testing"
+)
+
+func TestFormatArgs(t *testing.T) {
+	tests := map[string]map[string]byte{
+		"%s has %d items":       {"1": 's', "2": 'd'},
+		"%[2]d items for %[1]s": {"1": 's', "2": 'd'},
+		"100%% of %-5.2f":       {"1": 'f'},
+		"%*d":                   {"1": 'd', "2": 'd'},
+		"%(name)s is %(age)d":   {"(name)": 's', "(age)": 'd'},
+		"no placeholders":       {},
+		"%[1]s and again %[1]q": {"1": 's'},
+	}
+	for s, expected := range tests {
+		got := formatArgs(s)
+		if len(got) != len(expected) {
+			t.Errorf("%q: expected %v but got %v", s, expected, got)
+			continue
+		}
+		for k, v := range expected {
+			if got[k] != v {
+				t.Errorf("%q: expected %c for %s but got %c", s, v, k, got[k])
+			}
+		}
+	}
+}
+
+func TestValidateDomain(t *testing.T) {
+	po := NewPo()
+	po.Parse([]byte(`
+msgid "%s has %d items"
+msgstr "%[2]d Elemente hat %[1]s"
+
+msgid "Hello %s"
+msgstr "Hallo %d"
+
+msgid "Total: %d"
+msgstr "Summe"
+
+msgid "Welcome"
+msgstr "Willkommen %s"
+
+msgid "Value %d"
+msgstr "Wert %v"
+
+msgid "Untranslated %s"
+msgstr ""
+
+msgctxt "cart"
+msgid "%d file"
+msgid_plural "%d files"
+msgstr[0] "eine Datei"
+msgstr[1] "%d Dateien"
+msgstr[2] "%s Dateien"
+`))
+
+	issues := ValidateDomain(po.GetDomain())
+	expected := []struct {
+		msgid string
+		index int
+	}{
+		{"Hello %s", 0},
+		{"Total: %d", 0},
+		{"Welcome", 0},
+		{"%d file", 2},
+	}
+
+	if len(issues) != len(expected) {
+		t.Fatalf("Expected %d issues but got %v", len(expected), issues)
+	}
+	for i, e := range expected {
+		if issues[i].MsgID != e.msgid || issues[i].Index != e.index {
+			t.Errorf("Expected issue for %q msgstr[%d] but got %v", e.msgid, e.index, issues[i])
+		}
+	}
+	if s := issues[0].String(); s != `msgid "Hello %s" msgstr[0] "Hallo %d": #1 argument uses %d instead of %s` {
+		t.Errorf("Unexpected issue description '%s'", s)
+	}
+	if issues[3].Context != "cart" {
+		t.Errorf("Expected the issue in context 'cart' but got %v", issues[3])
+	}
+}
@@ -0,0 +1,122 @@
+package gotext
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"golang.org/x/text/feature/plural"
+	"golang.org/x/text/language"
+)
+
+// webBundle is the layout of ExportForWeb.
+type webBundle struct {
+	Lang    string                            `json:"lang"`
+	Domains map[string]map[string]interface{} `json:"domains"`
+}
+
+/*
+ExportForWeb returns the translated messages of the given domains, or of every domain when none is given,
+as a compact JSON bundle for browsers, so client side rendering uses the same catalogs as the server:
+
+	{
+	  "lang": "es",
+	  "domains": {
+	    "default": {
+	      "Hello": "Hola",
+	      "%d file": {"one": "%d archivo", "other": "%d archivos"},
+	      "menu\u0004Open": "Abrir"
+	    }
+	  }
+	}
+
+Messages are keyed by msgid, with their context and EotSeparator in front when they have one.
+Plural messages map the CLDR plural categories to their msgstr, so clients choose them with
+Intl.PluralRules instead of evaluating the Plural-Forms expression. Untranslated entries are left out,
+as clients fall back to the msgid anyway.
+*/
+func (l *Locale) ExportForWeb(domains ...string) ([]byte, error) {
+	l.RLock()
+	defer l.RUnlock()
+
+	if len(domains) == 0 {
+		for dom := range l.Domains {
+			domains = append(domains, dom)
+		}
+		sort.Strings(domains)
+	}
+
+	bundle := webBundle{Lang: l.lang, Domains: make(map[string]map[string]interface{}, len(domains))}
+	for _, dom := range domains {
+		tr := l.Domains[dom]
+		if tr == nil {
+			return nil, fmt.Errorf("gotext: no catalog found for domain %q", dom)
+		}
+
+		do, err := messageDomain(tr)
+		if err != nil {
+			return nil, err
+		}
+		bundle.Domains[dom] = do.webMessages(l.tag)
+	}
+
+	return json.Marshal(bundle)
+}
+
+// webMessages returns the translated messages of the Domain in the ExportForWeb layout.
+// tag is the language used for the plural categories when the Domain has none.
+func (do *Domain) webMessages(tag language.Tag) map[string]interface{} {
+	do.trMutex.RLock()
+	if do.tag != language.Und {
+		tag = do.tag
+	}
+	do.trMutex.RUnlock()
+	categories := do.webCategories(tag)
+
+	msgs := make(map[string]interface{})
+	for _, e := range do.entries() {
+		tr := e.Translation
+		if !tr.IsTranslated() {
+			continue
+		}
+
+		key := CatalogKey{Context: e.Context, MsgID: e.MsgID}.String()
+		if tr.PluralID == "" {
+			msgs[key] = tr.Get()
+			continue
+		}
+		forms := make(map[string]string, len(categories))
+		for cat, idx := range categories {
+			forms[cat] = tr.GetN(idx)
+		}
+		msgs[key] = forms
+	}
+	return msgs
+}
+
+// webCategories maps the CLDR cardinal categories of tag to the msgstr indexes the plural rule of the Domain
+// chooses for them, evaluating it on a number of each category. "other" is always mapped, as clients
+// use it for decimals, to the last form when the language only uses it for them.
+func (do *Domain) webCategories(tag language.Tag) map[string]int {
+	forms := cardinalForms(tag)
+	categories := make(map[string]int, len(forms)+1)
+	for _, form := range forms {
+		for n := 0; n < 1000; n++ {
+			if plural.Cardinal.MatchPlural(tag, n, 0, 0, 0, 0) == form {
+				categories[cldrCategory(form)] = do.pluralForm(n)
+				break
+			}
+		}
+	}
+
+	if _, ok := categories["other"]; !ok {
+		last := 0
+		for _, idx := range categories {
+			if idx > last {
+				last = idx
+			}
+		}
+		categories["other"] = last
+	}
+	return categories
+}
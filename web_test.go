@@ -0,0 +1,89 @@
+package gotext
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLocaleExportForWeb(t *testing.T) {
+	l := NewLocale("", "es")
+	if err := l.AddDomainBytes("default", []byte(lookupPo), FormatPO); err != nil {
+		t.Fatal(err)
+	}
+	ru := `msgid ""
+msgstr ""
+"Language: ru\n"
+"Plural-Forms: nplurals=3; plural=(n%10==1 && n%100!=11 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2);\n"
+
+msgid "%d day"
+msgid_plural "%d days"
+msgstr[0] "%d день"
+msgstr[1] "%d дня"
+msgstr[2] "%d дней"
+`
+	if err := l.AddDomainBytes("ru", []byte(ru), FormatPO); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := l.ExportForWeb()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var bundle struct {
+		Lang    string
+		Domains map[string]map[string]interface{}
+	}
+	if err := json.Unmarshal(data, &bundle); err != nil {
+		t.Fatal(err)
+	}
+
+	if bundle.Lang != "es" {
+		t.Errorf("unexpected language %q", bundle.Lang)
+	}
+	want := map[string]interface{}{
+		"Hello":        "Hola",
+		"OK":           "OK",
+		"%d file":      map[string]interface{}{"one": "%d archivo", "other": "%d archivos"},
+		"menu\x04Open": "Abrir",
+	}
+	if got := bundle.Domains["default"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected messages %v", got)
+	}
+
+	// Categories follow the language of each catalog, and "other" is always there for decimals
+	want = map[string]interface{}{
+		"%d day": map[string]interface{}{"one": "%d день", "few": "%d дня", "many": "%d дней", "other": "%d дней"},
+	}
+	if got := bundle.Domains["ru"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected messages %v", got)
+	}
+
+	data, err = l.ExportForWeb("ru")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "Hola") {
+		t.Error("only the requested domains should be exported")
+	}
+
+	if _, err := l.ExportForWeb("unknown"); err == nil {
+		t.Error("expected an error for an unknown domain")
+	}
+}
+
+func TestLocaleExportForWebIndexedMo(t *testing.T) {
+	l := NewLocale("fixtures/", "en_US")
+	l.SetMoOnDemand(true, 8)
+	l.SetExtensions("mo")
+	l.AddDomain("default")
+
+	data, err := l.ExportForWeb()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), translatedText) {
+		t.Errorf("expected the messages of the file, got %s", data)
+	}
+}
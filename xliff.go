@@ -0,0 +1,229 @@
+package gotext
+
+import (
+	"encoding/xml"
+	"io"
+	"strconv"
+)
+
+const (
+	xliffNamespace = "urn:oasis:names:tc:xliff:document:1.2"
+
+	// xliffPlurals is the restype of the groups holding the forms of a plural message
+	xliffPlurals = "x-gettext-plurals"
+	// xliffContext is the context-type holding the msgctxt of a message
+	xliffContext = "x-gettext-msgctxt"
+)
+
+type xliffDoc struct {
+	XMLName xml.Name    `xml:"xliff"`
+	Xmlns   string      `xml:"xmlns,attr,omitempty"`
+	Version string      `xml:"version,attr"`
+	Files   []xliffFile `xml:"file"`
+}
+
+type xliffFile struct {
+	Original       string       `xml:"original,attr"`
+	SourceLanguage string       `xml:"source-language,attr"`
+	TargetLanguage string       `xml:"target-language,attr,omitempty"`
+	Datatype       string       `xml:"datatype,attr"`
+	Units          []xliffUnit  `xml:"body>trans-unit"`
+	Groups         []xliffGroup `xml:"body>group"`
+}
+
+type xliffGroup struct {
+	ID      string      `xml:"id,attr"`
+	Restype string      `xml:"restype,attr,omitempty"`
+	Units   []xliffUnit `xml:"trans-unit"`
+}
+
+type xliffUnit struct {
+	ID            string              `xml:"id,attr"`
+	Source        string              `xml:"source"`
+	Target        string              `xml:"target,omitempty"`
+	ContextGroups []xliffContextGroup `xml:"context-group"`
+}
+
+type xliffContextGroup struct {
+	Purpose  string             `xml:"purpose,attr,omitempty"`
+	Contexts []xliffContextItem `xml:"context"`
+}
+
+type xliffContextItem struct {
+	Type  string `xml:"context-type,attr"`
+	Value string `xml:",chardata"`
+}
+
+// ExportXLIFF writes the translations of the Domain as an XLIFF 1.2 document, to be translated in CAT tools.
+// It can be loaded back with ImportXLIFF.
+//
+// Sources are taken to be in English. Every message is a trans-unit, with its context and source references
+// in context groups, and plural messages are groups of trans-units, one for each plural form.
+// Untranslated messages have no target.
+func (do *Domain) ExportXLIFF(w io.Writer) error {
+	do.trMutex.RLock()
+	file := xliffFile{
+		Original:       "messages",
+		SourceLanguage: "en",
+		TargetLanguage: do.Language,
+		Datatype:       "plaintext",
+	}
+	nplurals := do.nplurals
+	do.trMutex.RUnlock()
+
+	for i, e := range do.entries() {
+		id := strconv.Itoa(i + 1)
+		tr := e.Translation
+		contexts := xliffContextGroups(e.Context, tr.Refs)
+
+		if tr.PluralID == "" {
+			file.Units = append(file.Units, xliffUnit{ID: id, Source: e.MsgID, Target: tr.Trs[0], ContextGroups: contexts})
+			continue
+		}
+
+		group := xliffGroup{ID: id, Restype: xliffPlurals}
+		for j, str := range msgstrs(tr, nplurals) {
+			unit := xliffUnit{ID: id + "[" + strconv.Itoa(j) + "]", Source: tr.PluralID, Target: str}
+			if j == 0 {
+				unit.Source = e.MsgID
+				unit.ContextGroups = contexts
+			}
+			group.Units = append(group.Units, unit)
+		}
+		file.Groups = append(file.Groups, group)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(xliffDoc{Xmlns: xliffNamespace, Version: "1.2", Files: []xliffFile{file}}); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// xliffContextGroups returns the context groups for the msgctxt and source references of a message.
+func xliffContextGroups(ctx string, refs []string) []xliffContextGroup {
+	var groups []xliffContextGroup
+	if ctx != "" {
+		groups = append(groups, xliffContextGroup{
+			Purpose:  "information",
+			Contexts: []xliffContextItem{{Type: xliffContext, Value: ctx}},
+		})
+	}
+	for _, ref := range refs {
+		path, line := extractPathAndLine(ref)
+		group := xliffContextGroup{
+			Purpose:  "location",
+			Contexts: []xliffContextItem{{Type: "sourcefile", Value: path}},
+		}
+		if line > 0 {
+			group.Contexts = append(group.Contexts, xliffContextItem{Type: "linenumber", Value: strconv.Itoa(line)})
+		}
+		groups = append(groups, group)
+	}
+	return groups
+}
+
+// ImportXLIFF loads translations from an XLIFF 1.2 document in the ExportXLIFF layout,
+// replacing the ones with the same context and msgid.
+// The target language of the first file sets the Domain language when it has none.
+// Inline markup in sources and targets isn't supported.
+//
+// Nothing is loaded when the document can't be read.
+func (do *Domain) ImportXLIFF(r io.Reader) error {
+	var doc xliffDoc
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return err
+	}
+
+	if len(doc.Files) > 0 && doc.Files[0].TargetLanguage != "" && do.GetLanguage() == "" {
+		do.SetLanguage(doc.Files[0].TargetLanguage)
+	}
+
+	do.trMutex.Lock()
+	do.pluralMutex.Lock()
+	defer do.trMutex.Unlock()
+	defer do.pluralMutex.Unlock()
+
+	addUnit := func(unit xliffUnit) {
+		tr := NewTranslation()
+		tr.ID = unit.Source
+		tr.Trs[0] = unit.Target
+		do.addXLIFF(tr, unit.ContextGroups)
+	}
+
+	for _, file := range doc.Files {
+		for _, unit := range file.Units {
+			addUnit(unit)
+		}
+
+		for _, group := range file.Groups {
+			if group.Restype != xliffPlurals {
+				for _, unit := range group.Units {
+					addUnit(unit)
+				}
+				continue
+			}
+			if len(group.Units) == 0 {
+				continue
+			}
+
+			tr := NewTranslation()
+			tr.ID = group.Units[0].Source
+			for i, unit := range group.Units {
+				tr.Trs[i] = unit.Target
+				if i == 1 {
+					tr.PluralID = unit.Source
+				}
+			}
+			if tr.PluralID == "" {
+				tr.PluralID = tr.ID
+			}
+			do.addXLIFF(tr, group.Units[0].ContextGroups)
+		}
+	}
+
+	return nil
+}
+
+// addXLIFF stores tr with the msgctxt and source references found in its context groups.
+// The Domain must be locked.
+func (do *Domain) addXLIFF(tr *Translation, groups []xliffContextGroup) {
+	if tr.ID == "" {
+		return
+	}
+
+	ctx := ""
+	for _, group := range groups {
+		var path, line string
+		for _, c := range group.Contexts {
+			switch c.Type {
+			case xliffContext:
+				ctx = c.Value
+			case "sourcefile":
+				path = c.Value
+			case "linenumber":
+				line = c.Value
+			}
+		}
+		switch {
+		case path != "" && line != "":
+			tr.Refs = append(tr.Refs, path+":"+line)
+		case path != "":
+			tr.Refs = append(tr.Refs, path)
+		}
+	}
+
+	if ctx == "" {
+		do.translations[tr.ID] = tr
+		return
+	}
+	if _, ok := do.contexts[ctx]; !ok {
+		do.contexts[ctx] = make(map[string]*Translation)
+	}
+	do.contexts[ctx][tr.ID] = tr
+}
@@ -0,0 +1,66 @@
+package gotext
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestDomain_ExportXLIFF(t *testing.T) {
+	po := NewPo()
+	po.Parse([]byte(convertPo))
+
+	var buf bytes.Buffer
+	if err := po.GetDomain().ExportXLIFF(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{
+		`<xliff xmlns="urn:oasis:names:tc:xliff:document:1.2" version="1.2">`,
+		`<file original="messages" source-language="en" target-language="es" datatype="plaintext">`,
+		`<source>Untranslated</source>
+      </trans-unit>`,
+		`<group id="1" restype="x-gettext-plurals">
+        <trans-unit id="1[0]">
+          <source>%d file</source>
+          <target>%d archivo</target>`,
+		`<context context-type="linenumber">10</context>`,
+		`<source>Say &lt;hi&gt; &amp; bye</source>`,
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("expected output to contain %s, got:\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestDomain_ImportXLIFF(t *testing.T) {
+	do := NewDomain()
+	err := do.ImportXLIFF(strings.NewReader(`<?xml version="1.0"?>
+<xliff version="1.2">
+  <file original="app" source-language="en" target-language="de" datatype="plaintext">
+    <body>
+      <group id="buttons">
+        <trans-unit id="ok"><source>OK</source><target>Okay</target></trans-unit>
+      </group>
+      <trans-unit id="cancel"><source>Cancel</source></trans-unit>
+    </body>
+  </file>
+</xliff>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := do.Get("OK"); got != "Okay" {
+		t.Errorf("unexpected translation %q", got)
+	}
+	if tr, ok := do.GetTranslation("Cancel"); !ok || tr.IsTranslated() {
+		t.Error("expected an untranslated entry for a unit without target")
+	}
+	if lang := do.GetLanguage(); lang != "de" {
+		t.Errorf("unexpected language %q", lang)
+	}
+
+	if err := do.ImportXLIFF(strings.NewReader(`<xliff><file>`)); err == nil {
+		t.Error("expected an error for a truncated document")
+	}
+}
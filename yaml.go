@@ -0,0 +1,744 @@
+package gotext
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+/*
+YAML parses Rails i18n style YAML locale files and provides all the Translation functions needed,
+so Rails translation files can be used without converting them:
+
+	en:
+	  title: My App
+	  greeting: "Hello %{name}"
+	  users:
+	    count:
+	      one: "%{count} user"
+	      other: "%{count} users"
+
+Nested keys are flattened with dots, without the top level locale key, so the messages above are
+looked up with Get("title") and Get("greeting", "Ana"). Mappings whose keys are CLDR plural categories
+(zero, one, two, few, many, other) are plural entries, looked up with GetN("users.count", "users.count", n, n),
+and categories missing from the file use the "other" value. Sequence items are keyed by their index ("date.day_names.0").
+
+Interpolations (%{name} and %<name>d) are passed as positional vars, numbered by their first appearance,
+except %{count}, which is always the first one.
+
+The top level key used is the one matching the language given to NewYAML (or its base language),
+or the only one in the file, which then sets the language.
+
+The YAML parser supports block mappings and sequences, plain, quoted and block (| and >) scalars,
+flow sequences and comments. Anchors, aliases, tags and flow mappings aren't supported.
+*/
+type YAML struct {
+	domain *Domain
+}
+
+// NewYAML should always be used to instantiate a new YAML object.
+// lang is the language of the resources, which selects the top level key of the file.
+func NewYAML(lang string) *YAML {
+	y := &YAML{domain: NewDomain()}
+	y.domain.setCLDRLanguage(lang)
+
+	return y
+}
+
+func (y *YAML) GetDomain() *Domain {
+	return y.domain
+}
+
+func (y *YAML) Get(str string, vars ...interface{}) string {
+	return printfIndexed(y.domain.Get(str), vars...)
+}
+
+func (y *YAML) GetN(str, plural string, n int, vars ...interface{}) string {
+	return printfIndexed(y.domain.GetN(str, plural, n), vars...)
+}
+
+func (y *YAML) GetC(str, ctx string, vars ...interface{}) string {
+	return printfIndexed(y.domain.GetC(str, ctx), vars...)
+}
+
+func (y *YAML) GetNC(str, plural string, n int, ctx string, vars ...interface{}) string {
+	return printfIndexed(y.domain.GetNC(str, plural, n, ctx), vars...)
+}
+
+func (y *YAML) MarshalBinary() ([]byte, error) {
+	return y.domain.MarshalBinary()
+}
+
+func (y *YAML) UnmarshalBinary(data []byte) error {
+	return y.domain.UnmarshalBinary(data)
+}
+
+func (y *YAML) ParseFile(f string) {
+	data, err := getFileData(f)
+	if err != nil {
+		return
+	}
+
+	y.Parse(data)
+}
+
+// Parse loads the translations specified in the provided byte slice, in the Rails i18n YAML format.
+func (y *YAML) Parse(buf []byte) {
+	y.ParseWithError(buf)
+}
+
+// ParseWithError works like Parse, but returns a *ParseError with the line number of the first YAML syntax error,
+// without loading anything.
+func (y *YAML) ParseWithError(buf []byte) error {
+	p := &yamlParser{lines: strings.Split(strings.Replace(string(buf), "\r\n", "\n", -1), "\n")}
+	root, err := p.parse()
+	if err != nil {
+		return err
+	}
+	if root == nil || root.kind != yamlMapping {
+		return nil
+	}
+
+	lang := y.domain.GetLanguage()
+	top, ok := root.children[lang]
+	if !ok {
+		top, ok = root.children[SimplifiedLocale(lang)]
+	}
+	if !ok && len(lang) > 2 {
+		top, ok = root.children[lang[:2]]
+	}
+	if !ok && len(root.keys) == 1 {
+		top = root.children[root.keys[0]]
+		y.domain.setCLDRLanguage(root.keys[0])
+	}
+	if top == nil {
+		return nil
+	}
+
+	do := y.domain
+	do.trMutex.Lock()
+	do.pluralMutex.Lock()
+	defer do.trMutex.Unlock()
+	defer do.pluralMutex.Unlock()
+
+	do.addYAML("", top)
+	return nil
+}
+
+// addYAML stores the messages of a YAML node, with their keys prefixed by prefix. The Domain must be locked.
+func (do *Domain) addYAML(prefix string, n *yamlNode) {
+	key := func(k string) string {
+		if prefix == "" {
+			return k
+		}
+		return prefix + "." + k
+	}
+
+	switch n.kind {
+	case yamlScalar:
+		if prefix == "" {
+			return
+		}
+		tr := NewTranslation()
+		tr.ID = prefix
+		tr.Set(railsFormat([]string{n.value})[0])
+		do.translations[prefix] = tr
+
+	case yamlSequence:
+		for i, item := range n.items {
+			do.addYAML(key(strconv.Itoa(i)), item)
+		}
+
+	case yamlMapping:
+		if prefix != "" && yamlPlural(n) {
+			forms := cardinalForms(do.tag)
+			strs := make([]string, len(forms))
+			for i, form := range forms {
+				c, ok := n.children[cldrCategory(form)]
+				if !ok {
+					c = n.children["other"]
+				}
+				if c != nil {
+					strs[i] = c.value
+				}
+			}
+
+			tr := NewTranslation()
+			tr.ID = prefix
+			tr.PluralID = prefix
+			for i, str := range railsFormat(strs) {
+				tr.Trs[i] = str
+			}
+			do.translations[prefix] = tr
+			return
+		}
+
+		for _, k := range n.keys {
+			do.addYAML(key(k), n.children[k])
+		}
+	}
+}
+
+// yamlPlural tells whether a mapping is a Rails pluralization: its keys are CLDR categories and its values scalars.
+func yamlPlural(n *yamlNode) bool {
+	if len(n.keys) == 0 {
+		return false
+	}
+	for _, k := range n.keys {
+		if _, ok := cldrCategories[k]; !ok || n.children[k].kind != yamlScalar {
+			return false
+		}
+	}
+	return true
+}
+
+// railsFormat converts the Ruby interpolations of strs (%{name} and %<name>d) to fmt verbs with explicit argument indexes.
+// Arguments are numbered across all the strings, so plural forms share them, with "count" first.
+// Strings without interpolations are returned as is.
+func railsFormat(strs []string) []string {
+	// Number the arguments
+	args := make(map[string]int)
+	names := make([][]string, len(strs))
+	for i, s := range strs {
+		names[i] = railsInterpolations(s)
+		for _, name := range names[i] {
+			if name == "count" {
+				args["count"] = 1
+			}
+		}
+	}
+	for _, ns := range names {
+		for _, name := range ns {
+			if _, ok := args[name]; !ok {
+				args[name] = len(args) + 1
+			}
+		}
+	}
+
+	out := make([]string, len(strs))
+	for i, s := range strs {
+		if len(names[i]) == 0 {
+			out[i] = strings.Replace(s, "%%{", "%{", -1)
+			continue
+		}
+
+		var b strings.Builder
+		for j := 0; j < len(s); j++ {
+			if s[j] != '%' {
+				b.WriteByte(s[j])
+				continue
+			}
+			if strings.HasPrefix(s[j:], "%%") {
+				b.WriteString("%%")
+				j++
+				continue
+			}
+			if name, verb, size := railsInterpolation(s[j:]); size > 0 {
+				// The index goes right before the verb letter, after any flags, width and precision
+				b.WriteString("%" + verb[:len(verb)-1] + "[" + strconv.Itoa(args[name]) + "]" + verb[len(verb)-1:])
+				j += size - 1
+				continue
+			}
+			b.WriteString("%%")
+		}
+		out[i] = b.String()
+	}
+	return out
+}
+
+// railsInterpolations returns the names interpolated in s, in order.
+func railsInterpolations(s string) []string {
+	var names []string
+	for j := 0; j < len(s); j++ {
+		if s[j] != '%' {
+			continue
+		}
+		if strings.HasPrefix(s[j:], "%%") {
+			j++
+			continue
+		}
+		if name, _, size := railsInterpolation(s[j:]); size > 0 {
+			names = append(names, name)
+			j += size - 1
+		}
+	}
+	return names
+}
+
+// railsInterpolation parses the interpolation at the start of s, returning its name, the fmt verb to use and its length,
+// or a zero length if there's none.
+func railsInterpolation(s string) (name, verb string, size int) {
+	if len(s) < 3 {
+		return "", "", 0
+	}
+
+	switch s[1] {
+	case '{':
+		end := strings.IndexByte(s, '}')
+		if end > 2 {
+			return s[2:end], "v", end + 1
+		}
+	case '<':
+		end := strings.IndexByte(s, '>')
+		if end <= 2 {
+			break
+		}
+		// Format spec up to the verb letter, like %<price>.2f
+		i := end + 1
+		for i < len(s) && strings.IndexByte("-+# 0123456789.", s[i]) != -1 {
+			i++
+		}
+		if i < len(s) && (s[i] >= 'a' && s[i] <= 'z' || s[i] >= 'A' && s[i] <= 'Z') {
+			return s[2:end], s[end+1 : i+1], i + 1
+		}
+	}
+	return "", "", 0
+}
+
+// yamlKind is the kind of a yamlNode.
+type yamlKind int
+
+const (
+	yamlScalar yamlKind = iota
+	yamlMapping
+	yamlSequence
+)
+
+// yamlNode is a parsed YAML value.
+type yamlNode struct {
+	kind yamlKind
+
+	// Scalars
+	value string
+
+	// Mappings, with their keys in order
+	keys     []string
+	children map[string]*yamlNode
+
+	// Sequences
+	items []*yamlNode
+}
+
+// yamlParser is a line based parser for the subset of YAML used by locale files.
+type yamlParser struct {
+	lines []string
+	pos   int
+}
+
+func (p *yamlParser) fail(format string, args ...interface{}) error {
+	return &ParseError{Line: p.pos + 1, Msg: fmt.Sprintf(format, args...)}
+}
+
+// parse parses the document, which may be empty.
+func (p *yamlParser) parse() (*yamlNode, error) {
+	if p.next() && strings.HasPrefix(p.lines[p.pos], "---") {
+		p.pos++
+	}
+	n, err := p.parseNode(0)
+	if err != nil {
+		return nil, err
+	}
+	if p.next() && !strings.HasPrefix(p.lines[p.pos], "...") {
+		return nil, p.fail("unexpected content")
+	}
+	return n, nil
+}
+
+// next skips blank and comment lines, telling whether there's a line left.
+func (p *yamlParser) next() bool {
+	for ; p.pos < len(p.lines); p.pos++ {
+		l := strings.TrimSpace(p.lines[p.pos])
+		if l != "" && !strings.HasPrefix(l, "#") {
+			return true
+		}
+	}
+	return false
+}
+
+// indent returns the indentation of the current line.
+func (p *yamlParser) indent() int {
+	return len(p.lines[p.pos]) - len(strings.TrimLeft(p.lines[p.pos], " "))
+}
+
+// parseNode parses the block node starting at the next line, if it's indented at least min spaces.
+// It returns nil if there's none.
+func (p *yamlParser) parseNode(min int) (*yamlNode, error) {
+	if !p.next() || p.indent() < min {
+		return nil, nil
+	}
+
+	ind := p.indent()
+	if l := strings.TrimSpace(p.lines[p.pos]); l == "-" || strings.HasPrefix(l, "- ") {
+		return p.parseSequence(ind)
+	}
+	return p.parseMapping(ind)
+}
+
+// parseMapping parses the entries of a block mapping indented ind spaces.
+func (p *yamlParser) parseMapping(ind int) (*yamlNode, error) {
+	n := &yamlNode{kind: yamlMapping, children: make(map[string]*yamlNode)}
+
+	for p.next() && p.indent() == ind {
+		line := strings.TrimSpace(p.lines[p.pos])
+		if line == "-" || strings.HasPrefix(line, "- ") {
+			return nil, p.fail("unexpected sequence item")
+		}
+
+		key, rest, err := yamlKey(line)
+		if err != nil {
+			return nil, p.fail("%s", err)
+		}
+		if _, ok := n.children[key]; ok {
+			return nil, p.fail("duplicate key %q", key)
+		}
+
+		value, err := p.parseValue(ind, rest)
+		if err != nil {
+			return nil, err
+		}
+		n.keys = append(n.keys, key)
+		n.children[key] = value
+	}
+
+	if p.next() && p.indent() > ind {
+		return nil, p.fail("bad indentation")
+	}
+	return n, nil
+}
+
+// parseSequence parses the items of a block sequence indented ind spaces.
+func (p *yamlParser) parseSequence(ind int) (*yamlNode, error) {
+	n := &yamlNode{kind: yamlSequence}
+
+	for p.next() && p.indent() == ind {
+		line := strings.TrimSpace(p.lines[p.pos])
+		if line != "-" && !strings.HasPrefix(line, "- ") {
+			break
+		}
+		content := strings.TrimSpace(line[1:])
+
+		isMapping := false
+		if content != "" && strings.IndexByte(`"'[|>`, content[0]) == -1 {
+			_, _, kerr := yamlKey(content)
+			isMapping = kerr == nil
+		}
+
+		var item *yamlNode
+		var err error
+		if isMapping {
+			// Mapping item: parse it as if the dash was indentation
+			p.lines[p.pos] = strings.Repeat(" ", ind+2) + content
+			item, err = p.parseMapping(ind + 2)
+		} else {
+			item, err = p.parseValue(ind, content)
+		}
+		if err != nil {
+			return nil, err
+		}
+		n.items = append(n.items, item)
+	}
+
+	return n, nil
+}
+
+// parseValue parses the value of a mapping entry or sequence item indented ind spaces,
+// where rest is the text after the key or dash, and moves past it.
+func (p *yamlParser) parseValue(ind int, rest string) (*yamlNode, error) {
+	rest = strings.TrimSpace(rest)
+	if strings.HasPrefix(rest, "&") || strings.HasPrefix(rest, "*") || strings.HasPrefix(rest, "!") {
+		return nil, p.fail("anchors, aliases and tags aren't supported")
+	}
+
+	switch {
+	case rest == "" || strings.HasPrefix(rest, "#"):
+		p.pos++
+		// Sequences can have the same indentation as their key
+		if p.next() && p.indent() == ind {
+			if l := strings.TrimSpace(p.lines[p.pos]); l == "-" || strings.HasPrefix(l, "- ") {
+				return p.parseSequence(ind)
+			}
+		}
+		child, err := p.parseNode(ind + 1)
+		if err != nil || child != nil {
+			return child, err
+		}
+		return &yamlNode{kind: yamlScalar}, nil
+
+	case rest[0] == '|' || rest[0] == '>':
+		return p.parseBlockScalar(ind, rest)
+
+	case rest[0] == '"' || rest[0] == '\'':
+		return p.parseQuoted(ind, rest)
+
+	case rest[0] == '[':
+		return p.parseFlowSequence(rest)
+
+	case rest[0] == '{':
+		return nil, p.fail("flow mappings aren't supported")
+	}
+
+	// Plain scalar, which may continue on more indented lines
+	value := stripYAMLComment(rest)
+	for p.pos++; p.pos < len(p.lines); p.pos++ {
+		l := p.lines[p.pos]
+		trimmed := strings.TrimSpace(l)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") || len(l)-len(strings.TrimLeft(l, " ")) <= ind {
+			break
+		}
+		if _, _, err := yamlKey(trimmed); err == nil {
+			return nil, p.fail("unexpected mapping key in a plain scalar")
+		}
+		value += " " + stripYAMLComment(trimmed)
+	}
+	if yamlNull(value) {
+		value = ""
+	}
+	return &yamlNode{kind: yamlScalar, value: value}, nil
+}
+
+// parseBlockScalar parses a literal (|) or folded (>) block scalar, where header is its indicator line.
+func (p *yamlParser) parseBlockScalar(ind int, header string) (*yamlNode, error) {
+	header = stripYAMLComment(header)
+	folded := header[0] == '>'
+	chomp := ""
+	for _, c := range header[1:] {
+		switch {
+		case c == '-' || c == '+':
+			chomp = string(c)
+		case c >= '1' && c <= '9':
+		default:
+			return nil, p.fail("invalid block scalar header %q", header)
+		}
+	}
+
+	var lines []string
+	blockInd := -1
+	for p.pos++; p.pos < len(p.lines); p.pos++ {
+		l := p.lines[p.pos]
+		if strings.TrimSpace(l) == "" {
+			lines = append(lines, "")
+			continue
+		}
+		lineInd := len(l) - len(strings.TrimLeft(l, " "))
+		if blockInd == -1 {
+			blockInd = lineInd
+		}
+		if lineInd <= ind || lineInd < blockInd {
+			break
+		}
+		lines = append(lines, l[blockInd:])
+	}
+
+	// Trailing blank lines only matter for chomping
+	trailing := 0
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+		trailing++
+	}
+
+	var value string
+	if folded {
+		var b strings.Builder
+		for i, l := range lines {
+			switch {
+			case i == 0, lines[i-1] == "":
+			case l == "" || strings.HasPrefix(l, " ") || strings.HasPrefix(lines[i-1], " "):
+				b.WriteString("\n")
+			default:
+				b.WriteString(" ")
+			}
+			b.WriteString(l)
+		}
+		value = b.String()
+	} else {
+		value = strings.Join(lines, "\n")
+	}
+
+	switch {
+	case len(lines) == 0:
+	case chomp == "+":
+		value += "\n" + strings.Repeat("\n", trailing)
+	case chomp == "":
+		value += "\n"
+	}
+	return &yamlNode{kind: yamlScalar, value: value}, nil
+}
+
+// parseQuoted parses a single or double quoted scalar starting with rest, which may span several lines.
+func (p *yamlParser) parseQuoted(ind int, rest string) (*yamlNode, error) {
+	quote := rest[0]
+	text := rest
+	start := p.pos
+
+	for {
+		if end := yamlQuoteEnd(text, quote); end != -1 {
+			if tail := strings.TrimSpace(text[end+1:]); tail != "" && !strings.HasPrefix(tail, "#") {
+				return nil, p.fail("unexpected text after quoted string")
+			}
+			p.pos++
+			value, err := unquoteYAML(text[1:end], quote)
+			if err != nil {
+				return nil, p.fail("%s", err)
+			}
+			return &yamlNode{kind: yamlScalar, value: value}, nil
+		}
+
+		// Multi-line quoted scalars are folded
+		p.pos++
+		if p.pos >= len(p.lines) {
+			p.pos = start
+			return nil, p.fail("unterminated quoted string")
+		}
+		if l := strings.TrimSpace(p.lines[p.pos]); l == "" {
+			text += "\n"
+		} else if strings.HasSuffix(text, "\n") {
+			text += l
+		} else {
+			text += " " + l
+		}
+	}
+}
+
+// yamlQuoteEnd returns the index of the quote closing the quoted string s, or -1 if it isn't closed.
+func yamlQuoteEnd(s string, quote byte) int {
+	for i := 1; i < len(s); i++ {
+		switch {
+		case quote == '"' && s[i] == '\\':
+			i++
+		case quote == '\'' && s[i] == '\'' && i+1 < len(s) && s[i+1] == '\'':
+			i++
+		case s[i] == quote:
+			return i
+		}
+	}
+	return -1
+}
+
+// unquoteYAML resolves the escapes of the content of a quoted scalar.
+func unquoteYAML(s string, quote byte) (string, error) {
+	if quote == '\'' {
+		return strings.Replace(s, "''", "'", -1), nil
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		if i == len(s) {
+			return "", errors.New("invalid escape at end of string")
+		}
+		switch c := s[i]; c {
+		case 'n':
+			b.WriteByte('\n')
+		case 't':
+			b.WriteByte('\t')
+		case 'r':
+			b.WriteByte('\r')
+		case '0':
+			b.WriteByte(0)
+		case ' ', '"', '\\', '/':
+			b.WriteByte(c)
+		case 'x', 'u', 'U':
+			size := map[byte]int{'x': 2, 'u': 4, 'U': 8}[c]
+			if i+size >= len(s) {
+				return "", fmt.Errorf("invalid escape \\%c", c)
+			}
+			r, err := strconv.ParseUint(s[i+1:i+1+size], 16, 32)
+			if err != nil {
+				return "", fmt.Errorf("invalid escape \\%c", c)
+			}
+			b.WriteRune(rune(r))
+			i += size
+		default:
+			return "", fmt.Errorf("invalid escape \\%c", c)
+		}
+	}
+	return b.String(), nil
+}
+
+// parseFlowSequence parses a single line flow sequence of scalars, like [Sun, Mon, "Tue"].
+func (p *yamlParser) parseFlowSequence(rest string) (*yamlNode, error) {
+	rest = stripYAMLComment(rest)
+	if !strings.HasSuffix(rest, "]") {
+		return nil, p.fail("unterminated flow sequence")
+	}
+
+	n := &yamlNode{kind: yamlSequence}
+	body := strings.TrimSpace(rest[1 : len(rest)-1])
+	for body != "" {
+		var item string
+		if body[0] == '"' || body[0] == '\'' {
+			end := yamlQuoteEnd(body, body[0])
+			if end == -1 {
+				return nil, p.fail("unterminated quoted string")
+			}
+			value, err := unquoteYAML(body[1:end], body[0])
+			if err != nil {
+				return nil, p.fail("%s", err)
+			}
+			item = value
+			body = strings.TrimSpace(body[end+1:])
+		} else {
+			end := strings.IndexByte(body, ',')
+			if end == -1 {
+				end = len(body)
+			}
+			item = strings.TrimSpace(body[:end])
+			body = body[end:]
+		}
+
+		if yamlNull(item) {
+			item = ""
+		}
+		n.items = append(n.items, &yamlNode{kind: yamlScalar, value: item})
+
+		if body != "" {
+			if body[0] != ',' {
+				return nil, p.fail("expected ',' in flow sequence")
+			}
+			body = strings.TrimSpace(body[1:])
+		}
+	}
+
+	p.pos++
+	return n, nil
+}
+
+// yamlKey splits a mapping entry line into its key and the text after the colon.
+func yamlKey(line string) (key, rest string, err error) {
+	if line[0] == '"' || line[0] == '\'' {
+		end := yamlQuoteEnd(line, line[0])
+		if end == -1 || !strings.HasPrefix(line[end+1:], ":") {
+			return "", "", errors.New("invalid quoted key")
+		}
+		key, err = unquoteYAML(line[1:end], line[0])
+		return key, line[end+2:], err
+	}
+
+	for i := 0; i < len(line); i++ {
+		if line[i] == ':' && (i+1 == len(line) || line[i+1] == ' ') {
+			return strings.TrimSpace(line[:i]), line[i+1:], nil
+		}
+		if line[i] == '#' && i > 0 && line[i-1] == ' ' {
+			break
+		}
+	}
+	return "", "", errors.New("expected a mapping key")
+}
+
+// stripYAMLComment removes the comment at the end of a plain scalar, and its trailing spaces.
+func stripYAMLComment(s string) string {
+	if i := strings.Index(s, " #"); i != -1 {
+		s = s[:i]
+	}
+	return strings.TrimSpace(s)
+}
+
+// yamlNull tells whether a plain scalar means null, which is loaded as an empty string.
+func yamlNull(s string) bool {
+	return s == "~" || s == "null" || s == "Null" || s == "NULL"
+}
@@ -0,0 +1,147 @@
+package gotext
+
+import (
+	"testing"
+)
+
+const yamlFile = `---
+# Rails locale file
+ru:
+  title: My App  # trailing comment
+  greeting: "Привет, %{name}!"
+  price: 'It''s %<amount>.2f %{currency}'
+  percent: 100% sure
+  escaped: "%%{not_interpolated}"
+  "quoted key": Quoted
+  wrapped: This plain text
+    continues here
+  literal: |
+    Line one
+    Line two
+  folded: >-
+    Folded
+    text
+
+    New paragraph
+  escapes: "Tab\there é"
+  nothing: ~
+  users:
+    title: Users
+    count:
+      one: "%{count} пользователь"
+      few: "%{count} пользователя"
+      many: "%{count} пользователей"
+      other: "%{count} пользователя"
+  files:
+    one: "%{count} файл в %{folder}"
+    other: "%{count} файлов в %{folder}"
+  date:
+    abbr_day_names: [Вс, Пн, "Вт"]
+    month_names:
+    - ~
+    - Январь
+    - Февраль
+  list:
+    - name: First
+    - name: Second
+en:
+  title: Other language
+`
+
+func TestYAML(t *testing.T) {
+	y := NewYAML("ru_RU")
+	if err := y.ParseWithError([]byte(yamlFile)); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, c := range []struct {
+		key  string
+		vars []interface{}
+		want string
+	}{
+		{"title", nil, "My App"},
+		{"greeting", []interface{}{"Аня"}, "Привет, Аня!"},
+		{"price", []interface{}{9.5, "EUR"}, "It's 9.50 EUR"},
+		{"percent", nil, "100% sure"},
+		{"escaped", nil, "%{not_interpolated}"},
+		{"quoted key", nil, "Quoted"},
+		{"wrapped", nil, "This plain text continues here"},
+		{"literal", nil, "Line one\nLine two\n"},
+		{"folded", nil, "Folded text\nNew paragraph"},
+		{"escapes", nil, "Tab\there é"},
+		{"users.title", nil, "Users"},
+		{"date.abbr_day_names.2", nil, "Вт"},
+		{"date.month_names.1", nil, "Январь"},
+		{"list.1.name", nil, "Second"},
+	} {
+		if got := y.Get(c.key, c.vars...); got != c.want {
+			t.Errorf("Get(%q) = %q, want %q", c.key, got, c.want)
+		}
+	}
+
+	if tr, ok := y.GetDomain().GetTranslation("nothing"); !ok || tr.Trs[0] != "" {
+		t.Error("expected an empty translation for a null value")
+	}
+
+	for n, want := range map[int]string{1: "1 пользователь", 3: "3 пользователя", 5: "5 пользователей"} {
+		if got := y.GetN("users.count", "users.count", n, n); got != want {
+			t.Errorf("GetN(users.count, %d) = %q, want %q", n, got, want)
+		}
+	}
+
+	// Missing categories use other, and count is always the first argument
+	if got := y.GetN("files", "files", 3, 3, "docs"); got != "3 файлов в docs" {
+		t.Errorf("unexpected plural %q", got)
+	}
+}
+
+func TestYAMLSingleLocale(t *testing.T) {
+	y := NewYAML("en")
+	y.Parse([]byte("pt-BR:\n  hello: Olá\n"))
+
+	if got := y.Get("hello"); got != "Olá" {
+		t.Errorf("unexpected translation %q", got)
+	}
+	if lang := y.GetDomain().GetLanguage(); lang != "pt-BR" {
+		t.Errorf("unexpected language %q", lang)
+	}
+}
+
+func TestYAMLErrors(t *testing.T) {
+	for src, line := range map[string]int{
+		"en:\n  a: b\n    c: d\n":    3,
+		"en:\n  a: \"unterminated\n": 2,
+		"en:\n  a: b\n  a: c\n":      3,
+		"en:\n  a: *alias\n":         2,
+		"en:\n  a: {b: c}\n":         2,
+		"en:\n  just text\n":         2,
+	} {
+		y := NewYAML("en")
+		err := y.ParseWithError([]byte(src))
+		perr, ok := err.(*ParseError)
+		if !ok {
+			t.Errorf("%q: expected a *ParseError, got %v", src, err)
+			continue
+		}
+		if perr.Line != line {
+			t.Errorf("%q: error on line %d, want %d: %v", src, perr.Line, line, perr)
+		}
+	}
+}
+
+func TestYAMLLocale(t *testing.T) {
+	src := &MemorySource{Files: map[string][]byte{
+		"ru/app.yml": []byte(yamlFile),
+	}}
+
+	l := NewLocaleWithSource(src, "ru")
+	l.AddDomain("app")
+
+	if got := l.GetND("app", "users.count", "users.count", 21, 21); got != "21 пользователь" {
+		t.Errorf("unexpected translation %q", got)
+	}
+}
+
+func TestYAMLTranslator(t *testing.T) {
+	var _ Translator = NewYAML("en")
+}
@@ -0,0 +1,949 @@
+package gotext
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"math/bits"
+)
+
+/*
+This file is a minimal zstd decoder (RFC 8878), so .zst catalogs load without dependencies.
+It decodes whole frames in memory, which is all catalogs need, and doesn't support dictionaries.
+*/
+
+const (
+	zstdMagic         = 0xfd2fb528
+	zstdSkippableMask = 0xfffffff0
+	zstdSkippable     = 0x184d2a50
+	zstdMaxBlockSize  = 128 << 10
+)
+
+var errZstdTruncated = errors.New("zstd: truncated data")
+
+// zstdError returns an error about corrupt zstd data.
+func zstdError(msg string) error {
+	return errors.New("zstd: " + msg)
+}
+
+// fseEntry is a state of an FSE decoding table.
+type fseEntry struct {
+	sym  uint8
+	bits uint8
+	base uint16
+}
+
+// seqEntry is a state of a sequence decoding table, with the value of its symbol.
+type seqEntry struct {
+	baseline uint32
+	extra    uint8
+	bits     uint8
+	base     uint16
+}
+
+// The kinds of sequence values, in the order of their compression modes
+const (
+	seqLiteral = iota
+	seqOffset
+	seqMatch
+)
+
+// seqKind describes how the symbols of a kind of sequence value are coded.
+type seqKind struct {
+	maxSym    int
+	maxLog    int
+	predefLog int
+	predef    []int16
+	// Baselines and extra bits of the symbols from the first one not standing for itself
+	first     int
+	baselines []uint32
+	extra     []uint8
+}
+
+var seqKinds = [3]seqKind{
+	seqLiteral: {
+		maxSym: 35, maxLog: 9, predefLog: 6,
+		predef: []int16{4, 3, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 1, 1, 1, 2, 2, 2, 2, 2, 2, 2, 2, 2, 3, 2, 1, 1, 1, 1, 1,
+			-1, -1, -1, -1},
+		first: 16,
+		baselines: []uint32{16, 18, 20, 22, 24, 28, 32, 40, 48, 64, 128, 256, 512, 1024, 2048, 4096, 8192, 16384,
+			32768, 65536},
+		extra: []uint8{1, 1, 1, 1, 2, 2, 3, 3, 4, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16},
+	},
+	seqOffset: {
+		maxSym: 31, maxLog: 8, predefLog: 5,
+		predef: []int16{1, 1, 1, 1, 1, 1, 2, 2, 2, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, -1, -1, -1, -1, -1},
+	},
+	seqMatch: {
+		maxSym: 52, maxLog: 9, predefLog: 6,
+		predef: []int16{1, 4, 3, 2, 2, 2, 2, 2, 2, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1,
+			1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, -1, -1, -1, -1, -1, -1, -1},
+		first: 32,
+		baselines: []uint32{35, 37, 39, 41, 43, 47, 51, 59, 67, 83, 99, 131, 259, 515, 1027, 2051, 4099, 8195, 16387,
+			32771, 65539},
+		extra: []uint8{1, 1, 1, 1, 2, 2, 3, 3, 4, 4, 5, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16},
+	},
+}
+
+// seqTable returns the sequence decoding table of the FSE table of a kind of value.
+func (k *seqKind) seqTable(kind int, table []fseEntry) ([]seqEntry, error) {
+	seqs := make([]seqEntry, len(table))
+	for i, e := range table {
+		if int(e.sym) > k.maxSym {
+			return nil, zstdError("invalid sequence symbol")
+		}
+		s := seqEntry{bits: e.bits, base: e.base}
+		switch {
+		case kind == seqOffset:
+			s.baseline, s.extra = 1<<e.sym, e.sym
+		case int(e.sym) < k.first:
+			s.baseline = uint32(e.sym)
+			if kind == seqMatch {
+				s.baseline += 3
+			}
+		default:
+			s.baseline, s.extra = k.baselines[int(e.sym)-k.first], k.extra[int(e.sym)-k.first]
+		}
+		seqs[i] = s
+	}
+	return seqs, nil
+}
+
+// zstdPredefined are the sequence decoding tables of the predefined distributions
+var zstdPredefined = func() (tables [3][]seqEntry) {
+	for kind := range seqKinds {
+		k := &seqKinds[kind]
+		fse, err := buildFSE(k.predef, k.predefLog)
+		if err == nil {
+			tables[kind], err = k.seqTable(kind, fse)
+		}
+		if err != nil {
+			panic(err)
+		}
+	}
+	return tables
+}()
+
+// bitsAt returns the n bits of data starting at the bit lo, counting from the lowest bit of the first byte.
+// Bits out of data are zeros.
+func bitsAt(data []byte, lo, n int) uint32 {
+	if n == 0 {
+		return 0
+	}
+	var v uint64
+	for i := (lo + n - 1) >> 3; i >= lo>>3; i-- {
+		v <<= 8
+		if i >= 0 && i < len(data) {
+			v |= uint64(data[i])
+		}
+	}
+	return uint32(v>>uint(lo&7)) & (1<<uint(n) - 1)
+}
+
+// reverseBits reads a bitstream backwards, from its last bit to the first one, like zstd writes them.
+type reverseBits struct {
+	data []byte
+	// Number of bits left to read, negative when the stream was overread
+	pos int
+}
+
+func newReverseBits(data []byte) (*reverseBits, error) {
+	if len(data) == 0 || data[len(data)-1] == 0 {
+		return nil, zstdError("invalid bitstream end")
+	}
+	return &reverseBits{data: data, pos: (len(data)-1)*8 + bits.Len8(data[len(data)-1]) - 1}, nil
+}
+
+func (br *reverseBits) peek(n int) uint32 {
+	return bitsAt(br.data, br.pos-n, n)
+}
+
+func (br *reverseBits) read(n int) uint32 {
+	br.pos -= n
+	return bitsAt(br.data, br.pos, n)
+}
+
+// readFSE reads the FSE table description at the start of data, returning the table, its accuracy log
+// and the number of bytes read.
+func readFSE(data []byte, maxSym, maxLog int) ([]fseEntry, int, int, error) {
+	pos := 0
+	peek := func(n int) int {
+		return int(bitsAt(data, pos, n))
+	}
+	read := func(n int) int {
+		pos += n
+		return int(bitsAt(data, pos-n, n))
+	}
+
+	log := read(4) + 5
+	if log > maxLog {
+		return nil, 0, 0, zstdError("FSE accuracy log too large")
+	}
+
+	norm := make([]int16, maxSym+1)
+	remaining := 1<<uint(log) + 1
+	threshold := 1 << uint(log)
+	bitsNeeded := log + 1
+	sym := 0
+	prev0 := false
+
+	for remaining > 1 && sym <= maxSym {
+		if prev0 {
+			// Runs of symbols with a zero probability
+			zsym := sym
+			for peek(12) == 0xfff && pos < len(data)*8 {
+				zsym += 18
+				pos += 12
+			}
+			for peek(2) == 3 {
+				zsym += 3
+				pos += 2
+			}
+			zsym += read(2)
+			if zsym > maxSym {
+				return nil, 0, 0, zstdError("FSE symbol overflow")
+			}
+			sym = zsym
+			prev0 = false
+			continue
+		}
+
+		max := 2*threshold - 1 - remaining
+		count := peek(bitsNeeded - 1)
+		if count < max {
+			pos += bitsNeeded - 1
+		} else {
+			count = peek(bitsNeeded)
+			if count >= threshold {
+				count -= max
+			}
+			pos += bitsNeeded
+		}
+
+		count--
+		if count >= 0 {
+			remaining -= count
+		} else {
+			remaining--
+		}
+		norm[sym] = int16(count)
+		sym++
+		prev0 = count == 0
+
+		for remaining < threshold {
+			bitsNeeded--
+			threshold >>= 1
+		}
+	}
+
+	if remaining != 1 {
+		return nil, 0, 0, zstdError("invalid FSE table")
+	}
+	n := (pos + 7) / 8
+	if n > len(data) {
+		return nil, 0, 0, errZstdTruncated
+	}
+
+	table, err := buildFSE(norm, log)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	return table, log, n, nil
+}
+
+// buildFSE returns the FSE decoding table of the normalized symbol counts norm, where -1 is a low probability.
+func buildFSE(norm []int16, log int) ([]fseEntry, error) {
+	size := 1 << uint(log)
+	table := make([]fseEntry, size)
+	high := size - 1
+
+	next := make([]uint16, len(norm))
+	for sym, n := range norm {
+		if n < 0 {
+			table[high].sym = uint8(sym)
+			high--
+			next[sym] = 1
+		} else {
+			next[sym] = uint16(n)
+		}
+	}
+
+	pos := 0
+	step := size>>1 + size>>3 + 3
+	for sym, n := range norm {
+		for i := 0; i < int(n); i++ {
+			table[pos].sym = uint8(sym)
+			pos = (pos + step) & (size - 1)
+			for pos > high {
+				pos = (pos + step) & (size - 1)
+			}
+		}
+	}
+	if pos != 0 {
+		return nil, zstdError("invalid FSE table")
+	}
+
+	for i := range table {
+		state := next[table[i].sym]
+		next[table[i].sym]++
+		if state == 0 {
+			return nil, zstdError("invalid FSE table")
+		}
+		n := log - (bits.Len16(state) - 1)
+		table[i].bits = uint8(n)
+		table[i].base = state<<uint(n) - uint16(size)
+	}
+	return table, nil
+}
+
+// zstdDecoder holds the output and the state the blocks of a frame share.
+type zstdDecoder struct {
+	out   []byte
+	limit int
+	// Start of the current frame in out
+	start int
+
+	huffman     []uint16
+	huffmanBits int
+	seqTables   [3][]seqEntry
+	seqLogs     [3]int
+	reps        [3]int
+}
+
+// decodeZstd decompresses the zstd frames of data. It fails with errCatalogTooLarge when the content
+// is larger than limit, without decompressing it.
+func decodeZstd(data []byte, limit int) ([]byte, error) {
+	d := &zstdDecoder{limit: limit}
+	for len(data) > 0 {
+		if len(data) < 4 {
+			return nil, errZstdTruncated
+		}
+		magic := binary.LittleEndian.Uint32(data)
+		if magic&zstdSkippableMask == zstdSkippable {
+			if len(data) < 8 {
+				return nil, errZstdTruncated
+			}
+			size := binary.LittleEndian.Uint32(data[4:])
+			if uint64(len(data)-8) < uint64(size) {
+				return nil, errZstdTruncated
+			}
+			data = data[8+int(size):]
+			continue
+		}
+		if magic != zstdMagic {
+			return nil, zstdError("invalid frame magic number")
+		}
+
+		n, err := d.frame(data[4:])
+		if err != nil {
+			return nil, err
+		}
+		data = data[4+n:]
+	}
+	return d.out, nil
+}
+
+// grow checks that n more bytes of content don't exceed the limit.
+func (d *zstdDecoder) grow(n int) error {
+	if n > d.limit-len(d.out) {
+		return errCatalogTooLarge
+	}
+	return nil
+}
+
+// frame decodes the frame in data, after its magic number, returning its size.
+func (d *zstdDecoder) frame(data []byte) (int, error) {
+	if len(data) == 0 {
+		return 0, errZstdTruncated
+	}
+	desc := data[0]
+	off := 1
+	if desc&0x08 != 0 {
+		return 0, zstdError("reserved frame header bit set")
+	}
+	single := desc&0x20 != 0
+	if !single {
+		// The whole content is kept, so the window size doesn't matter
+		off++
+	}
+	dictSize := [4]int{0, 1, 2, 4}[desc&3]
+	sizeSize := [4]int{0, 2, 4, 8}[desc>>6]
+	if sizeSize == 0 && single {
+		sizeSize = 1
+	}
+	if len(data) < off+dictSize+sizeSize {
+		return 0, errZstdTruncated
+	}
+
+	var dict uint32
+	for i := dictSize - 1; i >= 0; i-- {
+		dict = dict<<8 | uint32(data[off+i])
+	}
+	if dict != 0 {
+		return 0, zstdError("dictionaries aren't supported")
+	}
+	off += dictSize
+
+	var size uint64
+	for i := sizeSize - 1; i >= 0; i-- {
+		size = size<<8 | uint64(data[off+i])
+	}
+	if sizeSize == 2 {
+		size += 256
+	}
+	off += sizeSize
+	if sizeSize > 0 && size > uint64(d.limit-len(d.out)) {
+		return 0, errCatalogTooLarge
+	}
+
+	d.start = len(d.out)
+	d.huffmanBits = 0
+	d.seqTables = [3][]seqEntry{}
+	d.reps = [3]int{1, 4, 8}
+
+	for last := false; !last; {
+		if len(data) < off+3 {
+			return 0, errZstdTruncated
+		}
+		header := int(data[off]) | int(data[off+1])<<8 | int(data[off+2])<<16
+		off += 3
+		last = header&1 != 0
+		blockSize := header >> 3
+
+		switch header >> 1 & 3 {
+		case 0:
+			if len(data) < off+blockSize {
+				return 0, errZstdTruncated
+			}
+			if err := d.grow(blockSize); err != nil {
+				return 0, err
+			}
+			d.out = append(d.out, data[off:off+blockSize]...)
+			off += blockSize
+		case 1:
+			if len(data) < off+1 {
+				return 0, errZstdTruncated
+			}
+			if err := d.grow(blockSize); err != nil {
+				return 0, err
+			}
+			d.out = append(d.out, bytes.Repeat(data[off:off+1], blockSize)...)
+			off++
+		case 2:
+			if blockSize > zstdMaxBlockSize {
+				return 0, zstdError("block too large")
+			}
+			if len(data) < off+blockSize {
+				return 0, errZstdTruncated
+			}
+			if err := d.block(data[off : off+blockSize]); err != nil {
+				return 0, err
+			}
+			off += blockSize
+		default:
+			return 0, zstdError("reserved block type")
+		}
+	}
+
+	if sizeSize > 0 && uint64(len(d.out)-d.start) != size {
+		return 0, zstdError("frame content size mismatch")
+	}
+	if desc&0x04 != 0 {
+		if len(data) < off+4 {
+			return 0, errZstdTruncated
+		}
+		if uint32(xxhash64(d.out[d.start:])) != binary.LittleEndian.Uint32(data[off:]) {
+			return 0, zstdError("checksum mismatch")
+		}
+		off += 4
+	}
+	return off, nil
+}
+
+// block decodes a compressed block.
+func (d *zstdDecoder) block(data []byte) error {
+	lits, off, err := d.literals(data)
+	if err != nil {
+		return err
+	}
+
+	if off >= len(data) {
+		return errZstdTruncated
+	}
+	count := int(data[off])
+	off++
+	switch {
+	case count == 255:
+		if len(data) < off+2 {
+			return errZstdTruncated
+		}
+		count = int(data[off]) + int(data[off+1])<<8 + 0x7f00
+		off += 2
+	case count >= 128:
+		if len(data) < off+1 {
+			return errZstdTruncated
+		}
+		count = (count-128)<<8 + int(data[off])
+		off++
+	}
+
+	if count == 0 {
+		if off != len(data) {
+			return zstdError("data after literals")
+		}
+		if err := d.grow(len(lits)); err != nil {
+			return err
+		}
+		d.out = append(d.out, lits...)
+		return nil
+	}
+
+	if off >= len(data) {
+		return errZstdTruncated
+	}
+	modes := data[off]
+	off++
+	if modes&3 != 0 {
+		return zstdError("reserved sequence modes bits set")
+	}
+	for kind, shift := range [3]uint{6, 4, 2} {
+		n, err := d.seqTable(kind, modes>>shift&3, data[off:])
+		if err != nil {
+			return err
+		}
+		off += n
+	}
+
+	return d.sequences(data[off:], count, lits)
+}
+
+// literals decodes the literals section at the start of a compressed block, returning the literals
+// and the size of the section.
+func (d *zstdDecoder) literals(data []byte) ([]byte, int, error) {
+	if len(data) == 0 {
+		return nil, 0, errZstdTruncated
+	}
+	header := data[0]
+	kind := header & 3
+	format := header >> 2 & 3
+
+	if kind < 2 {
+		// Raw and RLE literals
+		size, off := int(header>>3), 1
+		switch format {
+		case 1:
+			if len(data) < 2 {
+				return nil, 0, errZstdTruncated
+			}
+			size, off = int(header>>4)|int(data[1])<<4, 2
+		case 3:
+			if len(data) < 3 {
+				return nil, 0, errZstdTruncated
+			}
+			size, off = int(header>>4)|int(data[1])<<4|int(data[2])<<12, 3
+		}
+		if size > zstdMaxBlockSize {
+			return nil, 0, zstdError("literals too large")
+		}
+		if kind == 0 {
+			if len(data) < off+size {
+				return nil, 0, errZstdTruncated
+			}
+			return data[off : off+size], off + size, nil
+		}
+		if len(data) < off+1 {
+			return nil, 0, errZstdTruncated
+		}
+		return bytes.Repeat(data[off:off+1], size), off + 1, nil
+	}
+
+	// Huffman coded literals
+	var size, compressed, off int
+	streams := 4
+	switch format {
+	case 0, 1:
+		if len(data) < 3 {
+			return nil, 0, errZstdTruncated
+		}
+		size = int(header>>4) | int(data[1]&0x3f)<<4
+		compressed = int(data[1]>>6) | int(data[2])<<2
+		off = 3
+		if format == 0 {
+			streams = 1
+		}
+	case 2:
+		if len(data) < 4 {
+			return nil, 0, errZstdTruncated
+		}
+		size = int(header>>4) | int(data[1])<<4 | int(data[2]&3)<<12
+		compressed = int(data[2]>>2) | int(data[3])<<6
+		off = 4
+	case 3:
+		if len(data) < 5 {
+			return nil, 0, errZstdTruncated
+		}
+		size = int(header>>4) | int(data[1])<<4 | int(data[2]&0x3f)<<12
+		compressed = int(data[2]>>6) | int(data[3])<<2 | int(data[4])<<10
+		off = 5
+	}
+	if size > zstdMaxBlockSize {
+		return nil, 0, zstdError("literals too large")
+	}
+	if len(data) < off+compressed {
+		return nil, 0, errZstdTruncated
+	}
+	src := data[off : off+compressed]
+
+	if kind == 2 {
+		n, err := d.readHuffman(src)
+		if err != nil {
+			return nil, 0, err
+		}
+		src = src[n:]
+	} else if d.huffmanBits == 0 {
+		return nil, 0, zstdError("missing Huffman table")
+	}
+
+	lits := make([]byte, size)
+	if streams == 1 {
+		if err := d.huffmanStream(src, lits); err != nil {
+			return nil, 0, err
+		}
+		return lits, off + compressed, nil
+	}
+
+	if len(src) < 6 {
+		return nil, 0, errZstdTruncated
+	}
+	sizes := [4]int{int(binary.LittleEndian.Uint16(src)), int(binary.LittleEndian.Uint16(src[2:])),
+		int(binary.LittleEndian.Uint16(src[4:]))}
+	src = src[6:]
+	sizes[3] = len(src) - sizes[0] - sizes[1] - sizes[2]
+	if sizes[3] < 0 {
+		return nil, 0, errZstdTruncated
+	}
+	per := (size + 3) / 4
+	if size < 3*per {
+		return nil, 0, zstdError("invalid literals size")
+	}
+	for i, n := range sizes {
+		end := (i + 1) * per
+		if i == 3 {
+			end = size
+		}
+		if err := d.huffmanStream(src[:n], lits[i*per:end]); err != nil {
+			return nil, 0, err
+		}
+		src = src[n:]
+	}
+	return lits, off + compressed, nil
+}
+
+// readHuffman reads the Huffman table description at the start of data, returning its size.
+func (d *zstdDecoder) readHuffman(data []byte) (int, error) {
+	if len(data) == 0 {
+		return 0, errZstdTruncated
+	}
+	header := int(data[0])
+
+	var weights []uint8
+	var off int
+	if header < 128 {
+		// FSE compressed weights, decoded with two interleaved states
+		off = 1 + header
+		if len(data) < off {
+			return 0, errZstdTruncated
+		}
+		table, log, n, err := readFSE(data[1:off], 255, 6)
+		if err != nil {
+			return 0, err
+		}
+		br, err := newReverseBits(data[1+n : off])
+		if err != nil {
+			return 0, err
+		}
+		states := [2]uint32{br.read(log), br.read(log)}
+		if br.pos < 0 {
+			return 0, errZstdTruncated
+		}
+		for i := 0; ; i ^= 1 {
+			e := table[states[i]]
+			weights = append(weights, e.sym)
+			if br.pos < int(e.bits) {
+				weights = append(weights, table[states[i^1]].sym)
+				break
+			}
+			if len(weights) > 254 {
+				return 0, zstdError("too many Huffman weights")
+			}
+			states[i] = uint32(e.base) + br.read(int(e.bits))
+		}
+	} else {
+		count := header - 127
+		off = 1 + (count+1)/2
+		if len(data) < off {
+			return 0, errZstdTruncated
+		}
+		for i := 0; i < count; i++ {
+			w := data[1+i/2]
+			if i%2 == 0 {
+				w >>= 4
+			}
+			weights = append(weights, w&0xf)
+		}
+	}
+
+	// The weight of the last symbol is what's left to reach a power of 2
+	var sum uint32
+	var ranks [13]uint32
+	for _, w := range weights {
+		if w > 12 {
+			return 0, zstdError("invalid Huffman weight")
+		}
+		ranks[w]++
+		if w > 0 {
+			sum += 1 << (w - 1)
+		}
+	}
+	if sum == 0 {
+		return 0, zstdError("invalid Huffman weights")
+	}
+	tableBits := bits.Len32(sum)
+	if tableBits > 11 {
+		return 0, zstdError("invalid Huffman weights")
+	}
+	left := uint32(1)<<uint(tableBits) - sum
+	if left&(left-1) != 0 || len(weights) > 255 {
+		return 0, zstdError("invalid Huffman weights")
+	}
+	last := uint8(bits.Len32(left))
+	weights = append(weights, last)
+	ranks[last]++
+	if ranks[1] < 2 || ranks[1]&1 != 0 {
+		return 0, zstdError("invalid Huffman weights")
+	}
+
+	// Codes are given in order of weight, then of symbol
+	var next uint32
+	for w := 1; w <= tableBits; w++ {
+		start := next
+		next += ranks[w] << uint(w-1)
+		ranks[w] = start
+	}
+	d.huffman = make([]uint16, 1<<uint(tableBits))
+	for sym, w := range weights {
+		if w == 0 {
+			continue
+		}
+		entry := uint16(sym)<<8 | uint16(tableBits+1-int(w))
+		n := uint32(1) << (w - 1)
+		for i := ranks[w]; i < ranks[w]+n; i++ {
+			d.huffman[i] = entry
+		}
+		ranks[w] += n
+	}
+	d.huffmanBits = tableBits
+	return off, nil
+}
+
+// huffmanStream decodes the Huffman coded bitstream data, filling out.
+func (d *zstdDecoder) huffmanStream(data []byte, out []byte) error {
+	br, err := newReverseBits(data)
+	if err != nil {
+		return err
+	}
+	for i := range out {
+		e := d.huffman[br.peek(d.huffmanBits)]
+		out[i] = byte(e >> 8)
+		br.pos -= int(e & 0xff)
+	}
+	if br.pos != 0 {
+		return zstdError("corrupt Huffman stream")
+	}
+	return nil
+}
+
+// seqTable sets the decoding table of a kind of sequence value according to its mode, returning the size
+// of its description at the start of data.
+func (d *zstdDecoder) seqTable(kind int, mode byte, data []byte) (int, error) {
+	k := &seqKinds[kind]
+	switch mode {
+	case 0:
+		d.seqTables[kind], d.seqLogs[kind] = zstdPredefined[kind], k.predefLog
+		return 0, nil
+	case 1:
+		if len(data) == 0 {
+			return 0, errZstdTruncated
+		}
+		table, err := k.seqTable(kind, []fseEntry{{sym: data[0]}})
+		if err != nil {
+			return 0, err
+		}
+		d.seqTables[kind], d.seqLogs[kind] = table, 0
+		return 1, nil
+	case 2:
+		fse, log, n, err := readFSE(data, k.maxSym, k.maxLog)
+		if err != nil {
+			return 0, err
+		}
+		table, err := k.seqTable(kind, fse)
+		if err != nil {
+			return 0, err
+		}
+		d.seqTables[kind], d.seqLogs[kind] = table, log
+		return n, nil
+	default:
+		if d.seqTables[kind] == nil {
+			return 0, zstdError("missing sequence table")
+		}
+		return 0, nil
+	}
+}
+
+// sequences executes the count sequences of the bitstream data, copying literals and matches to the output.
+func (d *zstdDecoder) sequences(data []byte, count int, lits []byte) error {
+	br, err := newReverseBits(data)
+	if err != nil {
+		return err
+	}
+	var states [3]uint32
+	for _, kind := range [3]int{seqLiteral, seqOffset, seqMatch} {
+		states[kind] = br.read(d.seqLogs[kind])
+	}
+
+	for i := 0; i < count; i++ {
+		var e [3]seqEntry
+		for kind := range e {
+			e[kind] = d.seqTables[kind][states[kind]]
+		}
+		offset := int(e[seqOffset].baseline + br.read(int(e[seqOffset].extra)))
+		match := int(e[seqMatch].baseline + br.read(int(e[seqMatch].extra)))
+		literal := int(e[seqLiteral].baseline + br.read(int(e[seqLiteral].extra)))
+		if i < count-1 {
+			for _, kind := range [3]int{seqLiteral, seqMatch, seqOffset} {
+				states[kind] = uint32(e[kind].base) + br.read(int(e[kind].bits))
+			}
+		}
+		if br.pos < 0 {
+			return errZstdTruncated
+		}
+
+		offset = d.offset(offset, literal)
+
+		if literal > len(lits) {
+			return zstdError("literals overflow")
+		}
+		if err := d.grow(literal + match); err != nil {
+			return err
+		}
+		d.out = append(d.out, lits[:literal]...)
+		lits = lits[literal:]
+
+		if offset <= 0 || offset > len(d.out)-d.start {
+			return zstdError("invalid match offset")
+		}
+		// Matches can overlap what they copy
+		for match > 0 {
+			from := len(d.out) - offset
+			n := match
+			if n > offset {
+				n = offset
+			}
+			d.out = append(d.out, d.out[from:from+n]...)
+			match -= n
+		}
+	}
+
+	if br.pos != 0 {
+		return zstdError("corrupt sequences")
+	}
+	if err := d.grow(len(lits)); err != nil {
+		return err
+	}
+	d.out = append(d.out, lits...)
+	return nil
+}
+
+// offset returns the match offset of the offset value, updating the repeated offsets.
+func (d *zstdDecoder) offset(value, literal int) int {
+	if value > 3 {
+		d.reps = [3]int{value - 3, d.reps[0], d.reps[1]}
+		return d.reps[0]
+	}
+	if literal == 0 {
+		value++
+	}
+	var offset int
+	switch value {
+	case 1:
+		return d.reps[0]
+	case 2:
+		offset = d.reps[1]
+		d.reps[1] = d.reps[0]
+	case 3:
+		offset = d.reps[2]
+		d.reps[2], d.reps[1] = d.reps[1], d.reps[0]
+	default:
+		offset = d.reps[0] - 1
+		d.reps[2], d.reps[1] = d.reps[1], d.reps[0]
+	}
+	d.reps[0] = offset
+	return offset
+}
+
+const (
+	xxPrime1 uint64 = 11400714785074694791
+	xxPrime2 uint64 = 14029467366897019727
+	xxPrime3 uint64 = 1609587929392839161
+	xxPrime4 uint64 = 9650029242287828579
+	xxPrime5 uint64 = 2870177450012600261
+)
+
+func xxRound(acc, input uint64) uint64 {
+	return bits.RotateLeft64(acc+input*xxPrime2, 31) * xxPrime1
+}
+
+// xxhash64 returns the XXH64 hash of data with a zero seed, used by the checksums of zstd frames.
+func xxhash64(data []byte) uint64 {
+	n := uint64(len(data))
+	var h uint64
+	if len(data) >= 32 {
+		v := [4]uint64{xxPrime1, xxPrime2, 0, 0}
+		v[0] += xxPrime2
+		v[3] -= xxPrime1
+		for ; len(data) >= 32; data = data[32:] {
+			for i := range v {
+				v[i] = xxRound(v[i], binary.LittleEndian.Uint64(data[8*i:]))
+			}
+		}
+		h = bits.RotateLeft64(v[0], 1) + bits.RotateLeft64(v[1], 7) + bits.RotateLeft64(v[2], 12) +
+			bits.RotateLeft64(v[3], 18)
+		for _, x := range v {
+			h = (h^xxRound(0, x))*xxPrime1 + xxPrime4
+		}
+	} else {
+		h = xxPrime5
+	}
+	h += n
+
+	for ; len(data) >= 8; data = data[8:] {
+		h ^= xxRound(0, binary.LittleEndian.Uint64(data))
+		h = bits.RotateLeft64(h, 27)*xxPrime1 + xxPrime4
+	}
+	if len(data) >= 4 {
+		h ^= uint64(binary.LittleEndian.Uint32(data)) * xxPrime1
+		h = bits.RotateLeft64(h, 23)*xxPrime2 + xxPrime3
+		data = data[4:]
+	}
+	for _, b := range data {
+		h ^= uint64(b) * xxPrime5
+		h = bits.RotateLeft64(h, 11) * xxPrime1
+	}
+
+	h ^= h >> 33
+	h *= xxPrime2
+	h ^= h >> 29
+	h *= xxPrime3
+	h ^= h >> 32
+	return h
+}
@@ -0,0 +1,84 @@
+package gotext
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+)
+
+func TestDecodeZstd(t *testing.T) {
+	de, err := ioutil.ReadFile("fixtures/de/default.po")
+	if err != nil {
+		t.Fatal(err)
+	}
+	en, err := ioutil.ReadFile("fixtures/en_US/default.po")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fr, err := ioutil.ReadFile("fixtures/fr/LC_MESSAGES/default.po")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for file, want := range map[string][]byte{
+		// Compressed with zstd -19
+		"fixtures/zstd/default.po.zst": de,
+		// Two frames, compressed with zstd -1 --no-check and zstd --fast=5, with a skippable frame between them
+		"fixtures/zstd/frames.zst": append(append([]byte(nil), en...), fr...),
+	} {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := decodeZstd(data, maxDecompressedSize)
+		if err != nil {
+			t.Errorf("%s: %v", file, err)
+			continue
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("%s: unexpected content %q", file, got)
+		}
+
+		if _, err := decodeZstd(data, len(want)-1); err != errCatalogTooLarge {
+			t.Errorf("%s: unexpected error %v with a lower limit", file, err)
+		}
+		if _, err := decodeZstd(data[:len(data)-10], maxDecompressedSize); err == nil {
+			t.Errorf("%s: expected an error for truncated data", file)
+		}
+	}
+
+	// A compressed block with RLE literals and no sequences
+	rle := []byte{0x28, 0xb5, 0x2f, 0xfd, 0x20, 5, 0x1d, 0, 0, 0x29, 'a', 0}
+	if got, err := decodeZstd(rle, maxDecompressedSize); err != nil || string(got) != "aaaaa" {
+		t.Errorf("unexpected content %q, %v", got, err)
+	}
+
+	// The checksum of the first frame, changed
+	data, err := ioutil.ReadFile("fixtures/zstd/default.po.zst")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data[len(data)-1]++
+	if _, err := decodeZstd(data, maxDecompressedSize); err == nil {
+		t.Error("expected a checksum error")
+	}
+
+	// A frame using a dictionary
+	dict := []byte{0x28, 0xb5, 0x2f, 0xfd, 0x21, 7, 5, 0x1d, 0, 0, 0x29, 'a', 0}
+	if _, err := decodeZstd(dict, maxDecompressedSize); err == nil {
+		t.Error("expected an error for a dictionary")
+	}
+}
+
+func TestXXHash64(t *testing.T) {
+	for in, want := range map[string]uint64{
+		"":    0xef46db3751d8e999,
+		"a":   0xd24ec4f1a98c6e5b,
+		"abc": 0x44bc2cf5ad770999,
+		"Nobody inspects the spammish repetition": 0xfbcea83c8a378bf1,
+	} {
+		if got := xxhash64([]byte(in)); got != want {
+			t.Errorf("xxhash64(%q) = %#x, want %#x", in, got, want)
+		}
+	}
+}